@@ -0,0 +1,131 @@
+// Package affixes posts the week's mythic keystone affixes to Discord after each weekly reset.
+package affixes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/discord"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/DylanNZL/mythicplusbot/vault"
+	"github.com/bwmarrin/discordgo"
+)
+
+// postDelay gives Raider.IO time to pick up the new affixes after the reset.
+const postDelay = 30 * time.Minute
+
+type (
+	RaiderIOClient interface {
+		GetAffixes(ctx context.Context) (*raiderio.Affixes, error)
+	}
+
+	MessageSender interface {
+		SendComplexMessage(ctx context.Context, channelID string, message discordgo.MessageSend) error
+	}
+
+	// ChannelFinder finds the Discord server a channel is in.
+	ChannelFinder interface {
+		ChannelGuildID(ctx context.Context, channelID string) (string, error)
+	}
+
+	GuildSettingsRepository interface {
+		GetLocale(ctx context.Context, guildID string) (string, error)
+	}
+
+	TimeProvider interface {
+		Now() time.Time
+	}
+)
+
+type RealTimeProvider struct{}
+
+func (r *RealTimeProvider) Now() time.Time {
+	return time.Now()
+}
+
+// Service handles affix posts with injected dependencies
+type Service struct {
+	raiderIOClient RaiderIOClient
+	messageSender  MessageSender
+	channelFinder  ChannelFinder
+	settingsRepo   GuildSettingsRepository
+	timeProvider   TimeProvider
+}
+
+// NewService creates a new affixes service with dependencies
+func NewService(raiderIOClient RaiderIOClient, messageSender MessageSender, channelFinder ChannelFinder,
+	settingsRepo GuildSettingsRepository, timeProvider TimeProvider,
+) *Service {
+	return &Service{
+		raiderIOClient: raiderIOClient,
+		messageSender:  messageSender,
+		channelFinder:  channelFinder,
+		settingsRepo:   settingsRepo,
+		timeProvider:   timeProvider,
+	}
+}
+
+// Post sends this week's affixes to the channel.
+func (s *Service) Post(ctx context.Context, channelID string) error {
+	affixes, err := s.raiderIOClient.GetAffixes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get affixes: %w", err)
+	}
+
+	return s.messageSender.SendComplexMessage(ctx, channelID, discord.BuildAffixesMessage(i18n.FromContext(ctx), affixes))
+}
+
+// RunWeeklyPosts posts the affixes shortly after each weekly reset, until ctx is cancelled. Each post is in the
+// language chosen for the channel's server.
+func (s *Service) RunWeeklyPosts(ctx context.Context, channelID string) {
+	for {
+		now := s.timeProvider.Now()
+		next := NextPost(now)
+		slog.DebugContext(ctx, "waiting for weekly affixes post", "at", next)
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.Post(s.channelLocale(ctx, channelID), channelID); err != nil {
+			slog.ErrorContext(ctx, "failed to post weekly affixes", "error", err)
+		}
+	}
+}
+
+// channelLocale returns ctx in the language chosen for the channel's server, English if it hasn't chosen one. If the
+// language can't be found ctx is returned unchanged.
+func (s *Service) channelLocale(ctx context.Context, channelID string) context.Context {
+	guildID, err := s.channelFinder.ChannelGuildID(ctx, channelID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to find the server for the affixes channel", "error", err, "channel", channelID)
+		return ctx
+	}
+
+	code, err := s.settingsRepo.GetLocale(ctx, guildID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get locale", "error", err, "guild", guildID)
+		return ctx
+	}
+	if code == "" {
+		return i18n.WithLocale(ctx, i18n.English)
+	}
+
+	l, err := i18n.Parse(code)
+	if err != nil {
+		slog.WarnContext(ctx, "unknown locale stored for guild", "guild", guildID, "locale", code)
+	}
+	return i18n.WithLocale(ctx, l)
+}
+
+// NextPost returns the first time after now that the affixes should be posted.
+func NextPost(now time.Time) time.Time {
+	return vault.NextReset(now.Add(-postDelay)).Add(postDelay)
+}
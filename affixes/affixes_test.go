@@ -0,0 +1,159 @@
+package affixes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/discord"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock implementations for testing
+
+type MockRaiderIOClient struct {
+	mock.Mock
+}
+
+func (m *MockRaiderIOClient) GetAffixes(ctx context.Context) (*raiderio.Affixes, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*raiderio.Affixes), args.Error(1)
+}
+
+type MockMessageSender struct {
+	mock.Mock
+}
+
+func (m *MockMessageSender) SendComplexMessage(ctx context.Context, channelID string, message discordgo.MessageSend) error {
+	args := m.Called(ctx, channelID, message)
+	return args.Error(0)
+}
+
+type MockChannelFinder struct {
+	mock.Mock
+}
+
+func (m *MockChannelFinder) ChannelGuildID(ctx context.Context, channelID string) (string, error) {
+	args := m.Called(ctx, channelID)
+	return args.String(0), args.Error(1)
+}
+
+type MockGuildSettingsRepository struct {
+	mock.Mock
+}
+
+func (m *MockGuildSettingsRepository) GetLocale(ctx context.Context, guildID string) (string, error) {
+	args := m.Called(ctx, guildID)
+	return args.String(0), args.Error(1)
+}
+
+type MockTimeProvider struct {
+	now time.Time
+}
+
+func (m *MockTimeProvider) Now() time.Time {
+	return m.now
+}
+
+// Tests
+
+func TestService_Post(t *testing.T) {
+	client := &MockRaiderIOClient{}
+	messageSender := &MockMessageSender{}
+	service := NewService(client, messageSender, &MockChannelFinder{}, &MockGuildSettingsRepository{},
+		&MockTimeProvider{})
+
+	affixes := &raiderio.Affixes{AffixDetails: []raiderio.Affix{{Name: "Tyrannical", Description: "Bosses hit hard."}}}
+	client.On("GetAffixes", t.Context()).Return(affixes, nil)
+	messageSender.On("SendComplexMessage", t.Context(), "channel1", discord.BuildAffixesMessage(i18n.English, affixes)).Return(nil)
+
+	err := service.Post(t.Context(), "channel1")
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestService_Post_ClientError(t *testing.T) {
+	client := &MockRaiderIOClient{}
+	messageSender := &MockMessageSender{}
+	service := NewService(client, messageSender, &MockChannelFinder{}, &MockGuildSettingsRepository{},
+		&MockTimeProvider{})
+
+	client.On("GetAffixes", t.Context()).Return(nil, errors.New("api error"))
+
+	err := service.Post(t.Context(), "channel1")
+
+	assert.ErrorContains(t, err, "failed to get affixes")
+	messageSender.AssertNotCalled(t, "SendComplexMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_ChannelLocale(t *testing.T) {
+	tests := []struct {
+		name    string
+		locale  string
+		findErr error
+		repoErr error
+		want    i18n.Locale
+	}{
+		{name: "chosen language", locale: "fr", want: i18n.French},
+		{name: "no language chosen", locale: "", want: i18n.English},
+		{name: "unsupported language", locale: "xx", want: i18n.English},
+		// Without the server's language the context's own is used
+		{name: "channel not found", findErr: errors.New("api error"), want: i18n.German},
+		{name: "settings error", repoErr: errors.New("db error"), want: i18n.German},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channelFinder := &MockChannelFinder{}
+			settingsRepo := &MockGuildSettingsRepository{}
+			service := NewService(&MockRaiderIOClient{}, &MockMessageSender{}, channelFinder, settingsRepo,
+				&MockTimeProvider{})
+			ctx := i18n.WithLocale(t.Context(), i18n.German)
+
+			channelFinder.On("ChannelGuildID", ctx, "channel1").Return("guild1", tt.findErr)
+			settingsRepo.On("GetLocale", ctx, "guild1").Return(tt.locale, tt.repoErr)
+
+			assert.Equal(t, tt.want, i18n.FromContext(service.channelLocale(ctx, "channel1")))
+		})
+	}
+}
+
+func TestNextPost(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "before the reset",
+			now:  time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC),
+			want: time.Date(2024, time.January, 2, 15, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "between the reset and the post",
+			now:  time.Date(2024, time.January, 2, 15, 10, 0, 0, time.UTC),
+			want: time.Date(2024, time.January, 2, 15, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "at the post",
+			now:  time.Date(2024, time.January, 2, 15, 30, 0, 0, time.UTC),
+			want: time.Date(2024, time.January, 9, 15, 30, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NextPost(tt.now))
+		})
+	}
+}
@@ -0,0 +1,278 @@
+// Package backup takes online snapshots of the SQLite database and prunes old ones.
+//
+// Snapshots are made with VACUUM INTO so they are consistent while the bot keeps writing, and are written to a
+// directory as timestamped files. Retention keeps the newest snapshot of each of the last N days and N weeks.
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+)
+
+const (
+	filePrefix = "mythicplusbot-"
+	fileSuffix = ".sqlite"
+	timeLayout = "20060102-150405"
+)
+
+var ErrNoBackupDirectory = errors.New("backup directory is not configured")
+
+type (
+	// Database is the part of db.Database needed to take a snapshot.
+	Database interface {
+		Query(ctx context.Context, query string, args ...any) error
+	}
+
+	// TimeProvider defines the interface for getting current time (for testing).
+	TimeProvider interface {
+		Now() time.Time
+	}
+
+	// RealTimeProvider implements TimeProvider with real time.
+	RealTimeProvider struct{}
+
+	// Retention controls how many snapshots are kept when pruning.
+	Retention struct {
+		Daily  int
+		Weekly int
+	}
+)
+
+func (r *RealTimeProvider) Now() time.Time {
+	return time.Now()
+}
+
+// Manager takes and prunes snapshots of a database.
+type Manager struct {
+	db           Database
+	dir          string
+	retention    Retention
+	timeProvider TimeProvider
+}
+
+// NewManager creates a new backup manager writing snapshots to dir
+func NewManager(database Database, dir string, retention Retention, timeProvider TimeProvider) *Manager {
+	return &Manager{
+		db:           database,
+		dir:          dir,
+		retention:    retention,
+		timeProvider: timeProvider,
+	}
+}
+
+// Run takes a snapshot and prunes old ones every frequency until the context is cancelled.
+func (m *Manager) Run(ctx context.Context, frequency time.Duration) {
+	ticker := time.NewTicker(frequency)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Backup(ctx); err != nil {
+			slog.ErrorContext(ctx, "backup failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Backup takes a snapshot then prunes the snapshots that fall outside the retention policy.
+func (m *Manager) Backup(ctx context.Context) (string, error) {
+	path, err := m.Snapshot(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := m.Prune(ctx); err != nil {
+		return path, err
+	}
+
+	return path, nil
+}
+
+// Snapshot writes a copy of the database into the backup directory and returns its path.
+func (m *Manager) Snapshot(ctx context.Context) (string, error) {
+	if m.dir == "" {
+		return "", ErrNoBackupDirectory
+	}
+
+	if err := os.MkdirAll(m.dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	path := filepath.Join(m.dir, filePrefix+m.timeProvider.Now().UTC().Format(timeLayout)+fileSuffix)
+	slog.DebugContext(ctx, "taking database snapshot", "path", path)
+	if err := m.db.Query(ctx, "VACUUM INTO ?", path); err != nil {
+		return "", fmt.Errorf("failed to snapshot database: %w", err)
+	}
+
+	slog.InfoContext(ctx, "database backed up", "path", path)
+	return path, nil
+}
+
+// Prune deletes the snapshots that aren't the newest of one of the last Retention.Daily days or Retention.Weekly
+// weeks. The newest snapshot is always kept.
+func (m *Manager) Prune(ctx context.Context) ([]string, error) {
+	snapshots, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	keep := retain(snapshots, m.retention)
+
+	var removed []string
+	for _, s := range snapshots {
+		if keep[s.Path] {
+			continue
+		}
+
+		if err := os.Remove(s.Path); err != nil {
+			return removed, fmt.Errorf("failed to remove old backup: %w", err)
+		}
+		slog.DebugContext(ctx, "removed old backup", "path", s.Path)
+		removed = append(removed, s.Path)
+	}
+
+	return removed, nil
+}
+
+// Snapshot is a backup file found in the backup directory.
+type Snapshot struct {
+	Path  string
+	Taken time.Time
+}
+
+// List returns the snapshots in the backup directory, newest first. Files that weren't made by Snapshot are ignored.
+func (m *Manager) List() ([]Snapshot, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+
+		taken, err := time.Parse(timeLayout, strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileSuffix))
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{Path: filepath.Join(m.dir, name), Taken: taken})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Taken.After(snapshots[j].Taken)
+	})
+
+	return snapshots, nil
+}
+
+// retain picks which snapshots to keep, snapshots must be sorted newest first.
+func retain(snapshots []Snapshot, retention Retention) map[string]bool {
+	keep := make(map[string]bool)
+	if len(snapshots) == 0 {
+		return keep
+	}
+	keep[snapshots[0].Path] = true
+
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+	for _, s := range snapshots {
+		day := s.Taken.Format(time.DateOnly)
+		if !days[day] && len(days) < retention.Daily {
+			days[day] = true
+			keep[s.Path] = true
+		}
+
+		year, week := s.Taken.ISOWeek()
+		key := fmt.Sprintf("%d-%d", year, week)
+		if !weeks[key] && len(weeks) < retention.Weekly {
+			weeks[key] = true
+			keep[s.Path] = true
+		}
+	}
+
+	return keep
+}
+
+// Restore replaces the database at dbPath with the snapshot at backupPath.
+//
+// The snapshot is integrity checked first, and the current database is kept alongside as dbPath.pre-restore. The bot
+// must not be running while restoring.
+func Restore(ctx context.Context, backupPath, dbPath string) error {
+	if err := Verify(ctx, backupPath); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(dbPath); err == nil {
+		if err := copyFile(dbPath, dbPath+".pre-restore"); err != nil {
+			return fmt.Errorf("failed to keep current database: %w", err)
+		}
+	}
+
+	// Copy next to the database then rename so a failed copy can't leave a half-written database behind
+	tmp := dbPath + ".restoring"
+	if err := copyFile(backupPath, tmp); err != nil {
+		return fmt.Errorf("failed to copy backup: %w", err)
+	}
+	if err := os.Rename(tmp, dbPath); err != nil {
+		return fmt.Errorf("failed to replace database: %w", err)
+	}
+
+	slog.InfoContext(ctx, "database restored", "from", backupPath, "to", dbPath)
+	return nil
+}
+
+// Verify checks a database file exists and passes PRAGMA integrity_check.
+func Verify(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+
+	database, err := db.NewSQLiteDB(path)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	return database.CheckIntegrity(ctx)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
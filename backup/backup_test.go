@@ -0,0 +1,165 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations for testing
+
+type MockTimeProvider struct {
+	mock.Mock
+}
+
+func (m *MockTimeProvider) Now() time.Time {
+	args := m.Called()
+	return args.Get(0).(time.Time)
+}
+
+// Test helper functions
+
+func createTestDatabase(t *testing.T, path string) *db.SQLiteDB {
+	t.Helper()
+
+	database, err := db.NewSQLiteDB(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = database.Close() })
+
+	require.NoError(t, database.Init(context.Background()))
+	require.NoError(t, db.NewCharacterRepo(database).Upsert(context.Background(), &db.Character{
+		ID: 1, Name: "Paladylan", Realm: "tichondrius", Class: "Paladin", OverallScore: 2500,
+	}))
+
+	return database
+}
+
+func createSnapshotFile(t *testing.T, dir string, taken time.Time) string {
+	t.Helper()
+
+	path := filepath.Join(dir, filePrefix+taken.Format(timeLayout)+fileSuffix)
+	require.NoError(t, os.WriteFile(path, []byte("snapshot"), 0o600))
+	return path
+}
+
+// Test snapshots
+
+func TestManager_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	database := createTestDatabase(t, filepath.Join(tmp, "bot.sqlite"))
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	timeProvider := &MockTimeProvider{}
+	timeProvider.On("Now").Return(now)
+
+	manager := NewManager(database, filepath.Join(tmp, "backups"), Retention{Daily: 1}, timeProvider)
+	path, err := manager.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(tmp, "backups", "mythicplusbot-20240102-030405.sqlite"), path)
+
+	// The snapshot should be a usable copy of the database
+	require.NoError(t, Verify(ctx, path))
+	snapshot, err := db.NewSQLiteDB(path)
+	require.NoError(t, err)
+	defer snapshot.Close()
+
+	character, err := db.NewCharacterRepo(snapshot).GetCharacterByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Paladylan", character.Name)
+}
+
+func TestManager_Snapshot_NoDirectory(t *testing.T) {
+	manager := NewManager(nil, "", Retention{}, &MockTimeProvider{})
+
+	_, err := manager.Snapshot(context.Background())
+	assert.ErrorIs(t, err, ErrNoBackupDirectory)
+}
+
+// Test retention
+
+func TestManager_Prune(t *testing.T) {
+	dir := t.TempDir()
+	// Sunday 14 Jan 2024, so the 8th-14th is one ISO week
+	now := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+
+	newest := createSnapshotFile(t, dir, now)
+	sameDay := createSnapshotFile(t, dir, now.Add(-time.Hour))
+	yesterday := createSnapshotFile(t, dir, now.Add(-24*time.Hour))
+	twoDaysAgo := createSnapshotFile(t, dir, now.Add(-48*time.Hour))
+	lastWeek := createSnapshotFile(t, dir, now.Add(-7*24*time.Hour))
+	lastWeekOlder := createSnapshotFile(t, dir, now.Add(-8*24*time.Hour))
+	threeWeeksAgo := createSnapshotFile(t, dir, now.Add(-21*24*time.Hour))
+
+	// Files that aren't snapshots are left alone
+	other := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(other, []byte("keep me"), 0o600))
+
+	manager := NewManager(nil, dir, Retention{Daily: 2, Weekly: 2}, &MockTimeProvider{})
+	removed, err := manager.Prune(context.Background())
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{sameDay, twoDaysAgo, lastWeekOlder, threeWeeksAgo}, removed)
+	for _, kept := range []string{newest, yesterday, lastWeek, other} {
+		assert.FileExists(t, kept)
+	}
+}
+
+func TestRetain_AlwaysKeepsNewest(t *testing.T) {
+	snapshots := []Snapshot{
+		{Path: "a", Taken: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{Path: "b", Taken: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	keep := retain(snapshots, Retention{})
+
+	assert.Equal(t, map[string]bool{"a": true}, keep)
+}
+
+// Test restores
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	backupPath := filepath.Join(tmp, "backup.sqlite")
+	dbPath := filepath.Join(tmp, "bot.sqlite")
+
+	createTestDatabase(t, backupPath)
+	require.NoError(t, os.WriteFile(dbPath, []byte("corrupted"), 0o600))
+
+	require.NoError(t, Restore(ctx, backupPath, dbPath))
+
+	require.NoError(t, Verify(ctx, dbPath))
+	previous, err := os.ReadFile(dbPath + ".pre-restore")
+	require.NoError(t, err)
+	assert.Equal(t, "corrupted", string(previous))
+}
+
+func TestRestore_CorruptBackup(t *testing.T) {
+	ctx := context.Background()
+	tmp := t.TempDir()
+	backupPath := filepath.Join(tmp, "backup.sqlite")
+	dbPath := filepath.Join(tmp, "bot.sqlite")
+
+	require.NoError(t, os.WriteFile(backupPath, []byte("this is not a database"), 0o600))
+	require.NoError(t, os.WriteFile(dbPath, []byte("current"), 0o600))
+
+	assert.Error(t, Restore(ctx, backupPath, dbPath))
+
+	// The current database must be left untouched
+	current, err := os.ReadFile(dbPath)
+	require.NoError(t, err)
+	assert.Equal(t, "current", string(current))
+}
+
+func TestVerify_MissingFile(t *testing.T) {
+	err := Verify(context.Background(), filepath.Join(t.TempDir(), "missing.sqlite"))
+	assert.Error(t, err)
+}
@@ -7,9 +7,10 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
-	"net/url"
 	"strings"
 	"time"
+
+	"github.com/DylanNZL/mythicplusbot/i18n"
 )
 
 // HTTPClient defines the interface for making HTTP requests.
@@ -32,29 +33,26 @@ func (r *RealTimeProvider) Now() time.Time {
 // APIClient defines the interface for Blizzard API operations.
 type APIClient interface {
 	GetMythicKeystoneProfile(ctx context.Context, realm, character string) (*MythicKeystoneProfile, error)
+	GetMythicKeystoneSeason(ctx context.Context, realm, character string, seasonID int) (*MythicKeystoneSeason, error)
+	GetCurrentSeasonID(ctx context.Context) (int, error)
+	GetCharacterProfile(ctx context.Context, realm, character string) (*CharacterProfile, error)
+	GetCharacterEquipment(ctx context.Context, realm, character string) (*CharacterEquipment, error)
 	SetCredentials(clientID, clientSecret string)
-}
-
-type auth struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresIn   int    `json:"expires_in"`
-	Scope       string `json:"scope"`
+	SetCache(cache Cache)
+	FlushCache(ctx context.Context)
 }
 
 type Client struct {
 	ID           string
 	Secret       string
-	Bearer       string
-	Expires      time.Time
+	tokens       *TokenSource
 	httpClient   HTTPClient
 	timeProvider TimeProvider
+	cache        Cache
 	oauthURL     string
 	baseURL      string
 }
 
-const expiryBuffer = time.Minute * 5
-
 func NewClient(httpClient HTTPClient, timeProvider TimeProvider) *Client {
 	return &Client{
 		httpClient:   httpClient,
@@ -67,77 +65,127 @@ func NewClient(httpClient HTTPClient, timeProvider TimeProvider) *Client {
 func (c *Client) SetCredentials(clientID, clientSecret string) {
 	c.ID = clientID
 	c.Secret = clientSecret
+	c.tokens = NewTokenSource(c.httpClient, c.timeProvider, c.oauthURL, clientID, clientSecret)
 }
 
-func (c *Client) checkClient(ctx context.Context) error {
-	if c.ID == "" || c.Secret == "" {
-		return fmt.Errorf("client is not initialised")
+// RenewTokens keeps the bearer token renewed in the background until the context is cancelled, so requests don't
+// wait on a refresh.
+func (c *Client) RenewTokens(ctx context.Context) {
+	if c.tokens == nil {
+		return
 	}
+	c.tokens.Run(ctx)
+}
 
-	// Check the bearer is set and won't expire in the next 5 minutes
-	if c.Bearer == "" || c.timeProvider.Now().Add(expiryBuffer).After(c.Expires) {
-		if err := c.getBearerToken(ctx); err != nil {
-			return err
-		}
+// SetCache makes requests conditional on the validators of the cached response, a 304 is served from the cache.
+func (c *Client) SetCache(cache Cache) {
+	c.cache = cache
+}
+
+// FlushCache saves the responses cached since the last flush. Call it after a batch of requests, such as an update
+// cycle, rather than after each one.
+func (c *Client) FlushCache(ctx context.Context) {
+	if c.cache == nil {
+		return
 	}
 
-	return nil
+	// As with setting an entry, failing to save only costs us full downloads after a restart
+	if err := c.cache.Flush(); err != nil {
+		slog.WarnContext(ctx, "failed to flush cache", "error", err)
+	}
 }
 
-func (c *Client) getBearerToken(ctx context.Context) error {
-	slog.DebugContext(ctx, "getting bearer token")
+func (c *Client) checkClient() error {
+	if c.ID == "" || c.Secret == "" || c.tokens == nil {
+		return fmt.Errorf("client is not initialised")
+	}
 
-	data := url.Values{}
-	data.Set("grant_type", "client_credentials")
+	return nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.oauthURL, strings.NewReader(data.Encode()))
+func (c *Client) sendRequest(ctx context.Context, url, token string, cached *CacheEntry) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.SetBasicAuth(c.ID, c.Secret)
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Authorization", "Bearer "+token)
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Add("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Add("If-Modified-Since", cached.LastModified)
+		}
+	}
 
-	resp, err := c.httpClient.Do(req)
+	return c.httpClient.Do(req)
+}
+
+// authorisedRequest sends the request with the current bearer token. If Blizzard rejects the token it is invalidated
+// and the request is retried once with a new one.
+func (c *Client) authorisedRequest(ctx context.Context, url string, cached *CacheEntry) (*http.Response, error) {
+	token, err := c.tokens.Token(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to get bearer token: %s", resp.Status)
+	resp, err := c.sendRequest(ctx, url, token, cached)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
 	}
+	resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+	slog.DebugContext(ctx, "bearer token rejected, retrying with a new one")
+	c.tokens.Invalidate(token)
+	if token, err = c.tokens.Token(ctx); err != nil {
+		return nil, err
+	}
+	return c.sendRequest(ctx, url, token, cached)
+}
+
+// get fetches url and returns the response body, notModified is true when the body came from the cache.
+func (c *Client) get(ctx context.Context, url string) (body []byte, notModified bool, err error) {
+	var cached *CacheEntry
+	if c.cache != nil {
+		if entry, ok := c.cache.Get(url); ok {
+			cached = &entry
+		}
 	}
 
-	var authResp auth
-	if err := json.Unmarshal(body, &authResp); err != nil {
-		return fmt.Errorf("failed to parse response: %w", err)
+	resp, err := c.authorisedRequest(ctx, url, cached)
+	if err != nil {
+		return nil, false, err
 	}
+	defer resp.Body.Close()
 
-	c.Bearer = authResp.AccessToken
-	c.Expires = c.timeProvider.Now().Add(time.Duration(authResp.ExpiresIn) * time.Second)
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		slog.DebugContext(ctx, "not modified, using cached response", "url", url)
+		return cached.Body, true, nil
+	}
 
-	slog.DebugContext(ctx, "bearer token acquired", "expires", c.Expires)
-	return nil
-}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
 
-func (c *Client) sendRequest(ctx context.Context, url string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	body, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, false, err
 	}
 
-	req.Header.Add("Authorization", "Bearer "+c.Bearer)
+	entry := CacheEntry{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified"), Body: body}
+	if c.cache != nil && (entry.ETag != "" || entry.LastModified != "") {
+		// A cache failure only costs us a full download next time, so don't fail the request
+		if err := c.cache.Set(url, entry); err != nil {
+			slog.WarnContext(ctx, "failed to cache response", "url", url, "error", err)
+		}
+	}
 
-	return c.httpClient.Do(req)
+	return body, false, nil
 }
 
 func (c *Client) GetMythicKeystoneProfile(ctx context.Context, realm string, character string) (*MythicKeystoneProfile, error) {
-	if err := c.checkClient(ctx); err != nil {
+	if err := c.checkClient(); err != nil {
 		return nil, err
 	}
 
@@ -145,28 +193,95 @@ func (c *Client) GetMythicKeystoneProfile(ctx context.Context, realm string, cha
 	character = strings.ToLower(character)
 
 	slog.DebugContext(ctx, "getting mythic profile", "character", character, "realm", realm)
-	apiURL := fmt.Sprintf("%s/profile/wow/character/%s/%s/mythic-keystone-profile?namespace=profile-us&locale=en_US",
-		c.baseURL, realm, character)
+	apiURL := fmt.Sprintf("%s/profile/wow/character/%s/%s/mythic-keystone-profile?namespace=profile-us&locale=%s",
+		c.baseURL, realm, character, i18n.FromContext(ctx).Blizzard())
 
-	resp, err := c.sendRequest(ctx, apiURL)
+	body, notModified, err := c.get(ctx, apiURL)
 	if err != nil {
+		return nil, fmt.Errorf("failed to get mythic keystone profile: %w", err)
+	}
+
+	var profile MythicKeystoneProfile
+	if err := json.Unmarshal(body, &profile); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	profile.NotModified = notModified
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get mythic keystone profile: %s", resp.Status)
+	return &profile, nil
+}
+
+// GetMythicKeystoneSeason returns the character's best runs and rating for a season.
+func (c *Client) GetMythicKeystoneSeason(ctx context.Context, realm string, character string, seasonID int) (*MythicKeystoneSeason, error) {
+	var season MythicKeystoneSeason
+	path := fmt.Sprintf("/mythic-keystone-profile/season/%d", seasonID)
+	if err := c.getCharacterResource(ctx, realm, character, path, &season); err != nil {
+		return nil, fmt.Errorf("failed to get mythic keystone season %d: %w", seasonID, err)
+	}
+
+	return &season, nil
+}
+
+// GetCurrentSeasonID returns the ID of the mythic+ season that is currently running.
+func (c *Client) GetCurrentSeasonID(ctx context.Context) (int, error) {
+	if err := c.checkClient(); err != nil {
+		return 0, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	slog.DebugContext(ctx, "getting current mythic keystone season")
+	apiURL := fmt.Sprintf("%s/data/wow/mythic-keystone/season/index?namespace=dynamic-us&locale=%s",
+		c.baseURL, i18n.FromContext(ctx).Blizzard())
+
+	body, _, err := c.get(ctx, apiURL)
 	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("failed to get mythic keystone season index: %w", err)
 	}
 
-	var profile MythicKeystoneProfile
-	if err := json.Unmarshal(body, &profile); err != nil {
-		return nil, err
+	var index MythicKeystoneSeasonIndex
+	if err := json.Unmarshal(body, &index); err != nil {
+		return 0, err
+	}
+
+	return index.CurrentSeason.ID, nil
+}
+
+// GetCharacterProfile returns the character's level, spec, guild, item level and faction.
+func (c *Client) GetCharacterProfile(ctx context.Context, realm string, character string) (*CharacterProfile, error) {
+	var profile CharacterProfile
+	if err := c.getCharacterResource(ctx, realm, character, "", &profile); err != nil {
+		return nil, fmt.Errorf("failed to get character profile: %w", err)
 	}
 
 	return &profile, nil
 }
+
+// GetCharacterEquipment returns the gear the character has equipped.
+func (c *Client) GetCharacterEquipment(ctx context.Context, realm string, character string) (*CharacterEquipment, error) {
+	var equipment CharacterEquipment
+	if err := c.getCharacterResource(ctx, realm, character, "/equipment", &equipment); err != nil {
+		return nil, fmt.Errorf("failed to get character equipment: %w", err)
+	}
+
+	return &equipment, nil
+}
+
+// getCharacterResource decodes a character profile API resource, path is appended to the character's URL.
+func (c *Client) getCharacterResource(ctx context.Context, realm, character, path string, v any) error {
+	if err := c.checkClient(); err != nil {
+		return err
+	}
+
+	realm = strings.ToLower(realm)
+	character = strings.ToLower(character)
+
+	slog.DebugContext(ctx, "getting character resource", "character", character, "realm", realm, "path", path)
+	// Names such as dungeons and specs come back in the locale's language
+	apiURL := fmt.Sprintf("%s/profile/wow/character/%s/%s%s?namespace=profile-us&locale=%s",
+		c.baseURL, realm, character, path, i18n.FromContext(ctx).Blizzard())
+
+	body, _, err := c.get(ctx, apiURL)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, v)
+}
@@ -2,12 +2,14 @@ package blizzard
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/DylanNZL/mythicplusbot/i18n"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -94,58 +96,23 @@ func TestClient_SetCredentials(t *testing.T) {
 	assert.Equal(t, "test-secret", client.Secret)
 }
 
-// Test authentication and token management
+// Test authentication
 
 func TestClient_CheckClient_NotInitialized(t *testing.T) {
 	client := NewClient(&MockHTTPClient{}, &MockTimeProvider{})
-	ctx := context.Background()
 
-	err := client.checkClient(ctx)
+	err := client.checkClient()
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "client is not initialised")
 }
 
-func TestClient_GetBearerToken_Success(t *testing.T) {
-	httpClient := &MockHTTPClient{}
-	timeProvider := &MockTimeProvider{}
-	client := NewClient(httpClient, timeProvider)
-
-	client.SetCredentials("test-id", "test-secret")
-
-	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
-	timeProvider.On("Now").Return(now)
-
-	oauthResp := createHTTPResponse(200, createSuccessfulOAuthResponse())
-	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
-		if req.URL.String() != client.oauthURL {
-			return false
-		}
-		if req.Method != "POST" {
-			return false
-		}
-
-		username, password, ok := req.BasicAuth()
-		return ok && username == "test-id" && password == "test-secret"
-	})).Return(oauthResp, nil)
-
-	ctx := context.Background()
-	err := client.getBearerToken(ctx)
-
-	assert.NoError(t, err)
-	assert.Equal(t, "test-bearer-token", client.Bearer)
-	assert.Equal(t, now.Add(3600*time.Second), client.Expires)
-	httpClient.AssertExpectations(t)
-	timeProvider.AssertExpectations(t)
-}
-
 func TestClient_GetMythicKeystoneProfile_Success(t *testing.T) {
 	httpClient := &MockHTTPClient{}
 	timeProvider := &MockTimeProvider{}
 	client := NewClient(httpClient, timeProvider)
 
 	client.SetCredentials("test-id", "test-secret")
-	client.Bearer = "test-token"
-	client.Expires = time.Now().Add(time.Hour)
+	client.tokens.set("test-token", time.Now().Add(time.Hour))
 
 	now := time.Now()
 	timeProvider.On("Now").Return(now)
@@ -179,6 +146,327 @@ func TestClient_GetMythicKeystoneProfile_ClientNotInitialized(t *testing.T) {
 	assert.Contains(t, err.Error(), "client is not initialised")
 }
 
+func TestClient_GetMythicKeystoneProfile_RetriesUnauthorized(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	timeProvider := &MockTimeProvider{}
+	client := NewClient(httpClient, timeProvider)
+
+	client.SetCredentials("test-id", "test-secret")
+	client.tokens.set("revoked-token", time.Now().Add(time.Hour))
+	timeProvider.On("Now").Return(time.Now())
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Authorization") == "Bearer revoked-token"
+	})).Return(createHTTPResponse(http.StatusUnauthorized, ""), nil).Once()
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == client.oauthURL
+	})).Return(createHTTPResponse(200, createSuccessfulOAuthResponse()), nil).Once()
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("Authorization") == "Bearer test-bearer-token"
+	})).Return(createHTTPResponse(200, createMythicKeystoneProfileResponse()), nil).Once()
+
+	profile, err := client.GetMythicKeystoneProfile(context.Background(), "test-realm", "testchar")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2500.5, profile.CurrentMythicRating.Rating)
+	httpClient.AssertExpectations(t)
+}
+
+func TestClient_GetMythicKeystoneProfile_UnauthorizedTwice(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	timeProvider := &MockTimeProvider{}
+	client := NewClient(httpClient, timeProvider)
+
+	client.SetCredentials("test-id", "test-secret")
+	client.tokens.set("revoked-token", time.Now().Add(time.Hour))
+	timeProvider.On("Now").Return(time.Now())
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == client.oauthURL
+	})).Return(createHTTPResponse(200, createSuccessfulOAuthResponse()), nil).Once()
+	httpClient.On("Do", mock.Anything).Return(createHTTPResponse(http.StatusUnauthorized, ""), nil).Once()
+	httpClient.On("Do", mock.Anything).Return(createHTTPResponse(http.StatusUnauthorized, ""), nil).Once()
+
+	profile, err := client.GetMythicKeystoneProfile(context.Background(), "test-realm", "testchar")
+
+	// Only one retry is made
+	assert.Error(t, err)
+	assert.Nil(t, profile)
+	httpClient.AssertNumberOfCalls(t, "Do", 3)
+}
+
+// Test character profile endpoints
+
+func TestClient_GetCharacterProfile_Success(t *testing.T) {
+	client, httpClient, _ := setupCachedClient(t)
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "https://us.api.blizzard.com/profile/wow/character/test-realm/testchar?namespace=profile-us&locale=en_US"
+	})).Return(createHTTPResponse(200, `{
+		"id": 123,
+		"name": "Testchar",
+		"level": 80,
+		"faction": {"type": "HORDE", "name": "Horde"},
+		"character_class": {"id": 2, "name": "Paladin"},
+		"active_spec": {"id": 66, "name": "Protection"},
+		"realm": {"id": 456, "slug": "test-realm"},
+		"guild": {"id": 789, "name": "Method", "realm": {"id": 456, "slug": "test-realm"}},
+		"average_item_level": 620,
+		"equipped_item_level": 618
+	}`), nil)
+
+	profile, err := client.GetCharacterProfile(context.Background(), "Test-Realm", "TestChar")
+
+	require.NoError(t, err)
+	assert.Equal(t, 80, profile.Level)
+	assert.Equal(t, "Horde", profile.Faction.Name)
+	assert.Equal(t, "Protection", profile.ActiveSpec.Name)
+	assert.Equal(t, "Method", profile.GuildName())
+	assert.Equal(t, 620, profile.AverageItemLevel)
+	assert.Equal(t, 618, profile.EquippedItemLevel)
+}
+
+func TestCharacterProfile_GuildName_NoGuild(t *testing.T) {
+	profile := CharacterProfile{}
+
+	assert.Empty(t, profile.GuildName())
+}
+
+func TestCharacterProfile_ClassName(t *testing.T) {
+	// Profiles fetched in another locale still map to the English class name
+	profile := CharacterProfile{CharacterClass: NamedRef{ID: 6, Name: "Todesritter"}}
+	assert.Equal(t, "DeathKnight", profile.ClassName())
+
+	profile = CharacterProfile{CharacterClass: NamedRef{ID: 99, Name: "Demon Hunter"}}
+	assert.Equal(t, "DemonHunter", profile.ClassName())
+}
+
+func TestCharacterProfile_SpecName(t *testing.T) {
+	// Like the class, the spec is stored in English whatever locale the profile was fetched in
+	profile := CharacterProfile{ActiveSpec: NamedRef{ID: 253, Name: "Tierherrschaft"}}
+	assert.Equal(t, "Beast Mastery", profile.SpecName())
+
+	profile = CharacterProfile{ActiveSpec: NamedRef{ID: 9999, Name: "Devourer"}}
+	assert.Equal(t, "Devourer", profile.SpecName())
+}
+
+func TestClient_GetCharacterEquipment_Locale(t *testing.T) {
+	client, httpClient, _ := setupCachedClient(t)
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("locale") == "de_DE"
+	})).Return(createHTTPResponse(200, `{"equipped_items": [{"name": "Helm des Wächters"}]}`), nil)
+
+	ctx := i18n.WithLocale(context.Background(), i18n.German)
+	equipment, err := client.GetCharacterEquipment(ctx, "Test-Realm", "TestChar")
+
+	require.NoError(t, err)
+	require.Len(t, equipment.EquippedItems, 1)
+	assert.Equal(t, "Helm des Wächters", equipment.EquippedItems[0].Name)
+	httpClient.AssertExpectations(t)
+}
+
+func TestClient_GetCharacterEquipment_Success(t *testing.T) {
+	client, httpClient, _ := setupCachedClient(t)
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "https://us.api.blizzard.com/profile/wow/character/test-realm/testchar/equipment?namespace=profile-us&locale=en_US"
+	})).Return(createHTTPResponse(200, `{
+		"character": {"id": 123, "name": "Testchar", "realm": {"id": 456, "slug": "test-realm"}},
+		"equipped_items": [{
+			"item": {"id": 1001},
+			"slot": {"type": "HEAD", "name": "Head"},
+			"quality": {"type": "EPIC", "name": "Epic"},
+			"name": "Helm of Testing",
+			"level": {"value": 623, "display_string": "Item Level 623"}
+		}]
+	}`), nil)
+
+	equipment, err := client.GetCharacterEquipment(context.Background(), "test-realm", "testchar")
+
+	require.NoError(t, err)
+	require.Len(t, equipment.EquippedItems, 1)
+	item := equipment.EquippedItems[0]
+	assert.Equal(t, "Head", item.Slot.Name)
+	assert.Equal(t, "Helm of Testing", item.Name)
+	assert.Equal(t, 623, item.Level.Value)
+}
+
+func TestClient_GetCharacterEquipment_Error(t *testing.T) {
+	client, httpClient, _ := setupCachedClient(t)
+
+	httpClient.On("Do", mock.Anything).Return(createHTTPResponse(http.StatusNotFound, ""), nil)
+
+	equipment, err := client.GetCharacterEquipment(context.Background(), "test-realm", "testchar")
+
+	assert.Nil(t, equipment)
+	assert.ErrorContains(t, err, "failed to get character equipment")
+}
+
+func TestClient_GetMythicKeystoneSeason_Success(t *testing.T) {
+	client, httpClient, _ := setupCachedClient(t)
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "https://us.api.blizzard.com/profile/wow/character/test-realm/testchar/mythic-keystone-profile/season/13?namespace=profile-us&locale=en_US"
+	})).Return(createHTTPResponse(200, `{
+		"season": {"id": 13},
+		"best_runs": [
+			{"keystone_level": 12, "duration": 1800000, "is_completed_within_time": true,
+				"dungeon": {"id": 1, "name": "Ara-Kara"}, "map_rating": {"rating": 300.5}},
+			{"keystone_level": 10, "duration": 2000000, "is_completed_within_time": false,
+				"dungeon": {"id": 2, "name": "The Stonevault"}, "map_rating": {"rating": 250}}
+		],
+		"mythic_rating": {"rating": 2750.5},
+		"character": {"id": 123, "name": "Testchar", "realm": {"id": 456, "slug": "test-realm"}}
+	}`), nil)
+
+	season, err := client.GetMythicKeystoneSeason(context.Background(), "Test-Realm", "TestChar", 13)
+
+	require.NoError(t, err)
+	assert.Equal(t, 13, season.Season.ID)
+	assert.InDelta(t, 2750.5, season.MythicRating.Rating, 0.001)
+	require.Len(t, season.BestRuns, 2)
+	assert.Equal(t, "Ara-Kara", season.BestRuns[0].Dungeon.Name)
+	assert.True(t, season.BestRuns[0].IsCompletedWithinTime)
+}
+
+func TestClient_GetMythicKeystoneSeason_Error(t *testing.T) {
+	client, httpClient, _ := setupCachedClient(t)
+
+	httpClient.On("Do", mock.Anything).Return(createHTTPResponse(http.StatusNotFound, ""), nil)
+
+	season, err := client.GetMythicKeystoneSeason(context.Background(), "test-realm", "testchar", 13)
+
+	assert.Nil(t, season)
+	assert.ErrorContains(t, err, "failed to get mythic keystone season 13")
+}
+
+func TestClient_GetCurrentSeasonID_Success(t *testing.T) {
+	client, httpClient, _ := setupCachedClient(t)
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.String() == "https://us.api.blizzard.com/data/wow/mythic-keystone/season/index?namespace=dynamic-us&locale=en_US"
+	})).Return(createHTTPResponse(200, `{
+		"seasons": [{"id": 12}, {"id": 13}, {"id": 14}],
+		"current_season": {"id": 14}
+	}`), nil)
+
+	id, err := client.GetCurrentSeasonID(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 14, id)
+}
+
+func TestClient_GetCurrentSeasonID_Error(t *testing.T) {
+	client, httpClient, _ := setupCachedClient(t)
+
+	httpClient.On("Do", mock.Anything).Return(createHTTPResponse(http.StatusInternalServerError, ""), nil)
+
+	_, err := client.GetCurrentSeasonID(context.Background())
+
+	assert.ErrorContains(t, err, "failed to get mythic keystone season index")
+}
+
+func TestMythicKeystoneSeason_BestRunsByDungeon(t *testing.T) {
+	run := func(dungeonID int, name string, rating float64) BestRun {
+		return BestRun{Dungeon: NamedRef{ID: dungeonID, Name: name}, MapRating: Rating{Rating: rating}}
+	}
+	season := MythicKeystoneSeason{BestRuns: []BestRun{
+		run(1, "Ara-Kara", 280),
+		run(2, "The Stonevault", 300),
+		run(1, "Ara-Kara", 310),
+		run(3, "Grim Batol", 300),
+	}}
+
+	assert.Equal(t, []BestRun{
+		run(1, "Ara-Kara", 310),
+		run(3, "Grim Batol", 300),
+		run(2, "The Stonevault", 300),
+	}, season.BestRunsByDungeon())
+}
+
+func TestMythicKeystoneProfile_SeasonIDs(t *testing.T) {
+	var profile MythicKeystoneProfile
+	require.NoError(t, json.Unmarshal([]byte(`{"seasons": [{"id": 13}, {"id": 11}, {"id": 12}]}`), &profile))
+
+	assert.Equal(t, []int{11, 12, 13}, profile.SeasonIDs())
+}
+
+// Test conditional requests
+
+const testProfileURL = "https://us.api.blizzard.com/profile/wow/character/test-realm/testchar/mythic-keystone-profile?namespace=profile-us&locale=en_US"
+
+func setupCachedClient(t *testing.T) (*Client, *MockHTTPClient, *MemoryCache) {
+	t.Helper()
+
+	httpClient := &MockHTTPClient{}
+	timeProvider := &MockTimeProvider{}
+	timeProvider.On("Now").Return(time.Now())
+	cache := NewMemoryCache()
+
+	client := NewClient(httpClient, timeProvider)
+	client.SetCredentials("test-id", "test-secret")
+	client.SetCache(cache)
+	client.tokens.set("test-token", time.Now().Add(time.Hour))
+
+	return client, httpClient, cache
+}
+
+func TestClient_GetMythicKeystoneProfile_CachesValidators(t *testing.T) {
+	client, httpClient, cache := setupCachedClient(t)
+
+	resp := createHTTPResponse(200, createMythicKeystoneProfileResponse())
+	resp.Header.Set("ETag", `"abc"`)
+	resp.Header.Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("If-None-Match") == "" && req.Header.Get("If-Modified-Since") == ""
+	})).Return(resp, nil)
+
+	profile, err := client.GetMythicKeystoneProfile(context.Background(), "test-realm", "testchar")
+
+	require.NoError(t, err)
+	assert.False(t, profile.NotModified)
+	entry, ok := cache.Get(testProfileURL)
+	require.True(t, ok)
+	assert.Equal(t, `"abc"`, entry.ETag)
+	assert.Equal(t, "Mon, 01 Jan 2024 00:00:00 GMT", entry.LastModified)
+	assert.JSONEq(t, createMythicKeystoneProfileResponse(), string(entry.Body))
+}
+
+func TestClient_GetMythicKeystoneProfile_NotModified(t *testing.T) {
+	client, httpClient, cache := setupCachedClient(t)
+	require.NoError(t, cache.Set(testProfileURL, CacheEntry{
+		ETag:         `"abc"`,
+		LastModified: "Mon, 01 Jan 2024 00:00:00 GMT",
+		Body:         []byte(createMythicKeystoneProfileResponse()),
+	}))
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get("If-None-Match") == `"abc"` &&
+			req.Header.Get("If-Modified-Since") == "Mon, 01 Jan 2024 00:00:00 GMT"
+	})).Return(createHTTPResponse(http.StatusNotModified, ""), nil)
+
+	profile, err := client.GetMythicKeystoneProfile(context.Background(), "test-realm", "testchar")
+
+	require.NoError(t, err)
+	assert.True(t, profile.NotModified)
+	assert.Equal(t, 2500.5, profile.CurrentMythicRating.Rating)
+	httpClient.AssertExpectations(t)
+}
+
+func TestClient_GetMythicKeystoneProfile_ErrorStatus(t *testing.T) {
+	client, httpClient, cache := setupCachedClient(t)
+
+	httpClient.On("Do", mock.Anything).Return(createHTTPResponse(http.StatusNotFound, ""), nil)
+
+	profile, err := client.GetMythicKeystoneProfile(context.Background(), "test-realm", "testchar")
+
+	assert.Error(t, err)
+	assert.Nil(t, profile)
+	_, ok := cache.Get(testProfileURL)
+	assert.False(t, ok)
+}
+
 func TestRealTimeProvider_Now(t *testing.T) {
 	provider := &RealTimeProvider{}
 
@@ -0,0 +1,127 @@
+package blizzard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type (
+	// Cache stores the last response for each URL so requests can be made conditional.
+	Cache interface {
+		Get(key string) (CacheEntry, bool)
+		Set(key string, entry CacheEntry) error
+		// Flush saves any entries set since the last flush.
+		Flush() error
+	}
+
+	// CacheEntry is a response body along with the validators Blizzard sent with it.
+	CacheEntry struct {
+		ETag         string `json:"etag,omitempty"`
+		LastModified string `json:"last_modified,omitempty"`
+		Body         []byte `json:"body"`
+	}
+)
+
+// MemoryCache is a Cache that lives for as long as the process.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]CacheEntry
+}
+
+// NewMemoryCache creates an empty in memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]CacheEntry)}
+}
+
+func (m *MemoryCache) Get(key string) (CacheEntry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.entries[key]
+	return entry, ok
+}
+
+func (m *MemoryCache) Set(key string, entry CacheEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = entry
+	return nil
+}
+
+// Flush does nothing as the entries only live in memory.
+func (m *MemoryCache) Flush() error {
+	return nil
+}
+
+// FileCache is a MemoryCache that is also written to a JSON file, so the validators survive a restart.
+//
+// Set only updates the entries in memory, the file is rewritten by Flush. Rewriting it on every Set would write every
+// cached body again for each request.
+type FileCache struct {
+	*MemoryCache
+	path  string
+	dirty bool
+}
+
+// NewFileCache creates a cache backed by the file at path, loading any entries already saved there.
+func NewFileCache(path string) (*FileCache, error) {
+	cache := &FileCache{MemoryCache: NewMemoryCache(), path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		return nil, fmt.Errorf("failed to parse cache file: %w", err)
+	}
+	return cache, nil
+}
+
+// Set stores the entry, it is written to the file on the next Flush.
+func (f *FileCache) Set(key string, entry CacheEntry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.entries[key] = entry
+	f.dirty = true
+	return nil
+}
+
+// Flush rewrites the cache file if any entries have been set since it was last written.
+func (f *FileCache) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(f.entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+
+	// Write then rename so a crash mid-write can't corrupt the cache
+	tmp := f.path + ".tmp"
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+
+	f.dirty = false
+	return nil
+}
@@ -0,0 +1,66 @@
+package blizzard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCache_PersistsEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "blizzard.json")
+	entry := CacheEntry{ETag: `"abc"`, Body: []byte(`{"rating":1}`)}
+
+	cache, err := NewFileCache(path)
+	require.NoError(t, err)
+	_, ok := cache.Get("url")
+	assert.False(t, ok)
+	require.NoError(t, cache.Set("url", entry))
+	require.NoError(t, cache.Flush())
+
+	// A new cache reading the same file picks up where the last one left off
+	reloaded, err := NewFileCache(path)
+	require.NoError(t, err)
+	got, ok := reloaded.Get("url")
+	require.True(t, ok)
+	assert.Equal(t, entry, got)
+}
+
+func TestFileCache_FlushOnlyWhenChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blizzard.json")
+
+	cache, err := NewFileCache(path)
+	require.NoError(t, err)
+
+	// Nothing has been set so there is nothing to write
+	require.NoError(t, cache.Flush())
+	assert.NoFileExists(t, path)
+
+	// Sets are only kept in memory until the flush
+	require.NoError(t, cache.Set("first", CacheEntry{ETag: `"1"`}))
+	require.NoError(t, cache.Set("second", CacheEntry{ETag: `"2"`}))
+	assert.NoFileExists(t, path)
+
+	require.NoError(t, cache.Flush())
+	reloaded, err := NewFileCache(path)
+	require.NoError(t, err)
+	_, ok := reloaded.Get("second")
+	assert.True(t, ok)
+
+	// A flush with no new entries leaves the file alone
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, cache.Flush())
+	assert.NoFileExists(t, path)
+}
+
+func TestNewFileCache_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blizzard.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+	cache, err := NewFileCache(path)
+
+	assert.Nil(t, cache)
+	assert.ErrorContains(t, err, "failed to parse cache file")
+}
@@ -0,0 +1,153 @@
+package blizzard
+
+import "strings"
+
+type (
+	// NamedRef is how the profile API refers to other game data, e.g. a class or specialization.
+	NamedRef struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	// TypedName is an enum value, Type is stable and Name is localised.
+	TypedName struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	}
+
+	// CharacterProfile is the summary returned by the character profile API.
+	CharacterProfile struct {
+		ID                 int       `json:"id"`
+		Name               string    `json:"name"`
+		Level              int       `json:"level"`
+		Faction            TypedName `json:"faction"`
+		Race               NamedRef  `json:"race"`
+		CharacterClass     NamedRef  `json:"character_class"`
+		ActiveSpec         NamedRef  `json:"active_spec"`
+		Realm              Realm     `json:"realm"`
+		Guild              *Guild    `json:"guild,omitempty"`
+		AverageItemLevel   int       `json:"average_item_level"`
+		EquippedItemLevel  int       `json:"equipped_item_level"`
+		LastLoginTimestamp int64     `json:"last_login_timestamp"`
+	}
+
+	// Guild is the guild a character belongs to.
+	Guild struct {
+		ID    int    `json:"id"`
+		Name  string `json:"name"`
+		Realm Realm  `json:"realm"`
+	}
+
+	// CharacterEquipment is the gear a character currently has equipped.
+	CharacterEquipment struct {
+		Character struct {
+			ID    int    `json:"id"`
+			Name  string `json:"name"`
+			Realm Realm  `json:"realm"`
+		} `json:"character"`
+		EquippedItems []EquippedItem `json:"equipped_items"`
+	}
+
+	// EquippedItem is a single piece of gear.
+	EquippedItem struct {
+		Item struct {
+			ID int `json:"id"`
+		} `json:"item"`
+		Slot    TypedName `json:"slot"`
+		Quality TypedName `json:"quality"`
+		Name    string    `json:"name"`
+		Level   struct {
+			Value         int    `json:"value"`
+			DisplayString string `json:"display_string"`
+		} `json:"level"`
+	}
+)
+
+// GuildName returns the name of the character's guild, or an empty string if they aren't in one.
+func (p *CharacterProfile) GuildName() string {
+	if p.Guild == nil {
+		return ""
+	}
+	return p.Guild.Name
+}
+
+// classNames are the English names of the classes by ID, without spaces so they match Raider.IO's class names.
+var classNames = map[int]string{
+	1:  "Warrior",
+	2:  "Paladin",
+	3:  "Hunter",
+	4:  "Rogue",
+	5:  "Priest",
+	6:  "DeathKnight",
+	7:  "Shaman",
+	8:  "Mage",
+	9:  "Warlock",
+	10: "Monk",
+	11: "Druid",
+	12: "DemonHunter",
+	13: "Evoker",
+}
+
+// ClassName returns the English name of the character's class without spaces, e.g. DeathKnight.
+//
+// The name in the profile is localised, so it falls back to that for classes that aren't known yet.
+func (p *CharacterProfile) ClassName() string {
+	if name, ok := classNames[p.CharacterClass.ID]; ok {
+		return name
+	}
+	return strings.ReplaceAll(p.CharacterClass.Name, " ", "")
+}
+
+// specNames are the English names of the specializations by ID, matching Raider.IO's spec names.
+var specNames = map[int]string{
+	62:   "Arcane",
+	63:   "Fire",
+	64:   "Frost",
+	65:   "Holy",
+	66:   "Protection",
+	70:   "Retribution",
+	71:   "Arms",
+	72:   "Fury",
+	73:   "Protection",
+	102:  "Balance",
+	103:  "Feral",
+	104:  "Guardian",
+	105:  "Restoration",
+	250:  "Blood",
+	251:  "Frost",
+	252:  "Unholy",
+	253:  "Beast Mastery",
+	254:  "Marksmanship",
+	255:  "Survival",
+	256:  "Discipline",
+	257:  "Holy",
+	258:  "Shadow",
+	259:  "Assassination",
+	260:  "Outlaw",
+	261:  "Subtlety",
+	262:  "Elemental",
+	263:  "Enhancement",
+	264:  "Restoration",
+	265:  "Affliction",
+	266:  "Demonology",
+	267:  "Destruction",
+	268:  "Brewmaster",
+	269:  "Windwalker",
+	270:  "Mistweaver",
+	577:  "Havoc",
+	581:  "Vengeance",
+	1467: "Devastation",
+	1468: "Preservation",
+	1473: "Augmentation",
+}
+
+// SpecName returns the English name of the character's active specialization, e.g. Beast Mastery, so it is stored
+// the same way as the class and the spec scores from Raider.IO.
+//
+// The name in the profile is localised, so it falls back to that for specializations that aren't known yet.
+func (p *CharacterProfile) SpecName() string {
+	if name, ok := specNames[p.ActiveSpec.ID]; ok {
+		return name
+	}
+	return p.ActiveSpec.Name
+}
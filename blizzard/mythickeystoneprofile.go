@@ -99,5 +99,8 @@ type (
 			Color  Color   `json:"color"`
 			Rating float64 `json:"rating"`
 		} `json:"current_mythic_rating"`
+
+		// NotModified is set when Blizzard answered 304 and the profile was served from the cache.
+		NotModified bool `json:"-"`
 	}
 )
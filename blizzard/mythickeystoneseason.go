@@ -0,0 +1,79 @@
+package blizzard
+
+import "sort"
+
+type (
+	// Rating is a mythic+ rating along with the colour Blizzard shows it in.
+	Rating struct {
+		Color  Color   `json:"color"`
+		Rating float64 `json:"rating"`
+	}
+
+	// MythicKeystoneSeason is a character's mythic+ results for a single season.
+	MythicKeystoneSeason struct {
+		Season struct {
+			ID int `json:"id"`
+		} `json:"season"`
+		BestRuns     []BestRun `json:"best_runs"`
+		MythicRating Rating    `json:"mythic_rating"`
+		Character    struct {
+			Name  string `json:"name"`
+			ID    int    `json:"id"`
+			Realm Realm  `json:"realm"`
+		} `json:"character"`
+	}
+
+	// MythicKeystoneSeasonIndex lists the mythic+ seasons, including the one that is currently running.
+	MythicKeystoneSeasonIndex struct {
+		CurrentSeason struct {
+			ID int `json:"id"`
+		} `json:"current_season"`
+	}
+
+	// BestRun is one of the character's best keystone runs in a dungeon.
+	BestRun struct {
+		CompletedTimestamp    int64    `json:"completed_timestamp"`
+		Duration              int64    `json:"duration"` // milliseconds
+		KeystoneLevel         int      `json:"keystone_level"`
+		Dungeon               NamedRef `json:"dungeon"`
+		IsCompletedWithinTime bool     `json:"is_completed_within_time"`
+		MythicRating          Rating   `json:"mythic_rating"`
+		MapRating             Rating   `json:"map_rating"`
+	}
+)
+
+// BestRunsByDungeon returns the highest rated run in each dungeon, highest rated first.
+//
+// Some seasons keep a best run per affix, so a dungeon can appear more than once in BestRuns.
+func (s *MythicKeystoneSeason) BestRunsByDungeon() []BestRun {
+	best := make(map[int]BestRun, len(s.BestRuns))
+	for _, run := range s.BestRuns {
+		if current, ok := best[run.Dungeon.ID]; !ok || run.MapRating.Rating > current.MapRating.Rating {
+			best[run.Dungeon.ID] = run
+		}
+	}
+
+	runs := make([]BestRun, 0, len(best))
+	for _, run := range best {
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		if runs[i].MapRating.Rating == runs[j].MapRating.Rating {
+			return runs[i].Dungeon.Name < runs[j].Dungeon.Name
+		}
+		return runs[i].MapRating.Rating > runs[j].MapRating.Rating
+	})
+
+	return runs
+}
+
+// SeasonIDs returns the IDs of the seasons the character has played, oldest first.
+func (p *MythicKeystoneProfile) SeasonIDs() []int {
+	ids := make([]int, 0, len(p.Seasons))
+	for _, s := range p.Seasons {
+		ids = append(ids, s.ID)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
@@ -0,0 +1,166 @@
+package blizzard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// Tokens are refreshed when they expire within this long.
+	expiryBuffer = time.Minute * 5
+	// Run renews the token this long before Token would consider it expiring, so callers never wait on a refresh.
+	renewalLead = time.Minute * 5
+	// How long Run waits before trying again after a failed renewal.
+	renewalRetry = time.Minute
+)
+
+type auth struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
+
+// TokenSource hands out OAuth bearer tokens for the client credentials flow. It is safe for concurrent use:
+// refreshes are single-flight, so callers that find the token expiring at the same time share one request.
+type TokenSource struct {
+	httpClient   HTTPClient
+	timeProvider TimeProvider
+	oauthURL     string
+	id           string
+	secret       string
+
+	mu      sync.RWMutex
+	token   string
+	expires time.Time
+	refresh singleflight.Group
+}
+
+// NewTokenSource creates a token source for the given client credentials
+func NewTokenSource(httpClient HTTPClient, timeProvider TimeProvider, oauthURL, clientID, clientSecret string) *TokenSource {
+	return &TokenSource{
+		httpClient:   httpClient,
+		timeProvider: timeProvider,
+		oauthURL:     oauthURL,
+		id:           clientID,
+		secret:       clientSecret,
+	}
+}
+
+// Token returns the current token, fetching a new one if there isn't one or it expires in the next expiryBuffer.
+func (t *TokenSource) Token(ctx context.Context) (string, error) {
+	t.mu.RLock()
+	token, expires := t.token, t.expires
+	t.mu.RUnlock()
+
+	if token != "" && t.timeProvider.Now().Add(expiryBuffer).Before(expires) {
+		return token, nil
+	}
+
+	return t.renew(ctx)
+}
+
+// Invalidate drops token so the next call to Token fetches a new one. Tokens that have already been replaced are
+// ignored, so a burst of 401s for the same token only causes one refresh.
+func (t *TokenSource) Invalidate(token string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == token {
+		t.token = ""
+		t.expires = time.Time{}
+	}
+}
+
+// Run keeps the token renewed in the background until the context is cancelled.
+func (t *TokenSource) Run(ctx context.Context) {
+	for {
+		wait := renewalRetry
+		if _, err := t.renew(ctx); err != nil {
+			slog.WarnContext(ctx, "failed to renew bearer token", "error", err)
+		} else {
+			t.mu.RLock()
+			wait = t.expires.Sub(t.timeProvider.Now()) - expiryBuffer - renewalLead
+			t.mu.RUnlock()
+		}
+
+		timer := time.NewTimer(max(wait, renewalRetry))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// renew fetches a new token, joining a fetch that is already in flight. The in flight fetch uses the context of the
+// caller that started it.
+func (t *TokenSource) renew(ctx context.Context) (string, error) {
+	token, err, _ := t.refresh.Do("token", func() (any, error) {
+		return t.fetch(ctx)
+	})
+	if err != nil {
+		return "", err
+	}
+	return token.(string), nil
+}
+
+func (t *TokenSource) fetch(ctx context.Context) (string, error) {
+	slog.DebugContext(ctx, "getting bearer token")
+
+	data := url.Values{}
+	data.Set("grant_type", "client_credentials")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.oauthURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.SetBasicAuth(t.id, t.secret)
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get bearer token: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var authResp auth
+	if err := json.Unmarshal(body, &authResp); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	expires := t.timeProvider.Now().Add(time.Duration(authResp.ExpiresIn) * time.Second)
+	t.set(authResp.AccessToken, expires)
+
+	slog.DebugContext(ctx, "bearer token acquired", "expires", expires)
+	return authResp.AccessToken, nil
+}
+
+func (t *TokenSource) set(token string, expires time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.token = token
+	t.expires = expires
+}
@@ -0,0 +1,138 @@
+package blizzard
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testOAuthURL = "https://oauth.battle.net/token"
+
+func matchOAuthRequest(req *http.Request) bool {
+	if req.URL.String() != testOAuthURL || req.Method != http.MethodPost {
+		return false
+	}
+
+	username, password, ok := req.BasicAuth()
+	return ok && username == "test-id" && password == "test-secret"
+}
+
+func TestTokenSource_Token_Fetches(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	timeProvider := &MockTimeProvider{}
+	tokens := NewTokenSource(httpClient, timeProvider, testOAuthURL, "test-id", "test-secret")
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now)
+	httpClient.On("Do", mock.MatchedBy(matchOAuthRequest)).
+		Return(createHTTPResponse(200, createSuccessfulOAuthResponse()), nil).Once()
+
+	token, err := tokens.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "test-bearer-token", token)
+	assert.Equal(t, now.Add(3600*time.Second), tokens.expires)
+
+	// The token is reused until it is about to expire
+	token, err = tokens.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "test-bearer-token", token)
+	httpClient.AssertExpectations(t)
+}
+
+func TestTokenSource_Token_RefreshesBeforeExpiry(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	timeProvider := &MockTimeProvider{}
+	tokens := NewTokenSource(httpClient, timeProvider, testOAuthURL, "test-id", "test-secret")
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeProvider.On("Now").Return(now)
+	tokens.set("old-token", now.Add(expiryBuffer-time.Second))
+	httpClient.On("Do", mock.MatchedBy(matchOAuthRequest)).
+		Return(createHTTPResponse(200, createSuccessfulOAuthResponse()), nil).Once()
+
+	token, err := tokens.Token(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "test-bearer-token", token)
+	httpClient.AssertExpectations(t)
+}
+
+func TestTokenSource_Token_SingleFlight(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	timeProvider := &MockTimeProvider{}
+	tokens := NewTokenSource(httpClient, timeProvider, testOAuthURL, "test-id", "test-secret")
+
+	timeProvider.On("Now").Return(time.Now())
+	// Hold the request open so every caller finds the token missing while it is in flight
+	httpClient.On("Do", mock.MatchedBy(matchOAuthRequest)).
+		Return(createHTTPResponse(200, createSuccessfulOAuthResponse()), nil).
+		After(50 * time.Millisecond).Once()
+
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := tokens.Token(context.Background())
+			assert.NoError(t, err)
+			results[i] = token
+		}()
+	}
+	wg.Wait()
+
+	for _, token := range results {
+		assert.Equal(t, "test-bearer-token", token)
+	}
+	httpClient.AssertNumberOfCalls(t, "Do", 1)
+}
+
+func TestTokenSource_Token_Error(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	timeProvider := &MockTimeProvider{}
+	tokens := NewTokenSource(httpClient, timeProvider, testOAuthURL, "test-id", "test-secret")
+
+	httpClient.On("Do", mock.Anything).Return(createHTTPResponse(http.StatusUnauthorized, ""), nil)
+
+	token, err := tokens.Token(context.Background())
+
+	assert.Empty(t, token)
+	assert.ErrorContains(t, err, "failed to get bearer token")
+}
+
+func TestTokenSource_Invalidate(t *testing.T) {
+	tokens := NewTokenSource(&MockHTTPClient{}, &MockTimeProvider{}, testOAuthURL, "test-id", "test-secret")
+	expires := time.Now().Add(time.Hour)
+	tokens.set("current-token", expires)
+
+	// A token that has already been replaced doesn't clear the current one
+	tokens.Invalidate("old-token")
+	assert.Equal(t, "current-token", tokens.token)
+
+	tokens.Invalidate("current-token")
+	assert.Empty(t, tokens.token)
+}
+
+func TestTokenSource_Run(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	timeProvider := &MockTimeProvider{}
+	tokens := NewTokenSource(httpClient, timeProvider, testOAuthURL, "test-id", "test-secret")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	timeProvider.On("Now").Return(time.Now())
+	// Stop after the first renewal
+	httpClient.On("Do", mock.MatchedBy(matchOAuthRequest)).
+		Return(createHTTPResponse(200, createSuccessfulOAuthResponse()), nil).
+		Run(func(mock.Arguments) { cancel() }).Once()
+
+	tokens.Run(ctx)
+
+	assert.Equal(t, "test-bearer-token", tokens.token)
+	httpClient.AssertExpectations(t)
+}
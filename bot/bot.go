@@ -3,20 +3,45 @@
 // For now these commands are accepted by the bot:
 // - !mythicplusbot add <character> <realm>
 // - !mythicplusbot remove <character> <realm>
-// - !mythicplusbot scores [-n 10]
+// - !mythicplusbot scores [-n 10], with buttons to page through the leaderboard
 // - !mythicplusbot list [-n 10]
+// - !mythicplusbot profile <character> <realm>
+// - !mythicplusbot dungeons <character> <realm>
+// - !mythicplusbot dungeon <dungeon>
+// - !mythicplusbot runs <character> <realm> [--best|--recent]
+// - !mythicplusbot compare <character>-<realm> <character>-<realm> [...]
+// - !mythicplusbot link <character> <realm>
+// - !mythicplusbot unlink <character> <realm>
+// - !mythicplusbot vault
+// - !mythicplusbot cutoffs [season]
+// - !mythicplusbot affixes
+// - !mythicplusbot guild
 // - !mythicplusbot update
+// - !mythicplusbot export [json|csv] [history]
+// - !mythicplusbot preview-template
+// - !mythicplusbot language [en|de|fr]
 // - !mythicplusbot help
 package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/DylanNZL/mythicplusbot/blizzard"
 	"github.com/DylanNZL/mythicplusbot/db"
 	"github.com/DylanNZL/mythicplusbot/discord"
+	"github.com/DylanNZL/mythicplusbot/guild"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/DylanNZL/mythicplusbot/roster"
+	"github.com/DylanNZL/mythicplusbot/vault"
+	"github.com/bwmarrin/discordgo"
 )
 
 type (
@@ -28,43 +53,199 @@ type (
 		AddCharacter(ctx context.Context, name, realm string) error
 		RemoveCharacter(ctx context.Context, name, realm string) error
 		ListCharacters(ctx context.Context, limit int) ([]db.Character, error)
+		GetProfile(ctx context.Context, name, realm string) (db.Character, blizzard.CharacterEquipment, error)
+		GetDungeons(ctx context.Context, name, realm string) (*blizzard.MythicKeystoneSeason, error)
+		ListDungeons(ctx context.Context) ([]db.Dungeon, error)
+		GetDungeonLeaderboard(ctx context.Context, dungeonID, limit int) ([]db.DungeonRunEntry, error)
+		// GetRuns returns a tracked character's stored Raider.IO runs, best runs include the alternate runs.
+		// The character is empty if they aren't tracked.
+		GetRuns(ctx context.Context, name, realm string, kind db.RunKind) (db.Character, []db.CharacterRun, error)
+		// LinkCharacter links a tracked character to a Discord user, the character is empty if they aren't tracked.
+		// It returns db.ErrLinkedToOtherUser if someone else has already linked the character.
+		LinkCharacter(ctx context.Context, name, realm, userID string) (db.Character, error)
+		// UnlinkCharacter removes a tracked character's link, the character is empty if they aren't tracked.
+		// It returns db.ErrNotLinked if no one has linked the character, or db.ErrLinkedToOtherUser if someone else
+		// has and override isn't set.
+		UnlinkCharacter(ctx context.Context, name, realm, userID string, override bool) (db.Character, error)
+		// GetComparison returns what the compare command shows about a character, they don't need to be tracked.
+		GetComparison(ctx context.Context, name, realm string) (discord.Comparison, error)
+	}
+
+	RosterService interface {
+		Export(ctx context.Context, format roster.Format, includeHistory bool) ([]roster.File, error)
+	}
+
+	VaultService interface {
+		Progress(ctx context.Context) ([]vault.Progress, error)
+	}
+
+	CutoffService interface {
+		// GetCutoffs returns the cutoffs for the season along with its name, an empty season is the current one.
+		GetCutoffs(ctx context.Context, season string) (string, *raiderio.Cutoffs, error)
+	}
+
+	AffixService interface {
+		GetAffixes(ctx context.Context) (*raiderio.Affixes, error)
+	}
+
+	GuildService interface {
+		Summary(ctx context.Context) (guild.Summary, error)
+	}
+
+	LocaleService interface {
+		// GetLocale returns the locale the Discord server has chosen, English if it hasn't chosen one.
+		GetLocale(ctx context.Context, guildID string) (i18n.Locale, error)
+		SetLocale(ctx context.Context, guildID string, locale i18n.Locale) error
+	}
+
+	TimeProvider interface {
+		Now() time.Time
+	}
+
+	// Message is a command sent to the bot along with where it came from.
+	Message struct {
+		Content   string
+		ChannelID string
+		AuthorID  string
+		GuildID   string
 	}
 
 	Bot struct {
 		messageSender    discord.SenderIface
 		updater          Updater
 		characterService CharacterService
+		rosterService    RosterService
+		vaultService     VaultService
+		cutoffService    CutoffService
+		affixService     AffixService
+		guildService     GuildService
+		templates        *discord.Templates
+		localeService    LocaleService
+		// adminUserIDs can unlink characters linked to someone else
+		adminUserIDs []string
+		// leaderboardExpiry is how long the scores leaderboard's page buttons work for
+		leaderboardExpiry time.Duration
+		timeProvider      TimeProvider
 	}
 )
 
 const (
 	Command = "!mythicplusbot"
 
-	helpMessage = "This bot tracks characters M+ scores and will post updates to the channel whenever they increase:\n" +
-		"\n- To add a character send: `!mythicplusbot add <character> <realm>`" +
-		"\n- To remove a character send: `!mythicplusbot remove <character> <realm>`" +
-		"\n- To list the top `n` scores send: `!mythicplusbot scores [-n 10]`" +
-		"\n- To update scores outside the 30 minute window send: `!mythicplusbot update`"
-
 	defaultRows = 20
 )
 
-func NewBot(messageSender discord.SenderIface, updater Updater, characterService CharacterService) *Bot {
+func NewBot(messageSender discord.SenderIface, updater Updater, characterService CharacterService,
+	rosterService RosterService, vaultService VaultService, cutoffService CutoffService,
+	affixService AffixService, guildService GuildService, templates *discord.Templates, localeService LocaleService,
+	adminUserIDs []string, leaderboardExpiry time.Duration, timeProvider TimeProvider,
+) *Bot {
 	return &Bot{
-		messageSender:    messageSender,
-		updater:          updater,
-		characterService: characterService,
+		messageSender:     messageSender,
+		updater:           updater,
+		characterService:  characterService,
+		rosterService:     rosterService,
+		vaultService:      vaultService,
+		cutoffService:     cutoffService,
+		affixService:      affixService,
+		guildService:      guildService,
+		templates:         templates,
+		localeService:     localeService,
+		adminUserIDs:      adminUserIDs,
+		leaderboardExpiry: leaderboardExpiry,
+		timeProvider:      timeProvider,
 	}
 }
 
-func (b *Bot) HandleMessage(ctx context.Context, content, channelID string) error {
-	if !strings.HasPrefix(content, Command) {
+type RealTimeProvider struct{}
+
+func (r *RealTimeProvider) Now() time.Time {
+	return time.Now()
+}
+
+// helpMessage lists the commands in the locale.
+func helpMessage(l i18n.Locale) string {
+	return l.T("This bot tracks characters M+ scores and will post updates to the channel whenever they increase:") +
+		"\n\n- " + l.T("To add a character send: `%s add <character> <realm>`", Command) +
+		"\n- " + l.T("To remove a character send: `%s remove <character> <realm>`", Command) +
+		"\n- " + l.T("To list the top `n` scores send: `%s scores [-n 10]`", Command) +
+		"\n- " + l.T("To see a character's item level and gear send: `%s profile <character> <realm>`", Command) +
+		"\n- " + l.T("To see a character's best run in each dungeon this season send: `%s dungeons <character> <realm>`", Command) +
+		"\n- " + l.T("To rank the tracked characters by their best run in a dungeon send: `%s dungeon <dungeon>`", Command) +
+		"\n- " + l.T("To see a tracked character's best or recent Raider.IO runs send: `%s runs <character> <realm> [--best|--recent]`", Command) +
+		"\n- " + l.T("To compare characters side by side send: `%s compare <character>-<realm> <character>-<realm>`", Command) +
+		"\n- " + l.T("To link a character to yourself for milestone roles send: `%s link <character> <realm>`", Command) +
+		"\n- " + l.T("To unlink a character from yourself send: `%s unlink <character> <realm>`", Command) +
+		"\n- " + l.T("To see who still needs keys for their Great Vault this week send: `%s vault`", Command) +
+		"\n- " + l.T("To see the score needed for the top percentiles this season send: `%s cutoffs [season]`", Command) +
+		"\n- " + l.T("To see this week's affixes send: `%s affixes`", Command) +
+		"\n- " + l.T("To see the guild's Mythic+ ranking send: `%s guild`", Command) +
+		"\n- " + l.T("To update scores outside the 30 minute window send: `%s update`", Command) +
+		"\n- " + l.T("To export the tracked characters send: `%s export [json|csv] [history]`", Command) +
+		"\n- " + l.T("To preview the score update templates with a sample character send: `%s preview-template`", Command) +
+		"\n- " + l.T("To change the language the bot uses send: `%s language [%s]`", Command, localeCodes())
+}
+
+// localeCodes lists the locales users can choose from, e.g. en|de|fr.
+func localeCodes() string {
+	codes := make([]string, 0, len(i18n.Locales))
+	for _, l := range i18n.Locales {
+		codes = append(codes, string(l))
+	}
+	return strings.Join(codes, "|")
+}
+
+// withLocale returns a context to reply in the server's language with.
+func (b *Bot) withLocale(ctx context.Context, guildID string) (context.Context, i18n.Locale) {
+	// A failed lookup shouldn't stop the command, so it replies in English instead
+	l, err := b.localeService.GetLocale(ctx, guildID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get locale", "error", err, "guild", guildID)
+		l = i18n.English
+	}
+	return i18n.WithLocale(ctx, l), l
+}
+
+// HandleComponent handles a button on one of the bot's messages being pressed.
+func (b *Bot) HandleComponent(ctx context.Context, interaction *discordgo.Interaction) error {
+	if interaction.Type != discordgo.InteractionMessageComponent {
+		return nil
+	}
+
+	page, err := discord.ParseScoresPage(interaction.MessageComponentData().CustomID)
+	if errors.Is(err, discord.ErrNotScoresPage) {
 		return nil
 	}
+	if err != nil {
+		return err
+	}
+
+	ctx, l := b.withLocale(ctx, interaction.GuildID)
+	if b.timeProvider.Now().After(page.Expires) {
+		return b.messageSender.RespondEphemeral(ctx, interaction,
+			l.T("This leaderboard has expired, send `%s scores` for a new one.", Command))
+	}
+
+	characters, err := b.characterService.ListCharacters(ctx, page.Limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get scores", "error", err)
+		return b.messageSender.RespondEphemeral(ctx, interaction, l.T("Failed to get scores"))
+	}
+
+	return b.messageSender.UpdateInteractionMessage(ctx, interaction, discord.BuildScoresMessage(l, characters, page))
+}
+
+func (b *Bot) HandleMessage(ctx context.Context, msg Message) error {
+	if !strings.HasPrefix(msg.Content, Command) {
+		return nil
+	}
+
+	ctx, l := b.withLocale(ctx, msg.GuildID)
 
-	args := strings.Fields(content)
+	channelID := msg.ChannelID
+	args := strings.Fields(msg.Content)
 	if len(args) < 2 {
-		return b.messageSender.SendMessage(ctx, channelID, "Usage: !mythicplusbot <command> [args]")
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Usage: %s <command> [args]", Command))
 	}
 
 	switch args[1] {
@@ -74,48 +255,79 @@ func (b *Bot) HandleMessage(ctx context.Context, content, channelID string) erro
 		return b.handleRemoveCharacter(ctx, channelID, args)
 	case "scores", "list":
 		return b.handleScoresCommand(ctx, channelID, args)
+	case "profile":
+		return b.handleProfileCommand(ctx, channelID, args)
+	case "dungeons":
+		return b.handleDungeonsCommand(ctx, channelID, args)
+	case "dungeon":
+		return b.handleDungeonCommand(ctx, channelID, args)
+	case "runs":
+		return b.handleRunsCommand(ctx, channelID, args)
+	case "compare":
+		return b.handleCompareCommand(ctx, channelID, args)
+	case "link":
+		return b.handleLinkCommand(ctx, channelID, msg.AuthorID, args)
+	case "unlink":
+		return b.handleUnlinkCommand(ctx, channelID, msg.AuthorID, args)
+	case "vault":
+		return b.handleVaultCommand(ctx, channelID)
+	case "cutoffs":
+		return b.handleCutoffsCommand(ctx, channelID, args)
+	case "affixes":
+		return b.handleAffixesCommand(ctx, channelID)
+	case "guild":
+		return b.handleGuildCommand(ctx, channelID)
 	case "update":
 		return b.handleUpdateCommand(ctx, channelID)
+	case "export":
+		return b.handleExportCommand(ctx, channelID, args)
+	case "preview-template":
+		return b.messageSender.SendComplexMessage(ctx, channelID, b.templates.Preview(ctx, l))
+	case "language":
+		return b.handleLanguageCommand(ctx, channelID, msg.GuildID, args)
 	case "help":
-		return b.messageSender.SendMessage(ctx, channelID, helpMessage)
+		return b.messageSender.SendMessage(ctx, channelID, helpMessage(l))
 	default:
-		return b.messageSender.SendMessage(ctx, channelID, "Unknown command. Use "+Command+" help for a list of commands.")
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Unknown command. Use %s help for a list of commands.", Command))
 	}
 }
 
 // handleAddCharacter handles adding a character
 func (b *Bot) handleAddCharacter(ctx context.Context, channelID string, args []string) error {
+	l := i18n.FromContext(ctx)
 	if len(args) < 4 {
-		return b.messageSender.SendMessage(ctx, channelID, "Usage: !mythicplusbot add <character> <realm>")
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Usage: %s add <character> <realm>", Command))
 	}
 
 	character := formatName(args[2])
 	realm := formatRealm(args[3])
 	if err := b.characterService.AddCharacter(ctx, character, realm); err != nil {
 		slog.ErrorContext(ctx, "failed to add character", "error", err, "character", character, "realm", realm)
-		return b.messageSender.SendMessage(ctx, channelID, "Failed to add character.")
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to add character."))
 	}
 
-	return b.messageSender.SendMessage(ctx, channelID, fmt.Sprintf("Now tracking %s-%s", character, realm))
+	return b.messageSender.SendMessage(ctx, channelID, l.T("Now tracking %s-%s", character, realm))
 }
 
 // handleRemoveCharacter handles removing a character
 func (b *Bot) handleRemoveCharacter(ctx context.Context, channelID string, args []string) error {
+	l := i18n.FromContext(ctx)
 	if len(args) < 4 {
-		return b.messageSender.SendMessage(ctx, channelID, "Usage: !mythicplusbot remove <character> <realm>")
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Usage: %s remove <character> <realm>", Command))
 	}
 
 	character := formatName(args[2])
 	realm := formatRealm(args[3])
 	if err := b.characterService.RemoveCharacter(ctx, character, realm); err != nil {
 		slog.ErrorContext(ctx, "failed to remove character", "error", err, "character", character, "realm", realm)
-		return b.messageSender.SendMessage(ctx, channelID, "Failed to remove character.")
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to remove character."))
 	}
 
-	return b.messageSender.SendMessage(ctx, channelID, fmt.Sprintf("No longer tracking %s-%s.", character, realm))
+	return b.messageSender.SendMessage(ctx, channelID, l.T("No longer tracking %s-%s.", character, realm))
 }
 
 func (b *Bot) handleScoresCommand(ctx context.Context, channelID string, args []string) error {
+	l := i18n.FromContext(ctx)
 	n := defaultRows
 	for i, arg := range args {
 		if arg == "-n" && i+1 < len(args) {
@@ -129,30 +341,386 @@ func (b *Bot) handleScoresCommand(ctx context.Context, channelID string, args []
 	characters, err := b.characterService.ListCharacters(ctx, n)
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to get scores", "error", err)
-		return b.messageSender.SendMessage(ctx, channelID, "Failed to get scores")
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to get scores"))
 	}
 
 	if args[1] == "list" {
-		return b.messageSender.SendMessage(ctx, channelID, "todo :(")
+		return b.messageSender.SendMessage(ctx, channelID, l.T("todo :("))
 	}
 
-	return b.messageSender.SendComplexMessage(ctx, channelID, discord.BuildScoresMessage(characters))
+	return b.messageSender.SendComplexMessage(ctx, channelID, discord.BuildScoresMessage(l, characters, discord.ScoresPage{
+		Limit:   n,
+		Expires: b.timeProvider.Now().Add(b.leaderboardExpiry),
+	}))
+}
+
+// handleProfileCommand shows a character's profile and equipment, the character doesn't need to be tracked.
+func (b *Bot) handleProfileCommand(ctx context.Context, channelID string, args []string) error {
+	l := i18n.FromContext(ctx)
+	if len(args) < 4 {
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Usage: %s profile <character> <realm>", Command))
+	}
+
+	character := formatName(args[2])
+	realm := formatRealm(args[3])
+	profile, equipment, err := b.characterService.GetProfile(ctx, character, realm)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get profile", "error", err, "character", character, "realm", realm)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to get profile."))
+	}
+
+	return b.messageSender.SendComplexMessage(ctx, channelID, discord.BuildProfileMessage(l, profile, equipment))
+}
+
+// handleRunsCommand shows a tracked character's best or recent Raider.IO runs.
+func (b *Bot) handleRunsCommand(ctx context.Context, channelID string, args []string) error {
+	l := i18n.FromContext(ctx)
+	kind, ok := parseRunsArgs(args)
+	if !ok {
+		return b.messageSender.SendMessage(ctx, channelID,
+			l.T("Usage: %s runs <character> <realm> [--best|--recent]", Command))
+	}
+
+	name := formatName(args[2])
+	realm := formatRealm(args[3])
+	character, runs, err := b.characterService.GetRuns(ctx, name, realm, kind)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get runs", "error", err, "character", name, "realm", realm)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to get runs."))
+	}
+	if character.IsEmpty() {
+		return b.messageSender.SendMessage(ctx, channelID,
+			l.T("%s-%s isn't being tracked, add them with `%s add <character> <realm>`.", name, realm, Command))
+	}
+
+	return b.messageSender.SendComplexMessage(ctx, channelID, discord.BuildRunsMessage(l, character, kind, runs))
+}
+
+// handleCompareCommand shows two or more characters side by side, the characters don't need to be tracked.
+func (b *Bot) handleCompareCommand(ctx context.Context, channelID string, args []string) error {
+	l := i18n.FromContext(ctx)
+	if len(args) < 4 {
+		return b.messageSender.SendMessage(ctx, channelID,
+			l.T("Usage: %s compare <character>-<realm> <character>-<realm> [...]", Command))
+	}
+	if len(args)-2 > discord.MaxComparedCharacters {
+		return b.messageSender.SendMessage(ctx, channelID,
+			l.T("You can compare up to %d characters.", discord.MaxComparedCharacters))
+	}
+
+	characters := make([]db.Character, 0, len(args)-2)
+	for _, arg := range args[2:] {
+		// Realm slugs can have hyphens but character names can't, so the name ends at the first one
+		name, realm, ok := strings.Cut(arg, "-")
+		if !ok || name == "" || realm == "" {
+			return b.messageSender.SendMessage(ctx, channelID,
+				l.T("Usage: %s compare <character>-<realm> <character>-<realm> [...]", Command))
+		}
+		characters = append(characters, db.Character{Name: formatName(name), Realm: formatRealm(realm)})
+	}
+
+	// A character that can't be found, e.g. from a typo, is left out rather than failing the whole comparison
+	comparisons := make([]discord.Comparison, 0, len(characters))
+	var missing []string
+	for _, character := range characters {
+		comparison, err := b.characterService.GetComparison(ctx, character.Name, character.Realm)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to get comparison", "error", err,
+				"character", character.Name, "realm", character.Realm)
+			missing = append(missing, fmt.Sprintf("%s-%s", character.Name, character.Realm))
+			continue
+		}
+		comparisons = append(comparisons, comparison)
+	}
+	if len(comparisons) == 0 {
+		return b.messageSender.SendMessage(ctx, channelID,
+			l.T("Couldn't find %s on Raider.IO.", strings.Join(missing, ", ")))
+	}
+
+	message := discord.BuildCompareMessage(l, comparisons, b.timeProvider.Now())
+	if len(missing) > 0 {
+		message.Content = l.T("Couldn't find %s on Raider.IO, comparing the rest.", strings.Join(missing, ", "))
+	}
+	return b.messageSender.SendComplexMessage(ctx, channelID, message)
+}
+
+// parseRunsArgs returns the kind of runs asked for, best if no kind was given.
+func parseRunsArgs(args []string) (db.RunKind, bool) {
+	if len(args) < 4 || len(args) > 5 {
+		return "", false
+	}
+	if len(args) == 4 {
+		return db.RunKindBest, true
+	}
+
+	switch strings.ToLower(args[4]) {
+	case "--best":
+		return db.RunKindBest, true
+	case "--recent":
+		return db.RunKindRecent, true
+	default:
+		return "", false
+	}
+}
+
+// handleLinkCommand links a tracked character to the user who sent the command, so they get its milestone roles.
+func (b *Bot) handleLinkCommand(ctx context.Context, channelID, userID string, args []string) error {
+	l := i18n.FromContext(ctx)
+	if len(args) < 4 {
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Usage: %s link <character> <realm>", Command))
+	}
+
+	name := formatName(args[2])
+	realm := formatRealm(args[3])
+	character, err := b.characterService.LinkCharacter(ctx, name, realm, userID)
+	if errors.Is(err, db.ErrLinkedToOtherUser) {
+		return b.messageSender.SendMessage(ctx, channelID,
+			l.T("%s-%s is already linked to someone else, they or an admin can unlink it with `%s unlink <character> <realm>`.",
+				name, realm, Command))
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to link character", "error", err, "character", name, "realm", realm)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to link character."))
+	}
+	if character.IsEmpty() {
+		return b.messageSender.SendMessage(ctx, channelID,
+			l.T("%s-%s isn't being tracked, add them with `%s add <character> <realm>`.", name, realm, Command))
+	}
+
+	return b.messageSender.SendMessage(ctx, channelID, l.T("Linked %s-%s to <@%s>", name, realm, userID))
+}
+
+// handleUnlinkCommand removes a character's link, admins can unlink characters linked to anyone.
+func (b *Bot) handleUnlinkCommand(ctx context.Context, channelID, userID string, args []string) error {
+	l := i18n.FromContext(ctx)
+	if len(args) < 4 {
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Usage: %s unlink <character> <realm>", Command))
+	}
+
+	name := formatName(args[2])
+	realm := formatRealm(args[3])
+	character, err := b.characterService.UnlinkCharacter(ctx, name, realm, userID, slices.Contains(b.adminUserIDs, userID))
+	if errors.Is(err, db.ErrNotLinked) {
+		return b.messageSender.SendMessage(ctx, channelID, l.T("%s-%s isn't linked to anyone.", name, realm))
+	}
+	if errors.Is(err, db.ErrLinkedToOtherUser) {
+		return b.messageSender.SendMessage(ctx, channelID,
+			l.T("%s-%s is linked to someone else, only they or an admin can unlink it.", name, realm))
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to unlink character", "error", err, "character", name, "realm", realm)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to unlink character."))
+	}
+	if character.IsEmpty() {
+		return b.messageSender.SendMessage(ctx, channelID,
+			l.T("%s-%s isn't being tracked, add them with `%s add <character> <realm>`.", name, realm, Command))
+	}
+
+	return b.messageSender.SendMessage(ctx, channelID, l.T("Unlinked %s-%s", name, realm))
+}
+
+// handleDungeonsCommand shows a character's best run in each dungeon this season, the character doesn't need to be
+// tracked.
+func (b *Bot) handleDungeonsCommand(ctx context.Context, channelID string, args []string) error {
+	l := i18n.FromContext(ctx)
+	if len(args) < 4 {
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Usage: %s dungeons <character> <realm>", Command))
+	}
+
+	character := formatName(args[2])
+	realm := formatRealm(args[3])
+	season, err := b.characterService.GetDungeons(ctx, character, realm)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get dungeons", "error", err, "character", character, "realm", realm)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to get dungeons."))
+	}
+
+	return b.messageSender.SendComplexMessage(ctx, channelID, discord.BuildDungeonsMessage(l, season))
+}
+
+// handleDungeonCommand ranks the tracked characters by their best run in a dungeon this season.
+func (b *Bot) handleDungeonCommand(ctx context.Context, channelID string, args []string) error {
+	l := i18n.FromContext(ctx)
+	if len(args) < 3 {
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Usage: %s dungeon <dungeon>", Command))
+	}
+
+	dungeons, err := b.characterService.ListDungeons(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to list dungeons", "error", err)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to get dungeon leaderboard."))
+	}
+
+	dungeon, ok := findDungeon(dungeons, strings.Join(args[2:], " "))
+	if !ok {
+		names := make([]string, 0, len(dungeons))
+		for _, d := range dungeons {
+			names = append(names, d.Name)
+		}
+		if len(names) == 0 {
+			return b.messageSender.SendMessage(ctx, channelID, l.T("No dungeon runs have been recorded this season."))
+		}
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Unknown dungeon, try one of: %s", strings.Join(names, ", ")))
+	}
+
+	entries, err := b.characterService.GetDungeonLeaderboard(ctx, dungeon.ID, defaultRows)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get dungeon leaderboard", "error", err, "dungeon", dungeon.Name)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to get dungeon leaderboard."))
+	}
+
+	return b.messageSender.SendComplexMessage(ctx, channelID, discord.BuildDungeonLeaderboardMessage(l, dungeon, entries))
+}
+
+// findDungeon matches the dungeon the user asked for, ignoring case and punctuation so "ara kara" finds "Ara-Kara".
+// An exact match wins, otherwise the first dungeon whose name contains the query is used.
+func findDungeon(dungeons []db.Dungeon, query string) (db.Dungeon, bool) {
+	query = normaliseDungeonName(query)
+	if query == "" {
+		return db.Dungeon{}, false
+	}
+
+	for _, d := range dungeons {
+		if normaliseDungeonName(d.Name) == query {
+			return d, true
+		}
+	}
+	for _, d := range dungeons {
+		if strings.Contains(normaliseDungeonName(d.Name), query) {
+			return d, true
+		}
+	}
+
+	return db.Dungeon{}, false
+}
+
+func normaliseDungeonName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// handleVaultCommand shows each tracked character's Great Vault progress for the week.
+func (b *Bot) handleVaultCommand(ctx context.Context, channelID string) error {
+	l := i18n.FromContext(ctx)
+	progress, err := b.vaultService.Progress(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get vault progress", "error", err)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to get vault progress."))
+	}
+
+	return b.messageSender.SendComplexMessage(ctx, channelID, discord.BuildVaultMessage(l, progress))
+}
+
+// handleCutoffsCommand shows the Raider.IO percentile cutoffs for the current season, or the season given.
+func (b *Bot) handleCutoffsCommand(ctx context.Context, channelID string, args []string) error {
+	l := i18n.FromContext(ctx)
+	season := ""
+	if len(args) > 2 {
+		season = strings.ToLower(args[2])
+	}
+
+	season, cutoffs, err := b.cutoffService.GetCutoffs(ctx, season)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get season cutoffs", "error", err, "season", season)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to get season cutoffs."))
+	}
+
+	return b.messageSender.SendComplexMessage(ctx, channelID, discord.BuildCutoffsMessage(l, season, cutoffs))
+}
+
+// handleAffixesCommand shows this week's affixes.
+func (b *Bot) handleAffixesCommand(ctx context.Context, channelID string) error {
+	l := i18n.FromContext(ctx)
+	affixes, err := b.affixService.GetAffixes(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get affixes", "error", err)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to get this week's affixes."))
+	}
+
+	return b.messageSender.SendComplexMessage(ctx, channelID, discord.BuildAffixesMessage(l, affixes))
+}
+
+// handleGuildCommand shows the home guild's ranking and how its tracked members compare to the score thresholds.
+func (b *Bot) handleGuildCommand(ctx context.Context, channelID string) error {
+	l := i18n.FromContext(ctx)
+	summary, err := b.guildService.Summary(ctx)
+	if errors.Is(err, guild.ErrNoGuild) {
+		return b.messageSender.SendMessage(ctx, channelID, l.T("No home guild is configured."))
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get guild summary", "error", err)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to get the guild."))
+	}
+
+	return b.messageSender.SendComplexMessage(ctx, channelID, discord.BuildGuildMessage(l, summary))
 }
 
 // handleUpdateCommand handles the update command
 func (b *Bot) handleUpdateCommand(ctx context.Context, channelID string) error {
-	if err := b.messageSender.SendMessage(ctx, channelID, "Checking for updates..."); err != nil {
+	l := i18n.FromContext(ctx)
+	if err := b.messageSender.SendMessage(ctx, channelID, l.T("Checking for updates...")); err != nil {
 		return err
 	}
 
 	if err := b.updater.Update(ctx, channelID); err != nil {
 		slog.ErrorContext(ctx, "failed to update", "error", err)
-		return b.messageSender.SendMessage(ctx, channelID, "Failed to update scores")
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to update scores"))
 	}
 
 	return nil
 }
 
+// handleLanguageCommand shows the language the bot replies in for this Discord server, or changes it.
+func (b *Bot) handleLanguageCommand(ctx context.Context, channelID, guildID string, args []string) error {
+	l := i18n.FromContext(ctx)
+	if len(args) < 3 {
+		return b.messageSender.SendMessage(ctx, channelID,
+			l.T("The bot is replying in %s, change it with `%s language [%s]`.", l.Name(), Command, localeCodes()))
+	}
+
+	locale, err := i18n.Parse(args[2])
+	if err != nil {
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Unknown language, try one of: %s", localeCodes()))
+	}
+	if err := b.localeService.SetLocale(ctx, guildID, locale); err != nil {
+		slog.ErrorContext(ctx, "failed to set locale", "error", err, "guild", guildID, "locale", locale)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to change the language."))
+	}
+
+	return b.messageSender.SendMessage(ctx, channelID, locale.T("The bot will now reply in %s.", locale.Name()))
+}
+
+// handleExportCommand attaches an export of the tracked characters, optionally with their score history.
+func (b *Bot) handleExportCommand(ctx context.Context, channelID string, args []string) error {
+	l := i18n.FromContext(ctx)
+	format := roster.FormatJSON
+	includeHistory := false
+	for _, arg := range args[2:] {
+		if arg == "history" {
+			includeHistory = true
+			continue
+		}
+
+		f, err := roster.ParseFormat(arg)
+		if err != nil {
+			return b.messageSender.SendMessage(ctx, channelID, l.T("Usage: %s export [json|csv] [history]", Command))
+		}
+		format = f
+	}
+
+	files, err := b.rosterService.Export(ctx, format, includeHistory)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to export roster", "error", err)
+		return b.messageSender.SendMessage(ctx, channelID, l.T("Failed to export characters."))
+	}
+
+	return b.messageSender.SendComplexMessage(ctx, channelID, discord.BuildExportMessage(l, files))
+}
+
 // formatName makes sure the character name is in the right format.
 //
 // We want the names to have a capital letter to start and the rest be lowercase.
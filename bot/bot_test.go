@@ -3,9 +3,18 @@ package bot
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/DylanNZL/mythicplusbot/blizzard"
 	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/discord"
+	"github.com/DylanNZL/mythicplusbot/guild"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/DylanNZL/mythicplusbot/roster"
+	"github.com/DylanNZL/mythicplusbot/vault"
 	"github.com/bwmarrin/discordgo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -27,6 +36,26 @@ func (m *MockMessageSender) SendComplexMessage(ctx context.Context, channelID st
 	return args.Error(0)
 }
 
+func (m *MockMessageSender) AddRole(ctx context.Context, guildID, userID, roleID string) error {
+	args := m.Called(ctx, guildID, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockMessageSender) RemoveRole(ctx context.Context, guildID, userID, roleID string) error {
+	args := m.Called(ctx, guildID, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockMessageSender) UpdateInteractionMessage(ctx context.Context, interaction *discordgo.Interaction, message discordgo.MessageSend) error {
+	args := m.Called(ctx, interaction, message)
+	return args.Error(0)
+}
+
+func (m *MockMessageSender) RespondEphemeral(ctx context.Context, interaction *discordgo.Interaction, content string) error {
+	args := m.Called(ctx, interaction, content)
+	return args.Error(0)
+}
+
 type MockUpdater struct {
 	mock.Mock
 }
@@ -55,122 +84,301 @@ func (m *MockCharacterService) ListCharacters(ctx context.Context, n int) ([]db.
 	return args.Get(0).([]db.Character), args.Error(1)
 }
 
+func (m *MockCharacterService) GetProfile(ctx context.Context, name, realm string) (db.Character, blizzard.CharacterEquipment, error) {
+	args := m.Called(ctx, name, realm)
+	return args.Get(0).(db.Character), args.Get(1).(blizzard.CharacterEquipment), args.Error(2)
+}
+
+func (m *MockCharacterService) GetDungeons(ctx context.Context, name, realm string) (*blizzard.MythicKeystoneSeason, error) {
+	args := m.Called(ctx, name, realm)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*blizzard.MythicKeystoneSeason), args.Error(1)
+}
+
+func (m *MockCharacterService) ListDungeons(ctx context.Context) ([]db.Dungeon, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]db.Dungeon), args.Error(1)
+}
+
+func (m *MockCharacterService) GetDungeonLeaderboard(ctx context.Context, dungeonID, limit int) ([]db.DungeonRunEntry, error) {
+	args := m.Called(ctx, dungeonID, limit)
+	return args.Get(0).([]db.DungeonRunEntry), args.Error(1)
+}
+
+func (m *MockCharacterService) GetRuns(ctx context.Context, name, realm string, kind db.RunKind) (db.Character, []db.CharacterRun, error) {
+	args := m.Called(ctx, name, realm, kind)
+	return args.Get(0).(db.Character), args.Get(1).([]db.CharacterRun), args.Error(2)
+}
+
+func (m *MockCharacterService) LinkCharacter(ctx context.Context, name, realm, userID string) (db.Character, error) {
+	args := m.Called(ctx, name, realm, userID)
+	return args.Get(0).(db.Character), args.Error(1)
+}
+
+func (m *MockCharacterService) UnlinkCharacter(ctx context.Context, name, realm, userID string, override bool) (db.Character, error) {
+	args := m.Called(ctx, name, realm, userID, override)
+	return args.Get(0).(db.Character), args.Error(1)
+}
+
+func (m *MockCharacterService) GetComparison(ctx context.Context, name, realm string) (discord.Comparison, error) {
+	args := m.Called(ctx, name, realm)
+	return args.Get(0).(discord.Comparison), args.Error(1)
+}
+
+type MockRosterService struct {
+	mock.Mock
+}
+
+func (m *MockRosterService) Export(ctx context.Context, format roster.Format, includeHistory bool) ([]roster.File, error) {
+	args := m.Called(ctx, format, includeHistory)
+	return args.Get(0).([]roster.File), args.Error(1)
+}
+
+type MockVaultService struct {
+	mock.Mock
+}
+
+func (m *MockVaultService) Progress(ctx context.Context) ([]vault.Progress, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]vault.Progress), args.Error(1)
+}
+
+type MockCutoffService struct {
+	mock.Mock
+}
+
+func (m *MockCutoffService) GetCutoffs(ctx context.Context, season string) (string, *raiderio.Cutoffs, error) {
+	args := m.Called(ctx, season)
+	if args.Get(1) == nil {
+		return args.String(0), nil, args.Error(2)
+	}
+	return args.String(0), args.Get(1).(*raiderio.Cutoffs), args.Error(2)
+}
+
+type MockAffixService struct {
+	mock.Mock
+}
+
+func (m *MockAffixService) GetAffixes(ctx context.Context) (*raiderio.Affixes, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*raiderio.Affixes), args.Error(1)
+}
+
+type MockGuildService struct {
+	mock.Mock
+}
+
+func (m *MockGuildService) Summary(ctx context.Context) (guild.Summary, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(guild.Summary), args.Error(1)
+}
+
+type MockLocaleService struct {
+	mock.Mock
+}
+
+func (m *MockLocaleService) GetLocale(ctx context.Context, guildID string) (i18n.Locale, error) {
+	args := m.Called(ctx, guildID)
+	return args.Get(0).(i18n.Locale), args.Error(1)
+}
+
+func (m *MockLocaleService) SetLocale(ctx context.Context, guildID string, locale i18n.Locale) error {
+	args := m.Called(ctx, guildID, locale)
+	return args.Error(0)
+}
+
+type MockTimeProvider struct {
+	now time.Time
+}
+
+func (m *MockTimeProvider) Now() time.Time {
+	return m.now
+}
+
+var testNow = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+const (
+	testLeaderboardExpiry = 15 * time.Minute
+	testAdminUserID       = "admin1"
+)
+
+// localeContext matches a context that replies are being translated into the locale with.
+func localeContext(l i18n.Locale) any {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		return i18n.FromContext(ctx) == l
+	})
+}
+
+// testMocks holds every dependency of a bot created by newTestBot.
+type testMocks struct {
+	messageSender    *MockMessageSender
+	updater          *MockUpdater
+	characterService *MockCharacterService
+	rosterService    *MockRosterService
+	vaultService     *MockVaultService
+	cutoffService    *MockCutoffService
+	affixService     *MockAffixService
+	guildService     *MockGuildService
+	templates        *discord.Templates
+	localeService    *MockLocaleService
+}
+
+func newTestBot() (*Bot, *testMocks) {
+	m := &testMocks{
+		messageSender:    &MockMessageSender{},
+		updater:          &MockUpdater{},
+		characterService: &MockCharacterService{},
+		rosterService:    &MockRosterService{},
+		vaultService:     &MockVaultService{},
+		cutoffService:    &MockCutoffService{},
+		affixService:     &MockAffixService{},
+		guildService:     &MockGuildService{},
+		templates:        mustParseTemplates(discord.TemplateText{Title: "{{.Name}} preview"}),
+		localeService:    &MockLocaleService{},
+	}
+	m.localeService.On("GetLocale", mock.Anything, mock.Anything).Return(i18n.English, nil).Maybe()
+
+	bot := NewBot(m.messageSender, m.updater, m.characterService, m.rosterService, m.vaultService, m.cutoffService,
+		m.affixService, m.guildService, m.templates, m.localeService, []string{testAdminUserID}, testLeaderboardExpiry,
+		&MockTimeProvider{now: testNow})
+	return bot, m
+}
+
+func mustParseTemplates(text discord.TemplateText) *discord.Templates {
+	templates, err := discord.ParseTemplates(text)
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+// testMessage returns a command sent by user1 in channel1.
+func testMessage(content string) Message {
+	return Message{Content: content, ChannelID: "channel1", AuthorID: "user1", GuildID: "guild1"}
+}
+
 // Test setup helper
 func setupBot() (*Bot, *MockMessageSender, *MockUpdater, *MockCharacterService) {
-	messageSender := &MockMessageSender{}
-	updater := &MockUpdater{}
-	characterService := &MockCharacterService{}
+	bot, m := newTestBot()
+	return bot, m.messageSender, m.updater, m.characterService
+}
 
-	bot := NewBot(messageSender, updater, characterService)
-	return bot, messageSender, updater, characterService
+func setupBotWithRoster() (*Bot, *MockMessageSender, *MockUpdater, *MockCharacterService, *MockRosterService) {
+	bot, m := newTestBot()
+	return bot, m.messageSender, m.updater, m.characterService, m.rosterService
 }
 
 func TestBot_HandleMessage_InvalidCommand(t *testing.T) {
 	bot, messageSender, _, _ := setupBot()
 
 	// Test non-bot message
-	err := bot.HandleMessage(t.Context(), "regular message", "channel1")
+	err := bot.HandleMessage(t.Context(), testMessage("regular message"))
 	assert.NoError(t, err)
 	messageSender.AssertNotCalled(t, "SendMessage")
 
 	// Test message without subcommand
-	messageSender.On("SendMessage", t.Context(), "channel1", "Usage: !mythicplusbot <command> [args]").Return(nil)
-	err = bot.HandleMessage(t.Context(), "!mythicplusbot", "channel1")
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Usage: !mythicplusbot <command> [args]").Return(nil)
+	err = bot.HandleMessage(t.Context(), testMessage("!mythicplusbot"))
 	assert.NoError(t, err)
-	messageSender.AssertCalled(t, "SendMessage", t.Context(), "channel1", "Usage: !mythicplusbot <command> [args]")
+	messageSender.AssertCalled(t, "SendMessage", localeContext(i18n.English), "channel1", "Usage: !mythicplusbot <command> [args]")
 }
 
 func TestBot_HandleMessage_Help(t *testing.T) {
 	bot, messageSender, _, _ := setupBot()
 
-	messageSender.On("SendMessage", t.Context(), "channel1", helpMessage).Return(nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", helpMessage(i18n.English)).Return(nil)
 
-	err := bot.HandleMessage(t.Context(), "!mythicplusbot help", "channel1")
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot help"))
 	assert.NoError(t, err)
-	messageSender.AssertCalled(t, "SendMessage", t.Context(), "channel1", helpMessage)
+	messageSender.AssertCalled(t, "SendMessage", localeContext(i18n.English), "channel1", helpMessage(i18n.English))
 }
 
 func TestBot_HandleMessage_UnknownCommand(t *testing.T) {
 	bot, messageSender, _, _ := setupBot()
 
 	expectedMessage := "Unknown command. Use " + Command + " help for a list of commands."
-	messageSender.On("SendMessage", t.Context(), "channel1", expectedMessage).Return(nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", expectedMessage).Return(nil)
 
-	err := bot.HandleMessage(t.Context(), "!mythicplusbot unknown", "channel1")
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot unknown"))
 	assert.NoError(t, err)
-	messageSender.AssertCalled(t, "SendMessage", t.Context(), "channel1", expectedMessage)
+	messageSender.AssertCalled(t, "SendMessage", localeContext(i18n.English), "channel1", expectedMessage)
 }
 
 func TestBot_HandleAddCharacter_Success(t *testing.T) {
 	bot, messageSender, _, characterService := setupBot()
 
-	characterService.On("AddCharacter", t.Context(), "Testchar", "testrealm").Return(nil)
-	messageSender.On("SendMessage", t.Context(), "channel1", "Now tracking Testchar-testrealm").Return(nil)
+	characterService.On("AddCharacter", localeContext(i18n.English), "Testchar", "testrealm").Return(nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Now tracking Testchar-testrealm").Return(nil)
 
-	err := bot.HandleMessage(t.Context(), "!mythicplusbot add testchar testrealm", "channel1")
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot add testchar testrealm"))
 	assert.NoError(t, err)
 
-	characterService.AssertCalled(t, "AddCharacter", t.Context(), "Testchar", "testrealm")
-	messageSender.AssertCalled(t, "SendMessage", t.Context(), "channel1", "Now tracking Testchar-testrealm")
+	characterService.AssertCalled(t, "AddCharacter", localeContext(i18n.English), "Testchar", "testrealm")
+	messageSender.AssertCalled(t, "SendMessage", localeContext(i18n.English), "channel1", "Now tracking Testchar-testrealm")
 }
 
 func TestBot_HandleAddCharacter_ServiceError(t *testing.T) {
 	bot, messageSender, _, characterService := setupBot()
 
-	characterService.On("AddCharacter", t.Context(), "Testchar", "testrealm").Return(errors.New("service error"))
-	messageSender.On("SendMessage", t.Context(), "channel1", "Failed to add character.").Return(nil)
+	characterService.On("AddCharacter", localeContext(i18n.English), "Testchar", "testrealm").Return(errors.New("service error"))
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to add character.").Return(nil)
 
-	err := bot.HandleMessage(t.Context(), "!mythicplusbot add testchar testrealm", "channel1")
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot add testchar testrealm"))
 	assert.NoError(t, err)
 
-	characterService.AssertCalled(t, "AddCharacter", t.Context(), "Testchar", "testrealm")
-	messageSender.AssertCalled(t, "SendMessage", t.Context(), "channel1", "Failed to add character.")
+	characterService.AssertCalled(t, "AddCharacter", localeContext(i18n.English), "Testchar", "testrealm")
+	messageSender.AssertCalled(t, "SendMessage", localeContext(i18n.English), "channel1", "Failed to add character.")
 }
 
 func TestBot_HandleAddCharacter_InvalidArgs(t *testing.T) {
 	bot, messageSender, _, _ := setupBot()
 
-	messageSender.On("SendMessage", t.Context(), "channel1", "Usage: !mythicplusbot add <character> <realm>").Return(nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Usage: !mythicplusbot add <character> <realm>").Return(nil)
 
-	err := bot.HandleMessage(t.Context(), "!mythicplusbot add", "channel1")
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot add"))
 	assert.NoError(t, err)
-	messageSender.AssertCalled(t, "SendMessage", t.Context(), "channel1", "Usage: !mythicplusbot add <character> <realm>")
+	messageSender.AssertCalled(t, "SendMessage", localeContext(i18n.English), "channel1", "Usage: !mythicplusbot add <character> <realm>")
 }
 
 func TestBot_HandleRemoveCharacter_Success(t *testing.T) {
 	bot, messageSender, _, characterService := setupBot()
 
-	characterService.On("RemoveCharacter", t.Context(), "Testchar", "testrealm").Return(nil)
-	messageSender.On("SendMessage", t.Context(), "channel1", "No longer tracking Testchar-testrealm.").Return(nil)
+	characterService.On("RemoveCharacter", localeContext(i18n.English), "Testchar", "testrealm").Return(nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "No longer tracking Testchar-testrealm.").Return(nil)
 
-	err := bot.HandleMessage(t.Context(), "!mythicplusbot remove testchar testrealm", "channel1")
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot remove testchar testrealm"))
 	assert.NoError(t, err)
 
-	characterService.AssertCalled(t, "RemoveCharacter", t.Context(), "Testchar", "testrealm")
-	messageSender.AssertCalled(t, "SendMessage", t.Context(), "channel1", "No longer tracking Testchar-testrealm.")
+	characterService.AssertCalled(t, "RemoveCharacter", localeContext(i18n.English), "Testchar", "testrealm")
+	messageSender.AssertCalled(t, "SendMessage", localeContext(i18n.English), "channel1", "No longer tracking Testchar-testrealm.")
 }
 
 func TestBot_HandleRemoveCharacter_ServiceError(t *testing.T) {
 	bot, messageSender, _, characterService := setupBot()
 
-	characterService.On("RemoveCharacter", t.Context(), "Testchar", "testrealm").Return(errors.New("service error"))
-	messageSender.On("SendMessage", t.Context(), "channel1", "Failed to remove character.").Return(nil)
+	characterService.On("RemoveCharacter", localeContext(i18n.English), "Testchar", "testrealm").Return(errors.New("service error"))
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to remove character.").Return(nil)
 
-	err := bot.HandleMessage(t.Context(), "!mythicplusbot remove testchar testrealm", "channel1")
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot remove testchar testrealm"))
 	assert.NoError(t, err)
 
-	characterService.AssertCalled(t, "RemoveCharacter", t.Context(), "Testchar", "testrealm")
-	messageSender.AssertCalled(t, "SendMessage", t.Context(), "channel1", "Failed to remove character.")
+	characterService.AssertCalled(t, "RemoveCharacter", localeContext(i18n.English), "Testchar", "testrealm")
+	messageSender.AssertCalled(t, "SendMessage", localeContext(i18n.English), "channel1", "Failed to remove character.")
 }
 
 func TestBot_HandleRemoveCharacter_InvalidArgs(t *testing.T) {
 	bot, messageSender, _, _ := setupBot()
 
-	messageSender.On("SendMessage", t.Context(), "channel1", "Usage: !mythicplusbot remove <character> <realm>").Return(nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Usage: !mythicplusbot remove <character> <realm>").Return(nil)
 
-	err := bot.HandleMessage(t.Context(), "!mythicplusbot remove", "channel1")
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot remove"))
 	assert.NoError(t, err)
-	messageSender.AssertCalled(t, "SendMessage", t.Context(), "channel1", "Usage: !mythicplusbot remove <character> <realm>")
+	messageSender.AssertCalled(t, "SendMessage", localeContext(i18n.English), "channel1", "Usage: !mythicplusbot remove <character> <realm>")
 }
 
 func TestBot_HandleScores_Success(t *testing.T) {
@@ -181,14 +389,14 @@ func TestBot_HandleScores_Success(t *testing.T) {
 		{Name: "char2", Realm: "realm1", OverallScore: 2300.0},
 	}
 
-	characterService.On("ListCharacters", t.Context(), 10).Return(characters, nil)
-	messageSender.On("SendComplexMessage", t.Context(), "channel1", mock.Anything).Return(nil)
+	characterService.On("ListCharacters", localeContext(i18n.English), 10).Return(characters, nil)
+	messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1", mock.Anything).Return(nil)
 
-	err := bot.HandleMessage(t.Context(), "!mythicplusbot scores", "channel1")
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot scores"))
 	assert.NoError(t, err)
 
-	characterService.AssertCalled(t, "ListCharacters", t.Context(), 10)
-	messageSender.AssertCalled(t, "SendComplexMessage", t.Context(), "channel1", mock.Anything)
+	characterService.AssertCalled(t, "ListCharacters", localeContext(i18n.English), 10)
+	messageSender.AssertCalled(t, "SendComplexMessage", localeContext(i18n.English), "channel1", mock.Anything)
 }
 
 func TestBot_HandleList_Success(t *testing.T) {
@@ -199,12 +407,810 @@ func TestBot_HandleList_Success(t *testing.T) {
 		{Name: "char2", Realm: "realm1", OverallScore: 2300.0},
 	}
 
-	characterService.On("ListCharacters", t.Context(), 10).Return(characters, nil)
-	messageSender.On("SendMessage", t.Context(), "channel1", "todo :(").Return(nil)
+	characterService.On("ListCharacters", localeContext(i18n.English), 10).Return(characters, nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "todo :(").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot list"))
+	assert.NoError(t, err)
+
+	characterService.AssertCalled(t, "ListCharacters", localeContext(i18n.English), 10)
+	messageSender.AssertCalled(t, "SendMessage", localeContext(i18n.English), "channel1", "todo :(")
+}
+
+// testCharacters returns n characters with descending scores.
+func testCharacters(n int) []db.Character {
+	characters := make([]db.Character, 0, n)
+	for i := range n {
+		characters = append(characters, db.Character{
+			Name: fmt.Sprintf("Char%d", i+1), Realm: "realm1", OverallScore: float64(3000 - i*10),
+		})
+	}
+	return characters
+}
+
+func TestBot_HandleScores_Paginated(t *testing.T) {
+	bot, m := newTestBot()
+
+	characters := testCharacters(15)
+	page := discord.ScoresPage{Limit: 15, Expires: testNow.Add(testLeaderboardExpiry)}
+	m.characterService.On("ListCharacters", localeContext(i18n.English), 15).Return(characters, nil)
+	m.messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1",
+		discord.BuildScoresMessage(i18n.English, characters, page)).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot scores -n 15"))
+	assert.NoError(t, err)
+
+	m.messageSender.AssertExpectations(t)
+}
+
+// testComponent returns a button press on the scores leaderboard's page.
+func testComponent(page discord.ScoresPage) *discordgo.Interaction {
+	message := discord.BuildScoresMessage(i18n.English, testCharacters(page.Limit), page)
+	next := message.Components[0].(discordgo.ActionsRow).Components[1].(discordgo.Button)
+
+	return &discordgo.Interaction{
+		Type:      discordgo.InteractionMessageComponent,
+		ChannelID: "channel1",
+		GuildID:   "guild1",
+		Data:      discordgo.MessageComponentInteractionData{CustomID: next.CustomID},
+	}
+}
+
+func TestBot_HandleComponent_NextPage(t *testing.T) {
+	bot, m := newTestBot()
+
+	page := discord.ScoresPage{Limit: 25, Expires: testNow.Add(time.Minute)}
+	interaction := testComponent(page)
+	characters := testCharacters(25)
+	next := page
+	next.Page = 1
+	m.characterService.On("ListCharacters", localeContext(i18n.English), 25).Return(characters, nil)
+	m.messageSender.On("UpdateInteractionMessage", localeContext(i18n.English), interaction,
+		discord.BuildScoresMessage(i18n.English, characters, next)).Return(nil)
+
+	err := bot.HandleComponent(t.Context(), interaction)
+	assert.NoError(t, err)
+
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleComponent_Expired(t *testing.T) {
+	bot, m := newTestBot()
+
+	interaction := testComponent(discord.ScoresPage{Limit: 25, Expires: testNow.Add(-time.Minute)})
+	m.messageSender.On("RespondEphemeral", localeContext(i18n.English), interaction,
+		"This leaderboard has expired, send `!mythicplusbot scores` for a new one.").Return(nil)
+
+	err := bot.HandleComponent(t.Context(), interaction)
+	assert.NoError(t, err)
+
+	m.characterService.AssertNotCalled(t, "ListCharacters", mock.Anything, mock.Anything)
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleComponent_ServiceError(t *testing.T) {
+	bot, m := newTestBot()
+
+	interaction := testComponent(discord.ScoresPage{Limit: 25, Expires: testNow.Add(time.Minute)})
+	m.characterService.On("ListCharacters", localeContext(i18n.English), 25).Return([]db.Character(nil), errors.New("db error"))
+	m.messageSender.On("RespondEphemeral", localeContext(i18n.English), interaction, "Failed to get scores").Return(nil)
+
+	err := bot.HandleComponent(t.Context(), interaction)
+	assert.NoError(t, err)
+
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleComponent_OtherComponent(t *testing.T) {
+	bot, m := newTestBot()
+
+	err := bot.HandleComponent(t.Context(), &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{CustomID: "something-else"},
+	})
+	assert.NoError(t, err)
+
+	m.characterService.AssertNotCalled(t, "ListCharacters", mock.Anything, mock.Anything)
+	m.messageSender.AssertNotCalled(t, "UpdateInteractionMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBot_HandleProfile_Success(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	character := db.Character{Name: "Testchar", Realm: "testrealm", Class: "Paladin", Spec: "Protection", ItemLevel: 620}
+	equipment := blizzard.CharacterEquipment{}
+	characterService.On("GetProfile", localeContext(i18n.English), "Testchar", "testrealm").Return(character, equipment, nil)
+	messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1", discord.BuildProfileMessage(i18n.English, character, equipment)).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot profile testchar TestRealm"))
+	assert.NoError(t, err)
+
+	characterService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleProfile_ServiceError(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("GetProfile", localeContext(i18n.English), "Testchar", "testrealm").
+		Return(db.Character{}, blizzard.CharacterEquipment{}, errors.New("not found"))
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to get profile.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot profile testchar testrealm"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleProfile_InvalidArgs(t *testing.T) {
+	bot, messageSender, _, _ := setupBot()
+
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Usage: !mythicplusbot profile <character> <realm>").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot profile testchar"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleDungeons_Success(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	season := &blizzard.MythicKeystoneSeason{MythicRating: blizzard.Rating{Rating: 2750}}
+	characterService.On("GetDungeons", localeContext(i18n.English), "Testchar", "testrealm").Return(season, nil)
+	messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1", discord.BuildDungeonsMessage(i18n.English, season)).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot dungeons testchar TestRealm"))
+	assert.NoError(t, err)
+
+	characterService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleDungeons_ServiceError(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("GetDungeons", localeContext(i18n.English), "Testchar", "testrealm").Return(nil, errors.New("not found"))
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to get dungeons.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot dungeons testchar testrealm"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleDungeons_InvalidArgs(t *testing.T) {
+	bot, messageSender, _, _ := setupBot()
+
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Usage: !mythicplusbot dungeons <character> <realm>").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot dungeons testchar"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleRuns_Best(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	character := db.Character{ID: 1, Name: "Testchar", Realm: "testrealm"}
+	runs := []db.CharacterRun{{Kind: db.RunKindBest, ShortName: "SV", MythicLevel: 12, Score: 310}}
+	characterService.On("GetRuns", localeContext(i18n.English), "Testchar", "testrealm", db.RunKindBest).Return(character, runs, nil)
+	messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1",
+		discord.BuildRunsMessage(i18n.English, character, db.RunKindBest, runs)).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot runs testchar testrealm"))
+	assert.NoError(t, err)
+
+	characterService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleRuns_Recent(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	character := db.Character{ID: 1, Name: "Testchar", Realm: "testrealm"}
+	characterService.On("GetRuns", localeContext(i18n.English), "Testchar", "testrealm", db.RunKindRecent).
+		Return(character, []db.CharacterRun{}, nil)
+	messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1",
+		discord.BuildRunsMessage(i18n.English, character, db.RunKindRecent, []db.CharacterRun{})).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot runs testchar testrealm --recent"))
+	assert.NoError(t, err)
+
+	characterService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleRuns_NotTracked(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("GetRuns", localeContext(i18n.English), "Testchar", "testrealm", db.RunKindBest).
+		Return(db.Character{}, []db.CharacterRun(nil), nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1",
+		"Testchar-testrealm isn't being tracked, add them with `!mythicplusbot add <character> <realm>`.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot runs testchar testrealm --best"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleRuns_InvalidArgs(t *testing.T) {
+	bot, messageSender, _, _ := setupBot()
+
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1",
+		"Usage: !mythicplusbot runs <character> <realm> [--best|--recent]").Return(nil).Twice()
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot runs testchar"))
+	assert.NoError(t, err)
+	err = bot.HandleMessage(t.Context(), testMessage("!mythicplusbot runs testchar testrealm --worst"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleRuns_ServiceError(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("GetRuns", localeContext(i18n.English), "Testchar", "testrealm", db.RunKindBest).
+		Return(db.Character{}, []db.CharacterRun(nil), errors.New("database error"))
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to get runs.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot runs testchar testrealm"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleCompare_Success(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	first := discord.Comparison{Character: db.Character{ID: 1, Name: "Testchar", Realm: "testrealm"}}
+	second := discord.Comparison{Character: db.Character{Name: "Otherchar", Realm: "area-52"}}
+	characterService.On("GetComparison", localeContext(i18n.English), "Testchar", "testrealm").Return(first, nil)
+	// Only the first hyphen separates the name from the realm
+	characterService.On("GetComparison", localeContext(i18n.English), "Otherchar", "area-52").Return(second, nil)
+	messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1",
+		discord.BuildCompareMessage(i18n.English, []discord.Comparison{first, second}, testNow)).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot compare testchar-testrealm otherchar-Area-52"))
+	assert.NoError(t, err)
+
+	characterService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleCompare_InvalidArgs(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1",
+		"Usage: !mythicplusbot compare <character>-<realm> <character>-<realm> [...]").Return(nil).Twice()
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot compare testchar-testrealm"))
+	assert.NoError(t, err)
+	err = bot.HandleMessage(t.Context(), testMessage("!mythicplusbot compare testchar-testrealm otherchar"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+	characterService.AssertNotCalled(t, "GetComparison", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBot_HandleCompare_TooManyCharacters(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "You can compare up to 12 characters.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot compare a-realm b-realm c-realm d-realm e-realm f-realm g-realm h-realm i-realm j-realm k-realm l-realm m-realm"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+	characterService.AssertNotCalled(t, "GetComparison", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBot_HandleCompare_CharacterNotFound(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	first := discord.Comparison{Character: db.Character{ID: 1, Name: "Testchar", Realm: "testrealm"}}
+	third := discord.Comparison{Character: db.Character{Name: "Thirdchar", Realm: "testrealm"}}
+	characterService.On("GetComparison", localeContext(i18n.English), "Testchar", "testrealm").Return(first, nil)
+	characterService.On("GetComparison", localeContext(i18n.English), "Typochar", "testrealm").
+		Return(discord.Comparison{}, errors.New("raider.io error"))
+	characterService.On("GetComparison", localeContext(i18n.English), "Thirdchar", "testrealm").Return(third, nil)
+
+	// The rest are still compared, with the one that couldn't be found named
+	expected := discord.BuildCompareMessage(i18n.English, []discord.Comparison{first, third}, testNow)
+	expected.Content = "Couldn't find Typochar-testrealm on Raider.IO, comparing the rest."
+	messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1", expected).Return(nil)
+
+	err := bot.HandleMessage(t.Context(),
+		testMessage("!mythicplusbot compare testchar-testrealm typochar-testrealm thirdchar-testrealm"))
+	assert.NoError(t, err)
+
+	characterService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleCompare_NoneFound(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("GetComparison", localeContext(i18n.English), mock.Anything, "testrealm").
+		Return(discord.Comparison{}, errors.New("raider.io error"))
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1",
+		"Couldn't find Testchar-testrealm, Otherchar-testrealm on Raider.IO.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot compare testchar-testrealm otherchar-testrealm"))
+	assert.NoError(t, err)
+
+	characterService.AssertNumberOfCalls(t, "GetComparison", 2)
+	messageSender.AssertExpectations(t)
+	messageSender.AssertNotCalled(t, "SendComplexMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBot_HandleLink_Success(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("LinkCharacter", localeContext(i18n.English), "Testchar", "testrealm", "user1").
+		Return(db.Character{ID: 1, Name: "Testchar", Realm: "testrealm"}, nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Linked Testchar-testrealm to <@user1>").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot link testchar testrealm"))
+	assert.NoError(t, err)
+
+	characterService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleLink_NotTracked(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("LinkCharacter", localeContext(i18n.English), "Testchar", "testrealm", "user1").Return(db.Character{}, nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1",
+		"Testchar-testrealm isn't being tracked, add them with `!mythicplusbot add <character> <realm>`.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot link testchar testrealm"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleLink_LinkedToOtherUser(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("LinkCharacter", localeContext(i18n.English), "Testchar", "testrealm", "user1").
+		Return(db.Character{}, db.ErrLinkedToOtherUser)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1",
+		"Testchar-testrealm is already linked to someone else, they or an admin can unlink it with `!mythicplusbot unlink <character> <realm>`.").
+		Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot link testchar testrealm"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleLink_Errors(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("LinkCharacter", localeContext(i18n.English), "Testchar", "testrealm", "user1").
+		Return(db.Character{}, errors.New("database error"))
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Usage: !mythicplusbot link <character> <realm>").Return(nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to link character.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot link testchar"))
+	assert.NoError(t, err)
+	err = bot.HandleMessage(t.Context(), testMessage("!mythicplusbot link testchar testrealm"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleUnlink_Success(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("UnlinkCharacter", localeContext(i18n.English), "Testchar", "testrealm", "user1", false).
+		Return(db.Character{ID: 1, Name: "Testchar", Realm: "testrealm"}, nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Unlinked Testchar-testrealm").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot unlink testchar testrealm"))
+	assert.NoError(t, err)
+
+	characterService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleUnlink_Admin(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("UnlinkCharacter", localeContext(i18n.English), "Testchar", "testrealm", testAdminUserID, true).
+		Return(db.Character{ID: 1, Name: "Testchar", Realm: "testrealm"}, nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Unlinked Testchar-testrealm").Return(nil)
+
+	msg := testMessage("!mythicplusbot unlink testchar testrealm")
+	msg.AuthorID = testAdminUserID
+	err := bot.HandleMessage(t.Context(), msg)
+	assert.NoError(t, err)
+
+	characterService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleUnlink_Errors(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("UnlinkCharacter", localeContext(i18n.English), "Linked", "testrealm", "user1", false).
+		Return(db.Character{}, db.ErrLinkedToOtherUser)
+	characterService.On("UnlinkCharacter", localeContext(i18n.English), "Unlinked", "testrealm", "user1", false).
+		Return(db.Character{}, db.ErrNotLinked)
+	characterService.On("UnlinkCharacter", localeContext(i18n.English), "Untracked", "testrealm", "user1", false).
+		Return(db.Character{}, nil)
+	characterService.On("UnlinkCharacter", localeContext(i18n.English), "Testchar", "testrealm", "user1", false).
+		Return(db.Character{}, errors.New("database error"))
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Usage: !mythicplusbot unlink <character> <realm>").Return(nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1",
+		"Linked-testrealm is linked to someone else, only they or an admin can unlink it.").Return(nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Unlinked-testrealm isn't linked to anyone.").Return(nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1",
+		"Untracked-testrealm isn't being tracked, add them with `!mythicplusbot add <character> <realm>`.").Return(nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to unlink character.").Return(nil)
+
+	for _, content := range []string{
+		"!mythicplusbot unlink testchar",
+		"!mythicplusbot unlink linked testrealm",
+		"!mythicplusbot unlink unlinked testrealm",
+		"!mythicplusbot unlink untracked testrealm",
+		"!mythicplusbot unlink testchar testrealm",
+	} {
+		err := bot.HandleMessage(t.Context(), testMessage(content))
+		assert.NoError(t, err)
+	}
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandlePreviewTemplate(t *testing.T) {
+	bot, m := newTestBot()
+
+	m.messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1", mock.MatchedBy(func(msg discordgo.MessageSend) bool {
+		return len(msg.Embeds) == 1 && msg.Embeds[0].Title == "Paladylan preview"
+	})).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot preview-template"))
+	assert.NoError(t, err)
+
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleLanguage_Show(t *testing.T) {
+	bot, m := newTestBot()
+
+	m.messageSender.On("SendMessage", localeContext(i18n.English), "channel1",
+		"The bot is replying in English, change it with `!mythicplusbot language [en|de|fr]`.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot language"))
+	assert.NoError(t, err)
+
+	m.localeService.AssertNotCalled(t, "SetLocale", mock.Anything, mock.Anything, mock.Anything)
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleLanguage_Set(t *testing.T) {
+	bot, m := newTestBot()
+
+	m.localeService.On("SetLocale", localeContext(i18n.English), "guild1", i18n.German).Return(nil)
+	m.messageSender.On("SendMessage", localeContext(i18n.English), "channel1",
+		"Der Bot antwortet jetzt auf Deutsch.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot language DE"))
+	assert.NoError(t, err)
+
+	m.localeService.AssertExpectations(t)
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleLanguage_Unknown(t *testing.T) {
+	bot, m := newTestBot()
+
+	m.messageSender.On("SendMessage", localeContext(i18n.English), "channel1",
+		"Unknown language, try one of: en|de|fr").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot language klingon"))
+	assert.NoError(t, err)
+
+	m.localeService.AssertNotCalled(t, "SetLocale", mock.Anything, mock.Anything, mock.Anything)
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleLanguage_ServiceError(t *testing.T) {
+	bot, m := newTestBot()
+
+	m.localeService.On("SetLocale", localeContext(i18n.English), "guild1", i18n.French).Return(errors.New("db error"))
+	m.messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to change the language.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot language fr"))
+	assert.NoError(t, err)
+
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleMessage_ServerLocale(t *testing.T) {
+	bot, m := newTestBot()
+
+	m.localeService.ExpectedCalls = nil
+	m.localeService.On("GetLocale", t.Context(), "guild1").Return(i18n.German, nil)
+	m.characterService.On("RemoveCharacter", localeContext(i18n.German), "Testchar", "testrealm").Return(nil)
+	m.messageSender.On("SendMessage", localeContext(i18n.German), "channel1",
+		"Testchar-testrealm wird nicht mehr verfolgt.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot remove testchar testrealm"))
+	assert.NoError(t, err)
+
+	m.localeService.AssertExpectations(t)
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleMessage_LocaleError(t *testing.T) {
+	bot, m := newTestBot()
+
+	m.localeService.ExpectedCalls = nil
+	m.localeService.On("GetLocale", t.Context(), "guild1").Return(i18n.Locale(""), errors.New("db error"))
+	m.messageSender.On("SendMessage", localeContext(i18n.English), "channel1", helpMessage(i18n.English)).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot help"))
+	assert.NoError(t, err)
+
+	m.messageSender.AssertExpectations(t)
+}
+
+var testDungeons = []db.Dungeon{{ID: 1, Name: "Ara-Kara"}, {ID: 2, Name: "The Stonevault"}}
+
+func TestBot_HandleDungeon_Success(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	entries := []db.DungeonRunEntry{{DungeonRun: db.DungeonRun{DungeonID: 1, KeystoneLevel: 12}, Name: "Testchar"}}
+	characterService.On("ListDungeons", localeContext(i18n.English)).Return(testDungeons, nil)
+	characterService.On("GetDungeonLeaderboard", localeContext(i18n.English), 1, defaultRows).Return(entries, nil)
+	messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1",
+		discord.BuildDungeonLeaderboardMessage(i18n.English, testDungeons[0], entries)).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot dungeon ara kara"))
+	assert.NoError(t, err)
+
+	characterService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleDungeon_UnknownDungeon(t *testing.T) {
+	bot, messageSender, _, characterService := setupBot()
+
+	characterService.On("ListDungeons", localeContext(i18n.English)).Return(testDungeons, nil)
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1",
+		"Unknown dungeon, try one of: Ara-Kara, The Stonevault").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot dungeon grim batol"))
+	assert.NoError(t, err)
+
+	characterService.AssertNotCalled(t, "GetDungeonLeaderboard")
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleDungeon_InvalidArgs(t *testing.T) {
+	bot, messageSender, _, _ := setupBot()
+
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Usage: !mythicplusbot dungeon <dungeon>").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot dungeon"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestFindDungeon(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected db.Dungeon
+		found    bool
+	}{
+		{query: "Ara-Kara", expected: testDungeons[0], found: true},
+		{query: "arakara", expected: testDungeons[0], found: true},
+		{query: "stonevault", expected: testDungeons[1], found: true},
+		{query: "grim batol"},
+		{query: "--"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			dungeon, found := findDungeon(testDungeons, tt.query)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.expected, dungeon)
+		})
+	}
+}
+
+func TestBot_HandleVault_Success(t *testing.T) {
+	bot, m := newTestBot()
+	messageSender, vaultService := m.messageSender, m.vaultService
+
+	progress := []vault.Progress{{Character: db.Character{Name: "Testchar", Realm: "testrealm"}, Runs: 4}}
+	vaultService.On("Progress", localeContext(i18n.English)).Return(progress, nil)
+	messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1", discord.BuildVaultMessage(i18n.English, progress)).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot vault"))
+	assert.NoError(t, err)
+
+	vaultService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleVault_ServiceError(t *testing.T) {
+	bot, m := newTestBot()
+	messageSender, vaultService := m.messageSender, m.vaultService
+
+	vaultService.On("Progress", localeContext(i18n.English)).Return([]vault.Progress(nil), errors.New("db error"))
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to get vault progress.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot vault"))
+	assert.NoError(t, err)
+
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleCutoffs_Success(t *testing.T) {
+	bot, m := newTestBot()
+
+	cutoffs := &raiderio.Cutoffs{}
+	cutoffs.P999.All.QuantileMinValue = 3450
+	m.cutoffService.On("GetCutoffs", localeContext(i18n.English), "").Return("season-tww-2", cutoffs, nil)
+	m.messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1",
+		discord.BuildCutoffsMessage(i18n.English, "season-tww-2", cutoffs)).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot cutoffs"))
+	assert.NoError(t, err)
+
+	m.cutoffService.AssertExpectations(t)
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleCutoffs_Season(t *testing.T) {
+	bot, m := newTestBot()
+
+	cutoffs := &raiderio.Cutoffs{}
+	m.cutoffService.On("GetCutoffs", localeContext(i18n.English), "season-tww-1").Return("season-tww-1", cutoffs, nil)
+	m.messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1",
+		discord.BuildCutoffsMessage(i18n.English, "season-tww-1", cutoffs)).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot cutoffs Season-TWW-1"))
+	assert.NoError(t, err)
+
+	m.cutoffService.AssertExpectations(t)
+}
+
+func TestBot_HandleCutoffs_ServiceError(t *testing.T) {
+	bot, m := newTestBot()
+
+	m.cutoffService.On("GetCutoffs", localeContext(i18n.English), "").Return("", nil, errors.New("api error"))
+	m.messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to get season cutoffs.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot cutoffs"))
+	assert.NoError(t, err)
+
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleAffixes_Success(t *testing.T) {
+	bot, m := newTestBot()
+
+	affixes := &raiderio.Affixes{AffixDetails: []raiderio.Affix{{Name: "Tyrannical", Description: "Bosses hit hard."}}}
+	m.affixService.On("GetAffixes", localeContext(i18n.English)).Return(affixes, nil)
+	m.messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1", discord.BuildAffixesMessage(i18n.English, affixes)).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot affixes"))
+	assert.NoError(t, err)
+
+	m.affixService.AssertExpectations(t)
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleAffixes_ServiceError(t *testing.T) {
+	bot, m := newTestBot()
+
+	m.affixService.On("GetAffixes", localeContext(i18n.English)).Return(nil, errors.New("api error"))
+	m.messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to get this week's affixes.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot affixes"))
+	assert.NoError(t, err)
+
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleGuild_Success(t *testing.T) {
+	bot, m := newTestBot()
+
+	summary := guild.Summary{Guild: raiderio.Guild{Name: "Test Guild"}, Members: 2, AboveThreshold: []int{2, 1, 0}}
+	m.guildService.On("Summary", localeContext(i18n.English)).Return(summary, nil)
+	m.messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1", discord.BuildGuildMessage(i18n.English, summary)).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot guild"))
+	assert.NoError(t, err)
+
+	m.guildService.AssertExpectations(t)
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleGuild_NotConfigured(t *testing.T) {
+	bot, m := newTestBot()
+
+	m.guildService.On("Summary", localeContext(i18n.English)).Return(guild.Summary{}, guild.ErrNoGuild)
+	m.messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "No home guild is configured.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot guild"))
+	assert.NoError(t, err)
+
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleGuild_ServiceError(t *testing.T) {
+	bot, m := newTestBot()
+
+	m.guildService.On("Summary", localeContext(i18n.English)).Return(guild.Summary{}, errors.New("api error"))
+	m.messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to get the guild.").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot guild"))
+	assert.NoError(t, err)
+
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleExport_Default(t *testing.T) {
+	bot, messageSender, _, _, rosterService := setupBotWithRoster()
+
+	files := []roster.File{{Name: "roster.json", ContentType: "application/json", Data: []byte("{}")}}
+	rosterService.On("Export", localeContext(i18n.English), roster.FormatJSON, false).Return(files, nil)
+	messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1", mock.MatchedBy(func(msg discordgo.MessageSend) bool {
+		return len(msg.Files) == 1 && msg.Files[0].Name == "roster.json"
+	})).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot export"))
+	assert.NoError(t, err)
+
+	rosterService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleExport_CSVWithHistory(t *testing.T) {
+	bot, messageSender, _, _, rosterService := setupBotWithRoster()
+
+	files := []roster.File{{Name: "characters.csv"}, {Name: "score_history.csv"}}
+	rosterService.On("Export", localeContext(i18n.English), roster.FormatCSV, true).Return(files, nil)
+	messageSender.On("SendComplexMessage", localeContext(i18n.English), "channel1", mock.Anything).Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot export csv history"))
+	assert.NoError(t, err)
+
+	rosterService.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleExport_InvalidFormat(t *testing.T) {
+	bot, messageSender, _, _, rosterService := setupBotWithRoster()
+
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Usage: !mythicplusbot export [json|csv] [history]").Return(nil)
+
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot export xml"))
+	assert.NoError(t, err)
+
+	rosterService.AssertNotCalled(t, "Export")
+	messageSender.AssertExpectations(t)
+}
+
+func TestBot_HandleExport_ServiceError(t *testing.T) {
+	bot, messageSender, _, _, rosterService := setupBotWithRoster()
+
+	rosterService.On("Export", localeContext(i18n.English), roster.FormatJSON, false).Return([]roster.File(nil), errors.New("db error"))
+	messageSender.On("SendMessage", localeContext(i18n.English), "channel1", "Failed to export characters.").Return(nil)
 
-	err := bot.HandleMessage(t.Context(), "!mythicplusbot list", "channel1")
+	err := bot.HandleMessage(t.Context(), testMessage("!mythicplusbot export"))
 	assert.NoError(t, err)
 
-	characterService.AssertCalled(t, "ListCharacters", t.Context(), 10)
-	messageSender.AssertCalled(t, "SendMessage", t.Context(), "channel1", "todo :(")
+	messageSender.AssertExpectations(t)
 }
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DylanNZL/mythicplusbot/backup"
+	"github.com/DylanNZL/mythicplusbot/config"
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/roster"
+	"github.com/DylanNZL/mythicplusbot/season"
+)
+
+var (
+	errUnknownCommand     = errors.New("unknown command")
+	errBackupsUnsupported = errors.New("backups are only supported for sqlite, use pg_dump for postgres")
+)
+
+// runCommand runs one of the maintenance subcommands instead of starting the bot:
+//   - export [-format json|csv] [-history] [-dir .]
+//   - import [-format json|csv] [-history score_history.csv] <file>
+//   - backup
+//   - backfill-seasons
+//   - restore <backup> (handled by runRestore as the database must not be open)
+func runCommand(ctx context.Context, rosterService *roster.Service, backupManager *backup.Manager,
+	backfiller *season.Backfiller, args []string,
+) error {
+	switch args[0] {
+	case "export":
+		return runExport(ctx, rosterService, args[1:])
+	case "import":
+		return runImport(ctx, rosterService, args[1:])
+	case "backup":
+		if backupManager == nil {
+			return errBackupsUnsupported
+		}
+		path, err := backupManager.Backup(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println("wrote", path)
+		return nil
+	case "backfill-seasons":
+		result, err := backfiller.Backfill(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println(result.Summary())
+		return nil
+	default:
+		return fmt.Errorf("%w: %s (expected export, import, backup, backfill-seasons or restore)", errUnknownCommand,
+			args[0])
+	}
+}
+
+func runRestore(ctx context.Context, cfg config.Config, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: restore <backup>")
+	}
+	if db.Dialect(cfg.DatabaseDriver) != db.DialectSQLite {
+		return errBackupsUnsupported
+	}
+
+	dbLocation := cfg.DatabaseLocation
+
+	if err := backup.Restore(ctx, args[0], dbLocation); err != nil {
+		return err
+	}
+
+	fmt.Printf("restored %s from %s, the previous database was kept as %s.pre-restore\n", dbLocation, args[0], dbLocation)
+	return nil
+}
+
+func runExport(ctx context.Context, rosterService *roster.Service, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	formatFlag := flags.String("format", string(roster.FormatJSON), "export format, json or csv")
+	includeHistory := flags.Bool("history", false, "include score history")
+	dir := flags.String("dir", ".", "directory to write the export to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := roster.ParseFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+
+	files, err := rosterService.Export(ctx, format, *includeHistory)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		path := filepath.Join(*dir, f.Name)
+		if err := os.WriteFile(path, f.Data, 0o600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Println("wrote", path)
+	}
+
+	return nil
+}
+
+func runImport(ctx context.Context, rosterService *roster.Service, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	formatFlag := flags.String("format", "", "import format, json or csv (defaults to the file extension)")
+	historyPath := flags.String("history", "", "score history csv to import along with a characters csv")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: import [-format json|csv] [-history score_history.csv] <file>")
+	}
+
+	path := flags.Arg(0)
+	if *formatFlag == "" {
+		*formatFlag = strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+
+	format, err := roster.ParseFormat(*formatFlag)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var history io.Reader
+	if *historyPath != "" {
+		if format != roster.FormatCSV {
+			return errors.New("-history is only used with csv imports, json exports include their history")
+		}
+		h, err := os.Open(*historyPath)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", *historyPath, err)
+		}
+		defer h.Close()
+		history = h
+	}
+
+	result, err := rosterService.Import(ctx, format, f, history)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(result.Summary())
+	return nil
+}
@@ -9,33 +9,100 @@ import (
 )
 
 type Config struct {
-	BlizzardClientID     string `yaml:"blizzardClientId"`
-	BlizzardClientSecret string `yaml:"blizzardClientSecret"`
-	RaiderIOAccessKey    string `yaml:"raiderIOAccessKey"`
-	DiscordToken         string `yaml:"discordToken"`
-	DiscordChannelID     string `yaml:"discordChannelId"`
-	DatabaseLocation     string `yaml:"databaseLocation"`
-	LogLevel             int    `yaml:"logLevel"`         // maps to slog.LogLevels
-	UpdaterFrequency     int64  `yaml:"updaterFrequency"` // How frequently to run the updater
+	BlizzardClientID          string          `yaml:"blizzardClientId"`
+	BlizzardClientSecret      string          `yaml:"blizzardClientSecret"`
+	BlizzardCacheFile         string          `yaml:"blizzardCacheFile"` // Where to keep cached Blizzard responses, leave empty to only cache in memory
+	RaiderIOAccessKey         string          `yaml:"raiderIOAccessKey"`
+	DiscordToken              string          `yaml:"discordToken"`
+	DiscordChannelID          string          `yaml:"discordChannelId"`
+	DatabaseDriver            string          `yaml:"databaseDriver"` // sqlite3 or postgres
+	DatabaseLocation          string          `yaml:"databaseLocation"`
+	DatabaseURL               string          `yaml:"databaseURL"`               // Connection URL, used by the postgres driver
+	LogLevel                  int             `yaml:"logLevel"`                  // maps to slog.LogLevels
+	UpdaterFrequency          int64           `yaml:"updaterFrequency"`          // How frequently to run the updater
+	BackupDirectory           string          `yaml:"backupDirectory"`           // Where to write database snapshots, leave empty to disable
+	BackupFrequency           int64           `yaml:"backupFrequency"`           // How frequently to snapshot the database, in minutes
+	BackupKeepDaily           *int            `yaml:"backupKeepDaily"`           // How many days to keep a snapshot for, 0 to keep none
+	BackupKeepWeekly          *int            `yaml:"backupKeepWeekly"`          // How many weeks to keep a snapshot for, 0 to keep none
+	VaultReminderHours        int             `yaml:"vaultReminderHours"`        // Hours before the weekly reset to remind characters with no runs, 0 to disable
+	GuildName                 string          `yaml:"guildName"`                 // The home guild to show rankings for, leave empty to disable
+	GuildRealm                string          `yaml:"guildRealm"`                // The home guild's realm slug
+	DiscordGuildID            string          `yaml:"discordGuildId"`            // The Discord server to grant milestone roles in
+	AdminUserIDs              []string        `yaml:"adminUserIds"`              // Discord users who can unlink characters linked to someone else
+	Milestones                []Milestone     `yaml:"milestones"`                // Scores to celebrate, leave empty to disable
+	Templates                 Templates       `yaml:"templates"`                 // Template files to customise score updates with
+	LeaderboardExpiry         int64           `yaml:"leaderboardExpiry"`         // How long the scores leaderboard's page buttons work for, in minutes
+	AnnouncementChannelID     string          `yaml:"announcementChannelId"`     // Where to post announcements, leave empty to use DiscordChannelID
+	Webhooks                  []Webhook       `yaml:"webhooks"`                  // Channels to post through a webhook instead of as the bot
+	NotifyWebhooks            []NotifyWebhook `yaml:"notifyWebhooks"`            // Endpoints to send score change events to as JSON, e.g. for Slack or Matrix
+	Threads                   string          `yaml:"threads"`                   // Post score updates in a thread per "character" or per linked "owner", leave empty to post in the channel
+	LiveLeaderboardChannelIDs []string        `yaml:"liveLeaderboardChannelIds"` // Channels to keep a pinned leaderboard in, edited after every update
+}
+
+// Templates are paths to text/template files for each part of a score update, leave one empty to keep the default.
+//
+// The templates are rendered with discord.TemplateData.
+type Templates struct {
+	Content     string `yaml:"content"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Footer      string `yaml:"footer"`
+}
+
+// Webhook posts a channel's messages through a Discord webhook, so the bot doesn't need to be able to send in it.
+//
+// The channel doesn't have to be in a server the bot is in, its ID is only used to pick the webhook.
+type Webhook struct {
+	ChannelID string `yaml:"channelId"`
+	URL       string `yaml:"url"`
+	Username  string `yaml:"username"`  // Who to post messages that aren't about a character as, leave empty for the webhook's name
+	AvatarURL string `yaml:"avatarUrl"` // The avatar for those messages, leave empty for the webhook's avatar
+}
+
+// NotifyWebhook is an endpoint outside Discord that score change events are POSTed to.
+type NotifyWebhook struct {
+	URL         string            `yaml:"url"`
+	Headers     map[string]string `yaml:"headers"`     // Extra request headers, e.g. Authorization
+	Secret      string            `yaml:"secret"`      // Signs requests with HMAC-SHA256, leave empty to not sign
+	MaxAttempts int               `yaml:"maxAttempts"` // How many times to try sending an event, 0 for the default of 3
+}
+
+// Milestone is a score that gets announced when a character passes it, optionally granting a Discord role.
+type Milestone struct {
+	Name        string  `yaml:"name"`
+	Score       float64 `yaml:"score"`
+	TitleCutoff bool    `yaml:"titleCutoff"` // Use the season's title cutoff instead of Score
+	RoleID      string  `yaml:"roleId"`      // The role to grant linked users, leave empty to only announce
 }
 
 const (
-	defaultConfigPath       = "./config.yml"
-	defaultDatabaseLocation = "mythicplusdiscordbot.sqlite"
-	defaultUpdaterFrequency = 30
+	defaultConfigPath        = "./config.yml"
+	defaultDatabaseDriver    = "sqlite3"
+	defaultDatabaseLocation  = "mythicplusdiscordbot.sqlite"
+	defaultUpdaterFrequency  = 30
+	defaultBackupFrequency   = 24 * 60
+	defaultBackupKeepDaily   = 7
+	defaultBackupKeepWeekly  = 4
+	defaultLeaderboardExpiry = 15
 )
 
 // defaultConfig provides some normal defaults for config values that are optional.
 var defaultConfig = Config{
-	DatabaseLocation: defaultDatabaseLocation,
-	UpdaterFrequency: defaultUpdaterFrequency,
+	DatabaseDriver:    defaultDatabaseDriver,
+	DatabaseLocation:  defaultDatabaseLocation,
+	UpdaterFrequency:  defaultUpdaterFrequency,
+	BackupFrequency:   defaultBackupFrequency,
+	BackupKeepDaily:   intPtr(defaultBackupKeepDaily),
+	BackupKeepWeekly:  intPtr(defaultBackupKeepWeekly),
+	LeaderboardExpiry: defaultLeaderboardExpiry,
 }
 
 var config Config
 
 // merge copies values from the passed in Config.
 //
-// Note 0 is a valid value for Config.LogLevel, so we don't merge that attribute.
+// Note 0 is a valid value for Config.LogLevel, so we don't merge that attribute. It is also valid for the backup
+// retention, so those are only merged when they aren't set.
 func (c *Config) merge(cfg Config) {
 	if c.BlizzardClientID == "" {
 		c.BlizzardClientID = cfg.BlizzardClientID
@@ -43,18 +110,96 @@ func (c *Config) merge(cfg Config) {
 	if c.BlizzardClientSecret == "" {
 		c.BlizzardClientSecret = cfg.BlizzardClientSecret
 	}
+	if c.BlizzardCacheFile == "" {
+		c.BlizzardCacheFile = cfg.BlizzardCacheFile
+	}
 	if c.DiscordToken == "" {
 		c.DiscordToken = cfg.DiscordToken
 	}
 	if c.DiscordChannelID == "" {
 		c.DiscordChannelID = cfg.DiscordChannelID
 	}
+	if c.DatabaseDriver == "" {
+		c.DatabaseDriver = cfg.DatabaseDriver
+	}
 	if c.DatabaseLocation == "" {
 		c.DatabaseLocation = cfg.DatabaseLocation
 	}
+	if c.DatabaseURL == "" {
+		c.DatabaseURL = cfg.DatabaseURL
+	}
 	if c.UpdaterFrequency == 0 {
 		c.UpdaterFrequency = cfg.UpdaterFrequency
 	}
+	if c.BackupDirectory == "" {
+		c.BackupDirectory = cfg.BackupDirectory
+	}
+	if c.BackupFrequency == 0 {
+		c.BackupFrequency = cfg.BackupFrequency
+	}
+	if c.BackupKeepDaily == nil {
+		c.BackupKeepDaily = cfg.BackupKeepDaily
+	}
+	if c.BackupKeepWeekly == nil {
+		c.BackupKeepWeekly = cfg.BackupKeepWeekly
+	}
+	if c.VaultReminderHours == 0 {
+		c.VaultReminderHours = cfg.VaultReminderHours
+	}
+	if c.GuildName == "" {
+		c.GuildName = cfg.GuildName
+	}
+	if c.GuildRealm == "" {
+		c.GuildRealm = cfg.GuildRealm
+	}
+	if c.DiscordGuildID == "" {
+		c.DiscordGuildID = cfg.DiscordGuildID
+	}
+	if len(c.Milestones) == 0 {
+		c.Milestones = cfg.Milestones
+	}
+	if c.Templates == (Templates{}) {
+		c.Templates = cfg.Templates
+	}
+	if c.LeaderboardExpiry == 0 {
+		c.LeaderboardExpiry = cfg.LeaderboardExpiry
+	}
+	if c.AnnouncementChannelID == "" {
+		c.AnnouncementChannelID = cfg.AnnouncementChannelID
+	}
+	if len(c.Webhooks) == 0 {
+		c.Webhooks = cfg.Webhooks
+	}
+	if len(c.NotifyWebhooks) == 0 {
+		c.NotifyWebhooks = cfg.NotifyWebhooks
+	}
+	if c.Threads == "" {
+		c.Threads = cfg.Threads
+	}
+	if len(c.LiveLeaderboardChannelIDs) == 0 {
+		c.LiveLeaderboardChannelIDs = cfg.LiveLeaderboardChannelIDs
+	}
+}
+
+// validate checks the values that would stop the bot once it is running, e.g. a ticker can't run every -1 minutes.
+func (c *Config) validate() error {
+	if c.UpdaterFrequency <= 0 {
+		return fmt.Errorf("updaterFrequency must be more than 0, got %d", c.UpdaterFrequency)
+	}
+	if c.BackupFrequency <= 0 {
+		return fmt.Errorf("backupFrequency must be more than 0, got %d", c.BackupFrequency)
+	}
+	if *c.BackupKeepDaily < 0 {
+		return fmt.Errorf("backupKeepDaily can't be negative, got %d", *c.BackupKeepDaily)
+	}
+	if *c.BackupKeepWeekly < 0 {
+		return fmt.Errorf("backupKeepWeekly can't be negative, got %d", *c.BackupKeepWeekly)
+	}
+	return nil
+}
+
+func intPtr(i int) *int {
+	return &i
 }
 
 func LoadFs(fs afero.Fs) (Config, error) {
@@ -73,6 +218,9 @@ func LoadFs(fs afero.Fs) (Config, error) {
 	}
 
 	cfg.merge(defaultConfig)
+	if err := cfg.validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config: %w", err)
+	}
 	config = cfg
 
 	return cfg, nil
@@ -19,19 +19,90 @@ func TestLoadFs_Success(t *testing.T) {
 			name: "valid config with all fields",
 			yaml: `blizzardClientId: test-client-id
 blizzardClientSecret: test-client-secret
+blizzardCacheFile: /path/to/cache.json
 discordToken: test-discord-token
 discordChannelId: test-channel-id
+databaseDriver: postgres
 databaseLocation: /path/to/db.sqlite
+databaseURL: postgres://bot@localhost/mythicplusbot
 logLevel: 2
-updaterFrequency: 60`,
+updaterFrequency: 60
+backupDirectory: /path/to/backups
+backupFrequency: 720
+backupKeepDaily: 3
+backupKeepWeekly: 2
+vaultReminderHours: 12
+guildName: Test Guild
+guildRealm: tichondrius
+discordGuildId: test-guild-id
+milestones:
+  - name: Keystone Hero
+    score: 2500
+    roleId: hero-role
+  - name: Title
+    titleCutoff: true
+templates:
+  content: /path/to/content.tmpl
+  description: /path/to/description.tmpl
+leaderboardExpiry: 60
+announcementChannelId: announce-channel-id
+webhooks:
+  - channelId: webhook-channel-id
+    url: https://discord.com/api/webhooks/123/token
+    username: Mythic+
+    avatarUrl: https://example.com/avatar.png
+notifyWebhooks:
+  - url: https://example.com/events
+    headers:
+      Authorization: Bearer token
+    secret: shh
+    maxAttempts: 5
+threads: owner
+liveLeaderboardChannelIds:
+  - leaderboard-channel-id`,
 			expected: Config{
 				BlizzardClientID:     "test-client-id",
 				BlizzardClientSecret: "test-client-secret",
+				BlizzardCacheFile:    "/path/to/cache.json",
 				DiscordToken:         "test-discord-token",
 				DiscordChannelID:     "test-channel-id",
+				DatabaseDriver:       "postgres",
 				DatabaseLocation:     "/path/to/db.sqlite",
+				DatabaseURL:          "postgres://bot@localhost/mythicplusbot",
 				LogLevel:             2,
 				UpdaterFrequency:     60,
+				BackupDirectory:      "/path/to/backups",
+				BackupFrequency:      720,
+				BackupKeepDaily:      intPtr(3),
+				BackupKeepWeekly:     intPtr(2),
+				VaultReminderHours:   12,
+				GuildName:            "Test Guild",
+				GuildRealm:           "tichondrius",
+				DiscordGuildID:       "test-guild-id",
+				Milestones: []Milestone{
+					{Name: "Keystone Hero", Score: 2500, RoleID: "hero-role"},
+					{Name: "Title", TitleCutoff: true},
+				},
+				Templates: Templates{
+					Content:     "/path/to/content.tmpl",
+					Description: "/path/to/description.tmpl",
+				},
+				LeaderboardExpiry:     60,
+				AnnouncementChannelID: "announce-channel-id",
+				Webhooks: []Webhook{{
+					ChannelID: "webhook-channel-id",
+					URL:       "https://discord.com/api/webhooks/123/token",
+					Username:  "Mythic+",
+					AvatarURL: "https://example.com/avatar.png",
+				}},
+				NotifyWebhooks: []NotifyWebhook{{
+					URL:         "https://example.com/events",
+					Headers:     map[string]string{"Authorization": "Bearer token"},
+					Secret:      "shh",
+					MaxAttempts: 5,
+				}},
+				Threads:                   "owner",
+				LiveLeaderboardChannelIDs: []string{"leaderboard-channel-id"},
 			},
 		},
 		{
@@ -45,9 +116,14 @@ discordChannelId: minimal-channel`,
 				BlizzardClientSecret: "minimal-secret",
 				DiscordToken:         "minimal-token",
 				DiscordChannelID:     "minimal-channel",
+				DatabaseDriver:       "sqlite3",                     // default applied
 				DatabaseLocation:     "mythicplusdiscordbot.sqlite", // default applied
 				LogLevel:             0,
-				UpdaterFrequency:     30, // default applied
+				UpdaterFrequency:     30,        // default applied
+				BackupFrequency:      1440,      // default applied
+				BackupKeepDaily:      intPtr(7), // default applied
+				BackupKeepWeekly:     intPtr(4), // default applied
+				LeaderboardExpiry:    15,        // default applied
 			},
 		},
 	}
@@ -177,6 +253,45 @@ logLevel: 3`
 	assert.Equal(t, 3, cfg.LogLevel)
 }
 
+func TestLoadFs_KeepNoBackups(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	err := afero.WriteFile(fs, "./config.yml", []byte("backupKeepDaily: 0\nbackupKeepWeekly: 0"), 0644)
+	require.NoError(t, err)
+
+	cfg, err := LoadFs(fs)
+
+	// 0 keeps none rather than being replaced by the default
+	require.NoError(t, err)
+	assert.Equal(t, 0, *cfg.BackupKeepDaily)
+	assert.Equal(t, 0, *cfg.BackupKeepWeekly)
+}
+
+func TestLoadFs_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		err  string
+	}{
+		{name: "negative updater frequency", yaml: "updaterFrequency: -5", err: "updaterFrequency must be more than 0"},
+		{name: "negative backup frequency", yaml: "backupFrequency: -1", err: "backupFrequency must be more than 0"},
+		{name: "negative daily backups", yaml: "backupKeepDaily: -1", err: "backupKeepDaily can't be negative"},
+		{name: "negative weekly backups", yaml: "backupKeepWeekly: -2", err: "backupKeepWeekly can't be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			err := afero.WriteFile(fs, "./config.yml", []byte(tt.yaml), 0644)
+			require.NoError(t, err)
+
+			cfg, err := LoadFs(fs)
+
+			assert.ErrorContains(t, err, tt.err)
+			assert.Equal(t, Config{}, cfg)
+		})
+	}
+}
+
 func TestLoadFs_EmptyFile(t *testing.T) {
 	fs := afero.NewMemMapFs()
 	err := afero.WriteFile(fs, "./config.yml", []byte(""), 0644)
@@ -187,8 +302,13 @@ func TestLoadFs_EmptyFile(t *testing.T) {
 	// Empty file should parse as empty config with defaults applied
 	require.NoError(t, err)
 	expected := Config{
-		DatabaseLocation: "mythicplusdiscordbot.sqlite", // default applied
-		UpdaterFrequency: 30,                            // default applied
+		DatabaseDriver:    "sqlite3",                     // default applied
+		DatabaseLocation:  "mythicplusdiscordbot.sqlite", // default applied
+		UpdaterFrequency:  30,                            // default applied
+		BackupFrequency:   1440,                          // default applied
+		BackupKeepDaily:   intPtr(7),                     // default applied
+		BackupKeepWeekly:  intPtr(4),                     // default applied
+		LeaderboardExpiry: 15,                            // default applied
 	}
 	assert.Equal(t, expected, cfg)
 }
@@ -301,10 +421,15 @@ func TestConfig_Merge(t *testing.T) {
 			},
 			merge: defaultConfig,
 			expected: Config{
-				BlizzardClientID: "test-id",
-				DiscordToken:     "test-token",
-				DatabaseLocation: "mythicplusdiscordbot.sqlite",
-				UpdaterFrequency: 30,
+				BlizzardClientID:  "test-id",
+				DiscordToken:      "test-token",
+				DatabaseDriver:    "sqlite3",
+				DatabaseLocation:  "mythicplusdiscordbot.sqlite",
+				UpdaterFrequency:  30,
+				BackupFrequency:   1440,
+				BackupKeepDaily:   intPtr(7),
+				BackupKeepWeekly:  intPtr(4),
+				LeaderboardExpiry: 15,
 			},
 		},
 	}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// RunKind is which of a character's Raider.IO run lists a run came from.
+type RunKind string
+
+const (
+	RunKindBest      RunKind = "best"
+	RunKindAlternate RunKind = "alternate"
+	RunKindRecent    RunKind = "recent"
+)
+
+// CharacterRun is a run from a character's Raider.IO profile.
+type CharacterRun struct {
+	CharacterID         int     `json:"character_id"`
+	Kind                RunKind `json:"kind"`
+	KeystoneRunID       int     `json:"keystone_run_id"`
+	Dungeon             string  `json:"dungeon"`
+	ShortName           string  `json:"short_name"`
+	MythicLevel         int     `json:"mythic_level"`
+	NumKeystoneUpgrades int     `json:"num_keystone_upgrades"`
+	ClearTimeMs         int64   `json:"clear_time_ms"`
+	ParTimeMs           int64   `json:"par_time_ms"`
+	Score               float64 `json:"score"`
+	CompletedAt         int64   `json:"completed_at"`
+	URL                 string  `json:"url"`
+}
+
+const (
+	characterRunColumns = `character_id, kind, keystone_run_id, dungeon, short_name, mythic_level,
+		num_keystone_upgrades, clear_time_ms, par_time_ms, score, completed_at, url`
+
+	deleteCharacterRunsQuery = `DELETE FROM character_runs WHERE character_id = ?`
+
+	insertCharacterRunQuery = `INSERT INTO character_runs (` + characterRunColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	// Best runs are ordered by score, recent runs by when they were completed
+	listCharacterRunsQuery = `SELECT ` + characterRunColumns + ` FROM character_runs
+		WHERE character_id = ? AND kind = ? ORDER BY %s DESC`
+)
+
+// CharacterRunRepo implements CharacterRunRepository interface
+type CharacterRunRepo struct {
+	db Database
+}
+
+// NewCharacterRunRepo creates a new character run repository
+func NewCharacterRunRepo(db Database) *CharacterRunRepo {
+	return &CharacterRunRepo{db: db}
+}
+
+// Replace swaps the character's stored runs for the given runs.
+func (r *CharacterRunRepo) Replace(ctx context.Context, characterID int, runs []CharacterRun) error {
+	return r.db.WithTx(ctx, func(tx Database) error {
+		if err := tx.Query(ctx, deleteCharacterRunsQuery, characterID); err != nil {
+			return err
+		}
+
+		for _, run := range runs {
+			if err := tx.Query(ctx, insertCharacterRunQuery, characterID, run.Kind, run.KeystoneRunID, run.Dungeon,
+				run.ShortName, run.MythicLevel, run.NumKeystoneUpgrades, run.ClearTimeMs, run.ParTimeMs, run.Score,
+				run.CompletedAt, run.URL); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListForCharacter returns the character's runs of the given kind, recent runs newest first and the rest by score.
+func (r *CharacterRunRepo) ListForCharacter(ctx context.Context, characterID int, kind RunKind) ([]CharacterRun, error) {
+	order := "score"
+	if kind == RunKindRecent {
+		order = "completed_at"
+	}
+
+	rows, err := r.db.QueryRows(ctx, fmt.Sprintf(listCharacterRunsQuery, order), characterID, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []CharacterRun
+	for rows.Next() {
+		var run CharacterRun
+		if err := rows.Scan(&run.CharacterID, &run.Kind, &run.KeystoneRunID, &run.Dungeon, &run.ShortName,
+			&run.MythicLevel, &run.NumKeystoneUpgrades, &run.ClearTimeMs, &run.ParTimeMs, &run.Score,
+			&run.CompletedAt, &run.URL); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
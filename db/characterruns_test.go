@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCharacterRunRepo_Replace(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewCharacterRunRepo(mockDB)
+	ctx := context.Background()
+
+	run := CharacterRun{Kind: RunKindBest, KeystoneRunID: 100, Dungeon: "The Stonevault", ShortName: "SV",
+		MythicLevel: 12, NumKeystoneUpgrades: 2, ClearTimeMs: 1500000, ParTimeMs: 1980000, Score: 310.5, CompletedAt: 1000}
+
+	mockDB.On("WithTx", ctx).Return(nil)
+	mockDB.On("Query", ctx, deleteCharacterRunsQuery, []interface{}{1}).Return(nil)
+	mockDB.On("Query", ctx, insertCharacterRunQuery,
+		mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 12 &&
+				args[0] == 1 &&
+				args[1] == RunKindBest &&
+				args[2] == 100 &&
+				args[3] == "The Stonevault" &&
+				args[5] == 12 &&
+				args[6] == 2 &&
+				args[9] == 310.5
+		})).Return(nil)
+
+	err := repo.Replace(ctx, 1, []CharacterRun{run})
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestCharacterRunRepo_Replace_DeleteError(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewCharacterRunRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("WithTx", ctx).Return(nil)
+	mockDB.On("Query", ctx, deleteCharacterRunsQuery, []interface{}{1}).Return(errors.New("mock error"))
+
+	err := repo.Replace(ctx, 1, []CharacterRun{{Kind: RunKindBest}})
+	assert.Error(t, err)
+	mockDB.AssertNotCalled(t, "Query", ctx, insertCharacterRunQuery, mock.Anything)
+}
+
+func TestCharacterRunRepo_ListForCharacter_RecentOrder(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewCharacterRunRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("QueryRows", ctx, fmt.Sprintf(listCharacterRunsQuery, "completed_at"),
+		[]interface{}{1, RunKindRecent}).Return((*sql.Rows)(nil), errors.New("mock error"))
+
+	runs, err := repo.ListForCharacter(ctx, 1, RunKindRecent)
+	assert.Error(t, err)
+	assert.Nil(t, runs)
+	mockDB.AssertExpectations(t)
+}
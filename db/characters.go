@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 )
 
@@ -16,18 +17,58 @@ type Character struct {
 	HealScore    float64 `json:"heal_score"`
 	DateUpdated  int64   `json:"date_updated"`
 	DateCreated  int64   `json:"date_created"`
+
+	// Profile details from the Blizzard character profile API
+	Level             int    `json:"level"`
+	Spec              string `json:"spec"`
+	Guild             string `json:"guild"`
+	Faction           string `json:"faction"`
+	ItemLevel         int    `json:"item_level"`
+	EquippedItemLevel int    `json:"equipped_item_level"`
+
+	// SpecScores are kept in their own table, they are only filled in where they are shown
+	SpecScores []SpecScore `json:"-"`
+}
+
+// deleteCharacterDataQueries remove everything stored against a character, taking its name and realm. Characters keep
+// their ID if they are added again, so nothing may be left behind for them to inherit.
+var deleteCharacterDataQueries = []string{
+	`DELETE FROM score_history WHERE character_id IN (` + characterIDQuery + `)`,
+	`DELETE FROM season_ratings WHERE character_id IN (` + characterIDQuery + `)`,
+	`DELETE FROM dungeon_runs WHERE character_id IN (` + characterIDQuery + `)`,
+	`DELETE FROM character_runs WHERE character_id IN (` + characterIDQuery + `)`,
+	`DELETE FROM spec_scores WHERE character_id IN (` + characterIDQuery + `)`,
+	`DELETE FROM discord_links WHERE character_id IN (` + characterIDQuery + `)`,
+	`DELETE FROM announcement_threads WHERE thread_key IN (SELECT '` + characterThreadPrefix + `' || id FROM characters
+		WHERE name = ? AND realm = ?)`,
 }
 
 const (
-	getCharacterQuery = `SELECT id, name, realm, class, score, tank_score, dps_score, heal_score, date_updated, date_created FROM characters WHERE name=? AND realm=? LIMIT 1`
+	characterColumns = `id, name, realm, class, score, tank_score, dps_score, heal_score, date_updated, date_created,
+		level, spec, guild, faction, item_level, equipped_item_level`
+
+	getCharacterQuery = `SELECT ` + characterColumns + ` FROM characters WHERE name=? AND realm=? LIMIT 1`
+
+	getCharacterByIDQuery = `SELECT ` + characterColumns + ` FROM characters WHERE id=? LIMIT 1`
 
-	updateCharacterQuery = `UPDATE characters SET score = ?, tank_score = ?, dps_score = ?, heal_score = ? WHERE name = ? AND realm = ?`
+	updateCharacterQuery = `UPDATE characters SET score = ?, tank_score = ?, dps_score = ?, heal_score = ?,
+		level = ?, spec = ?, guild = ?, faction = ?, item_level = ?, equipped_item_level = ? WHERE name = ? AND realm = ?`
 
 	deleteCharacterQuery = `DELETE FROM characters WHERE name = ? AND realm = ?`
 
-	insertCharacterQuery = `INSERT INTO characters VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	// characterIDQuery selects the ID of the character with the given name and realm, for deleting their other rows.
+	characterIDQuery = `SELECT id FROM characters WHERE name = ? AND realm = ?`
 
-	listCharactersQuery = `SELECT id, name, realm, class, score, tank_score, dps_score, heal_score, date_updated, date_created FROM characters`
+	insertCharacterQuery = `INSERT INTO characters (` + characterColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	// Imports don't carry profile details, so an upsert leaves the ones we have alone.
+	upsertCharacterQuery = insertCharacterQuery + `
+		ON CONFLICT (id) DO UPDATE SET name = excluded.name, realm = excluded.realm, class = excluded.class,
+			score = excluded.score, tank_score = excluded.tank_score, dps_score = excluded.dps_score,
+			heal_score = excluded.heal_score`
+
+	listCharactersQuery = `SELECT ` + characterColumns + ` FROM characters`
 )
 
 func (c *Character) IsEmpty() bool {
@@ -45,23 +86,54 @@ func NewCharacterRepo(db Database) *CharacterRepo {
 }
 
 func (r *CharacterRepo) Insert(ctx context.Context, character *Character) error {
-	return r.db.Query(ctx, insertCharacterQuery, character.ID, character.Name, character.Realm, character.Class,
-		character.OverallScore, character.TankScore, character.DPSScore, character.HealScore, character.DateUpdated,
-		character.DateCreated)
+	return r.db.Query(ctx, insertCharacterQuery, character.insertArgs()...)
+}
+
+// Upsert inserts the character, or overwrites the stored character with the same ID.
+func (r *CharacterRepo) Upsert(ctx context.Context, character *Character) error {
+	return r.db.Query(ctx, upsertCharacterQuery, character.insertArgs()...)
+}
+
+func (c *Character) insertArgs() []any {
+	return []any{c.ID, c.Name, c.Realm, c.Class, c.OverallScore, c.TankScore, c.DPSScore, c.HealScore, c.DateUpdated,
+		c.DateCreated, c.Level, c.Spec, c.Guild, c.Faction, c.ItemLevel, c.EquippedItemLevel}
+}
+
+// scan reads a row selected with characterColumns.
+func (c *Character) scan(rows *sql.Rows) error {
+	return rows.Scan(&c.ID, &c.Name, &c.Realm, &c.Class, &c.OverallScore, &c.TankScore, &c.DPSScore, &c.HealScore,
+		&c.DateUpdated, &c.DateCreated, &c.Level, &c.Spec, &c.Guild, &c.Faction, &c.ItemLevel, &c.EquippedItemLevel)
 }
 
 func (r *CharacterRepo) Update(ctx context.Context, character *Character) error {
 	return r.db.Query(ctx, updateCharacterQuery, character.OverallScore, character.TankScore, character.DPSScore,
-		character.HealScore, character.Name, character.Realm)
+		character.HealScore, character.Level, character.Spec, character.Guild, character.Faction, character.ItemLevel,
+		character.EquippedItemLevel, character.Name, character.Realm)
 }
 
+// Delete removes the character along with their history, runs, link and thread.
 func (r *CharacterRepo) Delete(ctx context.Context, character *Character) error {
-	return r.db.Query(ctx, deleteCharacterQuery,
-		character.Name, character.Realm)
+	return r.db.WithTx(ctx, func(tx Database) error {
+		for _, query := range deleteCharacterDataQueries {
+			if err := tx.Query(ctx, query, character.Name, character.Realm); err != nil {
+				return err
+			}
+		}
+		return tx.Query(ctx, deleteCharacterQuery, character.Name, character.Realm)
+	})
 }
 
 func (r *CharacterRepo) GetCharacter(ctx context.Context, name, realm string) (Character, error) {
-	rows, err := r.db.QueryRows(ctx, getCharacterQuery, name, realm)
+	return r.getCharacter(ctx, getCharacterQuery, name, realm)
+}
+
+// GetCharacterByID returns the character with the given Blizzard character ID.
+func (r *CharacterRepo) GetCharacterByID(ctx context.Context, id int) (Character, error) {
+	return r.getCharacter(ctx, getCharacterByIDQuery, id)
+}
+
+func (r *CharacterRepo) getCharacter(ctx context.Context, query string, args ...any) (Character, error) {
+	rows, err := r.db.QueryRows(ctx, query, args...)
 	if err != nil {
 		return Character{}, err
 	}
@@ -73,8 +145,7 @@ func (r *CharacterRepo) GetCharacter(ctx context.Context, name, realm string) (C
 
 	if rows.Next() {
 		var c Character
-		if err := rows.Scan(&c.ID, &c.Name, &c.Realm, &c.Class, &c.OverallScore, &c.TankScore, &c.DPSScore, &c.HealScore,
-			&c.DateUpdated, &c.DateCreated); err != nil {
+		if err := c.scan(rows); err != nil {
 			return c, err
 		}
 		return c, nil
@@ -113,8 +184,7 @@ func (r *CharacterRepo) ListCharacters(ctx context.Context, limit int) ([]Charac
 	var characters []Character
 	for rows.Next() {
 		var c Character
-		if err := rows.Scan(&c.ID, &c.Name, &c.Realm, &c.Class, &c.OverallScore, &c.TankScore, &c.DPSScore, &c.HealScore,
-			&c.DateUpdated, &c.DateCreated); err != nil {
+		if err := c.scan(rows); err != nil {
 			return nil, err
 		}
 		characters = append(characters, c)
@@ -122,3 +192,10 @@ func (r *CharacterRepo) ListCharacters(ctx context.Context, limit int) ([]Charac
 
 	return characters, rows.Err()
 }
+
+// WithTx runs fn with a repository that writes in a transaction, see Database.WithTx.
+func (r *CharacterRepo) WithTx(ctx context.Context, fn func(repo CharacterRepository) error) error {
+	return r.db.WithTx(ctx, func(tx Database) error {
+		return fn(NewCharacterRepo(tx))
+	})
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCharacterRepo_Insert(t *testing.T) {
@@ -28,9 +30,9 @@ func TestCharacterRepo_Insert(t *testing.T) {
 		DateCreated:  1234567890,
 	}
 
-	mockDB.On("Query", ctx, "INSERT INTO characters VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+	mockDB.On("Query", ctx, insertCharacterQuery,
 		mock.MatchedBy(func(args []interface{}) bool {
-			return len(args) == 10 &&
+			return len(args) == 16 &&
 				args[0] == 1 &&
 				args[1] == "testchar" &&
 				args[2] == "testrealm" &&
@@ -48,29 +50,77 @@ func TestCharacterRepo_Insert(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
-func TestCharacterRepo_Update(t *testing.T) {
+func TestCharacterRepo_Upsert(t *testing.T) {
 	mockDB := &MockDatabase{}
 	repo := NewCharacterRepo(mockDB)
 	ctx := context.Background()
 
 	character := &Character{
+		ID:           1,
 		Name:         "testchar",
 		Realm:        "testrealm",
-		OverallScore: 2600.0,
-		TankScore:    2500.0,
-		DPSScore:     2400.0,
+		Class:        "warrior",
+		OverallScore: 2500.5,
+		TankScore:    2400.0,
+		DPSScore:     2300.0,
 		HealScore:    0.0,
+		DateUpdated:  1234567890,
+		DateCreated:  1234567890,
 	}
 
-	mockDB.On("Query", ctx, "UPDATE characters SET score = ?, tank_score = ?, dps_score = ?, heal_score = ? WHERE name = ? AND realm = ?",
+	mockDB.On("Query", ctx, upsertCharacterQuery,
 		mock.MatchedBy(func(args []interface{}) bool {
-			return len(args) == 6 &&
+			return len(args) == 16 &&
+				args[0] == 1 &&
+				args[1] == "testchar" &&
+				args[2] == "testrealm" &&
+				args[3] == "warrior" &&
+				args[4] == 2500.5 &&
+				args[5] == 2400.0 &&
+				args[6] == 2300.0 &&
+				args[7] == 0.0
+		})).Return(nil)
+
+	err := repo.Upsert(ctx, character)
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestCharacterRepo_Update(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewCharacterRepo(mockDB)
+	ctx := context.Background()
+
+	character := &Character{
+		Name:              "testchar",
+		Realm:             "testrealm",
+		OverallScore:      2600.0,
+		TankScore:         2500.0,
+		DPSScore:          2400.0,
+		HealScore:         0.0,
+		Level:             80,
+		Spec:              "Protection",
+		Guild:             "Method",
+		Faction:           "Horde",
+		ItemLevel:         620,
+		EquippedItemLevel: 618,
+	}
+
+	mockDB.On("Query", ctx, updateCharacterQuery,
+		mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 12 &&
 				args[0] == 2600.0 &&
 				args[1] == 2500.0 &&
 				args[2] == 2400.0 &&
 				args[3] == 0.0 &&
-				args[4] == "testchar" &&
-				args[5] == "testrealm"
+				args[4] == 80 &&
+				args[5] == "Protection" &&
+				args[6] == "Method" &&
+				args[7] == "Horde" &&
+				args[8] == 620 &&
+				args[9] == 618 &&
+				args[10] == "testchar" &&
+				args[11] == "testrealm"
 		})).Return(nil)
 
 	err := repo.Update(ctx, character)
@@ -88,6 +138,11 @@ func TestCharacterRepo_Delete(t *testing.T) {
 		Realm: "testrealm",
 	}
 
+	// Everything stored against the character goes in the same transaction as the character
+	mockDB.On("WithTx", ctx).Return(nil)
+	for _, query := range deleteCharacterDataQueries {
+		mockDB.On("Query", ctx, query, []interface{}{"testchar", "testrealm"}).Return(nil).Once()
+	}
 	mockDB.On("Query", ctx, "DELETE FROM characters WHERE name = ? AND realm = ?",
 		mock.MatchedBy(func(args []interface{}) bool {
 			return len(args) == 2 &&
@@ -100,13 +155,28 @@ func TestCharacterRepo_Delete(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestCharacterRepo_Delete_Error(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewCharacterRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("WithTx", ctx).Return(nil)
+	mockDB.On("Query", ctx, deleteCharacterDataQueries[0], []interface{}{"testchar", "testrealm"}).
+		Return(errors.New("db error"))
+
+	err := repo.Delete(ctx, &Character{Name: "testchar", Realm: "testrealm"})
+
+	assert.EqualError(t, err, "db error")
+	mockDB.AssertNotCalled(t, "Query", ctx, deleteCharacterQuery, mock.Anything)
+}
+
 func TestCharacterRepo_GetCharacter_Found(t *testing.T) {
 	mockDB := &MockDatabase{}
 	repo := NewCharacterRepo(mockDB)
 	ctx := context.Background()
 
 	// Test the error case since mocking sql.Rows is complex
-	mockDB.On("QueryRows", ctx, "SELECT id, name, realm, class, score, tank_score, dps_score, heal_score, date_updated, date_created FROM characters WHERE name=? AND realm=? LIMIT 1",
+	mockDB.On("QueryRows", ctx, getCharacterQuery,
 		mock.MatchedBy(func(args []interface{}) bool {
 			return len(args) == 2 && args[0] == "testchar" && args[1] == "testrealm"
 		})).Return((*sql.Rows)(nil), errors.New("mock error"))
@@ -117,6 +187,22 @@ func TestCharacterRepo_GetCharacter_Found(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestCharacterRepo_GetCharacterByID(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewCharacterRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("QueryRows", ctx, getCharacterByIDQuery,
+		mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 1 && args[0] == 42
+		})).Return((*sql.Rows)(nil), errors.New("mock error"))
+
+	character, err := repo.GetCharacterByID(ctx, 42)
+	assert.Error(t, err)
+	assert.Equal(t, Character{}, character)
+	mockDB.AssertExpectations(t)
+}
+
 func TestCharacterRepo_CheckCharacterExists(t *testing.T) {
 	mockDB := &MockDatabase{}
 	repo := NewCharacterRepo(mockDB)
@@ -138,7 +224,7 @@ func TestCharacterRepo_ListCharacters_WithLimit(t *testing.T) {
 	repo := NewCharacterRepo(mockDB)
 	ctx := context.Background()
 
-	expectedQuery := "SELECT id, name, realm, class, score, tank_score, dps_score, heal_score, date_updated, date_created FROM characters ORDER BY score DESC LIMIT 10"
+	expectedQuery := listCharactersQuery + " ORDER BY score DESC LIMIT 10"
 	mockDB.On("QueryRows", ctx, expectedQuery, []interface{}(nil)).Return((*sql.Rows)(nil), errors.New("mock error"))
 
 	characters, err := repo.ListCharacters(ctx, 10)
@@ -152,7 +238,7 @@ func TestCharacterRepo_ListCharacters_NoLimit(t *testing.T) {
 	repo := NewCharacterRepo(mockDB)
 	ctx := context.Background()
 
-	expectedQuery := "SELECT id, name, realm, class, score, tank_score, dps_score, heal_score, date_updated, date_created FROM characters ORDER BY score DESC"
+	expectedQuery := listCharactersQuery + " ORDER BY score DESC"
 	mockDB.On("QueryRows", ctx, expectedQuery, []interface{}(nil)).Return((*sql.Rows)(nil), errors.New("mock error"))
 
 	characters, err := repo.ListCharacters(ctx, 0)
@@ -161,6 +247,21 @@ func TestCharacterRepo_ListCharacters_NoLimit(t *testing.T) {
 	mockDB.AssertExpectations(t)
 }
 
+func TestCharacterRepo_WithTx(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewCharacterRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("WithTx", ctx).Return(nil)
+	mockDB.On("Query", ctx, updateCharacterQuery, mock.Anything).Return(nil)
+
+	err := repo.WithTx(ctx, func(repo CharacterRepository) error {
+		return repo.Update(ctx, &Character{Name: "testchar", Realm: "testrealm"})
+	})
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
+
 // Test SQLiteDB implementation
 
 func TestSQLiteDB_Query_NilDB(t *testing.T) {
@@ -180,6 +281,30 @@ func TestSQLiteDB_QueryRows_NilDB(t *testing.T) {
 	assert.Nil(t, rows)
 }
 
+func TestSQLiteDB_CheckIntegrity_NilDB(t *testing.T) {
+	db := &SQLiteDB{db: nil}
+
+	err := db.CheckIntegrity(context.Background())
+	assert.ErrorIs(t, err, ErrNoDatabase)
+}
+
+func TestSQLiteDB_CheckIntegrity(t *testing.T) {
+	db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "test.sqlite"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	require.NoError(t, db.Init(ctx))
+	assert.NoError(t, db.CheckIntegrity(ctx))
+}
+
+func TestSQLiteDB_WithTx_NilDB(t *testing.T) {
+	db := &SQLiteDB{db: nil}
+
+	err := db.WithTx(context.Background(), func(Database) error { return nil })
+	assert.ErrorIs(t, err, ErrNoDatabase)
+}
+
 func TestSQLiteDB_Close_NilDB(t *testing.T) {
 	db := &SQLiteDB{db: nil}
 	err := db.Close()
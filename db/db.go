@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
 
 	// import sqlite.
 	_ "github.com/mattn/go-sqlite3"
@@ -30,28 +32,205 @@ const (
 		BEGIN
 			UPDATE characters SET date_updated = unixepoch() WHERE id = OLD.id;
 		END;`
+
+	createScoreHistoryTableSQL = `CREATE TABLE IF NOT EXISTS score_history (
+		character_id INTEGER NOT NULL,
+		score REAL NOT NULL,
+		tank_score REAL NOT NULL,
+		heal_score REAL NOT NULL,
+		dps_score REAL NOT NULL,
+		date_recorded INTEGER DEFAULT (unixepoch()),
+		PRIMARY KEY (character_id, date_recorded)
+	);`
+
+	createSeasonRatingsTableSQL = `CREATE TABLE IF NOT EXISTS season_ratings (
+		character_id INTEGER NOT NULL,
+		season_id INTEGER NOT NULL,
+		rating REAL NOT NULL,
+		date_recorded INTEGER NOT NULL,
+		PRIMARY KEY (character_id, season_id)
+	);`
+
+	createDungeonRunsTableSQL = `CREATE TABLE IF NOT EXISTS dungeon_runs (
+		character_id INTEGER NOT NULL,
+		season_id INTEGER NOT NULL,
+		dungeon_id INTEGER NOT NULL,
+		dungeon_name TEXT NOT NULL,
+		keystone_level INTEGER NOT NULL,
+		map_rating REAL NOT NULL,
+		duration INTEGER NOT NULL,
+		timed BOOLEAN NOT NULL,
+		completed_timestamp INTEGER NOT NULL,
+		PRIMARY KEY (character_id, season_id, dungeon_id)
+	);`
+
+	createCharacterRunsTableSQL = `CREATE TABLE IF NOT EXISTS character_runs (
+		character_id INTEGER NOT NULL,
+		kind TEXT NOT NULL,
+		keystone_run_id INTEGER NOT NULL,
+		dungeon TEXT NOT NULL,
+		short_name TEXT NOT NULL,
+		mythic_level INTEGER NOT NULL,
+		num_keystone_upgrades INTEGER NOT NULL,
+		clear_time_ms INTEGER NOT NULL,
+		par_time_ms INTEGER NOT NULL,
+		score REAL NOT NULL,
+		completed_at INTEGER NOT NULL,
+		url TEXT NOT NULL,
+		PRIMARY KEY (character_id, kind, keystone_run_id)
+	);`
+
+	createSpecScoresTableSQL = `CREATE TABLE IF NOT EXISTS spec_scores (
+		character_id INTEGER NOT NULL,
+		spec TEXT NOT NULL,
+		score REAL NOT NULL,
+		PRIMARY KEY (character_id, spec)
+	);`
+
+	createGuildRanksTableSQL = `CREATE TABLE IF NOT EXISTS guild_ranks (
+		guild TEXT NOT NULL,
+		realm TEXT NOT NULL,
+		world_rank INTEGER NOT NULL,
+		region_rank INTEGER NOT NULL,
+		realm_rank INTEGER NOT NULL,
+		date_recorded INTEGER NOT NULL,
+		PRIMARY KEY (guild, realm, date_recorded)
+	);`
+
+	createDiscordLinksTableSQL = `CREATE TABLE IF NOT EXISTS discord_links (
+		character_id INTEGER PRIMARY KEY,
+		discord_user_id TEXT NOT NULL
+	);`
+
+	createGuildSettingsTableSQL = `CREATE TABLE IF NOT EXISTS guild_settings (
+		guild_id TEXT PRIMARY KEY,
+		locale TEXT NOT NULL
+	);`
+
+	createOutboundMessagesTableSQL = `CREATE TABLE IF NOT EXISTS outbound_messages (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		channel_id TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		attempts INTEGER NOT NULL,
+		next_attempt INTEGER NOT NULL,
+		date_created INTEGER NOT NULL
+	);`
+
+	createAnnouncementThreadsTableSQL = `CREATE TABLE IF NOT EXISTS announcement_threads (
+		channel_id TEXT NOT NULL,
+		thread_key TEXT NOT NULL,
+		thread_id TEXT NOT NULL,
+		PRIMARY KEY (channel_id, thread_key)
+	);`
+
+	createLiveLeaderboardsTableSQL = `CREATE TABLE IF NOT EXISTS live_leaderboards (
+		channel_id TEXT PRIMARY KEY,
+		message_id TEXT NOT NULL
+	);`
 )
 
 var (
-	ErrOpeningFile = errors.New("error opening file")
-	ErrNoDatabase  = errors.New("db is nil")
+	ErrOpeningFile    = errors.New("error opening file")
+	ErrNoDatabase     = errors.New("db is nil")
+	ErrIntegrityCheck = errors.New("database failed integrity check")
 )
 
 // Database defines the interface for database operations
 type Database interface {
 	Query(ctx context.Context, query string, args ...any) error
 	QueryRows(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	// WithTx runs fn in a transaction, committing if it returns nil and rolling back if it returns an error.
+	// Queries must go through the Database passed to fn to be part of the transaction.
+	WithTx(ctx context.Context, fn func(tx Database) error) error
 	Close() error
 }
 
 // CharacterRepository defines the interface for character operations
 type CharacterRepository interface {
 	Insert(ctx context.Context, character *Character) error
+	Upsert(ctx context.Context, character *Character) error
 	Update(ctx context.Context, character *Character) error
 	Delete(ctx context.Context, character *Character) error
 	GetCharacter(ctx context.Context, name, realm string) (Character, error)
+	GetCharacterByID(ctx context.Context, id int) (Character, error)
 	CheckCharacterExists(ctx context.Context, name, realm string) (bool, error)
 	ListCharacters(ctx context.Context, limit int) ([]Character, error)
+	WithTx(ctx context.Context, fn func(repo CharacterRepository) error) error
+}
+
+// ScoreHistoryRepository defines the interface for score history operations
+type ScoreHistoryRepository interface {
+	Insert(ctx context.Context, entry *ScoreHistory) error
+	ListForCharacter(ctx context.Context, characterID int, since int64) ([]ScoreHistory, error)
+	List(ctx context.Context) ([]ScoreHistory, error)
+	WithTx(ctx context.Context, fn func(repo ScoreHistoryRepository) error) error
+}
+
+// SeasonRatingRepository defines the interface for season rating operations
+type SeasonRatingRepository interface {
+	Upsert(ctx context.Context, rating *SeasonRating) error
+	ListForCharacter(ctx context.Context, characterID int) ([]SeasonRating, error)
+}
+
+// DungeonRunRepository defines the interface for dungeon run operations
+type DungeonRunRepository interface {
+	Upsert(ctx context.Context, run *DungeonRun) error
+	ListForCharacter(ctx context.Context, characterID, seasonID int) ([]DungeonRun, error)
+	ListDungeons(ctx context.Context) ([]Dungeon, error)
+	Leaderboard(ctx context.Context, dungeonID, limit int) ([]DungeonRunEntry, error)
+}
+
+// CharacterRunRepository defines the interface for Raider.IO run operations
+type CharacterRunRepository interface {
+	Replace(ctx context.Context, characterID int, runs []CharacterRun) error
+	ListForCharacter(ctx context.Context, characterID int, kind RunKind) ([]CharacterRun, error)
+}
+
+// SpecScoreRepository defines the interface for spec score operations
+type SpecScoreRepository interface {
+	Replace(ctx context.Context, characterID int, scores []SpecScore) error
+	List(ctx context.Context) (map[int][]SpecScore, error)
+}
+
+// GuildRankRepository defines the interface for guild rank operations
+type GuildRankRepository interface {
+	Insert(ctx context.Context, rank *GuildRank) error
+	Latest(ctx context.Context, guild, realm string) (GuildRank, error)
+}
+
+// LinkRepository defines the interface for linking characters to Discord users
+type LinkRepository interface {
+	Link(ctx context.Context, characterID int, userID string) error
+	Unlink(ctx context.Context, characterID int) error
+	GetUserID(ctx context.Context, characterID int) (string, error)
+	ListCharacters(ctx context.Context, userID string) ([]Character, error)
+}
+
+// GuildSettingsRepository defines the interface for per Discord server settings
+type GuildSettingsRepository interface {
+	SetLocale(ctx context.Context, guildID, locale string) error
+	GetLocale(ctx context.Context, guildID string) (string, error)
+}
+
+// OutboundMessageRepository defines the interface for Discord messages waiting to be sent
+type OutboundMessageRepository interface {
+	Insert(ctx context.Context, message *OutboundMessage) error
+	List(ctx context.Context) ([]OutboundMessage, error)
+	Delete(ctx context.Context, id int64) error
+	Retry(ctx context.Context, id int64, attempts int, nextAttempt int64) error
+}
+
+// ThreadRepository defines the interface for the Discord threads score updates are posted in
+type ThreadRepository interface {
+	GetThreadID(ctx context.Context, channelID, key string) (string, error)
+	SetThreadID(ctx context.Context, channelID, key, threadID string) error
+	GetChannelID(ctx context.Context, threadID string) (string, error)
+}
+
+// LiveLeaderboardRepository defines the interface for the leaderboard messages kept up to date in each channel
+type LiveLeaderboardRepository interface {
+	GetMessageID(ctx context.Context, channelID string) (string, error)
+	SetMessageID(ctx context.Context, channelID, messageID string) error
 }
 
 // SQLiteDB implements the Database interface
@@ -85,11 +264,7 @@ func (s *SQLiteDB) initTable(ctx context.Context) error {
 		return ErrNoDatabase
 	}
 
-	if err := s.Query(ctx, createCharactersTableSQL); err != nil {
-		return err
-	}
-
-	return s.Query(ctx, createCharactersTableTriggersSQL)
+	return migrate(ctx, s, DialectSQLite)
 }
 
 func (s *SQLiteDB) Close() error {
@@ -123,3 +298,35 @@ func (s *SQLiteDB) QueryRows(ctx context.Context, query string, args ...any) (*s
 	slog.DebugContext(ctx, "executing query", "query", query, "args", args)
 	return s.db.QueryContext(ctx, query, args...)
 }
+
+func (s *SQLiteDB) WithTx(ctx context.Context, fn func(tx Database) error) error {
+	return withTx(ctx, s.db, noRebind, fn)
+}
+
+// CheckIntegrity runs PRAGMA integrity_check and returns ErrIntegrityCheck describing any problems it finds.
+func (s *SQLiteDB) CheckIntegrity(ctx context.Context) error {
+	rows, err := s.QueryRows(ctx, "PRAGMA integrity_check")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return err
+		}
+		if result != "ok" {
+			problems = append(problems, result)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrIntegrityCheck, strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -23,6 +23,14 @@ func (m *MockDatabase) QueryRows(ctx context.Context, query string, args ...any)
 	return callArgs.Get(0).(*sql.Rows), callArgs.Error(1)
 }
 
+// WithTx runs fn against the mock itself, so the queries inside the transaction are asserted as usual.
+func (m *MockDatabase) WithTx(ctx context.Context, fn func(tx Database) error) error {
+	if err := m.Called(ctx).Error(0); err != nil {
+		return err
+	}
+	return fn(m)
+}
+
 func (m *MockDatabase) Close() error {
 	args := m.Called()
 	return args.Error(0)
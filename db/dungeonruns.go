@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// DungeonRun is a character's best run in a dungeon for a season.
+	DungeonRun struct {
+		CharacterID        int     `json:"character_id"`
+		SeasonID           int     `json:"season_id"`
+		DungeonID          int     `json:"dungeon_id"`
+		DungeonName        string  `json:"dungeon_name"`
+		KeystoneLevel      int     `json:"keystone_level"`
+		MapRating          float64 `json:"map_rating"`
+		Duration           int64   `json:"duration"` // milliseconds
+		Timed              bool    `json:"timed"`
+		CompletedTimestamp int64   `json:"completed_timestamp"`
+	}
+
+	// Dungeon is a dungeon that at least one tracked character has run.
+	Dungeon struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	// DungeonRunEntry is a row on a dungeon leaderboard, the run along with who ran it.
+	DungeonRunEntry struct {
+		DungeonRun
+		Name  string `json:"name"`
+		Realm string `json:"realm"`
+		Class string `json:"class"`
+	}
+)
+
+const (
+	dungeonRunColumns = `character_id, season_id, dungeon_id, dungeon_name, keystone_level, map_rating, duration, timed,
+		completed_timestamp`
+
+	upsertDungeonRunQuery = `INSERT INTO dungeon_runs (` + dungeonRunColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (character_id, season_id, dungeon_id) DO UPDATE SET dungeon_name = excluded.dungeon_name,
+			keystone_level = excluded.keystone_level, map_rating = excluded.map_rating, duration = excluded.duration,
+			timed = excluded.timed, completed_timestamp = excluded.completed_timestamp`
+
+	listDungeonRunsQuery = `SELECT ` + dungeonRunColumns + ` FROM dungeon_runs
+		WHERE character_id = ? AND season_id = ? ORDER BY map_rating DESC`
+
+	// Leaderboards only cover the latest season we have runs for
+	latestDungeonSeason = `(SELECT MAX(season_id) FROM dungeon_runs)`
+
+	listDungeonsQuery = `SELECT DISTINCT dungeon_id, dungeon_name FROM dungeon_runs
+		WHERE season_id = ` + latestDungeonSeason + ` ORDER BY dungeon_name`
+
+	listDungeonLeaderboardQuery = `SELECT r.character_id, r.season_id, r.dungeon_id, r.dungeon_name, r.keystone_level,
+			r.map_rating, r.duration, r.timed, r.completed_timestamp, c.name, c.realm, c.class
+		FROM dungeon_runs r JOIN characters c ON c.id = r.character_id
+		WHERE r.dungeon_id = ? AND r.season_id = ` + latestDungeonSeason + `
+		ORDER BY r.map_rating DESC, r.keystone_level DESC`
+)
+
+// DungeonRunRepo implements DungeonRunRepository interface
+type DungeonRunRepo struct {
+	db Database
+}
+
+// NewDungeonRunRepo creates a new dungeon run repository
+func NewDungeonRunRepo(db Database) *DungeonRunRepo {
+	return &DungeonRunRepo{db: db}
+}
+
+// Upsert stores the run, replacing the character's stored run for that dungeon and season.
+func (r *DungeonRunRepo) Upsert(ctx context.Context, run *DungeonRun) error {
+	return r.db.Query(ctx, upsertDungeonRunQuery, run.CharacterID, run.SeasonID, run.DungeonID, run.DungeonName,
+		run.KeystoneLevel, run.MapRating, run.Duration, run.Timed, run.CompletedTimestamp)
+}
+
+// ListForCharacter returns the character's best run in each dungeon for the season, highest rated first.
+func (r *DungeonRunRepo) ListForCharacter(ctx context.Context, characterID, seasonID int) ([]DungeonRun, error) {
+	rows, err := r.db.QueryRows(ctx, listDungeonRunsQuery, characterID, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []DungeonRun
+	for rows.Next() {
+		var run DungeonRun
+		if err := rows.Scan(&run.CharacterID, &run.SeasonID, &run.DungeonID, &run.DungeonName, &run.KeystoneLevel,
+			&run.MapRating, &run.Duration, &run.Timed, &run.CompletedTimestamp); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+// ListDungeons returns the dungeons with runs in the latest season, sorted by name.
+func (r *DungeonRunRepo) ListDungeons(ctx context.Context) ([]Dungeon, error) {
+	rows, err := r.db.QueryRows(ctx, listDungeonsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dungeons []Dungeon
+	for rows.Next() {
+		var d Dungeon
+		if err := rows.Scan(&d.ID, &d.Name); err != nil {
+			return nil, err
+		}
+		dungeons = append(dungeons, d)
+	}
+
+	return dungeons, rows.Err()
+}
+
+// Leaderboard returns every tracked character's best run in the dungeon for the latest season, highest rated first.
+func (r *DungeonRunRepo) Leaderboard(ctx context.Context, dungeonID, limit int) ([]DungeonRunEntry, error) {
+	query := listDungeonLeaderboardQuery
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := r.db.QueryRows(ctx, query, dungeonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []DungeonRunEntry
+	for rows.Next() {
+		var e DungeonRunEntry
+		if err := rows.Scan(&e.CharacterID, &e.SeasonID, &e.DungeonID, &e.DungeonName, &e.KeystoneLevel,
+			&e.MapRating, &e.Duration, &e.Timed, &e.CompletedTimestamp, &e.Name, &e.Realm, &e.Class); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
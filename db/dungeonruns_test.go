@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDungeonRunRepo_Upsert(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewDungeonRunRepo(mockDB)
+	ctx := context.Background()
+
+	run := &DungeonRun{CharacterID: 1, SeasonID: 13, DungeonID: 2, DungeonName: "Ara-Kara", KeystoneLevel: 12,
+		MapRating: 300.5, Duration: 1800000, Timed: true, CompletedTimestamp: 1234567890}
+
+	mockDB.On("Query", ctx, upsertDungeonRunQuery,
+		mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 9 &&
+				args[0] == 1 &&
+				args[1] == 13 &&
+				args[2] == 2 &&
+				args[3] == "Ara-Kara" &&
+				args[4] == 12 &&
+				args[5] == 300.5 &&
+				args[6] == int64(1800000) &&
+				args[7] == true &&
+				args[8] == int64(1234567890)
+		})).Return(nil)
+
+	err := repo.Upsert(ctx, run)
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestDungeonRunRepo_Leaderboard(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewDungeonRunRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("QueryRows", ctx, listDungeonLeaderboardQuery+" LIMIT 10",
+		mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 1 && args[0] == 2
+		})).Return((*sql.Rows)(nil), errors.New("mock error"))
+
+	entries, err := repo.Leaderboard(ctx, 2, 10)
+	assert.Error(t, err)
+	assert.Nil(t, entries)
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+)
+
+// GuildRank is a guild's Raider.IO mythic+ ranks at a point in time.
+type GuildRank struct {
+	Guild        string `json:"guild"`
+	Realm        string `json:"realm"`
+	WorldRank    int    `json:"world_rank"`
+	RegionRank   int    `json:"region_rank"`
+	RealmRank    int    `json:"realm_rank"`
+	DateRecorded int64  `json:"date_recorded"`
+}
+
+const (
+	insertGuildRankQuery = `INSERT INTO guild_ranks (guild, realm, world_rank, region_rank, realm_rank, date_recorded)
+		VALUES (?, ?, ?, ?, ?, ?)`
+
+	latestGuildRankQuery = `SELECT guild, realm, world_rank, region_rank, realm_rank, date_recorded FROM guild_ranks
+		WHERE guild = ? AND realm = ? ORDER BY date_recorded DESC LIMIT 1`
+)
+
+// GuildRankRepo implements GuildRankRepository interface
+type GuildRankRepo struct {
+	db Database
+}
+
+// NewGuildRankRepo creates a new guild rank repository
+func NewGuildRankRepo(db Database) *GuildRankRepo {
+	return &GuildRankRepo{db: db}
+}
+
+func (r *GuildRankRepo) Insert(ctx context.Context, rank *GuildRank) error {
+	return r.db.Query(ctx, insertGuildRankQuery, rank.Guild, rank.Realm, rank.WorldRank, rank.RegionRank,
+		rank.RealmRank, rank.DateRecorded)
+}
+
+// Latest returns the guild's most recently recorded ranks, or an empty GuildRank if none have been recorded.
+func (r *GuildRankRepo) Latest(ctx context.Context, guild, realm string) (GuildRank, error) {
+	rows, err := r.db.QueryRows(ctx, latestGuildRankQuery, guild, realm)
+	if err != nil {
+		return GuildRank{}, err
+	}
+	defer rows.Close()
+
+	var rank GuildRank
+	if rows.Next() {
+		if err := rows.Scan(&rank.Guild, &rank.Realm, &rank.WorldRank, &rank.RegionRank, &rank.RealmRank,
+			&rank.DateRecorded); err != nil {
+			return GuildRank{}, err
+		}
+	}
+
+	return rank, rows.Err()
+}
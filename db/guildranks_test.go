@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuildRankRepo_Insert(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewGuildRankRepo(mockDB)
+	ctx := context.Background()
+
+	rank := &GuildRank{Guild: "Test Guild", Realm: "tichondrius", WorldRank: 1200, RegionRank: 450, RealmRank: 12,
+		DateRecorded: 1000}
+	mockDB.On("Query", ctx, insertGuildRankQuery,
+		[]interface{}{"Test Guild", "tichondrius", 1200, 450, 12, int64(1000)}).Return(nil)
+
+	err := repo.Insert(ctx, rank)
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestGuildRankRepo_Latest_Error(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewGuildRankRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("QueryRows", ctx, latestGuildRankQuery, []interface{}{"Test Guild", "tichondrius"}).
+		Return((*sql.Rows)(nil), errors.New("mock error"))
+
+	rank, err := repo.Latest(ctx, "Test Guild", "tichondrius")
+	assert.Error(t, err)
+	assert.Equal(t, GuildRank{}, rank)
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+)
+
+const (
+	upsertLocaleQuery = `INSERT INTO guild_settings (guild_id, locale) VALUES (?, ?)
+		ON CONFLICT (guild_id) DO UPDATE SET locale = excluded.locale`
+
+	getLocaleQuery = `SELECT locale FROM guild_settings WHERE guild_id = ?`
+)
+
+// GuildSettingsRepo implements GuildSettingsRepository interface
+type GuildSettingsRepo struct {
+	db Database
+}
+
+// NewGuildSettingsRepo creates a new Discord server settings repository
+func NewGuildSettingsRepo(db Database) *GuildSettingsRepo {
+	return &GuildSettingsRepo{db: db}
+}
+
+// SetLocale sets the language the bot replies in for the Discord server.
+func (r *GuildSettingsRepo) SetLocale(ctx context.Context, guildID, locale string) error {
+	return r.db.Query(ctx, upsertLocaleQuery, guildID, locale)
+}
+
+// GetLocale returns the language chosen for the Discord server, or "" if it hasn't chosen one.
+func (r *GuildSettingsRepo) GetLocale(ctx context.Context, guildID string) (string, error) {
+	rows, err := r.db.QueryRows(ctx, getLocaleQuery, guildID)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var locale string
+	if rows.Next() {
+		if err := rows.Scan(&locale); err != nil {
+			return "", err
+		}
+	}
+
+	return locale, rows.Err()
+}
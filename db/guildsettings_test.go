@@ -0,0 +1,20 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuildSettingsRepo_SetLocale(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewGuildSettingsRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("Query", ctx, upsertLocaleQuery, []interface{}{"guild1", "de"}).Return(nil)
+
+	err := repo.SetLocale(ctx, "guild1", "de")
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
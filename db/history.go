@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+)
+
+// ScoreHistory is a snapshot of a character's scores, recorded whenever the updater sees a change.
+type ScoreHistory struct {
+	CharacterID  int     `json:"character_id"`
+	OverallScore float64 `json:"score"`
+	TankScore    float64 `json:"tank_score"`
+	DPSScore     float64 `json:"dps_score"`
+	HealScore    float64 `json:"heal_score"`
+	DateRecorded int64   `json:"date_recorded"`
+}
+
+const (
+	// Entries are keyed on (character_id, date_recorded), so re-importing the same history is a no-op.
+	insertScoreHistoryQuery = `INSERT INTO score_history (character_id, score, tank_score, dps_score, heal_score, date_recorded)
+		VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT DO NOTHING`
+
+	listScoreHistoryQuery = `SELECT character_id, score, tank_score, dps_score, heal_score, date_recorded FROM score_history`
+)
+
+// ScoreHistoryRepo implements ScoreHistoryRepository interface
+type ScoreHistoryRepo struct {
+	db Database
+}
+
+// NewScoreHistoryRepo creates a new score history repository
+func NewScoreHistoryRepo(db Database) *ScoreHistoryRepo {
+	return &ScoreHistoryRepo{db: db}
+}
+
+func (r *ScoreHistoryRepo) Insert(ctx context.Context, entry *ScoreHistory) error {
+	return r.db.Query(ctx, insertScoreHistoryQuery, entry.CharacterID, entry.OverallScore, entry.TankScore,
+		entry.DPSScore, entry.HealScore, entry.DateRecorded)
+}
+
+// WithTx runs fn with a repository that writes in a transaction, see Database.WithTx.
+func (r *ScoreHistoryRepo) WithTx(ctx context.Context, fn func(repo ScoreHistoryRepository) error) error {
+	return r.db.WithTx(ctx, func(tx Database) error {
+		return fn(NewScoreHistoryRepo(tx))
+	})
+}
+
+// ListForCharacter returns a character's history recorded at or after since (unix seconds), oldest first.
+func (r *ScoreHistoryRepo) ListForCharacter(ctx context.Context, characterID int, since int64) ([]ScoreHistory, error) {
+	return r.list(ctx, listScoreHistoryQuery+" WHERE character_id = ? AND date_recorded >= ? ORDER BY date_recorded",
+		characterID, since)
+}
+
+// List returns the history of every character, ordered by character then oldest first.
+func (r *ScoreHistoryRepo) List(ctx context.Context) ([]ScoreHistory, error) {
+	return r.list(ctx, listScoreHistoryQuery+" ORDER BY character_id, date_recorded")
+}
+
+func (r *ScoreHistoryRepo) list(ctx context.Context, query string, args ...any) ([]ScoreHistory, error) {
+	rows, err := r.db.QueryRows(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []ScoreHistory
+	for rows.Next() {
+		var h ScoreHistory
+		if err := rows.Scan(&h.CharacterID, &h.OverallScore, &h.TankScore, &h.DPSScore, &h.HealScore,
+			&h.DateRecorded); err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+
+	return history, rows.Err()
+}
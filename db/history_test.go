@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScoreHistoryRepo_Insert(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewScoreHistoryRepo(mockDB)
+	ctx := context.Background()
+
+	entry := &ScoreHistory{
+		CharacterID:  1,
+		OverallScore: 2500.5,
+		TankScore:    2400.0,
+		DPSScore:     2300.0,
+		HealScore:    0.0,
+		DateRecorded: 1234567890,
+	}
+
+	mockDB.On("Query", ctx, insertScoreHistoryQuery,
+		mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 6 &&
+				args[0] == 1 &&
+				args[1] == 2500.5 &&
+				args[2] == 2400.0 &&
+				args[3] == 2300.0 &&
+				args[4] == 0.0 &&
+				args[5] == int64(1234567890)
+		})).Return(nil)
+
+	err := repo.Insert(ctx, entry)
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestScoreHistoryRepo_ListForCharacter(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewScoreHistoryRepo(mockDB)
+	ctx := context.Background()
+
+	expectedQuery := "SELECT character_id, score, tank_score, dps_score, heal_score, date_recorded FROM score_history WHERE character_id = ? AND date_recorded >= ? ORDER BY date_recorded"
+	mockDB.On("QueryRows", ctx, expectedQuery,
+		mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 2 && args[0] == 1 && args[1] == int64(1000)
+		})).Return((*sql.Rows)(nil), errors.New("mock error"))
+
+	history, err := repo.ListForCharacter(ctx, 1, 1000)
+	assert.Error(t, err)
+	assert.Nil(t, history)
+	mockDB.AssertExpectations(t)
+}
+
+func TestScoreHistoryRepo_List(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewScoreHistoryRepo(mockDB)
+	ctx := context.Background()
+
+	expectedQuery := "SELECT character_id, score, tank_score, dps_score, heal_score, date_recorded FROM score_history ORDER BY character_id, date_recorded"
+	mockDB.On("QueryRows", ctx, expectedQuery, []interface{}(nil)).Return((*sql.Rows)(nil), errors.New("mock error"))
+
+	history, err := repo.List(ctx)
+	assert.Error(t, err)
+	assert.Nil(t, history)
+	mockDB.AssertExpectations(t)
+}
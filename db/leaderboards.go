@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+)
+
+const (
+	upsertLiveLeaderboardQuery = `INSERT INTO live_leaderboards (channel_id, message_id) VALUES (?, ?)
+		ON CONFLICT (channel_id) DO UPDATE SET message_id = excluded.message_id`
+
+	getLiveLeaderboardQuery = `SELECT message_id FROM live_leaderboards WHERE channel_id = ?`
+)
+
+// LiveLeaderboardRepo implements LiveLeaderboardRepository interface
+type LiveLeaderboardRepo struct {
+	db Database
+}
+
+// NewLiveLeaderboardRepo creates a new live leaderboard repository
+func NewLiveLeaderboardRepo(db Database) *LiveLeaderboardRepo {
+	return &LiveLeaderboardRepo{db: db}
+}
+
+// GetMessageID returns the channel's live leaderboard message, or "" if it doesn't have one.
+func (r *LiveLeaderboardRepo) GetMessageID(ctx context.Context, channelID string) (string, error) {
+	rows, err := r.db.QueryRows(ctx, getLiveLeaderboardQuery, channelID)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var messageID string
+	if rows.Next() {
+		if err := rows.Scan(&messageID); err != nil {
+			return "", err
+		}
+	}
+
+	return messageID, rows.Err()
+}
+
+// SetMessageID records the channel's live leaderboard message, replacing any message it had before.
+func (r *LiveLeaderboardRepo) SetMessageID(ctx context.Context, channelID, messageID string) error {
+	return r.db.Query(ctx, upsertLiveLeaderboardQuery, channelID, messageID)
+}
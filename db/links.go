@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	insertLinkQuery = `INSERT INTO discord_links (character_id, discord_user_id) VALUES (?, ?)
+		ON CONFLICT (character_id) DO NOTHING`
+
+	getLinkQuery = `SELECT discord_user_id FROM discord_links WHERE character_id = ?`
+
+	deleteLinkQuery = `DELETE FROM discord_links WHERE character_id = ?`
+
+	listLinkedCharactersQuery = `SELECT ` + characterColumns + ` FROM characters
+		WHERE id IN (SELECT character_id FROM discord_links WHERE discord_user_id = ?)`
+)
+
+var (
+	// ErrLinkedToOtherUser is returned when linking a character that is already linked to a different Discord user.
+	ErrLinkedToOtherUser = errors.New("character is linked to another user")
+	// ErrNotLinked is returned when unlinking a character that isn't linked to anyone.
+	ErrNotLinked = errors.New("character isn't linked")
+)
+
+// LinkRepo implements LinkRepository interface
+type LinkRepo struct {
+	db Database
+}
+
+// NewLinkRepo creates a new Discord link repository
+func NewLinkRepo(db Database) *LinkRepo {
+	return &LinkRepo{db: db}
+}
+
+// Link links the character to a Discord user. Characters stay linked to the first user to link them, linking one to
+// anyone else returns ErrLinkedToOtherUser.
+func (r *LinkRepo) Link(ctx context.Context, characterID int, userID string) error {
+	if err := r.db.Query(ctx, insertLinkQuery, characterID, userID); err != nil {
+		return err
+	}
+
+	linked, err := r.GetUserID(ctx, characterID)
+	if err != nil {
+		return fmt.Errorf("failed to check linked user: %w", err)
+	}
+	if linked != userID {
+		return ErrLinkedToOtherUser
+	}
+	return nil
+}
+
+// Unlink removes the character's link, so it can be linked to someone else.
+func (r *LinkRepo) Unlink(ctx context.Context, characterID int) error {
+	return r.db.Query(ctx, deleteLinkQuery, characterID)
+}
+
+// GetUserID returns the Discord user the character is linked to, or "" if it isn't linked.
+func (r *LinkRepo) GetUserID(ctx context.Context, characterID int) (string, error) {
+	rows, err := r.db.QueryRows(ctx, getLinkQuery, characterID)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var userID string
+	if rows.Next() {
+		if err := rows.Scan(&userID); err != nil {
+			return "", err
+		}
+	}
+
+	return userID, rows.Err()
+}
+
+// ListCharacters returns the characters linked to the Discord user.
+func (r *LinkRepo) ListCharacters(ctx context.Context, userID string) ([]Character, error) {
+	rows, err := r.db.QueryRows(ctx, listLinkedCharactersQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var characters []Character
+	for rows.Next() {
+		var c Character
+		if err := c.scan(rows); err != nil {
+			return nil, err
+		}
+		characters = append(characters, c)
+	}
+
+	return characters, rows.Err()
+}
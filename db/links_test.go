@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkRepo_Link(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewLinkRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("Query", ctx, insertLinkQuery, []interface{}{1, "1234"}).Return(errors.New("mock error"))
+
+	err := repo.Link(ctx, 1, "1234")
+	assert.EqualError(t, err, "mock error")
+	mockDB.AssertExpectations(t)
+	mockDB.AssertNotCalled(t, "QueryRows")
+}
+
+func TestLinkRepo_Unlink(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewLinkRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("Query", ctx, deleteLinkQuery, []interface{}{1}).Return(nil)
+
+	err := repo.Unlink(ctx, 1)
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
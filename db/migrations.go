@@ -0,0 +1,219 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Dialect identifies which SQL database a Database talks to, it matches the database/sql driver name.
+type Dialect string
+
+const (
+	DialectSQLite   Dialect = "sqlite3"
+	DialectPostgres Dialect = "postgres"
+)
+
+const (
+	createMigrationsTableSQL = `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		date_applied BIGINT NOT NULL
+	)`
+
+	listMigrationsQuery = `SELECT version FROM schema_migrations`
+
+	insertMigrationQuery = `INSERT INTO schema_migrations (version, name, date_applied) VALUES (?, ?, ?)`
+)
+
+// migration is a versioned schema change. Each dialect has its own statements, which are run in order.
+//
+// Migrations are append only: once released, change the schema by adding a new migration rather than editing one.
+type migration struct {
+	version    int
+	name       string
+	statements map[Dialect][]string
+}
+
+// The same statements work for both dialects, SQLite can only add one column per ALTER TABLE.
+var addCharacterProfileColumnsSQL = []string{
+	`ALTER TABLE characters ADD COLUMN level INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE characters ADD COLUMN spec TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE characters ADD COLUMN guild TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE characters ADD COLUMN faction TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE characters ADD COLUMN item_level INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE characters ADD COLUMN equipped_item_level INTEGER NOT NULL DEFAULT 0`,
+}
+
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "create characters",
+		statements: map[Dialect][]string{
+			DialectSQLite:   {createCharactersTableSQL, createCharactersTableTriggersSQL},
+			DialectPostgres: {pgCreateCharactersTableSQL, pgCreateDateUpdatedFunctionSQL, pgCreateCharactersTableTriggersSQL},
+		},
+	},
+	{
+		version: 2,
+		name:    "create score history",
+		statements: map[Dialect][]string{
+			DialectSQLite:   {createScoreHistoryTableSQL},
+			DialectPostgres: {pgCreateScoreHistoryTableSQL},
+		},
+	},
+	{
+		version: 3,
+		name:    "add character profile",
+		statements: map[Dialect][]string{
+			DialectSQLite:   addCharacterProfileColumnsSQL,
+			DialectPostgres: addCharacterProfileColumnsSQL,
+		},
+	},
+	{
+		version: 4,
+		name:    "create season ratings",
+		statements: map[Dialect][]string{
+			DialectSQLite:   {createSeasonRatingsTableSQL},
+			DialectPostgres: {pgCreateSeasonRatingsTableSQL},
+		},
+	},
+	{
+		version: 5,
+		name:    "create dungeon runs",
+		statements: map[Dialect][]string{
+			DialectSQLite:   {createDungeonRunsTableSQL},
+			DialectPostgres: {pgCreateDungeonRunsTableSQL},
+		},
+	},
+	{
+		version: 6,
+		name:    "create character runs",
+		statements: map[Dialect][]string{
+			DialectSQLite:   {createCharacterRunsTableSQL},
+			DialectPostgres: {pgCreateCharacterRunsTableSQL},
+		},
+	},
+	{
+		version: 7,
+		name:    "create spec scores",
+		statements: map[Dialect][]string{
+			DialectSQLite:   {createSpecScoresTableSQL},
+			DialectPostgres: {pgCreateSpecScoresTableSQL},
+		},
+	},
+	{
+		version: 8,
+		name:    "create guild ranks",
+		statements: map[Dialect][]string{
+			DialectSQLite:   {createGuildRanksTableSQL},
+			DialectPostgres: {pgCreateGuildRanksTableSQL},
+		},
+	},
+	{
+		version: 9,
+		name:    "create discord links",
+		statements: map[Dialect][]string{
+			DialectSQLite:   {createDiscordLinksTableSQL},
+			DialectPostgres: {pgCreateDiscordLinksTableSQL},
+		},
+	},
+	{
+		version: 10,
+		name:    "create guild settings",
+		statements: map[Dialect][]string{
+			DialectSQLite:   {createGuildSettingsTableSQL},
+			DialectPostgres: {pgCreateGuildSettingsTableSQL},
+		},
+	},
+	{
+		version: 11,
+		name:    "create outbound messages",
+		statements: map[Dialect][]string{
+			DialectSQLite:   {createOutboundMessagesTableSQL},
+			DialectPostgres: {pgCreateOutboundMessagesTableSQL},
+		},
+	},
+	{
+		version: 12,
+		name:    "create announcement threads",
+		statements: map[Dialect][]string{
+			DialectSQLite:   {createAnnouncementThreadsTableSQL},
+			DialectPostgres: {pgCreateAnnouncementThreadsTableSQL},
+		},
+	},
+	{
+		version: 13,
+		name:    "create live leaderboards",
+		statements: map[Dialect][]string{
+			DialectSQLite:   {createLiveLeaderboardsTableSQL},
+			DialectPostgres: {pgCreateLiveLeaderboardsTableSQL},
+		},
+	},
+}
+
+// migrate applies every migration that hasn't been applied to the database yet.
+//
+// Databases created before migrations were tracked already have the early tables, those migrations use
+// IF NOT EXISTS so they are safe to apply again.
+func migrate(ctx context.Context, database Database, dialect Dialect) error {
+	if err := database.Query(ctx, createMigrationsTableSQL); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	applied, err := appliedMigrations(ctx, database)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		slog.InfoContext(ctx, "applying migration", "version", m.version, "name", m.name, "dialect", dialect)
+		statements, ok := m.statements[dialect]
+		if !ok {
+			return fmt.Errorf("migration %d has no statements for %s", m.version, dialect)
+		}
+
+		// Each migration is applied in a transaction so a failed statement doesn't leave it half applied
+		err := database.WithTx(ctx, func(tx Database) error {
+			for _, statement := range statements {
+				if err := tx.Query(ctx, statement); err != nil {
+					return fmt.Errorf("failed to apply migration %d (%s): %w", m.version, m.name, err)
+				}
+			}
+
+			if err := tx.Query(ctx, insertMigrationQuery, m.version, m.name, time.Now().Unix()); err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func appliedMigrations(ctx context.Context, database Database) (map[int]bool, error) {
+	rows, err := database.QueryRows(ctx, listMigrationsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+)
+
+// OutboundMessage is a Discord message waiting to be sent.
+type OutboundMessage struct {
+	ID        int64  `json:"id"`
+	ChannelID string `json:"channel_id"`
+	// Payload is the JSON encoded message
+	Payload     string `json:"payload"`
+	Attempts    int    `json:"attempts"`
+	NextAttempt int64  `json:"next_attempt"`
+	DateCreated int64  `json:"date_created"`
+}
+
+const (
+	insertOutboundMessageQuery = `INSERT INTO outbound_messages (channel_id, payload, attempts, next_attempt, date_created)
+		VALUES (?, ?, ?, ?, ?) RETURNING id`
+
+	listOutboundMessagesQuery = `SELECT id, channel_id, payload, attempts, next_attempt, date_created
+		FROM outbound_messages ORDER BY id`
+
+	deleteOutboundMessageQuery = `DELETE FROM outbound_messages WHERE id = ?`
+
+	retryOutboundMessageQuery = `UPDATE outbound_messages SET attempts = ?, next_attempt = ? WHERE id = ?`
+)
+
+// OutboundMessageRepo implements OutboundMessageRepository interface
+type OutboundMessageRepo struct {
+	db Database
+}
+
+// NewOutboundMessageRepo creates a new outbound message repository
+func NewOutboundMessageRepo(db Database) *OutboundMessageRepo {
+	return &OutboundMessageRepo{db: db}
+}
+
+// Insert queues the message, setting its ID.
+func (r *OutboundMessageRepo) Insert(ctx context.Context, message *OutboundMessage) error {
+	rows, err := r.db.QueryRows(ctx, insertOutboundMessageQuery, message.ChannelID, message.Payload, message.Attempts,
+		message.NextAttempt, message.DateCreated)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&message.ID); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// List returns every queued message, oldest first.
+func (r *OutboundMessageRepo) List(ctx context.Context) ([]OutboundMessage, error) {
+	rows, err := r.db.QueryRows(ctx, listOutboundMessagesQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []OutboundMessage
+	for rows.Next() {
+		var m OutboundMessage
+		if err := rows.Scan(&m.ID, &m.ChannelID, &m.Payload, &m.Attempts, &m.NextAttempt, &m.DateCreated); err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+
+	return messages, rows.Err()
+}
+
+func (r *OutboundMessageRepo) Delete(ctx context.Context, id int64) error {
+	return r.db.Query(ctx, deleteOutboundMessageQuery, id)
+}
+
+// Retry records a failed attempt to send the message and when to try it again.
+func (r *OutboundMessageRepo) Retry(ctx context.Context, id int64, attempts int, nextAttempt int64) error {
+	return r.db.Query(ctx, retryOutboundMessageQuery, attempts, nextAttempt, id)
+}
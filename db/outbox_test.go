@@ -0,0 +1,20 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboundMessageRepo_Retry(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewOutboundMessageRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("Query", ctx, retryOutboundMessageQuery, []interface{}{2, int64(1700000000), int64(7)}).Return(nil)
+
+	err := repo.Retry(ctx, 7, 2, 1700000000)
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,238 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	// import postgres.
+	_ "github.com/lib/pq"
+)
+
+const (
+	pgCreateCharactersTableSQL = `CREATE TABLE IF NOT EXISTS characters (
+		id BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		realm TEXT NOT NULL,
+		class TEXT NOT NULL,
+		score DOUBLE PRECISION NOT NULL,
+		tank_score DOUBLE PRECISION NOT NULL,
+		heal_score DOUBLE PRECISION NOT NULL,
+		dps_score DOUBLE PRECISION NOT NULL,
+		date_updated BIGINT DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+		date_created BIGINT DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT)
+	)`
+
+	pgCreateDateUpdatedFunctionSQL = `CREATE OR REPLACE FUNCTION set_date_updated() RETURNS TRIGGER AS $$
+		BEGIN
+			NEW.date_updated = EXTRACT(EPOCH FROM NOW())::BIGINT;
+			RETURN NEW;
+		END;
+		$$ LANGUAGE plpgsql`
+
+	pgCreateCharactersTableTriggersSQL = `CREATE TRIGGER update_characters_date_updated
+		BEFORE UPDATE ON characters
+		FOR EACH ROW
+		EXECUTE FUNCTION set_date_updated()`
+
+	pgCreateScoreHistoryTableSQL = `CREATE TABLE IF NOT EXISTS score_history (
+		character_id BIGINT NOT NULL,
+		score DOUBLE PRECISION NOT NULL,
+		tank_score DOUBLE PRECISION NOT NULL,
+		heal_score DOUBLE PRECISION NOT NULL,
+		dps_score DOUBLE PRECISION NOT NULL,
+		date_recorded BIGINT DEFAULT (EXTRACT(EPOCH FROM NOW())::BIGINT),
+		PRIMARY KEY (character_id, date_recorded)
+	)`
+
+	pgCreateSeasonRatingsTableSQL = `CREATE TABLE IF NOT EXISTS season_ratings (
+		character_id BIGINT NOT NULL,
+		season_id INTEGER NOT NULL,
+		rating DOUBLE PRECISION NOT NULL,
+		date_recorded BIGINT NOT NULL,
+		PRIMARY KEY (character_id, season_id)
+	)`
+
+	pgCreateDungeonRunsTableSQL = `CREATE TABLE IF NOT EXISTS dungeon_runs (
+		character_id BIGINT NOT NULL,
+		season_id INTEGER NOT NULL,
+		dungeon_id INTEGER NOT NULL,
+		dungeon_name TEXT NOT NULL,
+		keystone_level INTEGER NOT NULL,
+		map_rating DOUBLE PRECISION NOT NULL,
+		duration BIGINT NOT NULL,
+		timed BOOLEAN NOT NULL,
+		completed_timestamp BIGINT NOT NULL,
+		PRIMARY KEY (character_id, season_id, dungeon_id)
+	)`
+
+	pgCreateCharacterRunsTableSQL = `CREATE TABLE IF NOT EXISTS character_runs (
+		character_id BIGINT NOT NULL,
+		kind TEXT NOT NULL,
+		keystone_run_id BIGINT NOT NULL,
+		dungeon TEXT NOT NULL,
+		short_name TEXT NOT NULL,
+		mythic_level INTEGER NOT NULL,
+		num_keystone_upgrades INTEGER NOT NULL,
+		clear_time_ms BIGINT NOT NULL,
+		par_time_ms BIGINT NOT NULL,
+		score DOUBLE PRECISION NOT NULL,
+		completed_at BIGINT NOT NULL,
+		url TEXT NOT NULL,
+		PRIMARY KEY (character_id, kind, keystone_run_id)
+	)`
+
+	pgCreateSpecScoresTableSQL = `CREATE TABLE IF NOT EXISTS spec_scores (
+		character_id BIGINT NOT NULL,
+		spec TEXT NOT NULL,
+		score DOUBLE PRECISION NOT NULL,
+		PRIMARY KEY (character_id, spec)
+	)`
+
+	pgCreateGuildRanksTableSQL = `CREATE TABLE IF NOT EXISTS guild_ranks (
+		guild TEXT NOT NULL,
+		realm TEXT NOT NULL,
+		world_rank INTEGER NOT NULL,
+		region_rank INTEGER NOT NULL,
+		realm_rank INTEGER NOT NULL,
+		date_recorded BIGINT NOT NULL,
+		PRIMARY KEY (guild, realm, date_recorded)
+	)`
+
+	pgCreateDiscordLinksTableSQL = `CREATE TABLE IF NOT EXISTS discord_links (
+		character_id BIGINT PRIMARY KEY,
+		discord_user_id TEXT NOT NULL
+	)`
+
+	pgCreateGuildSettingsTableSQL = `CREATE TABLE IF NOT EXISTS guild_settings (
+		guild_id TEXT PRIMARY KEY,
+		locale TEXT NOT NULL
+	)`
+
+	pgCreateOutboundMessagesTableSQL = `CREATE TABLE IF NOT EXISTS outbound_messages (
+		id BIGSERIAL PRIMARY KEY,
+		channel_id TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		attempts INTEGER NOT NULL,
+		next_attempt BIGINT NOT NULL,
+		date_created BIGINT NOT NULL
+	)`
+
+	pgCreateAnnouncementThreadsTableSQL = `CREATE TABLE IF NOT EXISTS announcement_threads (
+		channel_id TEXT NOT NULL,
+		thread_key TEXT NOT NULL,
+		thread_id TEXT NOT NULL,
+		PRIMARY KEY (channel_id, thread_key)
+	)`
+
+	pgCreateLiveLeaderboardsTableSQL = `CREATE TABLE IF NOT EXISTS live_leaderboards (
+		channel_id TEXT PRIMARY KEY,
+		message_id TEXT NOT NULL
+	)`
+)
+
+var ErrNoDatabaseURL = errors.New("database url is required for postgres")
+
+// PostgresDB implements the Database interface
+//
+// Queries are written with SQLite style ? placeholders, they are rebound to $1, $2... before being sent.
+type PostgresDB struct {
+	db *sql.DB
+}
+
+// NewPostgresDB creates a new PostgreSQL database instance from a connection URL
+func NewPostgresDB(databaseURL string) (*PostgresDB, error) {
+	if databaseURL == "" {
+		return nil, ErrNoDatabaseURL
+	}
+
+	db, err := sql.Open(string(DialectPostgres), databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	return &PostgresDB{db: db}, nil
+}
+
+// Init applies any outstanding migrations
+func (p *PostgresDB) Init(ctx context.Context) error {
+	slog.DebugContext(ctx, "connecting to database")
+	if p.db == nil {
+		return ErrNoDatabase
+	}
+
+	if err := p.db.PingContext(ctx); err != nil {
+		return err
+	}
+
+	if err := migrate(ctx, p, DialectPostgres); err != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "database ready")
+	return nil
+}
+
+func (p *PostgresDB) Close() error {
+	if p.db != nil {
+		return p.db.Close()
+	}
+	return nil
+}
+
+func (p *PostgresDB) Query(ctx context.Context, query string, args ...any) error {
+	if p.db == nil {
+		return ErrNoDatabase
+	}
+
+	query = rebind(query)
+	slog.DebugContext(ctx, "executing query", "query", query, "args", args)
+	stmt, err := p.db.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, args...)
+	return err
+}
+
+func (p *PostgresDB) QueryRows(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if p.db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	query = rebind(query)
+	slog.DebugContext(ctx, "executing query", "query", query, "args", args)
+	return p.db.QueryContext(ctx, query, args...)
+}
+
+func (p *PostgresDB) WithTx(ctx context.Context, fn func(tx Database) error) error {
+	return withTx(ctx, p.db, rebind, fn)
+}
+
+// rebind replaces ? placeholders with postgres' numbered $n placeholders.
+//
+// Our queries never contain a literal ?, so there is no need to handle quoting.
+func rebind(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPostgresDB_NoURL(t *testing.T) {
+	database, err := NewPostgresDB("")
+
+	assert.Nil(t, database)
+	assert.ErrorIs(t, err, ErrNoDatabaseURL)
+}
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "no placeholders",
+			query:    "SELECT * FROM characters",
+			expected: "SELECT * FROM characters",
+		},
+		{
+			name:     "numbers placeholders in order",
+			query:    "SELECT * FROM characters WHERE name = ? AND realm = ? LIMIT ?",
+			expected: "SELECT * FROM characters WHERE name = $1 AND realm = $2 LIMIT $3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, rebind(tt.query))
+		})
+	}
+}
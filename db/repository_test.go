@@ -0,0 +1,635 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The repository suite runs against every backend so the SQL is checked against a real database, not just mocks.
+//
+// Postgres runs in an embedded instance downloaded on first use, or against MYTHICPLUSBOT_TEST_POSTGRES_URL (see `make
+// test-postgres`). The suite only touches its own schema, which it drops when it is done. It is skipped with -short,
+// or when the embedded instance can't be started, e.g. offline.
+
+const (
+	postgresURLEnv = "MYTHICPLUSBOT_TEST_POSTGRES_URL"
+	postgresSchema = "mythicplusbot_test"
+)
+
+type testDatabase interface {
+	Database
+	Init(ctx context.Context) error
+}
+
+func TestRepositories_SQLite(t *testing.T) {
+	database, err := NewSQLiteDB(filepath.Join(t.TempDir(), "test.sqlite"))
+	require.NoError(t, err)
+	defer database.Close()
+
+	runRepositorySuite(t, database)
+}
+
+func TestRepositories_Postgres(t *testing.T) {
+	if testing.Short() {
+		t.Skip("postgres is not tested in short mode")
+	}
+
+	databaseURL := os.Getenv(postgresURLEnv)
+	if databaseURL == "" {
+		databaseURL = startEmbeddedPostgres(t)
+	}
+
+	database, err := NewPostgresDB(testSchemaURL(t, databaseURL))
+	require.NoError(t, err)
+	defer database.Close()
+
+	runRepositorySuite(t, database)
+}
+
+// testSchemaURL creates an empty schema for the suite and returns databaseURL with it as the search path, so the
+// suite's tables are kept apart from anything else in the database. The schema is dropped when the test ends.
+func testSchemaURL(t *testing.T, databaseURL string) string {
+	t.Helper()
+
+	admin, err := NewPostgresDB(databaseURL)
+	require.NoError(t, err)
+	dropSchema := func() {
+		require.NoError(t, admin.Query(context.Background(), "DROP SCHEMA IF EXISTS "+postgresSchema+" CASCADE"))
+	}
+	dropSchema()
+	require.NoError(t, admin.Query(context.Background(), "CREATE SCHEMA "+postgresSchema))
+	t.Cleanup(func() {
+		dropSchema()
+		admin.Close()
+	})
+
+	// lib/pq passes unknown parameters on to the server, in both URL and key=value connection strings
+	if u, err := url.Parse(databaseURL); err == nil && u.Scheme != "" {
+		query := u.Query()
+		query.Set("search_path", postgresSchema)
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+	return databaseURL + " search_path=" + postgresSchema
+}
+
+// startEmbeddedPostgres starts a postgres server for the test on a free port and returns its URL.
+func startEmbeddedPostgres(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	config := embeddedpostgres.DefaultConfig().
+		Version(embeddedpostgres.V16).
+		Port(uint32(port)).
+		RuntimePath(t.TempDir()).
+		Logger(io.Discard)
+	server := embeddedpostgres.NewDatabase(config)
+	if err := server.Start(); err != nil {
+		t.Skipf("failed to start embedded postgres, set %s to use another server: %v", postgresURLEnv, err)
+	}
+	t.Cleanup(func() {
+		assert.NoError(t, server.Stop())
+	})
+
+	return config.GetConnectionURL() + "?sslmode=disable"
+}
+
+func runRepositorySuite(t *testing.T, database testDatabase) {
+	t.Helper()
+	ctx := context.Background()
+
+	require.NoError(t, database.Init(ctx))
+	// Migrations that have been applied are skipped
+	require.NoError(t, database.Init(ctx))
+
+	t.Run("characters", func(t *testing.T) {
+		testCharacterRepo(t, NewCharacterRepo(database))
+	})
+	t.Run("score history", func(t *testing.T) {
+		testScoreHistoryRepo(t, NewScoreHistoryRepo(database))
+	})
+	t.Run("season ratings", func(t *testing.T) {
+		testSeasonRatingRepo(t, NewSeasonRatingRepo(database))
+	})
+	t.Run("dungeon runs", func(t *testing.T) {
+		testDungeonRunRepo(t, NewDungeonRunRepo(database), NewCharacterRepo(database))
+	})
+	t.Run("character runs", func(t *testing.T) {
+		testCharacterRunRepo(t, NewCharacterRunRepo(database))
+	})
+	t.Run("spec scores", func(t *testing.T) {
+		testSpecScoreRepo(t, NewSpecScoreRepo(database))
+	})
+	t.Run("guild ranks", func(t *testing.T) {
+		testGuildRankRepo(t, NewGuildRankRepo(database))
+	})
+	t.Run("discord links", func(t *testing.T) {
+		testLinkRepo(t, NewLinkRepo(database), NewCharacterRepo(database))
+	})
+	t.Run("guild settings", func(t *testing.T) {
+		testGuildSettingsRepo(t, NewGuildSettingsRepo(database))
+	})
+	t.Run("outbound messages", func(t *testing.T) {
+		testOutboundMessageRepo(t, NewOutboundMessageRepo(database))
+	})
+	t.Run("announcement threads", func(t *testing.T) {
+		testThreadRepo(t, NewThreadRepo(database))
+	})
+	t.Run("live leaderboards", func(t *testing.T) {
+		testLiveLeaderboardRepo(t, NewLiveLeaderboardRepo(database))
+	})
+	t.Run("transactions", func(t *testing.T) {
+		testTransactions(t, database)
+	})
+	t.Run("delete character", func(t *testing.T) {
+		testDeleteCharacter(t, database)
+	})
+}
+
+// testDeleteCharacter checks removing a character leaves nothing behind for them to inherit if they are added again.
+func testDeleteCharacter(t *testing.T, database Database) {
+	t.Helper()
+	ctx := context.Background()
+	const id = 900
+
+	characters := NewCharacterRepo(database)
+	require.NoError(t, characters.Insert(ctx, &Character{ID: id, Name: "Gonedylan", Realm: "tichondrius"}))
+	require.NoError(t, characters.Insert(ctx, &Character{ID: id + 1, Name: "Staydylan", Realm: "tichondrius"}))
+
+	history := NewScoreHistoryRepo(database)
+	runs := NewCharacterRunRepo(database)
+	links := NewLinkRepo(database)
+	threads := NewThreadRepo(database)
+	for _, characterID := range []int{id, id + 1} {
+		require.NoError(t, history.Insert(ctx, &ScoreHistory{CharacterID: characterID, OverallScore: 2000, DateRecorded: 1}))
+		require.NoError(t, NewSeasonRatingRepo(database).Upsert(ctx, &SeasonRating{CharacterID: characterID, SeasonID: 12}))
+		require.NoError(t, NewDungeonRunRepo(database).Upsert(ctx, &DungeonRun{CharacterID: characterID, SeasonID: 13, DungeonID: 1}))
+		require.NoError(t, runs.Replace(ctx, characterID, []CharacterRun{{CharacterID: characterID, Kind: RunKindBest, KeystoneRunID: 1}}))
+		require.NoError(t, NewSpecScoreRepo(database).Replace(ctx, characterID, []SpecScore{{CharacterID: characterID, Spec: "Holy"}}))
+		require.NoError(t, links.Link(ctx, characterID, "user1"))
+		require.NoError(t, threads.SetThreadID(ctx, "channel1", CharacterThreadKey(characterID), "thread1"))
+	}
+
+	require.NoError(t, characters.Delete(ctx, &Character{Name: "Gonedylan", Realm: "tichondrius"}))
+
+	for characterID, remaining := range map[int]int{id: 0, id + 1: 1} {
+		entries, err := history.ListForCharacter(ctx, characterID, 0)
+		require.NoError(t, err)
+		assert.Len(t, entries, remaining)
+		ratings, err := NewSeasonRatingRepo(database).ListForCharacter(ctx, characterID)
+		require.NoError(t, err)
+		assert.Len(t, ratings, remaining)
+		dungeonRuns, err := NewDungeonRunRepo(database).ListForCharacter(ctx, characterID, 13)
+		require.NoError(t, err)
+		assert.Len(t, dungeonRuns, remaining)
+		characterRuns, err := runs.ListForCharacter(ctx, characterID, RunKindBest)
+		require.NoError(t, err)
+		assert.Len(t, characterRuns, remaining)
+		specScores, err := NewSpecScoreRepo(database).List(ctx)
+		require.NoError(t, err)
+		assert.Len(t, specScores[characterID], remaining)
+
+		userID, err := links.GetUserID(ctx, characterID)
+		require.NoError(t, err)
+		threadID, err := threads.GetThreadID(ctx, "channel1", CharacterThreadKey(characterID))
+		require.NoError(t, err)
+		if remaining == 0 {
+			assert.Empty(t, userID)
+			assert.Empty(t, threadID)
+		} else {
+			assert.Equal(t, "user1", userID)
+			assert.Equal(t, "thread1", threadID)
+		}
+	}
+}
+
+func testCharacterRepo(t *testing.T, repo *CharacterRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	paladin := Character{ID: 1, Name: "Paladylan", Realm: "tichondrius", Class: "Paladin", OverallScore: 2500.5,
+		TankScore: 2400, DPSScore: 2300, HealScore: 100, DateUpdated: 1700000000, DateCreated: 1600000000,
+		Level: 80, Spec: "Protection", Guild: "Method", Faction: "Horde", ItemLevel: 620, EquippedItemLevel: 618}
+	mage := Character{ID: 2, Name: "Magedylan", Realm: "area-52", Class: "Mage", OverallScore: 3000,
+		DPSScore: 3000, DateUpdated: 1700000000, DateCreated: 1600000000}
+
+	require.NoError(t, repo.Insert(ctx, &paladin))
+	require.NoError(t, repo.Insert(ctx, &mage))
+
+	got, err := repo.GetCharacter(ctx, "Paladylan", "tichondrius")
+	require.NoError(t, err)
+	assert.Equal(t, paladin, got)
+
+	got, err = repo.GetCharacterByID(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, mage, got)
+
+	got, err = repo.GetCharacter(ctx, "Nobody", "tichondrius")
+	require.NoError(t, err)
+	assert.True(t, got.IsEmpty())
+
+	exists, err := repo.CheckCharacterExists(ctx, "Magedylan", "area-52")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	characters, err := repo.ListCharacters(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, characters, 2)
+	assert.Equal(t, "Magedylan", characters[0].Name) // highest score first
+
+	characters, err = repo.ListCharacters(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, characters, 1)
+
+	paladin.OverallScore = 2600
+	paladin.HealScore = 200
+	paladin.Spec = "Holy"
+	paladin.ItemLevel = 625
+	require.NoError(t, repo.Update(ctx, &paladin))
+	got, err = repo.GetCharacterByID(ctx, 1)
+	require.NoError(t, err)
+	assert.InDelta(t, 2600, got.OverallScore, 0.001)
+	assert.InDelta(t, 200, got.HealScore, 0.001)
+	assert.Equal(t, "Holy", got.Spec)
+	assert.Equal(t, 625, got.ItemLevel)
+	assert.Greater(t, got.DateUpdated, paladin.DateUpdated, "the trigger should bump date_updated")
+
+	renamed := paladin
+	renamed.Name = "Tankdylan"
+	renamed.OverallScore = 2700
+	renamed.Spec = ""
+	require.NoError(t, repo.Upsert(ctx, &renamed))
+	got, err = repo.GetCharacterByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Tankdylan", got.Name)
+	assert.InDelta(t, 2700, got.OverallScore, 0.001)
+	assert.Equal(t, "Holy", got.Spec, "upserts keep the stored profile")
+	assert.Equal(t, paladin.DateCreated, got.DateCreated)
+
+	require.NoError(t, repo.Delete(ctx, &mage))
+	exists, err = repo.CheckCharacterExists(ctx, "Magedylan", "area-52")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func testScoreHistoryRepo(t *testing.T, repo *ScoreHistoryRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	entries := []ScoreHistory{
+		{CharacterID: 2, OverallScore: 2000, DPSScore: 2000, DateRecorded: 1000},
+		{CharacterID: 1, OverallScore: 2500, TankScore: 2500, DateRecorded: 2000},
+		{CharacterID: 1, OverallScore: 2400, TankScore: 2400, DateRecorded: 1000},
+	}
+	for _, e := range entries {
+		require.NoError(t, repo.Insert(ctx, &e))
+	}
+	// Recording the same moment twice is ignored
+	require.NoError(t, repo.Insert(ctx, &entries[0]))
+
+	history, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []ScoreHistory{entries[2], entries[1], entries[0]}, history)
+
+	history, err = repo.ListForCharacter(ctx, 1, 1500)
+	require.NoError(t, err)
+	assert.Equal(t, []ScoreHistory{entries[1]}, history)
+}
+
+func testCharacterRunRepo(t *testing.T, repo *CharacterRunRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	araKara := CharacterRun{CharacterID: 1, Kind: RunKindBest, KeystoneRunID: 100, Dungeon: "Ara-Kara, City of Echoes",
+		ShortName: "ARAK", MythicLevel: 12, NumKeystoneUpgrades: 1, ClearTimeMs: 1700000, ParTimeMs: 1800000,
+		Score: 300, CompletedAt: 1000, URL: "https://raider.io/mythic-plus-runs/100"}
+	stonevault := CharacterRun{CharacterID: 1, Kind: RunKindBest, KeystoneRunID: 101, Dungeon: "The Stonevault",
+		ShortName: "SV", MythicLevel: 13, NumKeystoneUpgrades: 2, ClearTimeMs: 1500000, ParTimeMs: 1980000,
+		Score: 320, CompletedAt: 900}
+	recent := araKara
+	recent.Kind = RunKindRecent
+	older := stonevault
+	older.Kind = RunKindRecent
+	require.NoError(t, repo.Replace(ctx, 1, []CharacterRun{araKara, stonevault, recent, older}))
+
+	runs, err := repo.ListForCharacter(ctx, 1, RunKindBest)
+	require.NoError(t, err)
+	assert.Equal(t, []CharacterRun{stonevault, araKara}, runs)
+
+	runs, err = repo.ListForCharacter(ctx, 1, RunKindRecent)
+	require.NoError(t, err)
+	assert.Equal(t, []CharacterRun{recent, older}, runs)
+
+	// Replacing drops the runs that are no longer on the profile
+	require.NoError(t, repo.Replace(ctx, 1, []CharacterRun{araKara}))
+	runs, err = repo.ListForCharacter(ctx, 1, RunKindBest)
+	require.NoError(t, err)
+	assert.Equal(t, []CharacterRun{araKara}, runs)
+
+	runs, err = repo.ListForCharacter(ctx, 1, RunKindRecent)
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func testSpecScoreRepo(t *testing.T, repo *SpecScoreRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	blood := SpecScore{CharacterID: 1, Spec: "Blood", Score: 2100}
+	frost := SpecScore{CharacterID: 1, Spec: "Frost", Score: 2900}
+	unholy := SpecScore{CharacterID: 1, Spec: "Unholy", Score: 2650}
+	havoc := SpecScore{CharacterID: 2, Spec: "Havoc", Score: 2500}
+	require.NoError(t, repo.Replace(ctx, 1, []SpecScore{blood, frost, unholy}))
+	require.NoError(t, repo.Replace(ctx, 2, []SpecScore{havoc}))
+
+	scores, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[int][]SpecScore{1: {frost, unholy, blood}, 2: {havoc}}, scores)
+
+	// Replacing drops the specs that no longer have a score
+	require.NoError(t, repo.Replace(ctx, 1, []SpecScore{frost}))
+	scores, err = repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []SpecScore{frost}, scores[1])
+}
+
+func testGuildRankRepo(t *testing.T, repo *GuildRankRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	rank, err := repo.Latest(ctx, "Test Guild", "tichondrius")
+	require.NoError(t, err)
+	assert.Equal(t, GuildRank{}, rank)
+
+	first := GuildRank{Guild: "Test Guild", Realm: "tichondrius", WorldRank: 1200, RegionRank: 450, RealmRank: 12,
+		DateRecorded: 1000}
+	latest := GuildRank{Guild: "Test Guild", Realm: "tichondrius", WorldRank: 1100, RegionRank: 420, RealmRank: 10,
+		DateRecorded: 2000}
+	other := GuildRank{Guild: "Other Guild", Realm: "tichondrius", RealmRank: 1, DateRecorded: 3000}
+	for _, r := range []GuildRank{latest, first, other} {
+		require.NoError(t, repo.Insert(ctx, &r))
+	}
+
+	rank, err = repo.Latest(ctx, "Test Guild", "tichondrius")
+	require.NoError(t, err)
+	assert.Equal(t, latest, rank)
+}
+
+func testLinkRepo(t *testing.T, repo *LinkRepo, characters *CharacterRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	userID, err := repo.GetUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, userID)
+
+	require.NoError(t, repo.Link(ctx, 1, "1234"))
+	// The same user can link it again, but no one else can take it over
+	require.NoError(t, repo.Link(ctx, 1, "1234"))
+	require.ErrorIs(t, repo.Link(ctx, 1, "5678"), ErrLinkedToOtherUser)
+
+	userID, err = repo.GetUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "1234", userID)
+
+	// Only the user's tracked characters are listed
+	require.NoError(t, characters.Insert(ctx, &Character{ID: 800, Name: "Linkdylan", Realm: "tichondrius", OverallScore: 2500}))
+	require.NoError(t, repo.Link(ctx, 800, "4321"))
+	require.NoError(t, repo.Link(ctx, 801, "4321"))
+	linked, err := repo.ListCharacters(ctx, "4321")
+	require.NoError(t, err)
+	require.Len(t, linked, 1)
+	assert.Equal(t, "Linkdylan", linked[0].Name)
+	assert.InDelta(t, 2500, linked[0].OverallScore, 0.001)
+
+	// Once unlinked someone else can link it
+	require.NoError(t, repo.Unlink(ctx, 1))
+	require.NoError(t, repo.Link(ctx, 1, "5678"))
+
+	userID, err = repo.GetUserID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "5678", userID)
+}
+
+func testGuildSettingsRepo(t *testing.T, repo *GuildSettingsRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	locale, err := repo.GetLocale(ctx, "guild1")
+	require.NoError(t, err)
+	assert.Empty(t, locale)
+
+	require.NoError(t, repo.SetLocale(ctx, "guild1", "de"))
+	// Setting it again replaces the locale
+	require.NoError(t, repo.SetLocale(ctx, "guild1", "fr"))
+
+	locale, err = repo.GetLocale(ctx, "guild1")
+	require.NoError(t, err)
+	assert.Equal(t, "fr", locale)
+
+	locale, err = repo.GetLocale(ctx, "guild2")
+	require.NoError(t, err)
+	assert.Empty(t, locale)
+}
+
+func testThreadRepo(t *testing.T, repo *ThreadRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	threadID, err := repo.GetThreadID(ctx, "channel1", "character:1")
+	require.NoError(t, err)
+	assert.Empty(t, threadID)
+
+	require.NoError(t, repo.SetThreadID(ctx, "channel1", "character:1", "thread1"))
+	// Setting it again replaces the thread
+	require.NoError(t, repo.SetThreadID(ctx, "channel1", "character:1", "thread2"))
+	require.NoError(t, repo.SetThreadID(ctx, "channel2", "character:1", "thread3"))
+
+	threadID, err = repo.GetThreadID(ctx, "channel1", "character:1")
+	require.NoError(t, err)
+	assert.Equal(t, "thread2", threadID)
+
+	// Threads are kept per channel
+	threadID, err = repo.GetThreadID(ctx, "channel2", "character:1")
+	require.NoError(t, err)
+	assert.Equal(t, "thread3", threadID)
+
+	channelID, err := repo.GetChannelID(ctx, "thread3")
+	require.NoError(t, err)
+	assert.Equal(t, "channel2", channelID)
+
+	channelID, err = repo.GetChannelID(ctx, "channel1")
+	require.NoError(t, err)
+	assert.Empty(t, channelID)
+}
+
+func testLiveLeaderboardRepo(t *testing.T, repo *LiveLeaderboardRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	messageID, err := repo.GetMessageID(ctx, "channel1")
+	require.NoError(t, err)
+	assert.Empty(t, messageID)
+
+	require.NoError(t, repo.SetMessageID(ctx, "channel1", "message1"))
+	// Setting it again replaces the message, e.g. after it was deleted
+	require.NoError(t, repo.SetMessageID(ctx, "channel1", "message2"))
+
+	messageID, err = repo.GetMessageID(ctx, "channel1")
+	require.NoError(t, err)
+	assert.Equal(t, "message2", messageID)
+
+	messageID, err = repo.GetMessageID(ctx, "channel2")
+	require.NoError(t, err)
+	assert.Empty(t, messageID)
+}
+
+func testOutboundMessageRepo(t *testing.T, repo *OutboundMessageRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	first := OutboundMessage{ChannelID: "channel1", Payload: `{"content":"first"}`, NextAttempt: 100, DateCreated: 100}
+	second := OutboundMessage{ChannelID: "channel2", Payload: `{"content":"second"}`, NextAttempt: 101, DateCreated: 101}
+	require.NoError(t, repo.Insert(ctx, &first))
+	require.NoError(t, repo.Insert(ctx, &second))
+	assert.NotZero(t, first.ID)
+	assert.Greater(t, second.ID, first.ID)
+
+	require.NoError(t, repo.Retry(ctx, first.ID, 1, 200))
+	first.Attempts, first.NextAttempt = 1, 200
+
+	messages, err := repo.List(ctx)
+	require.NoError(t, err)
+	// Messages are listed in the order they were queued
+	assert.Equal(t, []OutboundMessage{first, second}, messages)
+
+	require.NoError(t, repo.Delete(ctx, first.ID))
+	messages, err = repo.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []OutboundMessage{second}, messages)
+}
+
+func testTransactions(t *testing.T, database Database) {
+	t.Helper()
+	ctx := context.Background()
+	characters := NewCharacterRepo(database)
+	history := NewScoreHistoryRepo(database)
+	rogue := Character{ID: 10, Name: "Roguedylan", Realm: "tichondrius", Class: "Rogue", OverallScore: 1000,
+		DPSScore: 1000, DateUpdated: 1700000000, DateCreated: 1600000000}
+	errFailed := errors.New("failed")
+
+	// Returning an error rolls back every write made in the transaction
+	err := database.WithTx(ctx, func(tx Database) error {
+		if err := NewCharacterRepo(tx).Insert(ctx, &rogue); err != nil {
+			return err
+		}
+		if err := NewScoreHistoryRepo(tx).Insert(ctx, &ScoreHistory{CharacterID: rogue.ID, DateRecorded: 3000}); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	require.ErrorIs(t, err, errFailed)
+
+	got, err := characters.GetCharacterByID(ctx, rogue.ID)
+	require.NoError(t, err)
+	assert.True(t, got.IsEmpty())
+	entries, err := history.ListForCharacter(ctx, rogue.ID, 0)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// Nested transactions join the outer one and are committed together
+	err = characters.WithTx(ctx, func(repo CharacterRepository) error {
+		if err := repo.Insert(ctx, &rogue); err != nil {
+			return err
+		}
+		return repo.WithTx(ctx, func(repo CharacterRepository) error {
+			rogue.OverallScore = 1100
+			return repo.Update(ctx, &rogue)
+		})
+	})
+	require.NoError(t, err)
+
+	got, err = characters.GetCharacterByID(ctx, rogue.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 1100, got.OverallScore, 0.001)
+}
+
+func testSeasonRatingRepo(t *testing.T, repo *SeasonRatingRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	require.NoError(t, repo.Upsert(ctx, &SeasonRating{CharacterID: 1, SeasonID: 13, Rating: 3000, DateRecorded: 1000}))
+	require.NoError(t, repo.Upsert(ctx, &SeasonRating{CharacterID: 1, SeasonID: 12, Rating: 2500, DateRecorded: 1000}))
+	require.NoError(t, repo.Upsert(ctx, &SeasonRating{CharacterID: 2, SeasonID: 12, Rating: 2000, DateRecorded: 1000}))
+	// Backfilling again replaces the rating
+	require.NoError(t, repo.Upsert(ctx, &SeasonRating{CharacterID: 1, SeasonID: 13, Rating: 3100, DateRecorded: 2000}))
+
+	ratings, err := repo.ListForCharacter(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []SeasonRating{
+		{CharacterID: 1, SeasonID: 12, Rating: 2500, DateRecorded: 1000},
+		{CharacterID: 1, SeasonID: 13, Rating: 3100, DateRecorded: 2000},
+	}, ratings)
+}
+
+func testDungeonRunRepo(t *testing.T, repo *DungeonRunRepo, characters *CharacterRepo) {
+	t.Helper()
+	ctx := context.Background()
+
+	// The character suite has already run, so add characters of our own for the leaderboard to join on
+	priest := Character{ID: 20, Name: "Priestdylan", Realm: "tichondrius", Class: "Priest"}
+	hunter := Character{ID: 21, Name: "Hunterdylan", Realm: "area-52", Class: "Hunter"}
+	require.NoError(t, characters.Insert(ctx, &priest))
+	require.NoError(t, characters.Insert(ctx, &hunter))
+
+	araKara := DungeonRun{CharacterID: 20, SeasonID: 13, DungeonID: 1, DungeonName: "Ara-Kara", KeystoneLevel: 10,
+		MapRating: 250, Duration: 1800000, Timed: true, CompletedTimestamp: 1000}
+	stonevault := DungeonRun{CharacterID: 20, SeasonID: 13, DungeonID: 2, DungeonName: "The Stonevault",
+		KeystoneLevel: 8, MapRating: 200, Duration: 2100000, CompletedTimestamp: 1000}
+	hunterAraKara := DungeonRun{CharacterID: 21, SeasonID: 13, DungeonID: 1, DungeonName: "Ara-Kara",
+		KeystoneLevel: 11, MapRating: 270, Timed: true, CompletedTimestamp: 1500}
+	lastSeason := DungeonRun{CharacterID: 21, SeasonID: 12, DungeonID: 3, DungeonName: "Grim Batol",
+		KeystoneLevel: 15, MapRating: 350, Timed: true, CompletedTimestamp: 500}
+	for _, run := range []DungeonRun{araKara, stonevault, hunterAraKara, lastSeason} {
+		require.NoError(t, repo.Upsert(ctx, &run))
+	}
+
+	// A better run replaces the stored one
+	araKara.KeystoneLevel = 12
+	araKara.MapRating = 300
+	araKara.CompletedTimestamp = 2000
+	require.NoError(t, repo.Upsert(ctx, &araKara))
+
+	runs, err := repo.ListForCharacter(ctx, 20, 13)
+	require.NoError(t, err)
+	assert.Equal(t, []DungeonRun{araKara, stonevault}, runs)
+
+	dungeons, err := repo.ListDungeons(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Dungeon{{ID: 1, Name: "Ara-Kara"}, {ID: 2, Name: "The Stonevault"}}, dungeons)
+
+	entries, err := repo.Leaderboard(ctx, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []DungeonRunEntry{
+		{DungeonRun: araKara, Name: "Priestdylan", Realm: "tichondrius", Class: "Priest"},
+		{DungeonRun: hunterAraKara, Name: "Hunterdylan", Realm: "area-52", Class: "Hunter"},
+	}, entries)
+
+	entries, err = repo.Leaderboard(ctx, 1, 1)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+)
+
+// SeasonRating is a character's final rating for a finished mythic+ season.
+type SeasonRating struct {
+	CharacterID  int     `json:"character_id"`
+	SeasonID     int     `json:"season_id"`
+	Rating       float64 `json:"rating"`
+	DateRecorded int64   `json:"date_recorded"`
+}
+
+const (
+	upsertSeasonRatingQuery = `INSERT INTO season_ratings (character_id, season_id, rating, date_recorded) VALUES (?, ?, ?, ?)
+		ON CONFLICT (character_id, season_id) DO UPDATE SET rating = excluded.rating, date_recorded = excluded.date_recorded`
+
+	listSeasonRatingsQuery = `SELECT character_id, season_id, rating, date_recorded FROM season_ratings
+		WHERE character_id = ? ORDER BY season_id`
+)
+
+// SeasonRatingRepo implements SeasonRatingRepository interface
+type SeasonRatingRepo struct {
+	db Database
+}
+
+// NewSeasonRatingRepo creates a new season rating repository
+func NewSeasonRatingRepo(db Database) *SeasonRatingRepo {
+	return &SeasonRatingRepo{db: db}
+}
+
+// Upsert stores the rating, replacing any rating already stored for that character and season.
+func (r *SeasonRatingRepo) Upsert(ctx context.Context, rating *SeasonRating) error {
+	return r.db.Query(ctx, upsertSeasonRatingQuery, rating.CharacterID, rating.SeasonID, rating.Rating,
+		rating.DateRecorded)
+}
+
+// ListForCharacter returns the character's ratings, oldest season first.
+func (r *SeasonRatingRepo) ListForCharacter(ctx context.Context, characterID int) ([]SeasonRating, error) {
+	rows, err := r.db.QueryRows(ctx, listSeasonRatingsQuery, characterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ratings []SeasonRating
+	for rows.Next() {
+		var s SeasonRating
+		if err := rows.Scan(&s.CharacterID, &s.SeasonID, &s.Rating, &s.DateRecorded); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, s)
+	}
+
+	return ratings, rows.Err()
+}
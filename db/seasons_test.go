@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSeasonRatingRepo_Upsert(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewSeasonRatingRepo(mockDB)
+	ctx := context.Background()
+
+	rating := &SeasonRating{CharacterID: 1, SeasonID: 13, Rating: 2750.5, DateRecorded: 1234567890}
+
+	mockDB.On("Query", ctx, upsertSeasonRatingQuery,
+		mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 4 &&
+				args[0] == 1 &&
+				args[1] == 13 &&
+				args[2] == 2750.5 &&
+				args[3] == int64(1234567890)
+		})).Return(nil)
+
+	err := repo.Upsert(ctx, rating)
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestSeasonRatingRepo_ListForCharacter(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewSeasonRatingRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("QueryRows", ctx, listSeasonRatingsQuery,
+		mock.MatchedBy(func(args []interface{}) bool {
+			return len(args) == 1 && args[0] == 1
+		})).Return((*sql.Rows)(nil), errors.New("mock error"))
+
+	ratings, err := repo.ListForCharacter(ctx, 1)
+	assert.Error(t, err)
+	assert.Nil(t, ratings)
+	mockDB.AssertExpectations(t)
+}
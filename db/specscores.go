@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+)
+
+// SpecScore is a character's Raider.IO score for one of their specs this season.
+type SpecScore struct {
+	CharacterID int     `json:"character_id"`
+	Spec        string  `json:"spec"`
+	Score       float64 `json:"score"`
+}
+
+const (
+	deleteSpecScoresQuery = `DELETE FROM spec_scores WHERE character_id = ?`
+
+	insertSpecScoreQuery = `INSERT INTO spec_scores (character_id, spec, score) VALUES (?, ?, ?)`
+
+	listSpecScoresQuery = `SELECT character_id, spec, score FROM spec_scores ORDER BY character_id, score DESC`
+)
+
+// SpecScoreRepo implements SpecScoreRepository interface
+type SpecScoreRepo struct {
+	db Database
+}
+
+// NewSpecScoreRepo creates a new spec score repository
+func NewSpecScoreRepo(db Database) *SpecScoreRepo {
+	return &SpecScoreRepo{db: db}
+}
+
+// Replace swaps the character's stored spec scores for the given scores.
+func (r *SpecScoreRepo) Replace(ctx context.Context, characterID int, scores []SpecScore) error {
+	return r.db.WithTx(ctx, func(tx Database) error {
+		if err := tx.Query(ctx, deleteSpecScoresQuery, characterID); err != nil {
+			return err
+		}
+
+		for _, score := range scores {
+			if err := tx.Query(ctx, insertSpecScoreQuery, characterID, score.Spec, score.Score); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// List returns every character's spec scores keyed by character ID, each character's highest first.
+func (r *SpecScoreRepo) List(ctx context.Context) (map[int][]SpecScore, error) {
+	rows, err := r.db.QueryRows(ctx, listSpecScoresQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scores := make(map[int][]SpecScore)
+	for rows.Next() {
+		var s SpecScore
+		if err := rows.Scan(&s.CharacterID, &s.Spec, &s.Score); err != nil {
+			return nil, err
+		}
+		scores[s.CharacterID] = append(scores[s.CharacterID], s)
+	}
+
+	return scores, rows.Err()
+}
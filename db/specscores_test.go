@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecScoreRepo_Replace(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewSpecScoreRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("WithTx", ctx).Return(nil)
+	mockDB.On("Query", ctx, deleteSpecScoresQuery, []interface{}{1}).Return(nil)
+	mockDB.On("Query", ctx, insertSpecScoreQuery, []interface{}{1, "Frost", 2900.5}).Return(nil)
+	mockDB.On("Query", ctx, insertSpecScoreQuery, []interface{}{1, "Unholy", 2650.0}).Return(nil)
+
+	err := repo.Replace(ctx, 1, []SpecScore{{Spec: "Frost", Score: 2900.5}, {Spec: "Unholy", Score: 2650}})
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestSpecScoreRepo_List_Error(t *testing.T) {
+	mockDB := &MockDatabase{}
+	repo := NewSpecScoreRepo(mockDB)
+	ctx := context.Background()
+
+	mockDB.On("QueryRows", ctx, listSpecScoresQuery, []interface{}(nil)).Return((*sql.Rows)(nil), errors.New("mock error"))
+
+	scores, err := repo.List(ctx)
+	assert.Error(t, err)
+	assert.Nil(t, scores)
+	mockDB.AssertExpectations(t)
+}
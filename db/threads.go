@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"strconv"
+)
+
+// characterThreadPrefix starts the key of a thread for a single character, see CharacterThreadKey.
+const characterThreadPrefix = "character:"
+
+const (
+	upsertThreadQuery = `INSERT INTO announcement_threads (channel_id, thread_key, thread_id) VALUES (?, ?, ?)
+		ON CONFLICT (channel_id, thread_key) DO UPDATE SET thread_id = excluded.thread_id`
+
+	getThreadQuery = `SELECT thread_id FROM announcement_threads WHERE channel_id = ? AND thread_key = ?`
+
+	getThreadChannelQuery = `SELECT channel_id FROM announcement_threads WHERE thread_id = ? LIMIT 1`
+)
+
+// CharacterThreadKey is the key a thread for a single character is stored under, removing the character removes it.
+func CharacterThreadKey(characterID int) string {
+	return characterThreadPrefix + strconv.Itoa(characterID)
+}
+
+// ThreadRepo implements ThreadRepository interface
+type ThreadRepo struct {
+	db Database
+}
+
+// NewThreadRepo creates a new announcement thread repository
+func NewThreadRepo(db Database) *ThreadRepo {
+	return &ThreadRepo{db: db}
+}
+
+// GetThreadID returns the thread under the channel that key's score updates are posted in, or "" if there isn't one.
+func (r *ThreadRepo) GetThreadID(ctx context.Context, channelID, key string) (string, error) {
+	rows, err := r.db.QueryRows(ctx, getThreadQuery, channelID, key)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var threadID string
+	if rows.Next() {
+		if err := rows.Scan(&threadID); err != nil {
+			return "", err
+		}
+	}
+
+	return threadID, rows.Err()
+}
+
+// SetThreadID records the thread key's score updates are posted in, replacing any thread it had before.
+func (r *ThreadRepo) SetThreadID(ctx context.Context, channelID, key, threadID string) error {
+	return r.db.Query(ctx, upsertThreadQuery, channelID, key, threadID)
+}
+
+// GetChannelID returns the channel the thread was started under, or "" if it isn't one of the score update threads.
+func (r *ThreadRepo) GetChannelID(ctx context.Context, threadID string) (string, error) {
+	rows, err := r.db.QueryRows(ctx, getThreadChannelQuery, threadID)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var channelID string
+	if rows.Next() {
+		if err := rows.Scan(&channelID); err != nil {
+			return "", err
+		}
+	}
+
+	return channelID, rows.Err()
+}
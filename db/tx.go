@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// txDB is a Database scoped to a single transaction, it is what WithTx hands to its callback.
+type txDB struct {
+	tx   *sql.Tx
+	bind func(query string) string
+}
+
+// withTx begins a transaction on db and runs fn in it, committing if fn returns nil and rolling back otherwise.
+func withTx(ctx context.Context, db *sql.DB, bind func(query string) string, fn func(tx Database) error) (err error) {
+	if db == nil {
+		return ErrNoDatabase
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(&txDB{tx: tx, bind: bind}); err != nil {
+		slog.DebugContext(ctx, "rolling back transaction", "error", err)
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rollbackErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (t *txDB) Query(ctx context.Context, query string, args ...any) error {
+	query = t.bind(query)
+	slog.DebugContext(ctx, "executing query", "query", query, "args", args)
+	_, err := t.tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (t *txDB) QueryRows(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	query = t.bind(query)
+	slog.DebugContext(ctx, "executing query", "query", query, "args", args)
+	return t.tx.QueryContext(ctx, query, args...)
+}
+
+// WithTx joins the transaction that is already open, so repositories can nest calls without knowing who started it.
+func (t *txDB) WithTx(_ context.Context, fn func(tx Database) error) error {
+	return fn(t)
+}
+
+// Close is a no-op, the transaction is finished by the WithTx call that opened it.
+func (t *txDB) Close() error {
+	return nil
+}
+
+func noRebind(query string) string {
+	return query
+}
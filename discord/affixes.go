@@ -0,0 +1,41 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/bwmarrin/discordgo"
+)
+
+// BuildAffixesMessage lists this week's affixes with their descriptions, each linked to Wowhead.
+func BuildAffixesMessage(l i18n.Locale, affixes *raiderio.Affixes) discordgo.MessageSend {
+	var s strings.Builder
+	for _, a := range affixes.AffixDetails {
+		name := "**" + a.Name + "**"
+		if a.WowheadUrl != "" {
+			name = fmt.Sprintf("**[%s](%s)**", a.Name, a.WowheadUrl)
+		}
+
+		entry := fmt.Sprintf("%s\n%s\n\n", name, a.Description)
+		if s.Len()+len(entry) > maxEmbedDescriptionChars {
+			break
+		}
+		s.WriteString(entry)
+	}
+	if s.Len() == 0 {
+		s.WriteString(l.T("Raider.IO hasn't listed this week's affixes yet."))
+	}
+
+	return discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				URL:         affixes.LeaderboardURL,
+				Title:       l.T("This Week's Affixes"),
+				Description: strings.TrimSpace(s.String()),
+				Color:       affixesColour,
+			},
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildAffixesMessage(t *testing.T) {
+	affixes := &raiderio.Affixes{
+		LeaderboardURL: "https://raider.io/mythic-plus/affixes",
+		AffixDetails: []raiderio.Affix{
+			{Name: "Tyrannical", Description: "Bosses have 30% more health.", WowheadUrl: "https://wowhead.com/affix=9"},
+			{Name: "Fortified", Description: "Non-boss enemies have 20% more health."},
+		},
+	}
+
+	message := BuildAffixesMessage(i18n.English, affixes)
+
+	require.Len(t, message.Embeds, 1)
+	embed := message.Embeds[0]
+	assert.Equal(t, "This Week's Affixes", embed.Title)
+	assert.Equal(t, "https://raider.io/mythic-plus/affixes", embed.URL)
+	assert.Equal(t, "**[Tyrannical](https://wowhead.com/affix=9)**\nBosses have 30% more health.\n\n"+
+		"**Fortified**\nNon-boss enemies have 20% more health.", embed.Description)
+}
+
+func TestBuildAffixesMessage_NoAffixes(t *testing.T) {
+	message := BuildAffixesMessage(i18n.English, &raiderio.Affixes{})
+
+	assert.Equal(t, "Raider.IO hasn't listed this week's affixes yet.", message.Embeds[0].Description)
+}
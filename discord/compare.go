@@ -0,0 +1,212 @@
+package discord
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// comparedPerRow is how many characters fit side by side, Discord shows 3 inline fields on a row.
+	comparedPerRow = 3
+
+	// MaxComparedCharacters is how many characters fit in an embed, each row of characters takes two rows of fields
+	// and Discord allows 25 fields.
+	MaxComparedCharacters = 12
+)
+
+// Comparison is what the compare command shows about a character.
+type Comparison struct {
+	// Character is the tracked character, only the name and realm are set if they aren't tracked
+	Character db.Character
+	RaiderIO  raiderio.Character
+	// History is the character's score history, oldest first, it is empty if they aren't tracked
+	History []db.ScoreHistory
+}
+
+// tracked reports whether the character is tracked, only tracked characters have score history.
+func (c Comparison) tracked() bool {
+	return c.Character.ID != 0
+}
+
+// BuildCompareMessage shows the characters side by side, with their scores, ranks and score changes in one row and
+// their best key in each dungeon in the next. The best key in each dungeon is in bold. More characters than fit on a
+// row carry on in the rows below.
+func BuildCompareMessage(l i18n.Locale, comparisons []Comparison, now time.Time) discordgo.MessageSend {
+	embed := &discordgo.MessageEmbed{
+		Title: l.T("Head to Head"),
+		Color: scoresColour, //nolint:misspell // Discord not using the right language
+	}
+
+	dungeons, best := bestKeys(comparisons)
+	for start := 0; start < len(comparisons); start += comparedPerRow {
+		end := min(start+comparedPerRow, len(comparisons))
+
+		for _, c := range comparisons[start:end] {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   fmt.Sprintf("%s-%s", c.Character.Name, c.Character.Realm),
+				Value:  buildComparisonSummary(l, c, now),
+				Inline: true,
+			})
+		}
+
+		for i := start; i < end; i++ {
+			name := " "
+			if i == start {
+				name = l.T("Best Keys")
+			}
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+				Name:   name,
+				Value:  buildComparisonKeys(l, best[i], dungeons, best),
+				Inline: true,
+			})
+		}
+	}
+
+	return discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}
+}
+
+// buildComparisonSummary lists the character's scores, ranks and how much their score changed recently. Role scores
+// show the role's region rank, as the characters may be on different realms.
+func buildComparisonSummary(l i18n.Locale, c Comparison, now time.Time) string {
+	var scores raiderio.Scores
+	if len(c.RaiderIO.MythicPlusScoresBySeason) > 0 {
+		scores = c.RaiderIO.MythicPlusScoresBySeason[0].Scores
+	}
+	roleRanks := c.RaiderIO.MythicPlusRanks
+	ranks := roleRanks.Overall
+
+	lines := []string{
+		l.T("**Overall** %0.1f", scores.All),
+		withRegionRank(l, l.T("**Tank** %0.1f", scores.Tank), roleRanks.Tank),
+		withRegionRank(l, l.T("**Healer** %0.1f", scores.Healer), roleRanks.Healer),
+		withRegionRank(l, l.T("**DPS** %0.1f", scores.Dps), roleRanks.Dps),
+		l.T("**Realm** %s", formatRank(ranks.Realm)),
+		l.T("**Region** %s", formatRank(ranks.Region)),
+		l.T("**World** %s", formatRank(ranks.World)),
+	}
+	if !c.tracked() {
+		return strings.Join(append(lines, l.T("Not tracked, so no score history")), "\n")
+	}
+
+	for _, days := range []int{7, 30} {
+		change := "-"
+		if delta, ok := scoreDelta(c.History, c.Character.OverallScore, now.AddDate(0, 0, -days)); ok {
+			change = fmt.Sprintf("%+0.1f", delta)
+		}
+		lines = append(lines, l.T("**%d days** %s", days, change))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// withRegionRank adds the region rank to a line, unless Raider.IO hasn't ranked the character.
+func withRegionRank(l i18n.Locale, line string, rank raiderio.Rank) string {
+	if rank.Region == 0 {
+		return line
+	}
+	return line + " " + l.T("(#%d Region)", rank.Region)
+}
+
+// formatRank formats a rank like #12, Raider.IO ranks unranked characters 0.
+func formatRank(rank int) string {
+	if rank == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("#%d", rank)
+}
+
+// scoreDelta returns how much the score has changed since the given time. If the character was first tracked after
+// then, it is the change since they were first tracked.
+func scoreDelta(history []db.ScoreHistory, score float64, since time.Time) (float64, bool) {
+	if len(history) == 0 {
+		return 0, false
+	}
+
+	base := history[0]
+	for _, h := range history {
+		if h.DateRecorded > since.Unix() {
+			break
+		}
+		base = h
+	}
+	return score - base.OverallScore, true
+}
+
+// keystone is a character's best key in a dungeon.
+type keystone struct {
+	level int
+	timed bool
+}
+
+// dungeon is a dungeon any of the compared characters have run.
+type dungeon struct {
+	name      string
+	shortName string
+}
+
+// bestKeys returns the dungeons any of the characters have run, sorted by name, along with each character's best key
+// in them. Alternate runs are included, as the best run in a dungeon might be on the other week's affixes.
+func bestKeys(comparisons []Comparison) ([]dungeon, []map[string]keystone) {
+	shortNames := make(map[string]string)
+	best := make([]map[string]keystone, len(comparisons))
+	for i, c := range comparisons {
+		best[i] = make(map[string]keystone)
+		for _, run := range append(c.RaiderIO.MythicPlusBestRuns, c.RaiderIO.MythicPlusAlternateRuns...) {
+			shortNames[run.Dungeon] = run.ShortName
+			if key := best[i][run.Dungeon]; run.MythicLevel > key.level {
+				best[i][run.Dungeon] = keystone{level: run.MythicLevel, timed: run.NumKeystoneUpgrades > 0}
+			}
+		}
+	}
+
+	dungeons := make([]dungeon, 0, len(shortNames))
+	for name, shortName := range shortNames {
+		dungeons = append(dungeons, dungeon{name: name, shortName: shortName})
+	}
+	sort.Slice(dungeons, func(i, j int) bool {
+		return dungeons[i].name < dungeons[j].name
+	})
+
+	return dungeons, best
+}
+
+// buildComparisonKeys lists a character's best key in each dungeon by its short name to fit the column, the highest
+// key of all the characters is in bold.
+func buildComparisonKeys(l i18n.Locale, keys map[string]keystone, dungeons []dungeon, best []map[string]keystone) string {
+	if len(dungeons) == 0 {
+		return l.T("No runs this season.")
+	}
+
+	var s strings.Builder
+	for _, d := range dungeons {
+		key, ok := keys[d.name]
+		if !ok {
+			s.WriteString(d.shortName + " -\n")
+			continue
+		}
+
+		level := formatKeystoneLevel(key.level, key.timed)
+		if key.level == highestKey(best, d.name) {
+			level = "**" + level + "**"
+		}
+		s.WriteString(d.shortName + " " + level + "\n")
+	}
+
+	return s.String()
+}
+
+// highestKey returns the highest key any of the characters has done in the dungeon.
+func highestKey(best []map[string]keystone, dungeon string) int {
+	highest := 0
+	for _, keys := range best {
+		highest = max(highest, keys[dungeon].level)
+	}
+	return highest
+}
@@ -0,0 +1,124 @@
+package discord
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var compareNow = time.Date(2024, time.September, 30, 12, 0, 0, 0, time.UTC)
+
+func testComparison(id int, name string, score float64, runs ...raiderio.Run) Comparison {
+	var rc raiderio.Character
+	rc.MythicPlusScoresBySeason = []raiderio.Season{{Scores: raiderio.Scores{All: score, Dps: score}}}
+	rc.MythicPlusRanks.Overall = raiderio.Rank{World: 1500, Region: 600, Realm: 12}
+	rc.MythicPlusBestRuns = runs
+
+	return Comparison{
+		Character: db.Character{ID: id, Name: name, Realm: "testrealm", OverallScore: score},
+		RaiderIO:  rc,
+	}
+}
+
+func TestBuildCompareMessage(t *testing.T) {
+	first := testComparison(1, "Testchar", 2600,
+		raiderio.Run{Dungeon: "Ara-Kara, City of Echoes", ShortName: "ARAK", MythicLevel: 12, NumKeystoneUpgrades: 1},
+		raiderio.Run{Dungeon: "The Stonevault", ShortName: "SV", MythicLevel: 10, NumKeystoneUpgrades: 1})
+	first.History = []db.ScoreHistory{
+		{OverallScore: 2400, DateRecorded: compareNow.AddDate(0, 0, -40).Unix()},
+		{OverallScore: 2550, DateRecorded: compareNow.AddDate(0, 0, -10).Unix()},
+	}
+	first.RaiderIO.MythicPlusRanks.Dps = raiderio.Rank{World: 900, Region: 350, Realm: 8}
+	second := testComparison(0, "Otherchar", 2500,
+		raiderio.Run{Dungeon: "The Stonevault", ShortName: "SV", MythicLevel: 11})
+
+	message := BuildCompareMessage(i18n.English, []Comparison{first, second}, compareNow)
+
+	embed := message.Embeds[0]
+	assert.Equal(t, "Head to Head", embed.Title)
+	require.Len(t, embed.Fields, 4)
+
+	assert.Equal(t, "Testchar-testrealm", embed.Fields[0].Name)
+	assert.True(t, embed.Fields[0].Inline)
+	assert.Equal(t, "**Overall** 2600.0\n**Tank** 0.0\n**Healer** 0.0\n**DPS** 2600.0 (#350 Region)\n"+
+		"**Realm** #12\n**Region** #600\n**World** #1500\n**7 days** +50.0\n**30 days** +200.0", embed.Fields[0].Value)
+	assert.Contains(t, embed.Fields[1].Value, "Not tracked, so no score history")
+	assert.NotContains(t, embed.Fields[1].Value, "days")
+
+	// The highest key in each dungeon is bold, dungeons a character hasn't run are still listed to keep rows aligned
+	assert.Equal(t, "Best Keys", embed.Fields[2].Name)
+	assert.Equal(t, "ARAK **+12**\nSV +10\n", embed.Fields[2].Value)
+	assert.Equal(t, " ", embed.Fields[3].Name)
+	assert.Equal(t, "ARAK -\nSV **~~+11~~**\n", embed.Fields[3].Value)
+}
+
+func TestBuildCompareMessage_Rows(t *testing.T) {
+	comparisons := make([]Comparison, 5)
+	for i := range comparisons {
+		comparisons[i] = testComparison(i+1, fmt.Sprintf("Char%d", i+1), 2000,
+			raiderio.Run{Dungeon: "The Stonevault", ShortName: "SV", MythicLevel: 10 + i, NumKeystoneUpgrades: 1})
+	}
+
+	message := BuildCompareMessage(i18n.English, comparisons, compareNow)
+
+	// Three characters fit on a row, the other two carry on below with their own best keys row
+	embed := message.Embeds[0]
+	require.Len(t, embed.Fields, 10)
+	names := make([]string, 0, len(embed.Fields))
+	for _, field := range embed.Fields {
+		names = append(names, field.Name)
+	}
+	assert.Equal(t, []string{
+		"Char1-testrealm", "Char2-testrealm", "Char3-testrealm", "Best Keys", " ", " ",
+		"Char4-testrealm", "Char5-testrealm", "Best Keys", " ",
+	}, names)
+
+	// The best key is the highest of every character, not just those on the row
+	assert.Equal(t, "SV +12\n", embed.Fields[5].Value)
+	assert.Equal(t, "SV **+14**\n", embed.Fields[9].Value)
+}
+
+func TestBuildCompareMessage_NoRuns(t *testing.T) {
+	message := BuildCompareMessage(i18n.English,
+		[]Comparison{testComparison(1, "Testchar", 0), testComparison(2, "Otherchar", 0)}, compareNow)
+
+	embed := message.Embeds[0]
+	assert.Contains(t, embed.Fields[0].Value, "**Realm** #12")
+	assert.Contains(t, embed.Fields[0].Value, "**7 days** -")
+	assert.Equal(t, "No runs this season.", embed.Fields[2].Value)
+}
+
+func TestScoreDelta(t *testing.T) {
+	history := []db.ScoreHistory{
+		{OverallScore: 2000, DateRecorded: 100},
+		{OverallScore: 2100, DateRecorded: 200},
+		{OverallScore: 2300, DateRecorded: 300},
+	}
+
+	tests := []struct {
+		name  string
+		since int64
+		want  float64
+	}{
+		{name: "before tracking starts uses the first score", since: 50, want: 500},
+		{name: "uses the score at the time", since: 200, want: 400},
+		{name: "uses the last score before the time", since: 250, want: 400},
+		{name: "after the last score", since: 400, want: 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta, ok := scoreDelta(history, 2500, time.Unix(tt.since, 0))
+			assert.True(t, ok)
+			assert.InDelta(t, tt.want, delta, 0.001)
+		})
+	}
+
+	_, ok := scoreDelta(nil, 2500, compareNow)
+	assert.False(t, ok)
+}
@@ -6,12 +6,19 @@ import (
 	"sort"
 
 	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
 	"github.com/bwmarrin/discordgo"
 )
 
 type SenderIface interface {
 	SendMessage(ctx context.Context, channelID, content string) error
 	SendComplexMessage(ctx context.Context, channelID string, message discordgo.MessageSend) error
+	AddRole(ctx context.Context, guildID, userID, roleID string) error
+	RemoveRole(ctx context.Context, guildID, userID, roleID string) error
+	// UpdateInteractionMessage replaces the message whose component was used with message.
+	UpdateInteractionMessage(ctx context.Context, interaction *discordgo.Interaction, message discordgo.MessageSend) error
+	// RespondEphemeral replies to an interaction with a message only the user who triggered it can see.
+	RespondEphemeral(ctx context.Context, interaction *discordgo.Interaction, content string) error
 }
 
 type Sender struct {
@@ -19,9 +26,13 @@ type Sender struct {
 }
 
 const (
-	maxEmbedFieldChars = 1024
-	maxEmbedFields     = 24
-	scoresColour       = 2326507
+	maxEmbedFieldChars       = 1024
+	maxEmbedDescriptionChars = 4096
+	maxEmbedFields           = 24
+	scoresColour             = 2326507
+	vaultColour              = 10181046
+	affixesColour            = 15105570
+	milestoneColour          = 16766720
 )
 
 func NewDiscordSender(session *discordgo.Session) *Sender {
@@ -38,33 +49,86 @@ func (d *Sender) SendComplexMessage(_ context.Context, channelID string, message
 	return err
 }
 
-func BuildScoresMessage(characters []db.Character) discordgo.MessageSend {
+func (d *Sender) AddRole(_ context.Context, guildID, userID, roleID string) error {
+	return d.session.GuildMemberRoleAdd(guildID, userID, roleID)
+}
+
+// ChannelGuildID returns the ID of the server the channel is in.
+func (d *Sender) ChannelGuildID(ctx context.Context, channelID string) (string, error) {
+	channel, err := d.session.Channel(channelID, discordgo.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	return channel.GuildID, nil
+}
+
+func (d *Sender) RemoveRole(_ context.Context, guildID, userID, roleID string) error {
+	return d.session.GuildMemberRoleRemove(guildID, userID, roleID)
+}
+
+func (d *Sender) UpdateInteractionMessage(_ context.Context, interaction *discordgo.Interaction, message discordgo.MessageSend) error {
+	return d.session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    message.Content,
+			Embeds:     message.Embeds,
+			Components: message.Components,
+		},
+	})
+}
+
+func (d *Sender) RespondEphemeral(_ context.Context, interaction *discordgo.Interaction, content string) error {
+	return d.session.InteractionRespond(interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// BuildScoresMessage shows a page of the characters ranked by score, with buttons to move between pages when they
+// don't fit on one.
+func BuildScoresMessage(l i18n.Locale, characters []db.Character, page ScoresPage) discordgo.MessageSend {
 	sort.Slice(characters, func(i, j int) bool {
 		return characters[i].OverallScore > characters[j].OverallScore
 	})
 
-	return discordgo.MessageSend{
-		Embeds: []*discordgo.MessageEmbed{
-			{
-				Title:  "Tracked Characters",
-				Color:  scoresColour, //nolint:misspell // Discord not using the right language
-				Fields: buildScoresFields(characters),
-			},
-		},
+	pages := scoresPages(len(characters))
+	page.Page = min(max(page.Page, 0), pages-1)
+	first := page.Page * scoresPageSize
+	last := min(first+scoresPageSize, len(characters))
+
+	embed := &discordgo.MessageEmbed{
+		Title:  l.T("Tracked Characters"),
+		Color:  scoresColour, //nolint:misspell // Discord not using the right language
+		Fields: buildScoresFields(l, characters[first:last], first+1),
+	}
+	message := discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}
+	if pages > 1 {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: l.T("Page %d of %d", page.Page+1, pages)}
+		message.Components = buildPageButtons(l, page, pages)
 	}
+
+	return message
 }
 
-func buildScoresFields(characters []db.Character) []*discordgo.MessageEmbedField {
+// buildScoresFields lists the characters in columns, numbering them from rank.
+func buildScoresFields(l i18n.Locale, characters []db.Character, rank int) []*discordgo.MessageEmbedField {
 	fields := getBasicScoresFields()
 	charField := 0
 	scoreField := 1
 	for i, c := range characters {
-		msg := fmt.Sprintf("%d) [%s-%s](https://raider.io/characters/us/%s/%s)\n", i+1, c.Name, c.Realm, c.Realm, c.Name)
+		msg := fmt.Sprintf("%d) [%s-%s](https://raider.io/characters/us/%s/%s)\n", rank+i, c.Name, c.Realm, c.Realm, c.Name)
 		score := fmt.Sprintf("%0.0f\n", c.OverallScore)
-		if len(msg)+len(fields[charField].Value) >= maxEmbedFieldChars {
+		if len(c.SpecScores) > 0 {
+			score = fmt.Sprintf("%0.0f · %s\n", c.OverallScore, formatSpecScores(l, c, 1))
+		}
+		if len(msg)+len(fields[charField].Value) >= maxEmbedFieldChars ||
+			len(score)+len(fields[scoreField].Value) >= maxEmbedFieldChars {
 			// there is a max of 25 fields
 			if charField >= maxEmbedFields {
-				fields[charField].Value += "\nToo many characters tracked to list them all."
+				fields[charField].Value += "\n" + l.T("Too many characters tracked to list them all.")
 				break
 			}
 			charField += 3
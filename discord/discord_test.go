@@ -7,9 +7,11 @@ import (
 	"testing"
 
 	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
 	"github.com/bwmarrin/discordgo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockSender is a mock implementation of the SenderIface interface for testing
@@ -27,6 +29,26 @@ func (m *MockSender) SendComplexMessage(ctx context.Context, channelID string, m
 	return args.Error(0)
 }
 
+func (m *MockSender) AddRole(ctx context.Context, guildID, userID, roleID string) error {
+	args := m.Called(ctx, guildID, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockSender) RemoveRole(ctx context.Context, guildID, userID, roleID string) error {
+	args := m.Called(ctx, guildID, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockSender) UpdateInteractionMessage(ctx context.Context, interaction *discordgo.Interaction, message discordgo.MessageSend) error {
+	args := m.Called(ctx, interaction, message)
+	return args.Error(0)
+}
+
+func (m *MockSender) RespondEphemeral(ctx context.Context, interaction *discordgo.Interaction, content string) error {
+	args := m.Called(ctx, interaction, content)
+	return args.Error(0)
+}
+
 // Test DiscordSender
 
 func TestNewDiscordSender(t *testing.T) {
@@ -149,7 +171,7 @@ func TestBuildScoresMessage(t *testing.T) {
 		},
 	}
 
-	message := BuildScoresMessage(characters)
+	message := BuildScoresMessage(i18n.English, characters, ScoresPage{})
 
 	// Test embeds
 	assert.Len(t, message.Embeds, 1)
@@ -159,12 +181,36 @@ func TestBuildScoresMessage(t *testing.T) {
 	assert.NotEmpty(t, embed.Fields)
 }
 
+func TestBuildScoresMessage_SpecScores(t *testing.T) {
+	characters := []db.Character{
+		{
+			Name:         "Char1",
+			Realm:        "realm1",
+			Class:        "DeathKnight",
+			OverallScore: 2950.0,
+			SpecScores:   []db.SpecScore{{Spec: "Frost", Score: 2900}, {Spec: "Unholy", Score: 2650}},
+		},
+		{
+			Name:         "Char2",
+			Realm:        "realm2",
+			Class:        "Mage",
+			OverallScore: 2300.0,
+		},
+	}
+
+	message := BuildScoresMessage(i18n.English, characters, ScoresPage{})
+
+	fields := message.Embeds[0].Fields
+	require.Len(t, fields, 2)
+	assert.Equal(t, "2950 · Frost DK 2900\n2300\n", fields[1].Value)
+}
+
 // Test BuildScoresMessage with empty characters
 
 func TestBuildScoresMessage_EmptyCharacters(t *testing.T) {
 	characters := []db.Character{}
 
-	message := BuildScoresMessage(characters)
+	message := BuildScoresMessage(i18n.English, characters, ScoresPage{})
 
 	// Test embeds
 	assert.Len(t, message.Embeds, 1)
@@ -188,7 +234,7 @@ func TestBuildScoresFields_ManyCharacters(t *testing.T) {
 		}
 	}
 
-	fields := buildScoresFields(characters)
+	fields := buildScoresFields(i18n.English, characters, 1)
 
 	// Should have fields but not exceed the limit
 	assert.NotEmpty(t, fields)
@@ -203,7 +249,7 @@ func TestBuildScoresFields_FewCharacters(t *testing.T) {
 		{Name: "Char2", Realm: "realm2", OverallScore: 2300.0},
 	}
 
-	fields := buildScoresFields(characters)
+	fields := buildScoresFields(i18n.English, characters, 1)
 
 	// Should have exactly 2 fields (character field and score field)
 	assert.Len(t, fields, 2)
@@ -0,0 +1,123 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/blizzard"
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// BuildDungeonsMessage shows the character's best run in each dungeon this season, highest rated first.
+func BuildDungeonsMessage(l i18n.Locale, season *blizzard.MythicKeystoneSeason) discordgo.MessageSend {
+	name, realm := season.Character.Name, season.Character.Realm.Slug
+
+	description := l.T("No runs this season.")
+	if runs := buildDungeonList(l, season.BestRunsByDungeon()); runs != "" {
+		description = runs
+	}
+
+	return discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				URL:         fmt.Sprintf("https://raider.io/characters/us/%s/%s", realm, name),
+				Title:       fmt.Sprintf("%s-%s", name, realm),
+				Description: description,
+				Fields: []*discordgo.MessageEmbedField{
+					{Name: l.T("Season"), Value: fmt.Sprintf("%d", season.Season.ID), Inline: true},
+					{Name: l.T("Mythic+ Rating"), Value: fmt.Sprintf("%0.1f", season.MythicRating.Rating), Inline: true},
+				},
+			},
+		},
+	}
+}
+
+// buildDungeonList lists each dungeon with its key level, time and map rating. Depleted keys are struck through.
+func buildDungeonList(l i18n.Locale, runs []blizzard.BestRun) string {
+	var s strings.Builder
+	for _, run := range runs {
+		s.WriteString(l.T("**%s** %s in %s (%0.1f)", run.Dungeon.Name,
+			formatKeystoneLevel(run.KeystoneLevel, run.IsCompletedWithinTime), formatRunDuration(run.Duration),
+			run.MapRating.Rating) + "\n")
+	}
+
+	return s.String()
+}
+
+// formatKeystoneLevel formats a key level like +12, depleted keys are struck through.
+func formatKeystoneLevel(level int, timed bool) string {
+	key := fmt.Sprintf("+%d", level)
+	if !timed {
+		key = "~~" + key + "~~"
+	}
+	return key
+}
+
+// formatRunDuration formats a run's duration in milliseconds as minutes and seconds, e.g. 32:05.
+func formatRunDuration(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// DungeonPB is a run that beat a character's previous best in the dungeon. Previous is empty if it was their first
+// run of the dungeon this season.
+type DungeonPB struct {
+	Run      db.DungeonRun
+	Previous db.DungeonRun
+}
+
+// BuildDungeonPBMessage announces the dungeons a character has set a new personal best in.
+func BuildDungeonPBMessage(l i18n.Locale, c db.Character, pbs []DungeonPB) discordgo.MessageSend {
+	var s strings.Builder
+	for _, pb := range pbs {
+		s.WriteString(l.T("New dungeon PB: **%s** %s", pb.Run.DungeonName,
+			formatKeystoneLevel(pb.Run.KeystoneLevel, pb.Run.Timed)))
+		if pb.Previous.KeystoneLevel > 0 {
+			s.WriteString(" " + l.T("(was %s)", formatKeystoneLevel(pb.Previous.KeystoneLevel, pb.Previous.Timed)))
+		}
+		s.WriteString("\n")
+	}
+
+	return discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				URL:         fmt.Sprintf("https://raider.io/characters/us/%s/%s", c.Realm, c.Name),
+				Title:       fmt.Sprintf("%s-%s", c.Name, c.Realm),
+				Description: s.String(),
+				Color:       getClassColour(c.Class), //nolint:misspell // blizzards fault
+				Author: &discordgo.MessageEmbedAuthor{
+					Name:    specAndClass(l, c),
+					IconURL: getClassIcon(c.Class),
+				},
+			},
+		},
+	}
+}
+
+// BuildDungeonLeaderboardMessage ranks the tracked characters by their best run in a dungeon.
+func BuildDungeonLeaderboardMessage(l i18n.Locale, dungeon db.Dungeon, entries []db.DungeonRunEntry) discordgo.MessageSend {
+	var s strings.Builder
+	for i, e := range entries {
+		line := fmt.Sprintf("%d. %s\n", i+1, l.T("**%s-%s** %s in %s (%0.1f)", e.Name, e.Realm,
+			formatKeystoneLevel(e.KeystoneLevel, e.Timed), formatRunDuration(e.Duration), e.MapRating))
+		if s.Len()+len(line) > maxEmbedDescriptionChars {
+			break
+		}
+		s.WriteString(line)
+	}
+	if s.Len() == 0 {
+		s.WriteString(l.T("No runs this season."))
+	}
+
+	return discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       dungeon.Name,
+				Description: s.String(),
+			},
+		},
+	}
+}
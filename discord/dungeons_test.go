@@ -0,0 +1,93 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/blizzard"
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDungeonsMessage(t *testing.T) {
+	season := &blizzard.MythicKeystoneSeason{
+		BestRuns: []blizzard.BestRun{
+			{
+				KeystoneLevel: 10, Duration: 1925000, IsCompletedWithinTime: false,
+				Dungeon: blizzard.NamedRef{ID: 2, Name: "The Stonevault"}, MapRating: blizzard.Rating{Rating: 250},
+			},
+			{
+				KeystoneLevel: 12, Duration: 1800000, IsCompletedWithinTime: true,
+				Dungeon: blizzard.NamedRef{ID: 1, Name: "Ara-Kara"}, MapRating: blizzard.Rating{Rating: 300.5},
+			},
+		},
+		MythicRating: blizzard.Rating{Rating: 2750.5},
+	}
+	season.Season.ID = 13
+	season.Character.Name = "Paladylan"
+	season.Character.Realm.Slug = "tichondrius"
+
+	message := BuildDungeonsMessage(i18n.English, season)
+
+	require.Len(t, message.Embeds, 1)
+	embed := message.Embeds[0]
+	assert.Equal(t, "Paladylan-tichondrius", embed.Title)
+	assert.Equal(t, "**Ara-Kara** +12 in 30:00 (300.5)\n**The Stonevault** ~~+10~~ in 32:05 (250.0)\n",
+		embed.Description)
+	require.Len(t, embed.Fields, 2)
+	assert.Equal(t, "13", embed.Fields[0].Value)
+	assert.Equal(t, "2750.5", embed.Fields[1].Value)
+}
+
+func TestBuildDungeonsMessage_NoRuns(t *testing.T) {
+	message := BuildDungeonsMessage(i18n.English, &blizzard.MythicKeystoneSeason{})
+
+	assert.Equal(t, "No runs this season.", message.Embeds[0].Description)
+}
+
+func TestBuildDungeonPBMessage(t *testing.T) {
+	character := db.Character{Name: "Paladylan", Realm: "tichondrius", Class: "Paladin", Spec: "Protection"}
+	pbs := []DungeonPB{
+		{
+			Run:      db.DungeonRun{DungeonName: "Ara-Kara", KeystoneLevel: 12, Timed: true},
+			Previous: db.DungeonRun{DungeonName: "Ara-Kara", KeystoneLevel: 10, Timed: true},
+		},
+		{Run: db.DungeonRun{DungeonName: "The Stonevault", KeystoneLevel: 9}},
+	}
+
+	message := BuildDungeonPBMessage(i18n.English, character, pbs)
+
+	require.Len(t, message.Embeds, 1)
+	embed := message.Embeds[0]
+	assert.Equal(t, "Paladylan-tichondrius", embed.Title)
+	assert.Equal(t, "Protection Paladin", embed.Author.Name)
+	assert.Equal(t, "New dungeon PB: **Ara-Kara** +12 (was +10)\nNew dungeon PB: **The Stonevault** ~~+9~~\n",
+		embed.Description)
+}
+
+func TestBuildDungeonLeaderboardMessage(t *testing.T) {
+	entries := []db.DungeonRunEntry{
+		{
+			DungeonRun: db.DungeonRun{KeystoneLevel: 12, MapRating: 300, Duration: 1800000, Timed: true},
+			Name:       "Paladylan", Realm: "tichondrius",
+		},
+		{
+			DungeonRun: db.DungeonRun{KeystoneLevel: 11, MapRating: 270.5, Duration: 2000000},
+			Name:       "Magedylan", Realm: "area-52",
+		},
+	}
+
+	message := BuildDungeonLeaderboardMessage(i18n.English, db.Dungeon{ID: 1, Name: "Ara-Kara"}, entries)
+
+	require.Len(t, message.Embeds, 1)
+	assert.Equal(t, "Ara-Kara", message.Embeds[0].Title)
+	assert.Equal(t, "1. **Paladylan-tichondrius** +12 in 30:00 (300.0)\n2. **Magedylan-area-52** ~~+11~~ in 33:20 (270.5)\n",
+		message.Embeds[0].Description)
+}
+
+func TestBuildDungeonLeaderboardMessage_NoRuns(t *testing.T) {
+	message := BuildDungeonLeaderboardMessage(i18n.English, db.Dungeon{ID: 1, Name: "Ara-Kara"}, nil)
+
+	assert.Equal(t, "No runs this season.", message.Embeds[0].Description)
+}
@@ -0,0 +1,25 @@
+package discord
+
+import (
+	"bytes"
+
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/roster"
+	"github.com/bwmarrin/discordgo"
+)
+
+// BuildExportMessage attaches the exported roster files to a message.
+func BuildExportMessage(l i18n.Locale, files []roster.File) discordgo.MessageSend {
+	msg := discordgo.MessageSend{
+		Content: l.T("Exported roster (%d file(s) attached).", len(files)),
+	}
+	for _, f := range files {
+		msg.Files = append(msg.Files, &discordgo.File{
+			Name:        f.Name,
+			ContentType: f.ContentType,
+			Reader:      bytes.NewReader(f.Data),
+		})
+	}
+
+	return msg
+}
@@ -0,0 +1,29 @@
+package discord
+
+import (
+	"io"
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/roster"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildExportMessage(t *testing.T) {
+	files := []roster.File{
+		{Name: "characters.csv", ContentType: "text/csv", Data: []byte("id,name\n")},
+		{Name: "score_history.csv", ContentType: "text/csv", Data: []byte("character_id\n")},
+	}
+
+	message := BuildExportMessage(i18n.English, files)
+
+	assert.Equal(t, "Exported roster (2 file(s) attached).", message.Content)
+	require.Len(t, message.Files, 2)
+	assert.Equal(t, "characters.csv", message.Files[0].Name)
+	assert.Equal(t, "text/csv", message.Files[0].ContentType)
+
+	data, err := io.ReadAll(message.Files[0].Reader)
+	require.NoError(t, err)
+	assert.Equal(t, "id,name\n", string(data))
+}
@@ -0,0 +1,52 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DylanNZL/mythicplusbot/guild"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/bwmarrin/discordgo"
+)
+
+// BuildGuildMessage shows the guild's mythic+ ranking and how many tracked members have reached each score threshold.
+func BuildGuildMessage(l i18n.Locale, summary guild.Summary) discordgo.MessageSend {
+	g := summary.Guild
+
+	ranking := l.T("Not ranked yet.")
+	if g.MythicPlusRanks.Realm != 0 {
+		ranking = l.T("#%d Realm - #%d Region - #%d World", g.MythicPlusRanks.Realm, g.MythicPlusRanks.Region,
+			g.MythicPlusRanks.World)
+	}
+
+	var thresholds strings.Builder
+	for i, threshold := range guild.Thresholds {
+		fmt.Fprintf(&thresholds, "**%0.f+**: %d\n", threshold, summary.AboveThreshold[i])
+	}
+
+	return discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				URL:   g.ProfileUrl,
+				Title: fmt.Sprintf("<%s> %s", g.Name, g.Realm),
+				Color: scoresColour,
+				Fields: []*discordgo.MessageEmbedField{
+					{Name: l.T("Mythic+ Ranking"), Value: ranking},
+					{Name: l.T("Tracked Members (%d)", summary.Members), Value: thresholds.String()},
+				},
+			},
+		},
+	}
+}
+
+// BuildGuildRankMessage announces the guild's realm rank moving from the previous rank.
+func BuildGuildRankMessage(l i18n.Locale, g raiderio.Guild, previousRealmRank int) string {
+	ranks := g.MythicPlusRanks
+	if ranks.Realm > previousRealmRank {
+		return l.T("<%s> dropped from #%d to #%d on %s for Mythic+ (#%d Region - #%d World)",
+			g.Name, previousRealmRank, ranks.Realm, g.Realm, ranks.Region, ranks.World)
+	}
+	return l.T("<%s> climbed from #%d to #%d on %s for Mythic+ (#%d Region - #%d World)",
+		g.Name, previousRealmRank, ranks.Realm, g.Realm, ranks.Region, ranks.World)
+}
@@ -0,0 +1,50 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/guild"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildGuildMessage(t *testing.T) {
+	summary := guild.Summary{
+		Guild: raiderio.Guild{
+			Name:            "Test Guild",
+			Realm:           "Tichondrius",
+			ProfileUrl:      "https://raider.io/guilds/us/tichondrius/Test%20Guild",
+			MythicPlusRanks: raiderio.Rank{World: 1200, Region: 450, Realm: 12},
+		},
+		Members:        3,
+		AboveThreshold: []int{2, 2, 1},
+	}
+
+	message := BuildGuildMessage(i18n.English, summary)
+
+	require.Len(t, message.Embeds, 1)
+	embed := message.Embeds[0]
+	assert.Equal(t, "<Test Guild> Tichondrius", embed.Title)
+	require.Len(t, embed.Fields, 2)
+	assert.Equal(t, "#12 Realm - #450 Region - #1200 World", embed.Fields[0].Value)
+	assert.Equal(t, "Tracked Members (3)", embed.Fields[1].Name)
+	assert.Equal(t, "**2000+**: 2\n**2500+**: 2\n**3000+**: 1\n", embed.Fields[1].Value)
+}
+
+func TestBuildGuildMessage_Unranked(t *testing.T) {
+	message := BuildGuildMessage(i18n.English, guild.Summary{AboveThreshold: []int{0, 0, 0}})
+
+	assert.Equal(t, "Not ranked yet.", message.Embeds[0].Fields[0].Value)
+}
+
+func TestBuildGuildRankMessage(t *testing.T) {
+	g := raiderio.Guild{Name: "Test Guild", Realm: "Tichondrius",
+		MythicPlusRanks: raiderio.Rank{World: 1100, Region: 420, Realm: 10}}
+
+	assert.Equal(t, "<Test Guild> climbed from #12 to #10 on Tichondrius for Mythic+ (#420 Region - #1100 World)",
+		BuildGuildRankMessage(i18n.English, g, 12))
+	assert.Equal(t, "<Test Guild> dropped from #8 to #10 on Tichondrius for Mythic+ (#420 Region - #1100 World)",
+		BuildGuildRankMessage(i18n.English, g, 8))
+}
@@ -0,0 +1,65 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// LiveLeaderboardSize is how many characters the live leaderboard shows.
+const LiveLeaderboardSize = 25
+
+var ErrMessageNotFound = errors.New("message not found")
+
+// PostMessage sends the message and returns its ID, so it can be edited later.
+func (d *Sender) PostMessage(ctx context.Context, channelID string, message discordgo.MessageSend) (string, error) {
+	sent, err := d.session.ChannelMessageSendComplex(channelID, &message, discordgo.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	return sent.ID, nil
+}
+
+// EditMessage replaces the content and embeds of a message the bot sent, returning ErrMessageNotFound if it was
+// deleted.
+func (d *Sender) EditMessage(ctx context.Context, channelID, messageID string, message discordgo.MessageSend) error {
+	_, err := d.session.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:      messageID,
+		Channel: channelID,
+		Content: &message.Content,
+		Embeds:  &message.Embeds,
+	}, discordgo.WithContext(ctx))
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound {
+		return ErrMessageNotFound
+	}
+	return err
+}
+
+func (d *Sender) PinMessage(ctx context.Context, channelID, messageID string) error {
+	return d.session.ChannelMessagePin(channelID, messageID, discordgo.WithContext(ctx))
+}
+
+// BuildLiveLeaderboardMessage shows the top characters by score, it is edited in place after each update so shows
+// when it was last updated.
+func BuildLiveLeaderboardMessage(l i18n.Locale, characters []db.Character, updated time.Time) discordgo.MessageSend {
+	sort.Slice(characters, func(i, j int) bool {
+		return characters[i].OverallScore > characters[j].OverallScore
+	})
+	characters = characters[:min(len(characters), LiveLeaderboardSize)]
+
+	return discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{{
+		Title:     l.T("Live Leaderboard"),
+		Color:     scoresColour, //nolint:misspell // Discord not using the right language
+		Fields:    buildScoresFields(l, characters, 1),
+		Footer:    &discordgo.MessageEmbedFooter{Text: l.T("Last updated")},
+		Timestamp: updated.UTC().Format(time.RFC3339),
+	}}}
+}
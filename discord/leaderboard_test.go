@@ -0,0 +1,29 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildLiveLeaderboardMessage(t *testing.T) {
+	updated := time.Date(2024, time.September, 17, 15, 0, 0, 0, time.UTC)
+
+	message := BuildLiveLeaderboardMessage(i18n.English, testLeaderboard(30), updated)
+
+	embed := message.Embeds[0]
+	var listed strings.Builder
+	for _, f := range embed.Fields {
+		listed.WriteString(f.Value)
+	}
+	assert.Equal(t, "Live Leaderboard", embed.Title)
+	assert.Contains(t, listed.String(), "1) [Char1-realm1]")
+	assert.Contains(t, listed.String(), "25) [Char25-realm1]")
+	assert.NotContains(t, listed.String(), "Char26-")
+	assert.Equal(t, "2024-09-17T15:00:00Z", embed.Timestamp)
+	// It is edited in place, so has no buttons that could expire
+	assert.Empty(t, message.Components)
+}
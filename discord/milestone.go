@@ -0,0 +1,28 @@
+package discord
+
+import (
+	"fmt"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// BuildMilestoneMessage celebrates a character reaching a score milestone, e.g. 2500 or the title cutoff.
+func BuildMilestoneMessage(l i18n.Locale, c db.Character, milestone string, threshold float64) discordgo.MessageSend {
+	return discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				URL:   fmt.Sprintf("https://raider.io/characters/us/%s/%s", c.Realm, c.Name),
+				Title: l.T("%s-%s reached %s!", c.Name, c.Realm, milestone),
+				Description: l.T("**%s-%s** (%s) is now at **%0.1f**, passing the %0.f milestone.",
+					c.Name, c.Realm, specAndClass(l, c), c.OverallScore, threshold),
+				Color: milestoneColour,
+				Author: &discordgo.MessageEmbedAuthor{
+					Name:    l.T("Milestone Reached"),
+					IconURL: getClassIcon(c.Class),
+				},
+			},
+		},
+	}
+}
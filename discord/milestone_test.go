@@ -0,0 +1,23 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMilestoneMessage(t *testing.T) {
+	c := db.Character{Name: "Paladylan", Realm: "tichondrius", Class: "Paladin", Spec: "Protection", OverallScore: 2512.4}
+
+	message := BuildMilestoneMessage(i18n.English, c, "2500", 2500)
+
+	require.Len(t, message.Embeds, 1)
+	embed := message.Embeds[0]
+	assert.Equal(t, "Paladylan-tichondrius reached 2500!", embed.Title)
+	assert.Equal(t, "**Paladylan-tichondrius** (Protection Paladin) is now at **2512.4**, passing the 2500 milestone.",
+		embed.Description)
+	assert.Equal(t, "https://raider.io/characters/us/tichondrius/Paladylan", embed.URL)
+}
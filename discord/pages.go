@@ -0,0 +1,85 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// ScoresPage is the page of the scores leaderboard a message shows.
+//
+// It is kept in the buttons' custom IDs, so paging works without storing anything and survives restarts.
+type ScoresPage struct {
+	Page int
+	// Limit is how many characters the leaderboard was asked for
+	Limit int
+	// Expires is when the buttons stop working
+	Expires time.Time
+}
+
+const (
+	scoresPageSize       = 10
+	scoresCustomIDPrefix = "scores"
+)
+
+var ErrNotScoresPage = errors.New("not a scores page")
+
+// ParseScoresPage decodes a page from the custom ID of one of the scores leaderboard's buttons.
+func ParseScoresPage(customID string) (ScoresPage, error) {
+	parts := strings.Split(customID, ":")
+	if len(parts) != 4 || parts[0] != scoresCustomIDPrefix {
+		return ScoresPage{}, ErrNotScoresPage
+	}
+
+	var values [3]int64
+	for i, part := range parts[1:] {
+		v, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return ScoresPage{}, fmt.Errorf("invalid scores page %q: %w", customID, err)
+		}
+		values[i] = v
+	}
+
+	return ScoresPage{Page: int(values[0]), Limit: int(values[1]), Expires: time.Unix(values[2], 0)}, nil
+}
+
+// customID encodes the page as a button custom ID.
+func (p ScoresPage) customID() string {
+	return fmt.Sprintf("%s:%d:%d:%d", scoresCustomIDPrefix, p.Page, p.Limit, p.Expires.Unix())
+}
+
+// withPage returns the page of the same leaderboard.
+func (p ScoresPage) withPage(page int) ScoresPage {
+	p.Page = page
+	return p
+}
+
+// scoresPages returns how many pages it takes to list the characters, an empty leaderboard still has one page.
+func scoresPages(characters int) int {
+	return max(1, (characters+scoresPageSize-1)/scoresPageSize)
+}
+
+// buildPageButtons returns the Previous and Next buttons, they are disabled at the first and last page.
+func buildPageButtons(l i18n.Locale, page ScoresPage, pages int) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    l.T("Previous"),
+				Style:    discordgo.SecondaryButton,
+				Disabled: page.Page == 0,
+				CustomID: page.withPage(max(0, page.Page-1)).customID(),
+			},
+			discordgo.Button{
+				Label:    l.T("Next"),
+				Style:    discordgo.SecondaryButton,
+				Disabled: page.Page == pages-1,
+				CustomID: page.withPage(min(pages-1, page.Page+1)).customID(),
+			},
+		}},
+	}
+}
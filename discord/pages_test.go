@@ -0,0 +1,91 @@
+package discord
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLeaderboard(n int) []db.Character {
+	characters := make([]db.Character, 0, n)
+	for i := range n {
+		characters = append(characters, db.Character{
+			Name: fmt.Sprintf("Char%d", i+1), Realm: "realm1", OverallScore: float64(3000 - i*10),
+		})
+	}
+	return characters
+}
+
+// pageButtons returns the Previous and Next buttons from the message.
+func pageButtons(t *testing.T, message discordgo.MessageSend) (discordgo.Button, discordgo.Button) {
+	t.Helper()
+	require.Len(t, message.Components, 1)
+	row, ok := message.Components[0].(discordgo.ActionsRow)
+	require.True(t, ok)
+	require.Len(t, row.Components, 2)
+
+	return row.Components[0].(discordgo.Button), row.Components[1].(discordgo.Button)
+}
+
+func TestScoresPage_CustomID(t *testing.T) {
+	page := ScoresPage{Page: 2, Limit: 30, Expires: time.Unix(1700000000, 0)}
+
+	parsed, err := ParseScoresPage(page.customID())
+	require.NoError(t, err)
+	assert.Equal(t, page, parsed)
+}
+
+func TestParseScoresPage_Invalid(t *testing.T) {
+	_, err := ParseScoresPage("vault:1")
+	assert.ErrorIs(t, err, ErrNotScoresPage)
+
+	_, err = ParseScoresPage("scores:one:30:1700000000")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotScoresPage)
+}
+
+func TestBuildScoresMessage_Pages(t *testing.T) {
+	page := ScoresPage{Page: 1, Limit: 25, Expires: time.Unix(1700000000, 0)}
+
+	message := BuildScoresMessage(i18n.English, testLeaderboard(25), page)
+
+	embed := message.Embeds[0]
+	require.Len(t, embed.Fields, 2)
+	// The second page carries on numbering from the first
+	assert.Contains(t, embed.Fields[0].Value, "11) [Char11-realm1]")
+	assert.Contains(t, embed.Fields[0].Value, "20) [Char20-realm1]")
+	assert.NotContains(t, embed.Fields[0].Value, "Char21-")
+	assert.Equal(t, "Page 2 of 3", embed.Footer.Text)
+
+	previous, next := pageButtons(t, message)
+	assert.False(t, previous.Disabled)
+	assert.False(t, next.Disabled)
+	assert.Equal(t, "scores:0:25:1700000000", previous.CustomID)
+	assert.Equal(t, "scores:2:25:1700000000", next.CustomID)
+}
+
+func TestBuildScoresMessage_LastPage(t *testing.T) {
+	// Pages past the end show the last page, e.g. when characters were removed since the message was sent
+	message := BuildScoresMessage(i18n.English, testLeaderboard(12), ScoresPage{Page: 4, Limit: 20})
+
+	assert.Contains(t, message.Embeds[0].Fields[0].Value, "12) [Char12-realm1]")
+	assert.Equal(t, "Page 2 of 2", message.Embeds[0].Footer.Text)
+
+	previous, next := pageButtons(t, message)
+	assert.False(t, previous.Disabled)
+	assert.True(t, next.Disabled)
+	assert.NotEqual(t, previous.CustomID, next.CustomID)
+}
+
+func TestBuildScoresMessage_SinglePage(t *testing.T) {
+	message := BuildScoresMessage(i18n.English, testLeaderboard(10), ScoresPage{Limit: 10})
+
+	assert.Empty(t, message.Components)
+	assert.Nil(t, message.Embeds[0].Footer)
+}
@@ -0,0 +1,65 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DylanNZL/mythicplusbot/blizzard"
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// BuildProfileMessage shows a character's profile and the item level of each piece of gear they have equipped.
+func BuildProfileMessage(l i18n.Locale, c db.Character, equipment blizzard.CharacterEquipment) discordgo.MessageSend {
+	fields := []*discordgo.MessageEmbedField{
+		{Name: l.T("Level"), Value: fmt.Sprintf("%d", c.Level), Inline: true},
+		{Name: l.T("Item Level"), Value: l.T("%d (%d equipped)", c.ItemLevel, c.EquippedItemLevel), Inline: true},
+		{Name: l.T("Faction"), Value: valueOrNone(l, c.Faction), Inline: true},
+		{Name: l.T("Guild"), Value: valueOrNone(l, c.Guild), Inline: true},
+	}
+	if c.OverallScore != 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name: l.T("Mythic+ Score"), Value: fmt.Sprintf("%0.2f", c.OverallScore), Inline: true,
+		})
+	}
+	if gear := buildEquipmentList(equipment); gear != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: l.T("Equipment"), Value: gear})
+	}
+
+	return discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				URL:    fmt.Sprintf("https://raider.io/characters/us/%s/%s", c.Realm, c.Name),
+				Title:  fmt.Sprintf("%s-%s", c.Name, c.Realm),
+				Color:  getClassColour(c.Class), //nolint:misspell // blizzards fault
+				Fields: fields,
+				Author: &discordgo.MessageEmbedAuthor{
+					Name:    specAndClass(l, c),
+					IconURL: getClassIcon(c.Class),
+				},
+			},
+		},
+	}
+}
+
+// buildEquipmentList lists each slot with its item level, trimmed to fit in a single embed field.
+func buildEquipmentList(equipment blizzard.CharacterEquipment) string {
+	var s strings.Builder
+	for _, item := range equipment.EquippedItems {
+		line := fmt.Sprintf("**%s**: %s (%d)\n", item.Slot.Name, item.Name, item.Level.Value)
+		if s.Len()+len(line) > maxEmbedFieldChars {
+			break
+		}
+		s.WriteString(line)
+	}
+
+	return s.String()
+}
+
+func valueOrNone(l i18n.Locale, value string) string {
+	if value == "" {
+		return l.T("None")
+	}
+	return value
+}
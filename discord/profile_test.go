@@ -0,0 +1,73 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/blizzard"
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestEquippedItem(slot, name string, level int) blizzard.EquippedItem {
+	item := blizzard.EquippedItem{Slot: blizzard.TypedName{Type: strings.ToUpper(slot), Name: slot}, Name: name}
+	item.Level.Value = level
+	return item
+}
+
+func TestBuildProfileMessage(t *testing.T) {
+	character := db.Character{
+		Name: "Paladylan", Realm: "tichondrius", Class: "Paladin", OverallScore: 2500, Level: 80, Spec: "Protection",
+		Faction: "Horde", ItemLevel: 620, EquippedItemLevel: 618,
+	}
+	equipment := blizzard.CharacterEquipment{EquippedItems: []blizzard.EquippedItem{
+		createTestEquippedItem("Head", "Helm of Testing", 623),
+		createTestEquippedItem("Neck", "Chain of Testing", 619),
+	}}
+
+	message := BuildProfileMessage(i18n.English, character, equipment)
+
+	require.Len(t, message.Embeds, 1)
+	embed := message.Embeds[0]
+	assert.Equal(t, "Paladylan-tichondrius", embed.Title)
+	assert.Equal(t, "Protection Paladin", embed.Author.Name)
+	assert.Equal(t, getClassColour("Paladin"), embed.Color)
+
+	values := make(map[string]string, len(embed.Fields))
+	for _, f := range embed.Fields {
+		values[f.Name] = f.Value
+	}
+	assert.Equal(t, map[string]string{
+		"Level":         "80",
+		"Item Level":    "620 (618 equipped)",
+		"Faction":       "Horde",
+		"Guild":         "None",
+		"Mythic+ Score": "2500.00",
+		"Equipment":     "**Head**: Helm of Testing (623)\n**Neck**: Chain of Testing (619)\n",
+	}, values)
+}
+
+func TestBuildProfileMessage_Untracked(t *testing.T) {
+	message := BuildProfileMessage(i18n.English, db.Character{Name: "Paladylan", Realm: "tichondrius"}, blizzard.CharacterEquipment{})
+
+	// Characters that aren't tracked have no score, and there is no gear to list
+	for _, f := range message.Embeds[0].Fields {
+		assert.NotEqual(t, "Mythic+ Score", f.Name)
+		assert.NotEqual(t, "Equipment", f.Name)
+	}
+}
+
+func TestBuildEquipmentList_Truncates(t *testing.T) {
+	var equipment blizzard.CharacterEquipment
+	for range 50 {
+		equipment.EquippedItems = append(equipment.EquippedItems,
+			createTestEquippedItem("Trinket", strings.Repeat("x", 50), 600))
+	}
+
+	list := buildEquipmentList(equipment)
+
+	assert.LessOrEqual(t, len(list), maxEmbedFieldChars)
+	assert.True(t, strings.HasSuffix(list, "\n"), "only whole lines are kept")
+}
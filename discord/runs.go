@@ -0,0 +1,73 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+// BuildRunsMessage shows a character's Raider.IO runs as a table. Alternate runs are listed in their own table after
+// the best runs.
+func BuildRunsMessage(l i18n.Locale, c db.Character, kind db.RunKind, runs []db.CharacterRun) discordgo.MessageSend {
+	var main, alternates []db.CharacterRun
+	for _, run := range runs {
+		if run.Kind == db.RunKindAlternate {
+			alternates = append(alternates, run)
+			continue
+		}
+		main = append(main, run)
+	}
+
+	title := l.T("Best Runs")
+	if kind == db.RunKindRecent {
+		title = l.T("Recent Runs")
+	}
+
+	description := l.T("No runs found.")
+	if len(main) > 0 {
+		description = buildRunsTable(l, main)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		URL:         fmt.Sprintf("https://raider.io/characters/us/%s/%s", c.Realm, c.Name),
+		Title:       fmt.Sprintf("%s-%s %s", c.Name, c.Realm, title),
+		Description: description,
+		Color:       scoresColour,
+	}
+	if len(alternates) > 0 {
+		table := buildRunsTable(l, alternates)
+		if len(table) <= maxEmbedFieldChars {
+			embed.Fields = []*discordgo.MessageEmbedField{{Name: l.T("Alternate Runs"), Value: table}}
+		}
+	}
+
+	return discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}
+}
+
+// buildRunsTable lays the runs out in a code block so the columns line up.
+func buildRunsTable(l i18n.Locale, runs []db.CharacterRun) string {
+	var s strings.Builder
+	w := tabwriter.NewWriter(&s, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, l.T("Dungeon\tLevel\tUpgrades\tTime / Par\tScore"))
+	for _, run := range runs {
+		upgrades := l.T("depleted")
+		if run.NumKeystoneUpgrades > 0 {
+			upgrades = fmt.Sprintf("+%d", run.NumKeystoneUpgrades)
+		}
+		fmt.Fprintf(w, "%s\t+%d\t%s\t%s / %s\t%0.1f\n", run.ShortName, run.MythicLevel, upgrades,
+			formatRunDuration(run.ClearTimeMs), formatRunDuration(run.ParTimeMs), run.Score)
+	}
+	_ = w.Flush()
+
+	// Leave room for the code fences
+	table := s.String()
+	for len(table) > maxEmbedDescriptionChars-8 {
+		table = table[:strings.LastIndex(strings.TrimSuffix(table, "\n"), "\n")+1]
+	}
+
+	return "```\n" + table + "```"
+}
@@ -0,0 +1,45 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRunsMessage_Best(t *testing.T) {
+	c := db.Character{Name: "Paladylan", Realm: "tichondrius"}
+	runs := []db.CharacterRun{
+		{Kind: db.RunKindBest, ShortName: "SV", MythicLevel: 12, NumKeystoneUpgrades: 2, ClearTimeMs: 1500000,
+			ParTimeMs: 1980000, Score: 310.5},
+		{Kind: db.RunKindBest, ShortName: "ARAK", MythicLevel: 10, ClearTimeMs: 1900000, ParTimeMs: 1800000, Score: 240},
+		{Kind: db.RunKindAlternate, ShortName: "SV", MythicLevel: 11, NumKeystoneUpgrades: 1, ClearTimeMs: 1800000,
+			ParTimeMs: 1980000, Score: 290},
+	}
+
+	message := BuildRunsMessage(i18n.English, c, db.RunKindBest, runs)
+
+	require.Len(t, message.Embeds, 1)
+	embed := message.Embeds[0]
+	assert.Equal(t, "Paladylan-tichondrius Best Runs", embed.Title)
+	assert.Equal(t, "https://raider.io/characters/us/tichondrius/Paladylan", embed.URL)
+	assert.Equal(t, "```\n"+
+		"Dungeon  Level  Upgrades  Time / Par     Score\n"+
+		"SV       +12    +2        25:00 / 33:00  310.5\n"+
+		"ARAK     +10    depleted  31:40 / 30:00  240.0\n"+
+		"```", embed.Description)
+	require.Len(t, embed.Fields, 1)
+	assert.Equal(t, "Alternate Runs", embed.Fields[0].Name)
+	assert.Contains(t, embed.Fields[0].Value, "SV       +11    +1        30:00 / 33:00  290.0")
+}
+
+func TestBuildRunsMessage_NoRuns(t *testing.T) {
+	message := BuildRunsMessage(i18n.English, db.Character{Name: "Paladylan", Realm: "tichondrius"}, db.RunKindRecent, nil)
+
+	embed := message.Embeds[0]
+	assert.Equal(t, "Paladylan-tichondrius Recent Runs", embed.Title)
+	assert.Equal(t, "No runs found.", embed.Description)
+	assert.Empty(t, embed.Fields)
+}
@@ -3,80 +3,119 @@ package discord
 import (
 	"context"
 	"fmt"
-	"log/slog"
 	"strings"
-	"text/template"
 
 	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
 	"github.com/DylanNZL/mythicplusbot/raiderio"
 	"github.com/bwmarrin/discordgo"
 )
 
-type (
-	descriptionData struct {
-		Scores      []scoreData
-		RealmRank   int
-		OverallRank int
-		Ranks       []rankData
-		Dungeon     string
-		Level       int
-		Result      int
-		Points      string
-		MoreInfo    string
+// ScoreUpdate is everything shown when a character's score increases.
+type ScoreUpdate struct {
+	Character db.Character
+	RaiderIO  raiderio.Character
+	OldScore  float64
+	// Cutoffs for the season, nil if they couldn't be fetched
+	Cutoffs *raiderio.Cutoffs
+}
+
+// BuildScoreUpdateMessage builds a score update announcement with the default templates.
+func BuildScoreUpdateMessage(ctx context.Context, l i18n.Locale, u ScoreUpdate) discordgo.MessageSend {
+	return defaultTemplates.BuildScoreUpdateMessage(ctx, l, u)
+}
+
+// BuildScoreUpdateMessage builds a score update announcement, the content, title, description and footer come from the
+// templates.
+func (t *Templates) BuildScoreUpdateMessage(ctx context.Context, l i18n.Locale, u ScoreUpdate) discordgo.MessageSend {
+	c, rc := u.Character, u.RaiderIO
+	latestRun := getLatestRun(rc)
+	data := newTemplateData(l, u)
+
+	embed := &discordgo.MessageEmbed{
+		URL:         rc.ProfileUrl,
+		Title:       executeOr(ctx, t.title, defaultTemplates.title, data),
+		Description: t.buildDescription(ctx, l, c, data),
+		Color:       getClassColour(c.Class), //nolint:misspell // blizzards fault
+		Fields:      buildCutoffFields(l, c.OverallScore, u.Cutoffs),
+		Image: &discordgo.MessageEmbedImage{
+			URL: latestRun.BackgroundImageUrl,
+		},
+		Thumbnail: &discordgo.MessageEmbedThumbnail{
+			URL: rc.ThumbnailUrl,
+		},
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    fmt.Sprintf("%s-%s (%s)", c.Name, c.Realm, specAndClass(l, c)),
+			IconURL: getClassIcon(c.Class),
+		},
+	}
+	if footer := executeOr(ctx, t.footer, defaultTemplates.footer, data); footer != "" {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: footer}
 	}
 
-	scoreData struct {
-		Role  string
-		Score string
+	return discordgo.MessageSend{
+		Content: executeOr(ctx, t.content, defaultTemplates.content, data),
+		Embeds:  []*discordgo.MessageEmbed{embed},
 	}
+}
 
-	rankData struct {
-		Role        string
-		RealmRank   int
-		OverallRank int
+// buildCutoffFields shows where the score sits against the season's percentile cutoffs and how far it is from the
+// next one, e.g. "Top 10% · 1% cutoff is 3100, you're 120 away".
+func buildCutoffFields(l i18n.Locale, score float64, cutoffs *raiderio.Cutoffs) []*discordgo.MessageEmbedField {
+	if cutoffs == nil {
+		return nil
 	}
-)
 
-const descriptionTemplate = `{{range $s := .Scores}}**{{$s.Role}} Score** {{$s.Score}}
-{{end}}
-**--- Ranks ---**
-**#{{.RealmRank}} Realm - #{{.OverallRank}} Overall**
-{{range $r := .Ranks}}**{{$r.Role}}**: #{{$r.RealmRank}} Realm - #{{$r.OverallRank}} Overall
-{{end}}
-**--- Last Run ---**
-**Dungeon**: {{.Dungeon}}
-**Level**: {{.Level}}
-**Result**: +{{.Result}}
-**Points**: {{.Points}}
-[More Info]({{.MoreInfo}}) 
-`
-
-func BuildScoreUpdateMessage(ctx context.Context, c db.Character, rc raiderio.Character, oldScore float64) discordgo.MessageSend {
-	latestRun := getLatestRun(rc)
+	reached, next := cutoffs.Standing(score)
+	var parts []string
+	if reached != nil {
+		parts = append(parts, l.T("Top %s", reached.Name))
+	}
+	switch {
+	case next != nil:
+		parts = append(parts, l.T("%s is %0.f, you're %0.f away", cutoffName(l, *next), next.Score,
+			next.Score-score))
+	case reached != nil:
+		parts = append(parts, l.T("above the %s of %0.f", cutoffName(l, *reached), reached.Score))
+	default:
+		return nil
+	}
 
-	return discordgo.MessageSend{
-		Content: fmt.Sprintf("[%s-%s](%s) increased their score from %0.2f to %0.2f",
-			c.Name, c.Realm, rc.ProfileUrl, oldScore, c.OverallScore),
-		Embeds: []*discordgo.MessageEmbed{
-			{
-				URL:         rc.ProfileUrl,
-				Title:       fmt.Sprintf("%0.2f Overall Mythic+ Score", c.OverallScore),
-				Description: buildScoreUpdateMessage(ctx, c, rc, latestRun),
-				Color:       getClassColour(c.Class), //nolint:misspell // blizzards fault
-				Footer:      nil,
-				Image: &discordgo.MessageEmbedImage{
-					URL: latestRun.BackgroundImageUrl,
-				},
-				Thumbnail: &discordgo.MessageEmbedThumbnail{
-					URL: rc.ThumbnailUrl,
-				},
-				Author: &discordgo.MessageEmbedAuthor{
-					Name:    fmt.Sprintf("%s-%s (%s)", c.Name, c.Realm, c.Class),
-					IconURL: getClassIcon(c.Class),
-				},
-			},
-		},
+	return []*discordgo.MessageEmbedField{{Name: l.T("Season Cutoffs"), Value: strings.Join(parts, " · ")}}
+}
+
+// cutoffName names a tier's cutoff, the top 0.1% is called out as it is roughly the cutoff for the season title.
+func cutoffName(l i18n.Locale, t raiderio.Tier) string {
+	if t.Name == "0.1%" {
+		return l.T("0.1%% title cutoff")
+	}
+	return l.T("%s cutoff", t.Name)
+}
+
+// BuildCutoffsMessage lists the season's percentile cutoffs.
+func BuildCutoffsMessage(l i18n.Locale, season string, cutoffs *raiderio.Cutoffs) discordgo.MessageSend {
+	var s strings.Builder
+	for _, t := range cutoffs.Tiers() {
+		if t.Score == 0 {
+			continue
+		}
+		s.WriteString(l.T("**Top %s**: %0.1f (%d characters)", t.Name, t.Score, t.Population) + "\n")
+	}
+	if s.Len() == 0 {
+		s.WriteString(l.T("Raider.IO hasn't calculated the cutoffs for this season yet."))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		URL:         "https://raider.io/mythic-plus-rankings/season-cutoffs",
+		Title:       l.T("Season Cutoffs (%s)", season),
+		Description: s.String(),
+		Color:       scoresColour,
+	}
+	if !cutoffs.UpdatedAt.IsZero() {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: l.T("Updated %s", cutoffs.UpdatedAt.UTC().Format("2006-01-02 15:04 MST"))}
 	}
+
+	return discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}
 }
 
 func getLatestRun(rc raiderio.Character) (latestRun raiderio.Run) {
@@ -94,76 +133,68 @@ func getLatestRun(rc raiderio.Character) (latestRun raiderio.Run) {
 	return
 }
 
-func buildScoreUpdateMessage(ctx context.Context, c db.Character, rc raiderio.Character, latestRun raiderio.Run) string {
-	tpl, err := template.New("description").Parse(descriptionTemplate)
-	if err != nil {
-		slog.ErrorContext(ctx, "failed to parse description template: "+err.Error())
-		return buildScoreUpdateMessageFallback(c)
+func (t *Templates) buildDescription(ctx context.Context, l i18n.Locale, c db.Character, data TemplateData) string {
+	if s, ok := execute(ctx, t.description, data); ok {
+		return s
 	}
-
-	var s strings.Builder
-	if err := tpl.Execute(&s, descriptionData{
-		Scores:      buildScoreData(c),
-		Ranks:       buildRankData(rc),
-		RealmRank:   rc.MythicPlusRanks.Overall.Realm,
-		OverallRank: rc.MythicPlusRanks.Overall.World,
-		Dungeon:     latestRun.Dungeon,
-		Level:       latestRun.MythicLevel,
-		Result:      latestRun.NumKeystoneUpgrades,
-		Points:      fmt.Sprintf("%0.2f", latestRun.Score),
-		MoreInfo:    latestRun.Url,
-	}); err != nil {
-		slog.ErrorContext(ctx, "failed to execute description template: "+err.Error())
-		return buildScoreUpdateMessageFallback(c)
-	}
-
-	return s.String()
+	return buildScoreUpdateMessageFallback(l, c)
 }
 
-func buildScoreData(c db.Character) (sd []scoreData) {
+// buildScoreData lists the character's score for each spec, or for each role when we don't have spec scores.
+func buildScoreData(l i18n.Locale, c db.Character) (sd []ScoreData) {
+	if len(c.SpecScores) > 0 {
+		for _, spec := range c.SpecScores {
+			sd = append(sd, ScoreData{
+				Role:  specLabel(l, spec.Spec, c.Class),
+				Score: fmt.Sprintf("%0.2f", spec.Score),
+			})
+		}
+		return
+	}
+
 	if c.TankScore != 0 {
-		sd = append(sd, scoreData{
-			Role:  "Tank",
+		sd = append(sd, ScoreData{
+			Role:  l.T("Tank"),
 			Score: fmt.Sprintf("%0.2f", c.TankScore),
 		})
 	}
 	if c.HealScore != 0 {
-		sd = append(sd, scoreData{
-			Role:  "Healer",
+		sd = append(sd, ScoreData{
+			Role:  l.T("Healer"),
 			Score: fmt.Sprintf("%0.2f", c.HealScore),
 		})
 	}
 	if c.DPSScore != 0 {
-		sd = append(sd, scoreData{
-			Role:  "DPS",
+		sd = append(sd, ScoreData{
+			Role:  l.T("DPS"),
 			Score: fmt.Sprintf("%0.2f", c.DPSScore),
 		})
 	}
 	return
 }
 
-func buildRankData(rc raiderio.Character) (rd []rankData) {
+func buildRankData(l i18n.Locale, rc raiderio.Character) (rd []RankData) {
 	if len(rc.MythicPlusScoresBySeason) == 0 {
 		return
 	}
 
 	if rc.MythicPlusScoresBySeason[0].Scores.Tank != 0 {
-		rd = append(rd, rankData{
-			Role:        "Tank",
+		rd = append(rd, RankData{
+			Role:        l.T("Tank"),
 			RealmRank:   rc.MythicPlusRanks.Tank.Realm,
 			OverallRank: rc.MythicPlusRanks.Tank.World,
 		})
 	}
 	if rc.MythicPlusScoresBySeason[0].Scores.Healer != 0 {
-		rd = append(rd, rankData{
-			Role:        "Healer",
+		rd = append(rd, RankData{
+			Role:        l.T("Healer"),
 			RealmRank:   rc.MythicPlusRanks.Healer.Realm,
 			OverallRank: rc.MythicPlusRanks.Healer.World,
 		})
 	}
 	if rc.MythicPlusScoresBySeason[0].Scores.Dps != 0 {
-		rd = append(rd, rankData{
-			Role:        "DPS",
+		rd = append(rd, RankData{
+			Role:        l.T("DPS"),
 			RealmRank:   rc.MythicPlusRanks.Dps.Realm,
 			OverallRank: rc.MythicPlusRanks.Dps.World,
 		})
@@ -172,8 +203,8 @@ func buildRankData(rc raiderio.Character) (rd []rankData) {
 	return
 }
 
-func buildScoreUpdateMessageFallback(c db.Character) string {
-	return fmt.Sprintf("**Tank Score** %02.f\n**Healer Score** %02.f\n**DPS Score** %02.f",
+func buildScoreUpdateMessageFallback(l i18n.Locale, c db.Character) string {
+	return l.T("**Tank Score** %02.f\n**Healer Score** %02.f\n**DPS Score** %02.f",
 		c.TankScore, c.HealScore, c.DPSScore)
 }
 
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
 	"github.com/DylanNZL/mythicplusbot/raiderio"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -168,9 +170,9 @@ func TestBuildScoreData(t *testing.T) {
 			DPSScore:  2200.25,
 		}
 
-		result := buildScoreData(character)
+		result := buildScoreData(i18n.English, character)
 
-		expected := []scoreData{
+		expected := []ScoreData{
 			{Role: "Tank", Score: "2400.50"},
 			{Role: "Healer", Score: "2300.75"},
 			{Role: "DPS", Score: "2200.25"},
@@ -186,9 +188,9 @@ func TestBuildScoreData(t *testing.T) {
 			DPSScore:  0,
 		}
 
-		result := buildScoreData(character)
+		result := buildScoreData(i18n.English, character)
 
-		expected := []scoreData{
+		expected := []ScoreData{
 			{Role: "Tank", Score: "2400.50"},
 		}
 
@@ -202,9 +204,9 @@ func TestBuildScoreData(t *testing.T) {
 			DPSScore:  0,
 		}
 
-		result := buildScoreData(character)
+		result := buildScoreData(i18n.English, character)
 
-		expected := []scoreData{
+		expected := []ScoreData{
 			{Role: "Healer", Score: "2300.75"},
 		}
 
@@ -218,9 +220,9 @@ func TestBuildScoreData(t *testing.T) {
 			DPSScore:  2200.25,
 		}
 
-		result := buildScoreData(character)
+		result := buildScoreData(i18n.English, character)
 
-		expected := []scoreData{
+		expected := []ScoreData{
 			{Role: "DPS", Score: "2200.25"},
 		}
 
@@ -234,10 +236,31 @@ func TestBuildScoreData(t *testing.T) {
 			DPSScore:  0,
 		}
 
-		result := buildScoreData(character)
+		result := buildScoreData(i18n.English, character)
 
 		assert.Empty(t, result)
 	})
+
+	t.Run("spec scores replace the roles", func(t *testing.T) {
+		character := db.Character{
+			Class:     "Death Knight",
+			TankScore: 2100,
+			DPSScore:  2900,
+			SpecScores: []db.SpecScore{
+				{Spec: "Frost", Score: 2900},
+				{Spec: "Unholy", Score: 2650.5},
+			},
+		}
+
+		result := buildScoreData(i18n.English, character)
+
+		expected := []ScoreData{
+			{Role: "Frost DK", Score: "2900.00"},
+			{Role: "Unholy DK", Score: "2650.50"},
+		}
+
+		assert.Equal(t, expected, result)
+	})
 }
 
 // Test BuildRankData function
@@ -270,9 +293,9 @@ func TestBuildRankData(t *testing.T) {
 			},
 		}
 
-		result := buildRankData(character)
+		result := buildRankData(i18n.English, character)
 
-		expected := []rankData{
+		expected := []RankData{
 			{Role: "Tank", RealmRank: 10, OverallRank: 100},
 			{Role: "Healer", RealmRank: 20, OverallRank: 200},
 			{Role: "DPS", RealmRank: 30, OverallRank: 300},
@@ -300,9 +323,9 @@ func TestBuildRankData(t *testing.T) {
 			},
 		}
 
-		result := buildRankData(character)
+		result := buildRankData(i18n.English, character)
 
-		expected := []rankData{
+		expected := []RankData{
 			{Role: "Tank", RealmRank: 10, OverallRank: 100},
 		}
 
@@ -322,7 +345,7 @@ func TestBuildRankData(t *testing.T) {
 			},
 		}
 
-		result := buildRankData(character)
+		result := buildRankData(i18n.English, character)
 
 		assert.Empty(t, result)
 	})
@@ -341,7 +364,11 @@ func TestBuildScoreUpdateMessage(t *testing.T) {
 			Url:                 "https://example.com/run",
 		}
 
-		result := buildScoreUpdateMessage(ctx, testDBCharacter, testRIOCharacter, latestRun)
+		rc := testRIOCharacter
+		rc.MythicPlusRecentRuns = []raiderio.Run{latestRun}
+		data := newTemplateData(i18n.English, ScoreUpdate{Character: testDBCharacter, RaiderIO: rc})
+
+		result := defaultTemplates.buildDescription(ctx, i18n.English, testDBCharacter, data)
 
 		assert.Contains(t, result, "Tank Score")
 		assert.Contains(t, result, "2400.00")
@@ -361,7 +388,7 @@ func TestBuildScoreUpdateMessage(t *testing.T) {
 	t.Run("template parse error fallback", func(t *testing.T) {
 		// This test would require mocking the template.New function to return an error
 		// For now, we'll test the fallback function directly
-		result := buildScoreUpdateMessageFallback(testDBCharacter)
+		result := buildScoreUpdateMessageFallback(i18n.English, testDBCharacter)
 
 		expected := "**Tank Score** 2400\n**Healer Score** 2300\n**DPS Score** 2200"
 		assert.Equal(t, expected, result)
@@ -376,7 +403,7 @@ func TestBuildScoreUpdateMessageFallback(t *testing.T) {
 			DPSScore:  2300.0,
 		}
 
-		result := buildScoreUpdateMessageFallback(character)
+		result := buildScoreUpdateMessageFallback(i18n.English, character)
 
 		expected := "**Tank Score** 2501\n**Healer Score** 2400\n**DPS Score** 2300"
 		assert.Equal(t, expected, result)
@@ -389,7 +416,7 @@ func TestBuildScoreUpdateMessageFallback(t *testing.T) {
 			DPSScore:  0,
 		}
 
-		result := buildScoreUpdateMessageFallback(character)
+		result := buildScoreUpdateMessageFallback(i18n.English, character)
 
 		expected := "**Tank Score** 00\n**Healer Score** 00\n**DPS Score** 00"
 		assert.Equal(t, expected, result)
@@ -403,7 +430,7 @@ func TestBuildScoreUpdateMessageMain(t *testing.T) {
 		ctx := context.Background()
 		oldScore := 2000.0
 
-		message := BuildScoreUpdateMessage(ctx, testDBCharacter, testRIOCharacter, oldScore)
+		message := BuildScoreUpdateMessage(ctx, i18n.English, ScoreUpdate{Character: testDBCharacter, RaiderIO: testRIOCharacter, OldScore: oldScore})
 
 		// Test the content
 		expectedContent := "[Paladylan-tichondrius](https://raider.io/characters/us/tichondrius/Paladylan) increased their score from 2000.00 to 2500.00"
@@ -420,6 +447,33 @@ func TestBuildScoreUpdateMessageMain(t *testing.T) {
 		assert.Equal(t, getClassColour("Paladin"), embed.Color)
 		assert.Equal(t, testRIOCharacter.ThumbnailUrl, embed.Thumbnail.URL)
 		assert.Equal(t, testRIOCharacter.MythicPlusRecentRuns[0].BackgroundImageUrl, embed.Image.URL)
+		assert.Nil(t, embed.Footer, "no profile has been fetched yet")
+		assert.Empty(t, embed.Fields, "no cutoffs were fetched")
+	})
+
+	t.Run("with cutoffs", func(t *testing.T) {
+		message := BuildScoreUpdateMessage(context.Background(), i18n.English, ScoreUpdate{
+			Character: testDBCharacter, RaiderIO: testRIOCharacter, OldScore: 2000.0, Cutoffs: createTestCutoffs(),
+		})
+
+		embed := message.Embeds[0]
+		require.Len(t, embed.Fields, 1)
+		assert.Equal(t, "Season Cutoffs", embed.Fields[0].Name)
+		assert.Equal(t, "Top 25% · 10% cutoff is 2700, you're 200 away", embed.Fields[0].Value)
+	})
+
+	t.Run("with profile", func(t *testing.T) {
+		character := testDBCharacter
+		character.Spec = "Protection"
+		character.Guild = "Method"
+		character.ItemLevel = 620
+
+		message := BuildScoreUpdateMessage(context.Background(), i18n.English, ScoreUpdate{Character: character, RaiderIO: testRIOCharacter, OldScore: 2000.0})
+
+		embed := message.Embeds[0]
+		assert.Equal(t, "Paladylan-tichondrius (Protection Paladin)", embed.Author.Name)
+		require.NotNil(t, embed.Footer)
+		assert.Equal(t, "Item Level 620 · <Method>", embed.Footer.Text)
 	})
 
 	t.Run("with empty recent runs", func(t *testing.T) {
@@ -429,7 +483,7 @@ func TestBuildScoreUpdateMessageMain(t *testing.T) {
 		emptyRunsCharacter := testRIOCharacter
 		emptyRunsCharacter.MythicPlusRecentRuns = []raiderio.Run{}
 
-		message := BuildScoreUpdateMessage(ctx, testDBCharacter, emptyRunsCharacter, oldScore)
+		message := BuildScoreUpdateMessage(ctx, i18n.English, ScoreUpdate{Character: testDBCharacter, RaiderIO: emptyRunsCharacter, OldScore: oldScore})
 
 		// Should still create a message but with empty run data
 		assert.NotEmpty(t, message.Content)
@@ -443,16 +497,16 @@ func TestBuildScoreUpdateMessageMain(t *testing.T) {
 
 // Test edge cases and error scenarios
 
-func TestDescriptionDataStructure(t *testing.T) {
-	t.Run("descriptionData with all fields", func(t *testing.T) {
-		data := descriptionData{
-			Scores: []scoreData{
+func TestTemplateDataStructure(t *testing.T) {
+	t.Run("TemplateData with all fields", func(t *testing.T) {
+		data := TemplateData{
+			Scores: []ScoreData{
 				{Role: "Tank", Score: "2400.00"},
 				{Role: "Healer", Score: "2300.00"},
 			},
 			RealmRank:   123,
 			OverallRank: 456,
-			Ranks: []rankData{
+			Ranks: []RankData{
 				{Role: "Tank", RealmRank: 10, OverallRank: 100},
 			},
 			Dungeon:  "Test Dungeon",
@@ -483,7 +537,7 @@ func TestBuildRankDataEdgeCases(t *testing.T) {
 			MythicPlusScoresBySeason: []raiderio.Season{},
 		}
 
-		result := buildRankData(character)
+		result := buildRankData(i18n.English, character)
 
 		// Should return empty slice without panicking
 		assert.Empty(t, result)
@@ -512,9 +566,9 @@ func TestBuildRankDataEdgeCases(t *testing.T) {
 			},
 		}
 
-		result := buildRankData(character)
+		result := buildRankData(i18n.English, character)
 
-		expected := []rankData{
+		expected := []RankData{
 			{Role: "Tank", RealmRank: 10, OverallRank: 100},
 			{Role: "DPS", RealmRank: 30, OverallRank: 300},
 		}
@@ -526,8 +580,8 @@ func TestBuildRankDataEdgeCases(t *testing.T) {
 }
 
 func TestScoreDataStructure(t *testing.T) {
-	t.Run("scoreData initialization", func(t *testing.T) {
-		score := scoreData{
+	t.Run("ScoreData initialization", func(t *testing.T) {
+		score := ScoreData{
 			Role:  "Tank",
 			Score: "2500.75",
 		}
@@ -538,8 +592,8 @@ func TestScoreDataStructure(t *testing.T) {
 }
 
 func TestRankDataStructure(t *testing.T) {
-	t.Run("rankData initialization", func(t *testing.T) {
-		rank := rankData{
+	t.Run("RankData initialization", func(t *testing.T) {
+		rank := RankData{
 			Role:        "Healer",
 			RealmRank:   50,
 			OverallRank: 500,
@@ -550,3 +604,63 @@ func TestRankDataStructure(t *testing.T) {
 		assert.Equal(t, 500, rank.OverallRank)
 	})
 }
+
+func createTestCutoffs() *raiderio.Cutoffs {
+	cutoffs := &raiderio.Cutoffs{UpdatedAt: time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)}
+	cutoffs.P999.All = raiderio.CutoffSegment{QuantileMinValue: 3450, QuantilePopulationCount: 150}
+	cutoffs.P990.All = raiderio.CutoffSegment{QuantileMinValue: 3100, QuantilePopulationCount: 1500}
+	cutoffs.P900.All = raiderio.CutoffSegment{QuantileMinValue: 2700, QuantilePopulationCount: 15000}
+	cutoffs.P750.All = raiderio.CutoffSegment{QuantileMinValue: 2300, QuantilePopulationCount: 37500}
+	cutoffs.P600.All = raiderio.CutoffSegment{QuantileMinValue: 2000, QuantilePopulationCount: 60000}
+	return cutoffs
+}
+
+func TestBuildCutoffFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		score    float64
+		expected string
+	}{
+		{name: "between cutoffs", score: 3330, expected: "Top 1% · 0.1% title cutoff is 3450, you're 120 away"},
+		{name: "above the title cutoff", score: 3500, expected: "Top 0.1% · above the 0.1% title cutoff of 3450"},
+		{name: "below every cutoff", score: 1800, expected: "40% cutoff is 2000, you're 200 away"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := buildCutoffFields(i18n.English, tt.score, createTestCutoffs())
+			require.Len(t, fields, 1)
+			assert.Equal(t, tt.expected, fields[0].Value)
+		})
+	}
+
+	assert.Nil(t, buildCutoffFields(i18n.English, 2500, nil))
+	assert.Nil(t, buildCutoffFields(i18n.English, 2500, &raiderio.Cutoffs{}), "cutoffs that haven't been calculated are left off")
+}
+
+func TestBuildCutoffsMessage(t *testing.T) {
+	message := BuildCutoffsMessage(i18n.English, "season-tww-2", createTestCutoffs())
+
+	require.Len(t, message.Embeds, 1)
+	embed := message.Embeds[0]
+	assert.Equal(t, "Season Cutoffs (season-tww-2)", embed.Title)
+	assert.Equal(t, "**Top 0.1%**: 3450.0 (150 characters)\n**Top 1%**: 3100.0 (1500 characters)\n"+
+		"**Top 10%**: 2700.0 (15000 characters)\n**Top 25%**: 2300.0 (37500 characters)\n"+
+		"**Top 40%**: 2000.0 (60000 characters)\n", embed.Description)
+	assert.Equal(t, "Updated 2024-01-01 12:00 UTC", embed.Footer.Text)
+}
+
+func TestBuildCutoffsMessage_NotCalculated(t *testing.T) {
+	message := BuildCutoffsMessage(i18n.English, "season-tww-2", &raiderio.Cutoffs{})
+
+	assert.Equal(t, "Raider.IO hasn't calculated the cutoffs for this season yet.", message.Embeds[0].Description)
+	assert.Nil(t, message.Embeds[0].Footer)
+}
+
+func TestBuildThreadSummaryMessage(t *testing.T) {
+	update := ScoreUpdate{Character: db.Character{Name: "Testchar", Realm: "testrealm", OverallScore: 2600}, OldScore: 2450}
+
+	message := BuildThreadSummaryMessage(i18n.German, update, "123")
+
+	assert.Equal(t, "Testchar-testrealm hat die Wertung von 2450.00 auf 2600.00 erhöht, mehr in <#123>", message.Content)
+}
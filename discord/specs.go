@@ -0,0 +1,74 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+)
+
+// classAbbreviations shortens the class names that are too long to sit next to a spec.
+var classAbbreviations = map[string]string{
+	"DeathKnight": "DK",
+	"DemonHunter": "DH",
+}
+
+// specLabel names a spec along with its class in the locale, e.g. Frost DK or Holy Paladin.
+func specLabel(l i18n.Locale, spec, class string) string {
+	class = strings.ReplaceAll(class, " ", "")
+	if short, ok := classAbbreviations[class]; ok {
+		class = short
+	} else {
+		class = localName(l, class)
+	}
+	return l.T("%s %s", localName(l, spec), class)
+}
+
+// specAndClass returns the character's class in the locale, prefixed with their spec when we know it.
+func specAndClass(l i18n.Locale, c db.Character) string {
+	if c.Spec == "" {
+		return localName(l, c.Class)
+	}
+	return l.T("%s %s", localName(l, c.Spec), localName(l, c.Class))
+}
+
+// localName translates a class or spec name. They are stored in English, with classes from the Blizzard profile
+// missing their spaces, e.g. DeathKnight, so they are looked up as they are written in game.
+func localName(l i18n.Locale, name string) string {
+	key := addSpaces(name)
+	if translated := l.T(key); translated != key {
+		return translated
+	}
+	return name
+}
+
+// addSpaces puts a space before each capital that follows a lower case letter, e.g. DeathKnight becomes Death Knight.
+func addSpaces(name string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range name {
+		if unicode.IsUpper(r) && unicode.IsLower(prev) {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// formatSpecScores lists up to n of the character's spec scores in the locale, e.g. "Frost DK 2900 / Unholy DK 2650".
+// All of them are listed if n is 0.
+func formatSpecScores(l i18n.Locale, c db.Character, n int) string {
+	scores := c.SpecScores
+	if n > 0 && len(scores) > n {
+		scores = scores[:n]
+	}
+
+	parts := make([]string, 0, len(scores))
+	for _, s := range scores {
+		parts = append(parts, fmt.Sprintf("%s %0.0f", specLabel(l, s.Spec, c.Class), s.Score))
+	}
+	return strings.Join(parts, " / ")
+}
@@ -0,0 +1,50 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatSpecScores(t *testing.T) {
+	c := db.Character{
+		Class: "DeathKnight",
+		SpecScores: []db.SpecScore{
+			{Spec: "Frost", Score: 2900},
+			{Spec: "Unholy", Score: 2650},
+			{Spec: "Blood", Score: 2100},
+		},
+	}
+
+	assert.Equal(t, "Frost DK 2900 / Unholy DK 2650 / Blood DK 2100", formatSpecScores(i18n.English, c, 0))
+	assert.Equal(t, "Frost DK 2900 / Unholy DK 2650", formatSpecScores(i18n.English, c, 2))
+	assert.Empty(t, formatSpecScores(i18n.English, db.Character{Class: "Mage"}, 0))
+}
+
+func TestSpecLabel(t *testing.T) {
+	assert.Equal(t, "Vengeance DH", specLabel(i18n.English, "Vengeance", "Demon Hunter"))
+	assert.Equal(t, "Holy Paladin", specLabel(i18n.English, "Holy", "Paladin"))
+}
+
+func TestSpecLabel_Localised(t *testing.T) {
+	// Specs and classes are translated the same way, whether they came from Blizzard or Raider.IO
+	assert.Equal(t, "Tierherrschaft Jäger", specLabel(i18n.German, "Beast Mastery", "Hunter"))
+	assert.Equal(t, "DK Givre", specLabel(i18n.French, "Frost", "DeathKnight"))
+	assert.Equal(t, "DK Givre 2900", formatSpecScores(i18n.French, db.Character{
+		Class: "DeathKnight", SpecScores: []db.SpecScore{{Spec: "Frost", Score: 2900}},
+	}, 0))
+}
+
+func TestSpecAndClass(t *testing.T) {
+	c := db.Character{Class: "DeathKnight", Spec: "Frost"}
+	assert.Equal(t, "Frost DeathKnight", specAndClass(i18n.English, c))
+	assert.Equal(t, "Frost Todesritter", specAndClass(i18n.German, c))
+	assert.Equal(t, "Chevalier de la mort Givre", specAndClass(i18n.French, c))
+
+	// Characters added from Raider.IO have spaces in their class
+	assert.Equal(t, "Chasseur de démons", specAndClass(i18n.French, db.Character{Class: "Demon Hunter"}))
+	// Names without a translation are left as they are
+	assert.Equal(t, "Chasseur de démons Devourer", specAndClass(i18n.French, db.Character{Class: "DemonHunter", Spec: "Devourer"}))
+}
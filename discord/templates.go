@@ -0,0 +1,239 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/bwmarrin/discordgo"
+)
+
+type (
+	// TemplateData is what the score update templates are rendered with, e.g. `{{.Name}} gained {{printf "%0.f" .Delta}}`.
+	TemplateData struct {
+		// Locale is the language to render in, translate text with e.g. `{{.Locale.T "Level %d" .Level}}`
+		Locale i18n.Locale
+
+		// Spec, Guild and ItemLevel are empty until the character's Blizzard profile has been fetched. Class and
+		// Spec are in the locale's language
+		Name       string
+		Realm      string
+		Class      string
+		Spec       string
+		Guild      string
+		ItemLevel  int
+		ProfileURL string
+
+		OldScore float64
+		NewScore float64
+		Delta    float64
+		// Scores holds the score for each spec played this season, or each role when spec scores aren't known
+		Scores []ScoreData
+
+		RealmRank   int
+		OverallRank int
+		Ranks       []RankData
+
+		// The latest run, Result is the number of keystone upgrades and MoreInfo links to the run on Raider.IO
+		Dungeon  string
+		Level    int
+		Result   int
+		Points   string
+		MoreInfo string
+		// Affixes are the names of the latest run's affixes
+		Affixes []string
+	}
+
+	ScoreData struct {
+		Role  string
+		Score string
+	}
+
+	RankData struct {
+		Role        string
+		RealmRank   int
+		OverallRank int
+	}
+
+	// TemplateText is the source of each score update template, an empty one uses the default.
+	TemplateText struct {
+		Content     string
+		Title       string
+		Description string
+		Footer      string
+	}
+
+	// Templates render score update announcements.
+	Templates struct {
+		content     *template.Template
+		title       *template.Template
+		description *template.Template
+		footer      *template.Template
+	}
+)
+
+const (
+	defaultContentTemplate = `{{.Locale.T "[%s-%s](%s) increased their score from %0.2f to %0.2f" ` +
+		`.Name .Realm .ProfileURL .OldScore .NewScore}}`
+
+	defaultTitleTemplate = `{{.Locale.T "%0.2f Overall Mythic+ Score" .NewScore}}`
+
+	descriptionTemplate = `{{range $s := .Scores}}{{$.Locale.T "**%s Score** %s" $s.Role $s.Score}}
+{{end}}
+**{{.Locale.T "--- Ranks ---"}}**
+{{.Locale.T "**#%d Realm - #%d Overall**" .RealmRank .OverallRank}}
+{{range $r := .Ranks}}{{$.Locale.T "**%s**: #%d Realm - #%d Overall" $r.Role $r.RealmRank $r.OverallRank}}
+{{end}}
+**{{.Locale.T "--- Last Run ---"}}**
+{{.Locale.T "**Dungeon**: %s" .Dungeon}}
+{{.Locale.T "**Level**: %d" .Level}}
+{{.Locale.T "**Result**: +%d" .Result}}
+{{.Locale.T "**Points**: %s" .Points}}
+[{{.Locale.T "More Info"}}]({{.MoreInfo}}) 
+`
+
+	// The footer is left off until the profile has been fetched
+	defaultFooterTemplate = `{{if .ItemLevel}}{{.Locale.T "Item Level %d" .ItemLevel}}` +
+		`{{if .Guild}} · <{{.Guild}}>{{end}}{{end}}`
+)
+
+// defaultTemplates are used when no templates have been configured.
+var defaultTemplates = mustParseTemplates(TemplateText{})
+
+// sampleScoreUpdate is what templates are previewed and validated with.
+var sampleScoreUpdate = ScoreUpdate{
+	Character: db.Character{
+		Name: "Paladylan", Realm: "tichondrius", Class: "Paladin", Spec: "Protection", Guild: "Method", ItemLevel: 620,
+		OverallScore: 2512.4, TankScore: 2512.4, HealScore: 2301.7,
+		SpecScores: []db.SpecScore{{Spec: "Protection", Score: 2512.4}, {Spec: "Holy", Score: 2301.7}},
+	},
+	RaiderIO: raiderio.Character{
+		ProfileUrl: "https://raider.io/characters/us/tichondrius/Paladylan",
+		MythicPlusRanks: raiderio.Ranks{
+			Overall: raiderio.Rank{World: 10432, Region: 4210, Realm: 87},
+			Tank:    raiderio.Rank{World: 2310, Region: 980, Realm: 21},
+			Healer:  raiderio.Rank{World: 15220, Region: 6102, Realm: 140},
+		},
+		MythicPlusScoresBySeason: []raiderio.Season{{Scores: raiderio.Scores{Tank: 2512.4, Healer: 2301.7}}},
+		MythicPlusRecentRuns: []raiderio.Run{{
+			Dungeon:             "The Stonevault",
+			MythicLevel:         14,
+			NumKeystoneUpgrades: 2,
+			Score:               312.6,
+			CompletedAt:         time.Date(2024, time.September, 18, 20, 0, 0, 0, time.UTC),
+			Url:                 "https://raider.io/mythic-plus-runs/season-tww-1/1",
+			Affixes:             []raiderio.Affix{{Name: "Xal'atath's Bargain: Ascendant"}, {Name: "Tyrannical"}},
+		}},
+	},
+	OldScore: 2488.9,
+}
+
+// ParseTemplates parses the templates and checks they render against sample data, so mistakes are caught at
+// startup instead of when a score update is sent.
+func ParseTemplates(text TemplateText) (*Templates, error) {
+	var (
+		t   Templates
+		err error
+	)
+	if t.content, err = parseTemplate("content", text.Content, defaultContentTemplate); err != nil {
+		return nil, err
+	}
+	if t.title, err = parseTemplate("title", text.Title, defaultTitleTemplate); err != nil {
+		return nil, err
+	}
+	if t.description, err = parseTemplate("description", text.Description, descriptionTemplate); err != nil {
+		return nil, err
+	}
+	if t.footer, err = parseTemplate("footer", text.Footer, defaultFooterTemplate); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// parseTemplate parses the template, or the fallback if it is empty, and renders it against the sample data.
+func parseTemplate(name, text, fallback string) (*template.Template, error) {
+	if text == "" {
+		text = fallback
+	}
+
+	tpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s template: %w", name, err)
+	}
+	if err := tpl.Execute(&strings.Builder{}, newTemplateData(i18n.English, sampleScoreUpdate)); err != nil {
+		return nil, fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return tpl, nil
+}
+
+func mustParseTemplates(text TemplateText) *Templates {
+	t, err := ParseTemplates(text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Preview renders a score update for a sample character.
+func (t *Templates) Preview(ctx context.Context, l i18n.Locale) discordgo.MessageSend {
+	return t.BuildScoreUpdateMessage(ctx, l, sampleScoreUpdate)
+}
+
+func newTemplateData(l i18n.Locale, u ScoreUpdate) TemplateData {
+	c, rc := u.Character, u.RaiderIO
+	latestRun := getLatestRun(rc)
+
+	affixes := make([]string, 0, len(latestRun.Affixes))
+	for _, a := range latestRun.Affixes {
+		affixes = append(affixes, a.Name)
+	}
+
+	return TemplateData{
+		Locale:      l,
+		Name:        c.Name,
+		Realm:       c.Realm,
+		Class:       localName(l, c.Class),
+		Spec:        localName(l, c.Spec),
+		Guild:       c.Guild,
+		ItemLevel:   c.ItemLevel,
+		ProfileURL:  rc.ProfileUrl,
+		OldScore:    u.OldScore,
+		NewScore:    c.OverallScore,
+		Delta:       c.OverallScore - u.OldScore,
+		Scores:      buildScoreData(l, c),
+		Ranks:       buildRankData(l, rc),
+		RealmRank:   rc.MythicPlusRanks.Overall.Realm,
+		OverallRank: rc.MythicPlusRanks.Overall.World,
+		Dungeon:     latestRun.Dungeon,
+		Level:       latestRun.MythicLevel,
+		Result:      latestRun.NumKeystoneUpgrades,
+		Points:      fmt.Sprintf("%0.2f", latestRun.Score),
+		MoreInfo:    latestRun.Url,
+		Affixes:     affixes,
+	}
+}
+
+// execute renders the template, returning ok false if it fails.
+func execute(ctx context.Context, tpl *template.Template, data TemplateData) (string, bool) {
+	var s strings.Builder
+	if err := tpl.Execute(&s, data); err != nil {
+		slog.ErrorContext(ctx, "failed to execute "+tpl.Name()+" template: "+err.Error())
+		return "", false
+	}
+	return s.String(), true
+}
+
+// executeOr renders the template, falling back to the default template if it fails.
+func executeOr(ctx context.Context, tpl, fallback *template.Template, data TemplateData) string {
+	if s, ok := execute(ctx, tpl, data); ok {
+		return s
+	}
+	s, _ := execute(ctx, fallback, data)
+	return s
+}
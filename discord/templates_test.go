@@ -0,0 +1,109 @@
+package discord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemplates_Custom(t *testing.T) {
+	templates, err := ParseTemplates(TemplateText{
+		Content:     `{{.Name}} gained {{printf "%0.f" .Delta}} points`,
+		Title:       `{{.Name}} is now {{printf "%0.f" .NewScore}}`,
+		Description: `Last run: +{{.Level}} {{.Dungeon}} ({{range $i, $a := .Affixes}}{{if $i}}, {{end}}{{$a}}{{end}})`,
+		Footer:      `{{.Guild}}`,
+	})
+	require.NoError(t, err)
+
+	message := templates.BuildScoreUpdateMessage(context.Background(), i18n.English, sampleScoreUpdate)
+
+	assert.Equal(t, "Paladylan gained 24 points", message.Content)
+	require.Len(t, message.Embeds, 1)
+	embed := message.Embeds[0]
+	assert.Equal(t, "Paladylan is now 2512", embed.Title)
+	assert.Equal(t, "Last run: +14 The Stonevault (Xal'atath's Bargain: Ascendant, Tyrannical)", embed.Description)
+	require.NotNil(t, embed.Footer)
+	assert.Equal(t, "Method", embed.Footer.Text)
+}
+
+func TestParseTemplates_Defaults(t *testing.T) {
+	templates, err := ParseTemplates(TemplateText{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	update := ScoreUpdate{Character: testDBCharacter, RaiderIO: testRIOCharacter, OldScore: 2000.0}
+	assert.Equal(t, BuildScoreUpdateMessage(ctx, i18n.English, update), templates.BuildScoreUpdateMessage(ctx, i18n.English, update))
+}
+
+func TestParseTemplates_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    TemplateText
+		wantErr string
+	}{
+		{
+			name:    "syntax error",
+			text:    TemplateText{Content: "{{.Name"},
+			wantErr: "failed to parse content template",
+		},
+		{
+			name:    "unknown field",
+			text:    TemplateText{Title: "{{.Score}}"},
+			wantErr: "failed to render title template",
+		},
+		{
+			name:    "index out of range",
+			text:    TemplateText{Footer: `{{index .Affixes 5}}`},
+			wantErr: "failed to render footer template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			templates, err := ParseTemplates(tt.text)
+
+			assert.Nil(t, templates)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestTemplates_BuildScoreUpdateMessage_Fallback(t *testing.T) {
+	// Renders against the sample data, but fails for a run without affixes
+	templates, err := ParseTemplates(TemplateText{
+		Content:     `{{index .Affixes 1}}`,
+		Description: `{{index .Affixes 1}}`,
+		Footer:      `{{if .Guild}}<{{.Guild}}>{{end}}`,
+	})
+	require.NoError(t, err)
+
+	message := templates.BuildScoreUpdateMessage(context.Background(), i18n.English,
+		ScoreUpdate{Character: testDBCharacter, RaiderIO: testRIOCharacter, OldScore: 2000.0})
+
+	assert.Equal(t, "[Paladylan-tichondrius](https://raider.io/characters/us/tichondrius/Paladylan) increased their score from 2000.00 to 2500.00", message.Content)
+	assert.Equal(t, buildScoreUpdateMessageFallback(i18n.English, testDBCharacter), message.Embeds[0].Description)
+	assert.Nil(t, message.Embeds[0].Footer, "an empty footer is left off")
+}
+
+func TestTemplates_Preview(t *testing.T) {
+	message := defaultTemplates.Preview(context.Background(), i18n.English)
+
+	assert.Equal(t, "[Paladylan-tichondrius](https://raider.io/characters/us/tichondrius/Paladylan) increased their score from 2488.90 to 2512.40", message.Content)
+	require.Len(t, message.Embeds, 1)
+	assert.Equal(t, "2512.40 Overall Mythic+ Score", message.Embeds[0].Title)
+	assert.Contains(t, message.Embeds[0].Description, "**Protection Paladin Score** 2512.40")
+	assert.Equal(t, "Item Level 620 · <Method>", message.Embeds[0].Footer.Text)
+}
+
+func TestTemplates_Preview_German(t *testing.T) {
+	message := defaultTemplates.Preview(context.Background(), i18n.German)
+
+	assert.Equal(t, "[Paladylan-tichondrius](https://raider.io/characters/us/tichondrius/Paladylan) hat die Wertung von 2488.90 auf 2512.40 erhöht", message.Content)
+	require.Len(t, message.Embeds, 1)
+	assert.Equal(t, "2512.40 Mythisch+-Gesamtwertung", message.Embeds[0].Title)
+	assert.Contains(t, message.Embeds[0].Description, "**--- Letzter Lauf ---**")
+	assert.Equal(t, "Gegenstandsstufe 620 · <Method>", message.Embeds[0].Footer.Text)
+}
@@ -0,0 +1,56 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// threadArchiveMinutes is how long a thread goes without messages before Discord hides it, posting reopens it
+	threadArchiveMinutes = 7 * 24 * 60
+	maxThreadNameChars   = 100
+)
+
+// StartThread creates a public thread under the channel, returning its ID.
+func (d *Sender) StartThread(ctx context.Context, channelID, name string) (string, error) {
+	if len([]rune(name)) > maxThreadNameChars {
+		name = string([]rune(name)[:maxThreadNameChars])
+	}
+
+	thread, err := d.session.ThreadStartComplex(channelID, &discordgo.ThreadStart{
+		Name:                name,
+		AutoArchiveDuration: threadArchiveMinutes,
+		Type:                discordgo.ChannelTypeGuildPublicThread,
+	}, discordgo.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	return thread.ID, nil
+}
+
+// ThreadUsable reports whether messages can still be posted in the thread, they can't once it is deleted or locked.
+func (d *Sender) ThreadUsable(ctx context.Context, threadID string) (bool, error) {
+	thread, err := d.session.Channel(threadID, discordgo.WithContext(ctx))
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return thread.ThreadMetadata == nil || !thread.ThreadMetadata.Locked, nil
+}
+
+// BuildThreadSummaryMessage is the line posted in the channel for a score update posted in a thread, linking to it.
+func BuildThreadSummaryMessage(l i18n.Locale, u ScoreUpdate, threadID string) discordgo.MessageSend {
+	c := u.Character
+	return discordgo.MessageSend{
+		Content: l.T("%s-%s increased their score from %0.2f to %0.2f, more in <#%s>",
+			c.Name, c.Realm, u.OldScore, c.OverallScore, threadID),
+	}
+}
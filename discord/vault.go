@@ -0,0 +1,56 @@
+package discord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/vault"
+	"github.com/bwmarrin/discordgo"
+)
+
+// BuildVaultMessage lists each character's runs this week and the key level each vault slot would reward.
+func BuildVaultMessage(l i18n.Locale, progress []vault.Progress) discordgo.MessageSend {
+	var s strings.Builder
+	for _, p := range progress {
+		slots := make([]string, 0, len(p.Slots))
+		for _, level := range p.Slots {
+			if level == 0 {
+				slots = append(slots, "-")
+				continue
+			}
+			slots = append(slots, fmt.Sprintf("+%d", level))
+		}
+
+		line := l.T("**%s-%s**: %d runs | %s", p.Character.Name, p.Character.Realm, p.Runs,
+			strings.Join(slots, " / ")) + "\n"
+		if s.Len()+len(line) > maxEmbedDescriptionChars {
+			break
+		}
+		s.WriteString(line)
+	}
+	if s.Len() == 0 {
+		s.WriteString(l.T("No characters are being tracked."))
+	}
+
+	return discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			{
+				Title:       l.T("Great Vault Progress"),
+				Description: s.String(),
+				Color:       vaultColour,
+				Footer: &discordgo.MessageEmbedFooter{
+					Text: l.T("Slots unlock after %d, %d and %d runs", vault.SlotRuns[0], vault.SlotRuns[1],
+						vault.SlotRuns[2]),
+				},
+			},
+		},
+	}
+}
+
+// BuildVaultReminderMessage reminds the characters that haven't run a key this week to do one before the reset.
+func BuildVaultReminderMessage(l i18n.Locale, reset time.Time, characters []string) string {
+	return l.T("The weekly reset is <t:%d:R> and these characters haven't run a key for their vault yet: %s",
+		reset.Unix(), strings.Join(characters, ", "))
+}
@@ -0,0 +1,42 @@
+package discord
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/vault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildVaultMessage(t *testing.T) {
+	progress := []vault.Progress{
+		{Character: db.Character{Name: "Paladylan", Realm: "tichondrius"}, Runs: 5, Slots: [3]int{12, 10, 0}},
+		{Character: db.Character{Name: "Magedylan", Realm: "area-52"}},
+	}
+
+	message := BuildVaultMessage(i18n.English, progress)
+
+	require.Len(t, message.Embeds, 1)
+	embed := message.Embeds[0]
+	assert.Equal(t, "Great Vault Progress", embed.Title)
+	assert.Equal(t, "**Paladylan-tichondrius**: 5 runs | +12 / +10 / -\n**Magedylan-area-52**: 0 runs | - / - / -\n",
+		embed.Description)
+	assert.Equal(t, "Slots unlock after 1, 4 and 8 runs", embed.Footer.Text)
+}
+
+func TestBuildVaultMessage_NoCharacters(t *testing.T) {
+	message := BuildVaultMessage(i18n.English, nil)
+
+	assert.Equal(t, "No characters are being tracked.", message.Embeds[0].Description)
+}
+
+func TestBuildVaultReminderMessage(t *testing.T) {
+	reset := time.Date(2024, time.January, 2, 15, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "The weekly reset is <t:1704207600:R> and these characters haven't run a key for their vault yet: "+
+		"Magedylan-area-52, Priestdylan-tichondrius",
+		BuildVaultReminderMessage(i18n.English, reset, []string{"Magedylan-area-52", "Priestdylan-tichondrius"}))
+}
@@ -0,0 +1,179 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Webhook is a Discord webhook a channel's messages are posted through instead of by the bot.
+type Webhook struct {
+	ID    string
+	Token string
+	// Username and AvatarURL are used for messages that aren't about a single character, leave empty to use the
+	// webhook's own
+	Username  string
+	AvatarURL string
+}
+
+type webhookExecutor interface {
+	WebhookExecute(webhookID, token string, wait bool, data *discordgo.WebhookParams,
+		options ...discordgo.RequestOption) (*discordgo.Message, error)
+	WebhookThreadExecute(webhookID, token string, wait bool, threadID string, data *discordgo.WebhookParams,
+		options ...discordgo.RequestOption) (*discordgo.Message, error)
+}
+
+// ThreadRepository finds the channel a thread was started under, so it can be posted in through the channel's webhook.
+type ThreadRepository interface {
+	GetChannelID(ctx context.Context, threadID string) (string, error)
+}
+
+// WebhookSender posts channel messages through the channel's webhook, so the bot doesn't need permission to send in
+// it, or even be in the channel's server. Threads under a channel with a webhook are posted in through the same
+// webhook. Channels without a webhook, and everything else, use the bot.
+type WebhookSender struct {
+	bot      SenderIface
+	executor webhookExecutor
+	// webhooks maps a channel ID to its webhook
+	webhooks map[string]Webhook
+	threads  ThreadRepository
+}
+
+var _ SenderIface = (*WebhookSender)(nil)
+
+// NewWebhookSender creates a sender that posts to the channels in webhooks through them, executing them with session.
+// threads finds which channel the score update threads belong to.
+func NewWebhookSender(bot SenderIface, session *discordgo.Session, webhooks map[string]Webhook,
+	threads ThreadRepository,
+) *WebhookSender {
+	return &WebhookSender{bot: bot, executor: session, webhooks: webhooks, threads: threads}
+}
+
+// ParseWebhookURL creates a webhook from its URL, e.g. https://discord.com/api/webhooks/<id>/<token>.
+func ParseWebhookURL(rawURL string) (Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("invalid webhook URL: %w", err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[len(parts)-3] != "webhooks" {
+		return Webhook{}, fmt.Errorf("invalid webhook URL %q, expected .../api/webhooks/<id>/<token>", u.Redacted())
+	}
+
+	return Webhook{ID: parts[len(parts)-2], Token: parts[len(parts)-1]}, nil
+}
+
+func (w *WebhookSender) SendMessage(ctx context.Context, channelID, content string) error {
+	return w.SendComplexMessage(ctx, channelID, discordgo.MessageSend{Content: content})
+}
+
+// SendComplexMessage posts the message through the channel's webhook.
+//
+// Webhooks that don't belong to the bot can't send buttons, so messages with components are sent by the bot.
+func (w *WebhookSender) SendComplexMessage(ctx context.Context, channelID string, message discordgo.MessageSend) error {
+	if len(message.Components) > 0 {
+		return w.bot.SendComplexMessage(ctx, channelID, message)
+	}
+	webhook, threadID, ok, err := w.webhook(ctx, channelID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return w.bot.SendComplexMessage(ctx, channelID, message)
+	}
+
+	username, avatarURL := webhookIdentity(webhook, message)
+	files := message.Files
+	if message.File != nil {
+		files = append(files, message.File)
+	}
+
+	params := &discordgo.WebhookParams{
+		Content:         message.Content,
+		Username:        username,
+		AvatarURL:       avatarURL,
+		Embeds:          message.Embeds,
+		Files:           files,
+		AllowedMentions: message.AllowedMentions,
+	}
+	if threadID != "" {
+		_, err = w.executor.WebhookThreadExecute(webhook.ID, webhook.Token, false, threadID, params, discordgo.WithContext(ctx))
+	} else {
+		_, err = w.executor.WebhookExecute(webhook.ID, webhook.Token, false, params, discordgo.WithContext(ctx))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to execute webhook for channel %s: %w", channelID, err)
+	}
+	return nil
+}
+
+// webhook returns the webhook to post in the channel with, and the thread to post in if the channel is a thread under
+// a channel with a webhook. It returns false if the channel doesn't have a webhook.
+func (w *WebhookSender) webhook(ctx context.Context, channelID string) (Webhook, string, bool, error) {
+	if webhook, ok := w.webhooks[channelID]; ok {
+		return webhook, "", true, nil
+	}
+	if len(w.webhooks) == 0 || w.threads == nil {
+		return Webhook{}, "", false, nil
+	}
+
+	parentID, err := w.threads.GetChannelID(ctx, channelID)
+	if err != nil {
+		return Webhook{}, "", false, fmt.Errorf("failed to get thread %s's channel: %w", channelID, err)
+	}
+	webhook, ok := w.webhooks[parentID]
+	if !ok {
+		return Webhook{}, "", false, nil
+	}
+	return webhook, channelID, true, nil
+}
+
+// webhookIdentity returns who a message is posted as, messages about a character are posted as that character with
+// their thumbnail as the avatar.
+func webhookIdentity(webhook Webhook, message discordgo.MessageSend) (string, string) {
+	username, avatarURL := webhook.Username, webhook.AvatarURL
+
+	var author *discordgo.MessageEmbed
+	for _, e := range message.Embeds {
+		if e.Author == nil || e.Author.Name == "" {
+			continue
+		}
+		// Announcements combined by the outbox can be about different characters, which is nobody in particular
+		if author != nil && author.Author.Name != e.Author.Name {
+			return webhook.Username, webhook.AvatarURL
+		}
+		author = e
+	}
+
+	if author != nil {
+		username = author.Author.Name
+		if author.Thumbnail != nil && author.Thumbnail.URL != "" {
+			avatarURL = author.Thumbnail.URL
+		}
+	}
+	return username, avatarURL
+}
+
+// AddRole needs the bot, webhooks can only post messages.
+func (w *WebhookSender) AddRole(ctx context.Context, guildID, userID, roleID string) error {
+	return w.bot.AddRole(ctx, guildID, userID, roleID)
+}
+
+// RemoveRole needs the bot, webhooks can only post messages.
+func (w *WebhookSender) RemoveRole(ctx context.Context, guildID, userID, roleID string) error {
+	return w.bot.RemoveRole(ctx, guildID, userID, roleID)
+}
+
+// UpdateInteractionMessage needs the bot, interactions are with the bot's messages.
+func (w *WebhookSender) UpdateInteractionMessage(ctx context.Context, interaction *discordgo.Interaction, message discordgo.MessageSend) error {
+	return w.bot.UpdateInteractionMessage(ctx, interaction, message)
+}
+
+// RespondEphemeral needs the bot, interactions are with the bot's messages.
+func (w *WebhookSender) RespondEphemeral(ctx context.Context, interaction *discordgo.Interaction, content string) error {
+	return w.bot.RespondEphemeral(ctx, interaction, content)
+}
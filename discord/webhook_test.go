@@ -0,0 +1,186 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockWebhookExecutor struct {
+	mock.Mock
+}
+
+func (m *MockWebhookExecutor) WebhookExecute(webhookID, token string, wait bool, data *discordgo.WebhookParams,
+	_ ...discordgo.RequestOption,
+) (*discordgo.Message, error) {
+	args := m.Called(webhookID, token, wait, data)
+	return nil, args.Error(0)
+}
+
+func (m *MockWebhookExecutor) WebhookThreadExecute(webhookID, token string, wait bool, threadID string,
+	data *discordgo.WebhookParams, _ ...discordgo.RequestOption,
+) (*discordgo.Message, error) {
+	args := m.Called(webhookID, token, wait, threadID, data)
+	return nil, args.Error(0)
+}
+
+type MockThreadRepository struct {
+	mock.Mock
+}
+
+func (m *MockThreadRepository) GetChannelID(ctx context.Context, threadID string) (string, error) {
+	args := m.Called(ctx, threadID)
+	return args.String(0), args.Error(1)
+}
+
+func setupWebhookSender() (*WebhookSender, *MockSender, *MockWebhookExecutor) {
+	sender, bot, executor, _ := setupWebhookSenderWithThreads()
+	return sender, bot, executor
+}
+
+func setupWebhookSenderWithThreads() (*WebhookSender, *MockSender, *MockWebhookExecutor, *MockThreadRepository) {
+	bot := &MockSender{}
+	executor := &MockWebhookExecutor{}
+	threads := &MockThreadRepository{}
+	return &WebhookSender{
+		bot:      bot,
+		executor: executor,
+		webhooks: map[string]Webhook{
+			"webhook-channel": {ID: "123", Token: "token", Username: "Mythic+", AvatarURL: "https://example.com/bot.png"},
+		},
+		threads: threads,
+	}, bot, executor, threads
+}
+
+func characterEmbed(name, thumbnail string) *discordgo.MessageEmbed {
+	return &discordgo.MessageEmbed{
+		Title:     "Score Update",
+		Author:    &discordgo.MessageEmbedAuthor{Name: name},
+		Thumbnail: &discordgo.MessageEmbedThumbnail{URL: thumbnail},
+	}
+}
+
+func TestParseWebhookURL(t *testing.T) {
+	webhook, err := ParseWebhookURL("https://discord.com/api/webhooks/123456/abc-DEF_ghi")
+	require.NoError(t, err)
+	assert.Equal(t, Webhook{ID: "123456", Token: "abc-DEF_ghi"}, webhook)
+
+	_, err = ParseWebhookURL("https://discord.com/channels/123456/789")
+	assert.Error(t, err)
+}
+
+func TestWebhookSender_SendComplexMessage_AsCharacter(t *testing.T) {
+	sender, bot, executor := setupWebhookSender()
+	embed := characterEmbed("Testchar-testrealm (Frost Mage)", "https://render.example.com/testchar.jpg")
+
+	executor.On("WebhookExecute", "123", "token", false, &discordgo.WebhookParams{
+		Content:   "New high score!",
+		Username:  "Testchar-testrealm (Frost Mage)",
+		AvatarURL: "https://render.example.com/testchar.jpg",
+		Embeds:    []*discordgo.MessageEmbed{embed},
+	}).Return(nil)
+
+	err := sender.SendComplexMessage(context.Background(), "webhook-channel", discordgo.MessageSend{
+		Content: "New high score!",
+		Embeds:  []*discordgo.MessageEmbed{embed},
+	})
+	require.NoError(t, err)
+
+	executor.AssertExpectations(t)
+	bot.AssertNotCalled(t, "SendComplexMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWebhookSender_SendMessage_Default(t *testing.T) {
+	sender, _, executor := setupWebhookSender()
+
+	executor.On("WebhookExecute", "123", "token", false, &discordgo.WebhookParams{
+		Content:   "Checking for updates...",
+		Username:  "Mythic+",
+		AvatarURL: "https://example.com/bot.png",
+	}).Return(nil)
+
+	require.NoError(t, sender.SendMessage(context.Background(), "webhook-channel", "Checking for updates..."))
+	executor.AssertExpectations(t)
+}
+
+func TestWebhookSender_SendComplexMessage_DifferentCharacters(t *testing.T) {
+	sender, _, executor := setupWebhookSender()
+	embeds := []*discordgo.MessageEmbed{
+		characterEmbed("Testchar-testrealm (Frost Mage)", "https://render.example.com/testchar.jpg"),
+		characterEmbed("Otherchar-testrealm (Holy Priest)", "https://render.example.com/otherchar.jpg"),
+	}
+
+	// Combined announcements aren't posted as either character
+	executor.On("WebhookExecute", "123", "token", false, &discordgo.WebhookParams{
+		Username:  "Mythic+",
+		AvatarURL: "https://example.com/bot.png",
+		Embeds:    embeds,
+	}).Return(nil)
+
+	require.NoError(t, sender.SendComplexMessage(context.Background(), "webhook-channel",
+		discordgo.MessageSend{Embeds: embeds}))
+	executor.AssertExpectations(t)
+}
+
+func TestWebhookSender_SendComplexMessage_Error(t *testing.T) {
+	sender, _, executor := setupWebhookSender()
+
+	executor.On("WebhookExecute", "123", "token", false, mock.Anything).Return(errors.New("unknown webhook"))
+
+	err := sender.SendMessage(context.Background(), "webhook-channel", "hello")
+	assert.ErrorContains(t, err, "unknown webhook")
+}
+
+func TestWebhookSender_SendComplexMessage_Bot(t *testing.T) {
+	sender, bot, executor, threads := setupWebhookSenderWithThreads()
+	ctx := context.Background()
+
+	// Channels without a webhook are sent by the bot
+	threads.On("GetChannelID", ctx, "bot-channel").Return("", nil)
+	bot.On("SendComplexMessage", ctx, "bot-channel", discordgo.MessageSend{Content: "hello"}).Return(nil)
+	require.NoError(t, sender.SendMessage(ctx, "bot-channel", "hello"))
+
+	// As are messages with buttons, which webhooks can't send
+	buttons := discordgo.MessageSend{Components: []discordgo.MessageComponent{discordgo.ActionsRow{}}}
+	bot.On("SendComplexMessage", ctx, "webhook-channel", buttons).Return(nil)
+	require.NoError(t, sender.SendComplexMessage(ctx, "webhook-channel", buttons))
+
+	bot.AssertExpectations(t)
+	executor.AssertNotCalled(t, "WebhookExecute", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWebhookSender_SendComplexMessage_Thread(t *testing.T) {
+	sender, bot, executor, threads := setupWebhookSenderWithThreads()
+	ctx := context.Background()
+
+	// Threads under a channel with a webhook are posted in through its webhook
+	threads.On("GetChannelID", ctx, "thread1").Return("webhook-channel", nil)
+	executor.On("WebhookThreadExecute", "123", "token", false, "thread1", &discordgo.WebhookParams{
+		Content:   "hello",
+		Username:  "Mythic+",
+		AvatarURL: "https://example.com/bot.png",
+	}).Return(nil)
+	require.NoError(t, sender.SendMessage(ctx, "thread1", "hello"))
+
+	threads.On("GetChannelID", ctx, "thread2").Return("", errors.New("database error"))
+	assert.ErrorContains(t, sender.SendMessage(ctx, "thread2", "hello"), "database error")
+
+	executor.AssertExpectations(t)
+	executor.AssertNotCalled(t, "WebhookExecute", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	bot.AssertNotCalled(t, "SendComplexMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWebhookSender_AddRole(t *testing.T) {
+	sender, bot, _ := setupWebhookSender()
+	ctx := context.Background()
+
+	bot.On("AddRole", ctx, "guild1", "user1", "role1").Return(nil)
+
+	require.NoError(t, sender.AddRole(ctx, "guild1", "user1", "role1"))
+	bot.AssertExpectations(t)
+}
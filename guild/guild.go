@@ -0,0 +1,188 @@
+// Package guild tracks the home guild's Raider.IO mythic+ ranking and how its tracked members compare to common
+// score thresholds.
+package guild
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+)
+
+// ErrNoGuild is returned when no home guild has been configured.
+var ErrNoGuild = errors.New("no home guild is configured")
+
+// Thresholds are the scores members are counted against, roughly Keystone Master, Keystone Hero and title range.
+var Thresholds = []float64{2000, 2500, 3000}
+
+type (
+	CharacterRepository interface {
+		ListCharacters(ctx context.Context, limit int) ([]db.Character, error)
+	}
+
+	RankRepository interface {
+		Insert(ctx context.Context, rank *db.GuildRank) error
+		Latest(ctx context.Context, guild, realm string) (db.GuildRank, error)
+	}
+
+	RaiderIOClient interface {
+		GetGuild(ctx context.Context, realm, name string) (*raiderio.Guild, error)
+	}
+
+	MessageSender interface {
+		SendMessage(ctx context.Context, channelID, content string) error
+	}
+
+	// MessageBuilder builds the rank announcement in the announcement's language, see discord.BuildGuildRankMessage.
+	MessageBuilder interface {
+		BuildGuildRankMessage(l i18n.Locale, g raiderio.Guild, previousRealmRank int) string
+	}
+
+	TimeProvider interface {
+		Now() time.Time
+	}
+
+	// Summary is the guild's ranking along with how many of its tracked members are above each threshold.
+	Summary struct {
+		Guild   raiderio.Guild
+		Members int
+		// AboveThreshold holds the number of members at or above each of the Thresholds
+		AboveThreshold []int
+	}
+)
+
+type RealTimeProvider struct{}
+
+func (r *RealTimeProvider) Now() time.Time {
+	return time.Now()
+}
+
+// Service handles the home guild with injected dependencies
+type Service struct {
+	name           string
+	realm          string
+	characterRepo  CharacterRepository
+	rankRepo       RankRepository
+	raiderIOClient RaiderIOClient
+	messageSender  MessageSender
+	messageBuilder MessageBuilder
+	timeProvider   TimeProvider
+}
+
+// NewService creates a new guild service for the guild with dependencies, an empty name disables it
+func NewService(name, realm string, characterRepo CharacterRepository, rankRepo RankRepository,
+	raiderIOClient RaiderIOClient, messageSender MessageSender, messageBuilder MessageBuilder, timeProvider TimeProvider,
+) *Service {
+	return &Service{
+		name:           name,
+		realm:          realm,
+		characterRepo:  characterRepo,
+		rankRepo:       rankRepo,
+		raiderIOClient: raiderIOClient,
+		messageSender:  messageSender,
+		messageBuilder: messageBuilder,
+		timeProvider:   timeProvider,
+	}
+}
+
+// Summary returns the guild's ranking and the score thresholds its tracked members have reached.
+//
+// Members are the tracked characters on the guild's Raider.IO roster, which Raider.IO keeps up to date itself rather
+// than relying on when we last saw the character.
+func (s *Service) Summary(ctx context.Context) (Summary, error) {
+	if s.name == "" {
+		return Summary{}, ErrNoGuild
+	}
+
+	g, err := s.raiderIOClient.GetGuild(ctx, s.realm, s.name)
+	if err != nil {
+		return Summary{}, fmt.Errorf("failed to get guild: %w", err)
+	}
+
+	characters, err := s.characterRepo.ListCharacters(ctx, 0)
+	if err != nil {
+		return Summary{}, fmt.Errorf("failed to list characters: %w", err)
+	}
+
+	roster := make(map[string]bool, len(g.Members))
+	for _, m := range g.Members {
+		roster[memberKey(m.Character.Name, realmSlug(m.Character.Realm))] = true
+	}
+
+	summary := Summary{Guild: *g, AboveThreshold: make([]int, len(Thresholds))}
+	for _, c := range characters {
+		if !roster[memberKey(c.Name, c.Realm)] {
+			continue
+		}
+
+		summary.Members++
+		for i, threshold := range Thresholds {
+			if c.OverallScore >= threshold {
+				summary.AboveThreshold[i]++
+			}
+		}
+	}
+
+	return summary, nil
+}
+
+// memberKey identifies a character on the roster, ignoring case as we store names as they were typed.
+func memberKey(name, realm string) string {
+	return strings.ToLower(name) + "-" + strings.ToLower(realm)
+}
+
+// realmSlug converts a realm name like Mal'Ganis or Area 52 into the slug we store, malganis or area-52.
+func realmSlug(realm string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(strings.ToLower(realm), "'", ""), " ", "-")
+}
+
+// CheckRank records the guild's ranks when they have moved since the last check, and announces when its realm rank has.
+//
+// Nothing is announced the first time the guild is checked, or while Raider.IO hasn't ranked it.
+func (s *Service) CheckRank(ctx context.Context, channelID string) error {
+	if s.name == "" {
+		return ErrNoGuild
+	}
+
+	g, err := s.raiderIOClient.GetGuild(ctx, s.realm, s.name)
+	if err != nil {
+		return fmt.Errorf("failed to get guild: %w", err)
+	}
+	ranks := g.MythicPlusRanks
+	if ranks.Realm == 0 {
+		return nil
+	}
+
+	previous, err := s.rankRepo.Latest(ctx, s.name, s.realm)
+	if err != nil {
+		return fmt.Errorf("failed to get previous guild rank: %w", err)
+	}
+
+	// Ranks are only recorded when they move, so the history isn't a row per check
+	if previous.WorldRank == ranks.World && previous.RegionRank == ranks.Region && previous.RealmRank == ranks.Realm {
+		return nil
+	}
+
+	if err := s.rankRepo.Insert(ctx, &db.GuildRank{
+		Guild:        s.name,
+		Realm:        s.realm,
+		WorldRank:    ranks.World,
+		RegionRank:   ranks.Region,
+		RealmRank:    ranks.Realm,
+		DateRecorded: s.timeProvider.Now().Unix(),
+	}); err != nil {
+		return fmt.Errorf("failed to record guild rank: %w", err)
+	}
+
+	if previous.RealmRank == 0 || previous.RealmRank == ranks.Realm {
+		return nil
+	}
+
+	return s.messageSender.SendMessage(ctx, channelID,
+		s.messageBuilder.BuildGuildRankMessage(i18n.FromContext(ctx), *g, previous.RealmRank))
+}
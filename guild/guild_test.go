@@ -0,0 +1,232 @@
+package guild
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations for testing
+
+type MockCharacterRepository struct {
+	mock.Mock
+}
+
+func (m *MockCharacterRepository) ListCharacters(ctx context.Context, limit int) ([]db.Character, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]db.Character), args.Error(1)
+}
+
+type MockRankRepository struct {
+	mock.Mock
+}
+
+func (m *MockRankRepository) Insert(ctx context.Context, rank *db.GuildRank) error {
+	args := m.Called(ctx, rank)
+	return args.Error(0)
+}
+
+func (m *MockRankRepository) Latest(ctx context.Context, guild, realm string) (db.GuildRank, error) {
+	args := m.Called(ctx, guild, realm)
+	return args.Get(0).(db.GuildRank), args.Error(1)
+}
+
+type MockRaiderIOClient struct {
+	mock.Mock
+}
+
+func (m *MockRaiderIOClient) GetGuild(ctx context.Context, realm, name string) (*raiderio.Guild, error) {
+	args := m.Called(ctx, realm, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*raiderio.Guild), args.Error(1)
+}
+
+type MockMessageSender struct {
+	mock.Mock
+}
+
+func (m *MockMessageSender) SendMessage(ctx context.Context, channelID, content string) error {
+	args := m.Called(ctx, channelID, content)
+	return args.Error(0)
+}
+
+type MockMessageBuilder struct {
+	mock.Mock
+}
+
+func (m *MockMessageBuilder) BuildGuildRankMessage(l i18n.Locale, g raiderio.Guild, previousRealmRank int) string {
+	args := m.Called(l, g, previousRealmRank)
+	return args.String(0)
+}
+
+type MockTimeProvider struct {
+	now time.Time
+}
+
+func (m *MockTimeProvider) Now() time.Time {
+	return m.now
+}
+
+// Test helpers
+
+var testNow = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+type mocks struct {
+	characterRepo  *MockCharacterRepository
+	rankRepo       *MockRankRepository
+	raiderIOClient *MockRaiderIOClient
+	messageSender  *MockMessageSender
+	messageBuilder *MockMessageBuilder
+}
+
+func setupService() (*Service, mocks) {
+	m := mocks{
+		characterRepo:  &MockCharacterRepository{},
+		rankRepo:       &MockRankRepository{},
+		raiderIOClient: &MockRaiderIOClient{},
+		messageSender:  &MockMessageSender{},
+		messageBuilder: &MockMessageBuilder{},
+	}
+
+	service := NewService("Test Guild", "tichondrius", m.characterRepo, m.rankRepo, m.raiderIOClient,
+		m.messageSender, m.messageBuilder, &MockTimeProvider{now: testNow})
+	return service, m
+}
+
+func createTestGuild(world, region, realm int) *raiderio.Guild {
+	return &raiderio.Guild{
+		Name:            "Test Guild",
+		Realm:           "Tichondrius",
+		MythicPlusRanks: raiderio.Rank{World: world, Region: region, Realm: realm},
+	}
+}
+
+// Tests
+
+func TestService_Summary(t *testing.T) {
+	service, m := setupService()
+	ctx := context.Background()
+
+	g := createTestGuild(1200, 450, 12)
+	g.Members = []raiderio.GuildMember{
+		{Character: raiderio.GuildMemberCharacter{Name: "Paladylan", Realm: "Tichondrius"}},
+		{Character: raiderio.GuildMemberCharacter{Name: "Magedylan", Realm: "Area 52"}},
+		{Character: raiderio.GuildMemberCharacter{Name: "Alt", Realm: "Tichondrius"}},
+		{Character: raiderio.GuildMemberCharacter{Name: "Untracked", Realm: "Tichondrius"}},
+	}
+	m.raiderIOClient.On("GetGuild", ctx, "tichondrius", "Test Guild").Return(g, nil)
+	m.characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{
+		// The stored guild may be out of date, the roster is what counts
+		{Name: "Paladylan", Realm: "tichondrius", OverallScore: 3050},
+		{Name: "Magedylan", Realm: "area-52", Guild: "Old Guild", OverallScore: 2600},
+		{Name: "Alt", Realm: "tichondrius", Guild: "Test Guild", OverallScore: 1500},
+		{Name: "Friend", Realm: "tichondrius", Guild: "Test Guild", OverallScore: 3300},
+		{Name: "Alt", Realm: "area-52", OverallScore: 2000},
+	}, nil)
+
+	summary, err := service.Summary(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 12, summary.Guild.MythicPlusRanks.Realm)
+	assert.Equal(t, 3, summary.Members)
+	assert.Equal(t, []int{2, 2, 1}, summary.AboveThreshold)
+}
+
+func TestRealmSlug(t *testing.T) {
+	assert.Equal(t, "tichondrius", realmSlug("Tichondrius"))
+	assert.Equal(t, "area-52", realmSlug("Area 52"))
+	assert.Equal(t, "malganis", realmSlug("Mal'Ganis"))
+}
+
+func TestService_Summary_NoGuild(t *testing.T) {
+	service := NewService("", "", nil, nil, nil, nil, nil, nil)
+
+	_, err := service.Summary(context.Background())
+
+	assert.ErrorIs(t, err, ErrNoGuild)
+}
+
+func TestService_CheckRank_Moved(t *testing.T) {
+	service, m := setupService()
+	ctx := context.Background()
+
+	m.raiderIOClient.On("GetGuild", ctx, "tichondrius", "Test Guild").Return(createTestGuild(1100, 420, 10), nil)
+	m.rankRepo.On("Latest", ctx, "Test Guild", "tichondrius").Return(db.GuildRank{RealmRank: 12}, nil)
+	m.rankRepo.On("Insert", ctx, &db.GuildRank{Guild: "Test Guild", Realm: "tichondrius", WorldRank: 1100,
+		RegionRank: 420, RealmRank: 10, DateRecorded: testNow.Unix()}).Return(nil)
+	m.messageBuilder.On("BuildGuildRankMessage", i18n.English, *createTestGuild(1100, 420, 10), 12).Return("climbed")
+	m.messageSender.On("SendMessage", ctx, "channel1", "climbed").Return(nil)
+
+	err := service.CheckRank(ctx, "channel1")
+
+	require.NoError(t, err)
+	m.rankRepo.AssertExpectations(t)
+	m.messageSender.AssertExpectations(t)
+}
+
+func TestService_CheckRank_FirstCheck(t *testing.T) {
+	service, m := setupService()
+	ctx := context.Background()
+
+	m.raiderIOClient.On("GetGuild", ctx, "tichondrius", "Test Guild").Return(createTestGuild(1100, 420, 10), nil)
+	m.rankRepo.On("Latest", ctx, "Test Guild", "tichondrius").Return(db.GuildRank{}, nil)
+	m.rankRepo.On("Insert", ctx, mock.AnythingOfType("*db.GuildRank")).Return(nil)
+
+	err := service.CheckRank(ctx, "channel1")
+
+	require.NoError(t, err)
+	m.rankRepo.AssertExpectations(t)
+	m.messageSender.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_CheckRank_RealmRankUnchanged(t *testing.T) {
+	service, m := setupService()
+	ctx := context.Background()
+
+	m.raiderIOClient.On("GetGuild", ctx, "tichondrius", "Test Guild").Return(createTestGuild(1100, 420, 10), nil)
+	m.rankRepo.On("Latest", ctx, "Test Guild", "tichondrius").Return(db.GuildRank{RealmRank: 10, RegionRank: 430}, nil)
+	m.rankRepo.On("Insert", ctx, mock.AnythingOfType("*db.GuildRank")).Return(nil)
+
+	err := service.CheckRank(ctx, "channel1")
+
+	require.NoError(t, err)
+	m.rankRepo.AssertExpectations(t)
+	m.messageSender.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_CheckRank_Unchanged(t *testing.T) {
+	service, m := setupService()
+	ctx := context.Background()
+
+	m.raiderIOClient.On("GetGuild", ctx, "tichondrius", "Test Guild").Return(createTestGuild(1100, 420, 10), nil)
+	m.rankRepo.On("Latest", ctx, "Test Guild", "tichondrius").
+		Return(db.GuildRank{WorldRank: 1100, RegionRank: 420, RealmRank: 10}, nil)
+
+	err := service.CheckRank(ctx, "channel1")
+
+	require.NoError(t, err)
+	m.rankRepo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+	m.messageSender.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_CheckRank_ClientError(t *testing.T) {
+	service, m := setupService()
+	ctx := context.Background()
+
+	m.raiderIOClient.On("GetGuild", ctx, "tichondrius", "Test Guild").Return(nil, errors.New("api error"))
+
+	err := service.CheckRank(ctx, "channel1")
+
+	assert.ErrorContains(t, err, "failed to get guild")
+	m.rankRepo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,209 @@
+package i18n
+
+// german are the German translations of the bot's messages.
+var german = map[string]string{
+	"This bot tracks characters M+ scores and will post updates to the channel whenever they increase:":                "Dieser Bot verfolgt die M+-Wertungen von Charakteren und postet ein Update in den Kanal, sobald sie steigen:",
+	"To add a character send: `%s add <character> <realm>`":                                                            "Um einen Charakter hinzuzufügen, sende: `%s add <Charakter> <Realm>`",
+	"To remove a character send: `%s remove <character> <realm>`":                                                      "Um einen Charakter zu entfernen, sende: `%s remove <Charakter> <Realm>`",
+	"To list the top `n` scores send: `%s scores [-n 10]`":                                                             "Um die besten `n` Wertungen aufzulisten, sende: `%s scores [-n 10]`",
+	"To see a character's item level and gear send: `%s profile <character> <realm>`":                                  "Um Gegenstandsstufe und Ausrüstung eines Charakters zu sehen, sende: `%s profile <Charakter> <Realm>`",
+	"To see a character's best run in each dungeon this season send: `%s dungeons <character> <realm>`":                "Um den besten Lauf eines Charakters in jedem Dungeon dieser Saison zu sehen, sende: `%s dungeons <Charakter> <Realm>`",
+	"To rank the tracked characters by their best run in a dungeon send: `%s dungeon <dungeon>`":                       "Um die verfolgten Charaktere nach ihrem besten Lauf in einem Dungeon zu ordnen, sende: `%s dungeon <Dungeon>`",
+	"To see a tracked character's best or recent Raider.IO runs send: `%s runs <character> <realm> [--best|--recent]`": "Um die besten oder letzten Raider.IO-Läufe eines verfolgten Charakters zu sehen, sende: `%s runs <Charakter> <Realm> [--best|--recent]`",
+	"To link a character to yourself for milestone roles send: `%s link <character> <realm>`":                          "Um einen Charakter für Meilenstein-Rollen mit dir zu verknüpfen, sende: `%s link <Charakter> <Realm>`",
+	"To see who still needs keys for their Great Vault this week send: `%s vault`":                                     "Um zu sehen, wer diese Woche noch Schlüssel für die Große Schatzkammer braucht, sende: `%s vault`",
+	"To see the score needed for the top percentiles this season send: `%s cutoffs [season]`":                          "Um die benötigte Wertung für die besten Perzentile dieser Saison zu sehen, sende: `%s cutoffs [Saison]`",
+	"To see this week's affixes send: `%s affixes`":                                                                    "Um die Affixe dieser Woche zu sehen, sende: `%s affixes`",
+	"To see the guild's Mythic+ ranking send: `%s guild`":                                                              "Um die Mythisch+-Platzierung der Gilde zu sehen, sende: `%s guild`",
+	"To update scores outside the 30 minute window send: `%s update`":                                                  "Um die Wertungen außerhalb des 30-Minuten-Intervalls zu aktualisieren, sende: `%s update`",
+	"To export the tracked characters send: `%s export [json|csv] [history]`":                                          "Um die verfolgten Charaktere zu exportieren, sende: `%s export [json|csv] [history]`",
+	"To preview the score update templates with a sample character send: `%s preview-template`":                        "Um die Vorlagen für Wertungs-Updates mit einem Beispielcharakter anzusehen, sende: `%s preview-template`",
+	"To change the language the bot uses send: `%s language [%s]`":                                                     "Um die Sprache des Bots zu ändern, sende: `%s language [%s]`",
+	"Usage: %s <command> [args]":                           "Verwendung: %s <Befehl> [Argumente]",
+	"Unknown command. Use %s help for a list of commands.": "Unbekannter Befehl. Sende %s help für eine Liste der Befehle.",
+	"Usage: %s add <character> <realm>":                    "Verwendung: %s add <Charakter> <Realm>",
+	"Failed to add character.":                             "Charakter konnte nicht hinzugefügt werden.",
+	"Now tracking %s-%s":                                   "Verfolge jetzt %s-%s",
+	"Usage: %s remove <character> <realm>":                 "Verwendung: %s remove <Charakter> <Realm>",
+	"Failed to remove character.":                          "Charakter konnte nicht entfernt werden.",
+	"No longer tracking %s-%s.":                            "%s-%s wird nicht mehr verfolgt.",
+	"Failed to get scores":                                 "Wertungen konnten nicht abgerufen werden",
+	"todo :(":                                              "todo :(",
+	"Usage: %s profile <character> <realm>":                "Verwendung: %s profile <Charakter> <Realm>",
+	"Failed to get profile.":                               "Profil konnte nicht abgerufen werden.",
+	"Usage: %s runs <character> <realm> [--best|--recent]": "Verwendung: %s runs <Charakter> <Realm> [--best|--recent]",
+	"Failed to get runs.":                                  "Läufe konnten nicht abgerufen werden.",
+	"%s-%s isn't being tracked, add them with `%s add <character> <realm>`.": "%s-%s wird nicht verfolgt, füge den Charakter mit `%s add <Charakter> <Realm>` hinzu.",
+	"Usage: %s link <character> <realm>":                                     "Verwendung: %s link <Charakter> <Realm>",
+	"Failed to link character.":                                              "Charakter konnte nicht verknüpft werden.",
+	"Linked %s-%s to <@%s>":                                                  "%s-%s ist jetzt mit <@%s> verknüpft",
+	"Usage: %s dungeons <character> <realm>":                                 "Verwendung: %s dungeons <Charakter> <Realm>",
+	"Failed to get dungeons.":                                                "Dungeons konnten nicht abgerufen werden.",
+	"Usage: %s dungeon <dungeon>":                                            "Verwendung: %s dungeon <Dungeon>",
+	"Failed to get dungeon leaderboard.":                                     "Dungeon-Bestenliste konnte nicht abgerufen werden.",
+	"No dungeon runs have been recorded this season.":                        "In dieser Saison wurden noch keine Dungeonläufe erfasst.",
+	"Unknown dungeon, try one of: %s":                                        "Unbekannter Dungeon, versuche einen von: %s",
+	"Failed to get vault progress.":                                          "Fortschritt der Schatzkammer konnte nicht abgerufen werden.",
+	"Failed to get season cutoffs.":                                          "Saison-Grenzwerte konnten nicht abgerufen werden.",
+	"Failed to get this week's affixes.":                                     "Die Affixe dieser Woche konnten nicht abgerufen werden.",
+	"No home guild is configured.":                                           "Es ist keine Heimatgilde eingerichtet.",
+	"Failed to get the guild.":                                               "Gilde konnte nicht abgerufen werden.",
+	"Checking for updates...":                                                "Suche nach Updates...",
+	"Failed to update scores":                                                "Wertungen konnten nicht aktualisiert werden",
+	"The bot is replying in %s, change it with `%s language [%s]`.":          "Der Bot antwortet auf %s, ändere das mit `%s language [%s]`.",
+	"Unknown language, try one of: %s":                                       "Unbekannte Sprache, versuche eine von: %s",
+	"Failed to change the language.":                                         "Die Sprache konnte nicht geändert werden.",
+	"The bot will now reply in %s.":                                          "Der Bot antwortet jetzt auf %s.",
+	"Usage: %s export [json|csv] [history]":                                  "Verwendung: %s export [json|csv] [history]",
+	"Failed to export characters.":                                           "Charaktere konnten nicht exportiert werden.",
+	"Raider.IO hasn't listed this week's affixes yet.":                       "Raider.IO hat die Affixe dieser Woche noch nicht veröffentlicht.",
+	"This Week's Affixes":                                                    "Affixe dieser Woche",
+	"Tracked Characters":                                                     "Verfolgte Charaktere",
+	"Too many characters tracked to list them all.":                          "Zu viele Charaktere werden verfolgt, um alle aufzulisten.",
+	"No runs this season.":                                                   "Keine Läufe in dieser Saison.",
+	"Season":                                                                 "Saison",
+	"Mythic+ Rating":                                                         "Mythisch+-Wertung",
+	"**%s** %s in %s (%0.1f)":                                                "**%s** %s in %s (%0.1f)",
+	"New dungeon PB: **%s** %s":                                              "Neue Dungeon-Bestleistung: **%s** %s",
+	"(was %s)":                                                               "(vorher %s)",
+	"**%s-%s** %s in %s (%0.1f)":                                             "**%s-%s** %s in %s (%0.1f)",
+	"Exported roster (%d file(s) attached).":                                 "Liste exportiert (%d Datei(en) angehängt).",
+	"Not ranked yet.":                                                        "Noch nicht platziert.",
+	"#%d Realm - #%d Region - #%d World":                                     "#%d Realm - #%d Region - #%d Welt",
+	"Mythic+ Ranking":                                                        "Mythisch+-Platzierung",
+	"Tracked Members (%d)":                                                   "Verfolgte Mitglieder (%d)",
+	"%s-%s reached %s!":                                                      "%s-%s hat %s erreicht!",
+	"**%s-%s** (%s) is now at **%0.1f**, passing the %0.f milestone.":        "**%s-%s** (%s) steht jetzt bei **%0.1f** und hat den Meilenstein %0.f überschritten.",
+	"Milestone Reached":                                                      "Meilenstein erreicht",
+	"Level":                                                                  "Stufe",
+	"Item Level":                                                             "Gegenstandsstufe",
+	"%d (%d equipped)":                                                       "%d (%d angelegt)",
+	"Faction":                                                                "Fraktion",
+	"Guild":                                                                  "Gilde",
+	"Mythic+ Score":                                                          "Mythisch+-Wertung",
+	"Equipment":                                                              "Ausrüstung",
+	"None":                                                                   "Keine",
+	"Best Runs":                                                              "Beste Läufe",
+	"Recent Runs":                                                            "Letzte Läufe",
+	"No runs found.":                                                         "Keine Läufe gefunden.",
+	"Alternate Runs":                                                         "Alternative Läufe",
+	"Dungeon\tLevel\tUpgrades\tTime / Par\tScore":                            "Dungeon\tStufe\tAufwertungen\tZeit / Limit\tWertung",
+	"depleted":                                                               "nicht geschafft",
+	"Top %s":                                                                 "Top %s",
+	"%s is %0.f, you're %0.f away":                                           "%s liegt bei %0.f, dir fehlen %0.f",
+	"above the %s of %0.f":                                                   "über dem %s von %0.f",
+	"Season Cutoffs":                                                         "Saison-Grenzwerte",
+	"0.1%% title cutoff":                                                     "Titel-Grenzwert 0.1%%",
+	"%s cutoff":                                                              "%s-Grenzwert",
+	"**Top %s**: %0.1f (%d characters)":                                      "**Top %s**: %0.1f (%d Charaktere)",
+	"Raider.IO hasn't calculated the cutoffs for this season yet.": "Raider.IO hat die Grenzwerte für diese Saison noch nicht berechnet.",
+	"Season Cutoffs (%s)": "Saison-Grenzwerte (%s)",
+	"Updated %s":          "Aktualisiert %s",
+	"Tank":                "Tank",
+	"Healer":              "Heiler",
+	"DPS":                 "DPS",
+	"**Tank Score** %02.f\n**Healer Score** %02.f\n**DPS Score** %02.f": "**Tank-Wertung** %02.f\n**Heiler-Wertung** %02.f\n**DPS-Wertung** %02.f",
+	"[%s-%s](%s) increased their score from %0.2f to %0.2f":             "[%s-%s](%s) hat die Wertung von %0.2f auf %0.2f erhöht",
+	"%0.2f Overall Mythic+ Score":                                       "%0.2f Mythisch+-Gesamtwertung",
+	"**%s Score** %s":                                                   "**%s-Wertung** %s",
+	"--- Ranks ---":                                                     "--- Platzierungen ---",
+	"**#%d Realm - #%d Overall**":                                       "**#%d Realm - #%d Gesamt**",
+	"**%s**: #%d Realm - #%d Overall":                                   "**%s**: #%d Realm - #%d Gesamt",
+	"--- Last Run ---":                                                  "--- Letzter Lauf ---",
+	"**Dungeon**: %s":                                                   "**Dungeon**: %s",
+	"**Level**: %d":                                                     "**Stufe**: %d",
+	"**Result**: +%d":                                                   "**Ergebnis**: +%d",
+	"**Points**: %s":                                                    "**Punkte**: %s",
+	"More Info":                                                         "Mehr Infos",
+	"Item Level %d":                                                     "Gegenstandsstufe %d",
+	"**%s-%s**: %d runs | %s":                                           "**%s-%s**: %d Läufe | %s",
+	"No characters are being tracked.":                                  "Es werden keine Charaktere verfolgt.",
+	"Great Vault Progress":                                              "Fortschritt der Großen Schatzkammer",
+	"Slots unlock after %d, %d and %d runs":                             "Plätze werden nach %d, %d und %d Läufen freigeschaltet",
+	"Previous":                                                          "Zurück",
+	"Next":                                                              "Weiter",
+	"Page %d of %d":                                                     "Seite %d von %d",
+	"This leaderboard has expired, send `%s scores` for a new one.":  "Diese Bestenliste ist abgelaufen, sende `%s scores` für eine neue.",
+	"%s-%s increased their score from %0.2f to %0.2f, more in <#%s>": "%s-%s hat die Wertung von %0.2f auf %0.2f erhöht, mehr in <#%s>",
+	"Score updates for %s":          "Wertungs-Updates für %s",
+	"Score updates for %s and alts": "Wertungs-Updates für %s und Twinks",
+	"Live Leaderboard":              "Live-Bestenliste",
+	"Last updated":                  "Zuletzt aktualisiert",
+	"Usage: %s compare <character>-<realm> <character>-<realm> [...]":                               "Verwendung: %s compare <Charakter>-<Realm> <Charakter>-<Realm> [...]",
+	"You can compare up to %d characters.":                                                          "Du kannst bis zu %d Charaktere vergleichen.",
+	"To compare characters side by side send: `%s compare <character>-<realm> <character>-<realm>`": "Um Charaktere nebeneinander zu vergleichen, sende: `%s compare <Charakter>-<Realm> <Charakter>-<Realm>`",
+	"Head to Head":                     "Direktvergleich",
+	"Best Keys":                        "Beste Schlüssel",
+	"**Overall** %0.1f":                "**Gesamt** %0.1f",
+	"**Tank** %0.1f":                   "**Tank** %0.1f",
+	"**Healer** %0.1f":                 "**Heiler** %0.1f",
+	"**DPS** %0.1f":                    "**DPS** %0.1f",
+	"**Realm** %s":                     "**Realm** %s",
+	"**Region** %s":                    "**Region** %s",
+	"**World** %s":                     "**Welt** %s",
+	"**%d days** %s":                   "**%d Tage** %s",
+	"Not tracked, so no score history": "Nicht verfolgt, daher kein Wertungsverlauf",
+	"The weekly reset is <t:%d:R> and these characters haven't run a key for their vault yet: %s": "Die wöchentliche Zurücksetzung ist <t:%d:R> und diese Charaktere haben noch keinen Schlüssel für ihre Schatzkammer gespielt: %s",
+	"<%s> climbed from #%d to #%d on %s for Mythic+ (#%d Region - #%d World)":                     "<%s> ist von #%d auf #%d auf %s in Mythisch+ aufgestiegen (#%d Region - #%d Welt)",
+	"<%s> dropped from #%d to #%d on %s for Mythic+ (#%d Region - #%d World)":                     "<%s> ist von #%d auf #%d auf %s in Mythisch+ abgerutscht (#%d Region - #%d Welt)",
+	"(#%d Region)": "(#%d Region)",
+	"%s-%s is already linked to someone else, they or an admin can unlink it with `%s unlink <character> <realm>`.": "%s-%s ist bereits mit jemand anderem verknüpft, diese Person oder ein Admin kann die Verknüpfung mit `%s unlink <Charakter> <Realm>` aufheben.",
+	"To unlink a character from yourself send: `%s unlink <character> <realm>`":                                     "Um die Verknüpfung eines Charakters mit dir aufzuheben, sende: `%s unlink <Charakter> <Realm>`",
+	"Usage: %s unlink <character> <realm>":                                                                          "Verwendung: %s unlink <Charakter> <Realm>",
+	"%s-%s isn't linked to anyone.":                                                                                 "%s-%s ist mit niemandem verknüpft.",
+	"%s-%s is linked to someone else, only they or an admin can unlink it.":                                         "%s-%s ist mit jemand anderem verknüpft, nur diese Person oder ein Admin kann die Verknüpfung aufheben.",
+	"Failed to unlink character.":                                                                                   "Verknüpfung des Charakters konnte nicht aufgehoben werden.",
+	"Unlinked %s-%s":                                                                                                "Verknüpfung von %s-%s aufgehoben",
+	"Couldn't find %s on Raider.IO.":                                                                                "%s wurde auf Raider.IO nicht gefunden.",
+	"Couldn't find %s on Raider.IO, comparing the rest.":                                                            "%s wurde auf Raider.IO nicht gefunden, die übrigen werden verglichen.",
+	// Classes and specs, which are stored in English
+	"%s %s":         "%s %s",
+	"Warrior":       "Krieger",
+	"Paladin":       "Paladin",
+	"Hunter":        "Jäger",
+	"Rogue":         "Schurke",
+	"Priest":        "Priester",
+	"Death Knight":  "Todesritter",
+	"Shaman":        "Schamane",
+	"Mage":          "Magier",
+	"Warlock":       "Hexenmeister",
+	"Monk":          "Mönch",
+	"Druid":         "Druide",
+	"Demon Hunter":  "Dämonenjäger",
+	"Evoker":        "Rufer",
+	"Arcane":        "Arkan",
+	"Fire":          "Feuer",
+	"Frost":         "Frost",
+	"Holy":          "Heilig",
+	"Protection":    "Schutz",
+	"Retribution":   "Vergeltung",
+	"Arms":          "Waffen",
+	"Fury":          "Furor",
+	"Balance":       "Gleichgewicht",
+	"Feral":         "Wildheit",
+	"Guardian":      "Wächter",
+	"Restoration":   "Wiederherstellung",
+	"Blood":         "Blut",
+	"Unholy":        "Unheilig",
+	"Beast Mastery": "Tierherrschaft",
+	"Marksmanship":  "Treffsicherheit",
+	"Survival":      "Überleben",
+	"Discipline":    "Disziplin",
+	"Shadow":        "Schatten",
+	"Assassination": "Meucheln",
+	"Outlaw":        "Gesetzlosigkeit",
+	"Subtlety":      "Täuschung",
+	"Elemental":     "Elementar",
+	"Enhancement":   "Verstärkung",
+	"Affliction":    "Gebrechen",
+	"Demonology":    "Dämonologie",
+	"Destruction":   "Zerstörung",
+	"Brewmaster":    "Braumeister",
+	"Windwalker":    "Windläufer",
+	"Mistweaver":    "Nebelwirker",
+	"Havoc":         "Verwüstung",
+	"Vengeance":     "Rachsucht",
+	"Devastation":   "Verheerung",
+	"Preservation":  "Bewahrung",
+	"Augmentation":  "Verstärkung",
+}
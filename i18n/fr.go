@@ -0,0 +1,209 @@
+package i18n
+
+// french are the French translations of the bot's messages.
+var french = map[string]string{
+	"This bot tracks characters M+ scores and will post updates to the channel whenever they increase:":                "Ce bot suit le score M+ des personnages et publie une mise à jour dans le salon dès qu'il augmente :",
+	"To add a character send: `%s add <character> <realm>`":                                                            "Pour ajouter un personnage, envoyez : `%s add <personnage> <royaume>`",
+	"To remove a character send: `%s remove <character> <realm>`":                                                      "Pour retirer un personnage, envoyez : `%s remove <personnage> <royaume>`",
+	"To list the top `n` scores send: `%s scores [-n 10]`":                                                             "Pour lister les `n` meilleurs scores, envoyez : `%s scores [-n 10]`",
+	"To see a character's item level and gear send: `%s profile <character> <realm>`":                                  "Pour voir le niveau d'objet et l'équipement d'un personnage, envoyez : `%s profile <personnage> <royaume>`",
+	"To see a character's best run in each dungeon this season send: `%s dungeons <character> <realm>`":                "Pour voir la meilleure clé d'un personnage dans chaque donjon cette saison, envoyez : `%s dungeons <personnage> <royaume>`",
+	"To rank the tracked characters by their best run in a dungeon send: `%s dungeon <dungeon>`":                       "Pour classer les personnages suivis selon leur meilleure clé dans un donjon, envoyez : `%s dungeon <donjon>`",
+	"To see a tracked character's best or recent Raider.IO runs send: `%s runs <character> <realm> [--best|--recent]`": "Pour voir les meilleures clés ou les clés récentes Raider.IO d'un personnage suivi, envoyez : `%s runs <personnage> <royaume> [--best|--recent]`",
+	"To link a character to yourself for milestone roles send: `%s link <character> <realm>`":                          "Pour lier un personnage à vous et recevoir les rôles de palier, envoyez : `%s link <personnage> <royaume>`",
+	"To see who still needs keys for their Great Vault this week send: `%s vault`":                                     "Pour voir qui a encore besoin de clés pour sa Grande chambre forte cette semaine, envoyez : `%s vault`",
+	"To see the score needed for the top percentiles this season send: `%s cutoffs [season]`":                          "Pour voir le score nécessaire pour les meilleurs centiles de la saison, envoyez : `%s cutoffs [saison]`",
+	"To see this week's affixes send: `%s affixes`":                                                                    "Pour voir les affixes de la semaine, envoyez : `%s affixes`",
+	"To see the guild's Mythic+ ranking send: `%s guild`":                                                              "Pour voir le classement Mythique+ de la guilde, envoyez : `%s guild`",
+	"To update scores outside the 30 minute window send: `%s update`":                                                  "Pour mettre à jour les scores en dehors de l'intervalle de 30 minutes, envoyez : `%s update`",
+	"To export the tracked characters send: `%s export [json|csv] [history]`":                                          "Pour exporter les personnages suivis, envoyez : `%s export [json|csv] [history]`",
+	"To preview the score update templates with a sample character send: `%s preview-template`":                        "Pour prévisualiser les modèles de mise à jour de score avec un personnage d'exemple, envoyez : `%s preview-template`",
+	"To change the language the bot uses send: `%s language [%s]`":                                                     "Pour changer la langue du bot, envoyez : `%s language [%s]`",
+	"Usage: %s <command> [args]":                           "Utilisation : %s <commande> [arguments]",
+	"Unknown command. Use %s help for a list of commands.": "Commande inconnue. Utilisez %s help pour la liste des commandes.",
+	"Usage: %s add <character> <realm>":                    "Utilisation : %s add <personnage> <royaume>",
+	"Failed to add character.":                             "Impossible d'ajouter le personnage.",
+	"Now tracking %s-%s":                                   "%s-%s est maintenant suivi",
+	"Usage: %s remove <character> <realm>":                 "Utilisation : %s remove <personnage> <royaume>",
+	"Failed to remove character.":                          "Impossible de retirer le personnage.",
+	"No longer tracking %s-%s.":                            "%s-%s n'est plus suivi.",
+	"Failed to get scores":                                 "Impossible de récupérer les scores",
+	"todo :(":                                              "todo :(",
+	"Usage: %s profile <character> <realm>":                "Utilisation : %s profile <personnage> <royaume>",
+	"Failed to get profile.":                               "Impossible de récupérer le profil.",
+	"Usage: %s runs <character> <realm> [--best|--recent]": "Utilisation : %s runs <personnage> <royaume> [--best|--recent]",
+	"Failed to get runs.":                                  "Impossible de récupérer les clés.",
+	"%s-%s isn't being tracked, add them with `%s add <character> <realm>`.": "%s-%s n'est pas suivi, ajoutez-le avec `%s add <personnage> <royaume>`.",
+	"Usage: %s link <character> <realm>":                                     "Utilisation : %s link <personnage> <royaume>",
+	"Failed to link character.":                                              "Impossible de lier le personnage.",
+	"Linked %s-%s to <@%s>":                                                  "%s-%s est maintenant lié à <@%s>",
+	"Usage: %s dungeons <character> <realm>":                                 "Utilisation : %s dungeons <personnage> <royaume>",
+	"Failed to get dungeons.":                                                "Impossible de récupérer les donjons.",
+	"Usage: %s dungeon <dungeon>":                                            "Utilisation : %s dungeon <donjon>",
+	"Failed to get dungeon leaderboard.":                                     "Impossible de récupérer le classement du donjon.",
+	"No dungeon runs have been recorded this season.":                        "Aucune clé n'a été enregistrée cette saison.",
+	"Unknown dungeon, try one of: %s":                                        "Donjon inconnu, essayez l'un de : %s",
+	"Failed to get vault progress.":                                          "Impossible de récupérer la progression de la chambre forte.",
+	"Failed to get season cutoffs.":                                          "Impossible de récupérer les seuils de la saison.",
+	"Failed to get this week's affixes.":                                     "Impossible de récupérer les affixes de la semaine.",
+	"No home guild is configured.":                                           "Aucune guilde principale n'est configurée.",
+	"Failed to get the guild.":                                               "Impossible de récupérer la guilde.",
+	"Checking for updates...":                                                "Recherche de mises à jour...",
+	"Failed to update scores":                                                "Impossible de mettre à jour les scores",
+	"The bot is replying in %s, change it with `%s language [%s]`.":          "Le bot répond en %s, changez-le avec `%s language [%s]`.",
+	"Unknown language, try one of: %s":                                       "Langue inconnue, essayez l'une de : %s",
+	"Failed to change the language.":                                         "Impossible de changer la langue.",
+	"The bot will now reply in %s.":                                          "Le bot répondra désormais en %s.",
+	"Usage: %s export [json|csv] [history]":                                  "Utilisation : %s export [json|csv] [history]",
+	"Failed to export characters.":                                           "Impossible d'exporter les personnages.",
+	"Raider.IO hasn't listed this week's affixes yet.":                       "Raider.IO n'a pas encore publié les affixes de la semaine.",
+	"This Week's Affixes":                                                    "Affixes de la semaine",
+	"Tracked Characters":                                                     "Personnages suivis",
+	"Too many characters tracked to list them all.":                          "Trop de personnages sont suivis pour tous les lister.",
+	"No runs this season.":                                                   "Aucune clé cette saison.",
+	"Season":                                                                 "Saison",
+	"Mythic+ Rating":                                                         "Cote Mythique+",
+	"**%s** %s in %s (%0.1f)":                                                "**%s** %s en %s (%0.1f)",
+	"New dungeon PB: **%s** %s":                                              "Nouveau record de donjon : **%s** %s",
+	"(was %s)":                                                               "(avant %s)",
+	"**%s-%s** %s in %s (%0.1f)":                                             "**%s-%s** %s en %s (%0.1f)",
+	"Exported roster (%d file(s) attached).":                                 "Liste exportée (%d fichier(s) joint(s)).",
+	"Not ranked yet.":                                                        "Pas encore classée.",
+	"#%d Realm - #%d Region - #%d World":                                     "#%d Royaume - #%d Région - #%d Monde",
+	"Mythic+ Ranking":                                                        "Classement Mythique+",
+	"Tracked Members (%d)":                                                   "Membres suivis (%d)",
+	"%s-%s reached %s!":                                                      "%s-%s a atteint %s !",
+	"**%s-%s** (%s) is now at **%0.1f**, passing the %0.f milestone.":        "**%s-%s** (%s) est maintenant à **%0.1f** et dépasse le palier de %0.f.",
+	"Milestone Reached":                                                      "Palier atteint",
+	"Level":                                                                  "Niveau",
+	"Item Level":                                                             "Niveau d'objet",
+	"%d (%d equipped)":                                                       "%d (%d équipé)",
+	"Faction":                                                                "Faction",
+	"Guild":                                                                  "Guilde",
+	"Mythic+ Score":                                                          "Score Mythique+",
+	"Equipment":                                                              "Équipement",
+	"None":                                                                   "Aucun",
+	"Best Runs":                                                              "Meilleures clés",
+	"Recent Runs":                                                            "Clés récentes",
+	"No runs found.":                                                         "Aucune clé trouvée.",
+	"Alternate Runs":                                                         "Clés alternatives",
+	"Dungeon\tLevel\tUpgrades\tTime / Par\tScore":                            "Donjon\tNiveau\tAméliorations\tTemps / Limite\tScore",
+	"depleted":                                                               "épuisée",
+	"Top %s":                                                                 "Top %s",
+	"%s is %0.f, you're %0.f away":                                           "%s est à %0.f, il vous manque %0.f",
+	"above the %s of %0.f":                                                   "au-dessus du %s de %0.f",
+	"Season Cutoffs":                                                         "Seuils de la saison",
+	"0.1%% title cutoff":                                                     "seuil du titre 0.1%%",
+	"%s cutoff":                                                              "seuil %s",
+	"**Top %s**: %0.1f (%d characters)":                                      "**Top %s** : %0.1f (%d personnages)",
+	"Raider.IO hasn't calculated the cutoffs for this season yet.": "Raider.IO n'a pas encore calculé les seuils de cette saison.",
+	"Season Cutoffs (%s)": "Seuils de la saison (%s)",
+	"Updated %s":          "Mis à jour %s",
+	"Tank":                "Tank",
+	"Healer":              "Soigneur",
+	"DPS":                 "DPS",
+	"**Tank Score** %02.f\n**Healer Score** %02.f\n**DPS Score** %02.f": "**Score tank** %02.f\n**Score soigneur** %02.f\n**Score DPS** %02.f",
+	"[%s-%s](%s) increased their score from %0.2f to %0.2f":             "[%s-%s](%s) a augmenté son score de %0.2f à %0.2f",
+	"%0.2f Overall Mythic+ Score":                                       "%0.2f de score Mythique+ global",
+	"**%s Score** %s":                                                   "**Score %s** %s",
+	"--- Ranks ---":                                                     "--- Classements ---",
+	"**#%d Realm - #%d Overall**":                                       "**#%d Royaume - #%d Global**",
+	"**%s**: #%d Realm - #%d Overall":                                   "**%s** : #%d Royaume - #%d Global",
+	"--- Last Run ---":                                                  "--- Dernière clé ---",
+	"**Dungeon**: %s":                                                   "**Donjon** : %s",
+	"**Level**: %d":                                                     "**Niveau** : %d",
+	"**Result**: +%d":                                                   "**Résultat** : +%d",
+	"**Points**: %s":                                                    "**Points** : %s",
+	"More Info":                                                         "Plus d'infos",
+	"Item Level %d":                                                     "Niveau d'objet %d",
+	"**%s-%s**: %d runs | %s":                                           "**%s-%s** : %d clés | %s",
+	"No characters are being tracked.":                                  "Aucun personnage n'est suivi.",
+	"Great Vault Progress":                                              "Progression de la Grande chambre forte",
+	"Slots unlock after %d, %d and %d runs":                             "Les emplacements se débloquent après %d, %d et %d clés",
+	"Previous":                                                          "Précédent",
+	"Next":                                                              "Suivant",
+	"Page %d of %d":                                                     "Page %d sur %d",
+	"This leaderboard has expired, send `%s scores` for a new one.":  "Ce classement a expiré, envoyez `%s scores` pour en obtenir un nouveau.",
+	"%s-%s increased their score from %0.2f to %0.2f, more in <#%s>": "%s-%s a augmenté son score de %0.2f à %0.2f, plus dans <#%s>",
+	"Score updates for %s":          "Mises à jour du score de %s",
+	"Score updates for %s and alts": "Mises à jour du score de %s et ses rerolls",
+	"Live Leaderboard":              "Classement en direct",
+	"Last updated":                  "Dernière mise à jour",
+	"Usage: %s compare <character>-<realm> <character>-<realm> [...]":                               "Utilisation : %s compare <personnage>-<royaume> <personnage>-<royaume> [...]",
+	"You can compare up to %d characters.":                                                          "Vous pouvez comparer jusqu'à %d personnages.",
+	"To compare characters side by side send: `%s compare <character>-<realm> <character>-<realm>`": "Pour comparer des personnages côte à côte, envoyez : `%s compare <personnage>-<royaume> <personnage>-<royaume>`",
+	"Head to Head":                     "Face à face",
+	"Best Keys":                        "Meilleures clés",
+	"**Overall** %0.1f":                "**Global** %0.1f",
+	"**Tank** %0.1f":                   "**Tank** %0.1f",
+	"**Healer** %0.1f":                 "**Soigneur** %0.1f",
+	"**DPS** %0.1f":                    "**DPS** %0.1f",
+	"**Realm** %s":                     "**Royaume** %s",
+	"**Region** %s":                    "**Région** %s",
+	"**World** %s":                     "**Monde** %s",
+	"**%d days** %s":                   "**%d jours** %s",
+	"Not tracked, so no score history": "Non suivi, donc pas d'historique de score",
+	"The weekly reset is <t:%d:R> and these characters haven't run a key for their vault yet: %s": "La réinitialisation hebdomadaire est <t:%d:R> et ces personnages n'ont pas encore fait de clé pour leur chambre forte : %s",
+	"<%s> climbed from #%d to #%d on %s for Mythic+ (#%d Region - #%d World)":                     "<%s> est monté de #%d à #%d sur %s en Mythique+ (#%d Région - #%d Monde)",
+	"<%s> dropped from #%d to #%d on %s for Mythic+ (#%d Region - #%d World)":                     "<%s> est descendu de #%d à #%d sur %s en Mythique+ (#%d Région - #%d Monde)",
+	"(#%d Region)": "(#%d Région)",
+	"%s-%s is already linked to someone else, they or an admin can unlink it with `%s unlink <character> <realm>`.": "%s-%s est déjà lié à quelqu'un d'autre, cette personne ou un admin peut le délier avec `%s unlink <personnage> <royaume>`.",
+	"To unlink a character from yourself send: `%s unlink <character> <realm>`":                                     "Pour délier un personnage de vous, envoyez : `%s unlink <personnage> <royaume>`",
+	"Usage: %s unlink <character> <realm>":                                                                          "Utilisation : %s unlink <personnage> <royaume>",
+	"%s-%s isn't linked to anyone.":                                                                                 "%s-%s n'est lié à personne.",
+	"%s-%s is linked to someone else, only they or an admin can unlink it.":                                         "%s-%s est lié à quelqu'un d'autre, seule cette personne ou un admin peut le délier.",
+	"Failed to unlink character.":                                                                                   "Impossible de délier le personnage.",
+	"Unlinked %s-%s":                                                                                                "%s-%s délié",
+	"Couldn't find %s on Raider.IO.":                                                                                "%s est introuvable sur Raider.IO.",
+	"Couldn't find %s on Raider.IO, comparing the rest.":                                                            "%s est introuvable sur Raider.IO, comparaison des autres personnages.",
+	// Classes and specs, which are stored in English
+	"%s %s":         "%[2]s %[1]s",
+	"Warrior":       "Guerrier",
+	"Paladin":       "Paladin",
+	"Hunter":        "Chasseur",
+	"Rogue":         "Voleur",
+	"Priest":        "Prêtre",
+	"Death Knight":  "Chevalier de la mort",
+	"Shaman":        "Chaman",
+	"Mage":          "Mage",
+	"Warlock":       "Démoniste",
+	"Monk":          "Moine",
+	"Druid":         "Druide",
+	"Demon Hunter":  "Chasseur de démons",
+	"Evoker":        "Évocateur",
+	"Arcane":        "Arcanes",
+	"Fire":          "Feu",
+	"Frost":         "Givre",
+	"Holy":          "Sacré",
+	"Protection":    "Protection",
+	"Retribution":   "Vindicte",
+	"Arms":          "Armes",
+	"Fury":          "Fureur",
+	"Balance":       "Équilibre",
+	"Feral":         "Farouche",
+	"Guardian":      "Gardien",
+	"Restoration":   "Restauration",
+	"Blood":         "Sang",
+	"Unholy":        "Impie",
+	"Beast Mastery": "Maîtrise des bêtes",
+	"Marksmanship":  "Précision",
+	"Survival":      "Survie",
+	"Discipline":    "Discipline",
+	"Shadow":        "Ombre",
+	"Assassination": "Assassinat",
+	"Outlaw":        "Hors-la-loi",
+	"Subtlety":      "Finesse",
+	"Elemental":     "Élémentaire",
+	"Enhancement":   "Amélioration",
+	"Affliction":    "Affliction",
+	"Demonology":    "Démonologie",
+	"Destruction":   "Destruction",
+	"Brewmaster":    "Maître brasseur",
+	"Windwalker":    "Marche-vent",
+	"Mistweaver":    "Tisse-brume",
+	"Havoc":         "Dévastation",
+	"Vengeance":     "Vengeance",
+	"Devastation":   "Dévastation",
+	"Preservation":  "Préservation",
+	"Augmentation":  "Augmentation",
+}
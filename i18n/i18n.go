@@ -0,0 +1,105 @@
+// Package i18n translates the bot's replies and announcements.
+//
+// Messages are looked up by their English text, so English needs no catalogue and a message missing from another
+// locale's catalogue falls back to English.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Locale is a language the bot can reply in.
+type Locale string
+
+const (
+	English Locale = "en"
+	German  Locale = "de"
+	French  Locale = "fr"
+)
+
+// Locales are the locales with a catalogue, in the order they are listed to users.
+var Locales = []Locale{English, German, French}
+
+// catalogues maps each locale's messages from their English text.
+var catalogues = map[Locale]map[string]string{
+	German: german,
+	French: french,
+}
+
+// names are what each locale calls itself.
+var names = map[Locale]string{
+	English: "English",
+	German:  "Deutsch",
+	French:  "Français",
+}
+
+// blizzardLocales are the locale parameters the Blizzard API expects.
+var blizzardLocales = map[Locale]string{
+	English: "en_US",
+	German:  "de_DE",
+	French:  "fr_FR",
+}
+
+// Parse returns the locale for a code like "de", ignoring case.
+func Parse(code string) (Locale, error) {
+	l := Locale(strings.ToLower(code))
+	if _, ok := names[l]; !ok {
+		return English, fmt.Errorf("unknown locale %q", code)
+	}
+	return l, nil
+}
+
+// T translates the message and formats it with args like fmt.Sprintf, so a literal % is written as %%.
+func (l Locale) T(msg string, args ...any) string {
+	if translated, ok := catalogues[l][msg]; ok {
+		msg = translated
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Name is what the locale calls itself, e.g. Deutsch.
+func (l Locale) Name() string {
+	if name, ok := names[l]; ok {
+		return name
+	}
+	return names[English]
+}
+
+// Blizzard returns the Blizzard API locale parameter, e.g. de_DE.
+func (l Locale) Blizzard() string {
+	if locale, ok := blizzardLocales[l]; ok {
+		return locale
+	}
+	return blizzardLocales[English]
+}
+
+type (
+	localeKey   struct{}
+	resolverKey struct{}
+)
+
+// Resolver looks up the locale when it is needed, so long running jobs follow changes to it.
+type Resolver func(ctx context.Context) Locale
+
+// WithLocale returns a context whose messages are in the locale.
+func WithLocale(ctx context.Context, l Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, l)
+}
+
+// WithResolver returns a context whose messages are in the locale the resolver returns, unless WithLocale overrides it.
+func WithResolver(ctx context.Context, r Resolver) context.Context {
+	return context.WithValue(ctx, resolverKey{}, r)
+}
+
+// FromContext returns the locale messages should be in, English if none has been set.
+func FromContext(ctx context.Context) Locale {
+	if l, ok := ctx.Value(localeKey{}).(Locale); ok {
+		return l
+	}
+	if r, ok := ctx.Value(resolverKey{}).(Resolver); ok {
+		return r(ctx)
+	}
+	return English
+}
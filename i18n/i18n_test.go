@@ -0,0 +1,162 @@
+package i18n
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocale_T(t *testing.T) {
+	assert.Equal(t, "Now tracking Testchar-testrealm", English.T("Now tracking %s-%s", "Testchar", "testrealm"))
+	assert.Equal(t, "Verfolge jetzt Testchar-testrealm", German.T("Now tracking %s-%s", "Testchar", "testrealm"))
+	assert.Equal(t, "Testchar-testrealm est maintenant suivi", French.T("Now tracking %s-%s", "Testchar", "testrealm"))
+
+	// Messages missing from a catalogue fall back to English
+	assert.Equal(t, "Not translated 1", German.T("Not translated %d", 1))
+	assert.Equal(t, "Top 0.1%", English.T("Top 0.1%%"))
+}
+
+func TestParse(t *testing.T) {
+	l, err := Parse("DE")
+	require.NoError(t, err)
+	assert.Equal(t, German, l)
+
+	l, err = Parse("klingon")
+	assert.Error(t, err)
+	assert.Equal(t, English, l)
+}
+
+func TestLocale_NameAndBlizzard(t *testing.T) {
+	assert.Equal(t, "Français", French.Name())
+	assert.Equal(t, "fr_FR", French.Blizzard())
+	// Unknown locales use English
+	assert.Equal(t, "English", Locale("xx").Name())
+	assert.Equal(t, "en_US", Locale("xx").Blizzard())
+}
+
+func TestFromContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, English, FromContext(ctx))
+
+	ctx = WithResolver(ctx, func(context.Context) Locale { return French })
+	assert.Equal(t, French, FromContext(ctx))
+
+	// A fixed locale takes priority over the resolver
+	assert.Equal(t, German, FromContext(WithLocale(ctx, German)))
+}
+
+var verbPattern = regexp.MustCompile(`%(?:\[([0-9]+)\])?([-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%])`)
+
+func TestCatalogues_KeepVerbs(t *testing.T) {
+	for l, catalogue := range catalogues {
+		for msg, translated := range catalogue {
+			assert.Equal(t, verbs(msg), verbs(translated), "%s translation of %q", l, msg)
+		}
+	}
+}
+
+// verbs returns the message's verbs in the order of the arguments they format, as translations can put them in a
+// different order with explicit indexes, e.g. %[2]s %[1]s.
+func verbs(msg string) []string {
+	matches := verbPattern.FindAllStringSubmatch(msg, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	ordered := make([]string, len(matches))
+	next := 0
+	for _, m := range matches {
+		i := next
+		if m[1] != "" {
+			i, _ = strconv.Atoi(m[1])
+			i--
+		}
+		if i < 0 || i >= len(ordered) {
+			return append(ordered, m[0])
+		}
+		ordered[i] = "%" + m[2]
+		next = i + 1
+	}
+	return ordered
+}
+
+// templatePattern finds messages translated inside the default templates, e.g. {{.Locale.T "Level %d" .Level}}.
+var templatePattern = regexp.MustCompile(`\.Locale\.T "((?:[^"\\]|\\.)*)"`)
+
+func TestCatalogues_Complete(t *testing.T) {
+	messages := make(map[string]bool)
+	for _, dir := range []string{"../bot", "../discord", "../threads"} {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		require.NoError(t, err)
+
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+			for _, msg := range sourceMessages(t, file) {
+				messages[msg] = true
+			}
+		}
+	}
+	require.NotEmpty(t, messages)
+
+	for _, l := range Locales {
+		if l == English {
+			continue
+		}
+		for msg := range messages {
+			_, ok := catalogues[l][msg]
+			assert.True(t, ok, "%s is missing a translation of %q", l, msg)
+		}
+	}
+}
+
+// sourceMessages returns the messages passed to T in the file, including those in template strings.
+func sourceMessages(t *testing.T, file string) []string {
+	t.Helper()
+	src, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	f, err := parser.ParseFile(token.NewFileSet(), file, src, 0)
+	require.NoError(t, err)
+
+	var messages []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "T" || len(n.Args) == 0 {
+				return true
+			}
+			if lit, ok := n.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				msg, err := strconv.Unquote(lit.Value)
+				require.NoError(t, err)
+				messages = append(messages, msg)
+			}
+		case *ast.BasicLit:
+			if n.Kind != token.STRING {
+				return true
+			}
+			text, err := strconv.Unquote(n.Value)
+			require.NoError(t, err)
+			for _, match := range templatePattern.FindAllStringSubmatch(text, -1) {
+				msg, err := strconv.Unquote(`"` + match[1] + `"`)
+				require.NoError(t, err)
+				messages = append(messages, msg)
+			}
+		}
+		return true
+	})
+
+	return messages
+}
@@ -0,0 +1,100 @@
+// Package leaderboard keeps a pinned message in a channel showing the top scores, edited after every update so nobody
+// has to keep asking for the scores.
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/discord"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/bwmarrin/discordgo"
+)
+
+type (
+	CharacterService interface {
+		ListCharacters(ctx context.Context, limit int) ([]db.Character, error)
+	}
+
+	Repository interface {
+		GetMessageID(ctx context.Context, channelID string) (string, error)
+		SetMessageID(ctx context.Context, channelID, messageID string) error
+	}
+
+	// MessageSender sends the leaderboard as the bot, webhooks and the outbox can't edit messages later.
+	MessageSender interface {
+		PostMessage(ctx context.Context, channelID string, message discordgo.MessageSend) (string, error)
+		EditMessage(ctx context.Context, channelID, messageID string, message discordgo.MessageSend) error
+		PinMessage(ctx context.Context, channelID, messageID string) error
+	}
+
+	TimeProvider interface {
+		Now() time.Time
+	}
+)
+
+type RealTimeProvider struct{}
+
+func (r *RealTimeProvider) Now() time.Time {
+	return time.Now()
+}
+
+// Service handles live leaderboards with injected dependencies
+type Service struct {
+	characterService CharacterService
+	repo             Repository
+	messageSender    MessageSender
+	timeProvider     TimeProvider
+}
+
+// NewService creates a new live leaderboard service with dependencies
+func NewService(characterService CharacterService, repo Repository, messageSender MessageSender, timeProvider TimeProvider) *Service {
+	return &Service{
+		characterService: characterService,
+		repo:             repo,
+		messageSender:    messageSender,
+		timeProvider:     timeProvider,
+	}
+}
+
+// Refresh edits the channel's leaderboard to show the latest scores, posting and pinning a new one if the channel
+// doesn't have one yet or it was deleted.
+func (s *Service) Refresh(ctx context.Context, channelID string) error {
+	characters, err := s.characterService.ListCharacters(ctx, discord.LiveLeaderboardSize)
+	if err != nil {
+		return fmt.Errorf("failed to list characters: %w", err)
+	}
+	message := discord.BuildLiveLeaderboardMessage(i18n.FromContext(ctx), characters, s.timeProvider.Now())
+
+	messageID, err := s.repo.GetMessageID(ctx, channelID)
+	if err != nil {
+		return fmt.Errorf("failed to get leaderboard message: %w", err)
+	}
+
+	if messageID != "" {
+		err := s.messageSender.EditMessage(ctx, channelID, messageID, message)
+		if !errors.Is(err, discord.ErrMessageNotFound) {
+			return err
+		}
+		slog.InfoContext(ctx, "leaderboard message was deleted, posting a new one", "channel", channelID,
+			"message", messageID)
+	}
+
+	messageID, err = s.messageSender.PostMessage(ctx, channelID, message)
+	if err != nil {
+		return fmt.Errorf("failed to post leaderboard: %w", err)
+	}
+	if err := s.repo.SetMessageID(ctx, channelID, messageID); err != nil {
+		return fmt.Errorf("failed to save leaderboard message: %w", err)
+	}
+
+	// The leaderboard still works unpinned, pinning needs the Manage Messages permission
+	if err := s.messageSender.PinMessage(ctx, channelID, messageID); err != nil {
+		slog.WarnContext(ctx, "failed to pin leaderboard", "error", err, "channel", channelID)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/discord"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCharacterService struct {
+	mock.Mock
+}
+
+func (m *MockCharacterService) ListCharacters(ctx context.Context, limit int) ([]db.Character, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]db.Character), args.Error(1)
+}
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetMessageID(ctx context.Context, channelID string) (string, error) {
+	args := m.Called(ctx, channelID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRepository) SetMessageID(ctx context.Context, channelID, messageID string) error {
+	args := m.Called(ctx, channelID, messageID)
+	return args.Error(0)
+}
+
+type MockMessageSender struct {
+	mock.Mock
+}
+
+func (m *MockMessageSender) PostMessage(ctx context.Context, channelID string, message discordgo.MessageSend) (string, error) {
+	args := m.Called(ctx, channelID, message)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockMessageSender) EditMessage(ctx context.Context, channelID, messageID string, message discordgo.MessageSend) error {
+	args := m.Called(ctx, channelID, messageID, message)
+	return args.Error(0)
+}
+
+func (m *MockMessageSender) PinMessage(ctx context.Context, channelID, messageID string) error {
+	args := m.Called(ctx, channelID, messageID)
+	return args.Error(0)
+}
+
+type MockTimeProvider struct {
+	now time.Time
+}
+
+func (m *MockTimeProvider) Now() time.Time {
+	return m.now
+}
+
+var testNow = time.Date(2024, time.September, 17, 15, 0, 0, 0, time.UTC)
+
+func setupService() (*Service, *MockCharacterService, *MockRepository, *MockMessageSender) {
+	characters := &MockCharacterService{}
+	repo := &MockRepository{}
+	sender := &MockMessageSender{}
+	return NewService(characters, repo, sender, &MockTimeProvider{now: testNow}), characters, repo, sender
+}
+
+// isLeaderboard matches the leaderboard built at testNow.
+func isLeaderboard(message discordgo.MessageSend) bool {
+	return len(message.Embeds) == 1 && message.Embeds[0].Timestamp == "2024-09-17T15:00:00Z"
+}
+
+func TestService_Refresh_Edits(t *testing.T) {
+	service, characters, repo, sender := setupService()
+	ctx := context.Background()
+
+	characters.On("ListCharacters", ctx, discord.LiveLeaderboardSize).Return([]db.Character{{Name: "Testchar"}}, nil)
+	repo.On("GetMessageID", ctx, "channel1").Return("message1", nil)
+	sender.On("EditMessage", ctx, "channel1", "message1", mock.MatchedBy(isLeaderboard)).Return(nil)
+
+	require.NoError(t, service.Refresh(ctx, "channel1"))
+
+	sender.AssertExpectations(t)
+	sender.AssertNotCalled(t, "PostMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_Refresh_Posts(t *testing.T) {
+	tests := []struct {
+		name      string
+		messageID string
+	}{
+		{name: "first refresh"},
+		{name: "deleted", messageID: "message1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, characters, repo, sender := setupService()
+			ctx := context.Background()
+
+			characters.On("ListCharacters", ctx, discord.LiveLeaderboardSize).Return([]db.Character{}, nil)
+			repo.On("GetMessageID", ctx, "channel1").Return(tt.messageID, nil)
+			if tt.messageID != "" {
+				sender.On("EditMessage", ctx, "channel1", tt.messageID, mock.Anything).Return(discord.ErrMessageNotFound)
+			}
+			sender.On("PostMessage", ctx, "channel1", mock.MatchedBy(isLeaderboard)).Return("message2", nil)
+			repo.On("SetMessageID", ctx, "channel1", "message2").Return(nil)
+			// The leaderboard is still kept if it can't be pinned
+			sender.On("PinMessage", ctx, "channel1", "message2").Return(errors.New("missing permissions"))
+
+			require.NoError(t, service.Refresh(ctx, "channel1"))
+
+			repo.AssertExpectations(t)
+			sender.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Refresh_EditError(t *testing.T) {
+	service, characters, repo, sender := setupService()
+	ctx := context.Background()
+
+	characters.On("ListCharacters", ctx, discord.LiveLeaderboardSize).Return([]db.Character{}, nil)
+	repo.On("GetMessageID", ctx, "channel1").Return("message1", nil)
+	sender.On("EditMessage", ctx, "channel1", "message1", mock.Anything).Return(errors.New("discord error"))
+
+	err := service.Refresh(ctx, "channel1")
+
+	// Only a deleted message is replaced, otherwise the channel would fill with leaderboards
+	assert.ErrorContains(t, err, "discord error")
+	sender.AssertNotCalled(t, "PostMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_Refresh_ListError(t *testing.T) {
+	service, characters, _, _ := setupService()
+	ctx := context.Background()
+
+	characters.On("ListCharacters", ctx, discord.LiveLeaderboardSize).Return([]db.Character(nil), errors.New("db error"))
+
+	assert.ErrorContains(t, service.Refresh(ctx, "channel1"), "failed to list characters")
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -9,13 +11,25 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/DylanNZL/mythicplusbot/affixes"
+	"github.com/DylanNZL/mythicplusbot/backup"
 	"github.com/DylanNZL/mythicplusbot/blizzard"
 	"github.com/DylanNZL/mythicplusbot/bot"
 	"github.com/DylanNZL/mythicplusbot/config"
 	"github.com/DylanNZL/mythicplusbot/db"
 	"github.com/DylanNZL/mythicplusbot/discord"
+	"github.com/DylanNZL/mythicplusbot/guild"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/leaderboard"
+	"github.com/DylanNZL/mythicplusbot/milestone"
+	"github.com/DylanNZL/mythicplusbot/notify"
+	"github.com/DylanNZL/mythicplusbot/outbox"
 	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/DylanNZL/mythicplusbot/roster"
+	"github.com/DylanNZL/mythicplusbot/season"
+	"github.com/DylanNZL/mythicplusbot/threads"
 	"github.com/DylanNZL/mythicplusbot/updater"
+	"github.com/DylanNZL/mythicplusbot/vault"
 	"github.com/bwmarrin/discordgo"
 )
 
@@ -34,7 +48,16 @@ func main() {
 	ctx := context.Background()
 	cfg := config.Get()
 
-	database, err := db.NewSQLiteDB(cfg.DatabaseLocation)
+	// Restoring replaces the database file, so it has to run before the database is opened
+	if len(os.Args) > 1 && os.Args[1] == "restore" {
+		if err := runRestore(ctx, cfg, os.Args[2:]); err != nil {
+			slog.ErrorContext(ctx, "command failed", "command", os.Args[1], "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	database, err := openDatabase(ctx, cfg)
 	if err != nil {
 		slog.ErrorContext(ctx, "error creating database", "error", err)
 		panic(err)
@@ -47,11 +70,38 @@ func main() {
 	}
 
 	characterRepo := db.NewCharacterRepo(database)
+	historyRepo := db.NewScoreHistoryRepo(database)
+	rosterService := roster.NewService(characterRepo, historyRepo, &RosterStore{database: database})
+
+	// Snapshots use VACUUM INTO so are only available for SQLite, postgres should be backed up with pg_dump
+	var backupManager *backup.Manager
+	if _, ok := database.(*db.SQLiteDB); ok {
+		backupManager = backup.NewManager(database, cfg.BackupDirectory, backup.Retention{
+			Daily:  *cfg.BackupKeepDaily,
+			Weekly: *cfg.BackupKeepWeekly,
+		}, &backup.RealTimeProvider{})
+	}
 
 	httpClient := &http.Client{Timeout: defaultHTTPTimeout}
 	timeProvider := &blizzard.RealTimeProvider{}
 	blizzardClient := blizzard.NewClient(httpClient, timeProvider)
 	blizzardClient.SetCredentials(cfg.BlizzardClientID, cfg.BlizzardClientSecret)
+	blizzardClient.SetCache(createBlizzardCache(ctx, cfg.BlizzardCacheFile))
+	backfiller := season.NewBackfiller(characterRepo, db.NewSeasonRatingRepo(database), blizzardClient)
+
+	// Maintenance subcommands run instead of the bot
+	if len(os.Args) > 1 {
+		err := runCommand(ctx, rosterService, backupManager, backfiller, os.Args[1:])
+		blizzardClient.FlushCache(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "command failed", "command", os.Args[1], "error", err)
+			database.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	go blizzardClient.RenewTokens(ctx)
 	raiderIOClient := raiderio.NewClient(cfg.RaiderIOAccessKey, httpClient)
 
 	slog.DebugContext(ctx, "setting up discord")
@@ -63,16 +113,97 @@ func main() {
 
 	d.Identify.Intents = discordgo.MakeIntent(discordgo.IntentsGuildMessages)
 
-	messageSender := discord.NewDiscordSender(d)
+	templates, err := loadTemplates(cfg.Templates)
+	if err != nil {
+		slog.ErrorContext(ctx, "error loading templates", "error", err)
+		panic(err)
+	}
+
+	// Announcements aren't replies to a server, so they use the language chosen for the home server
+	localeService := &BotLocaleService{repo: db.NewGuildSettingsRepo(database)}
+	ctx = i18n.WithResolver(ctx, func(ctx context.Context) i18n.Locale {
+		l, err := localeService.GetLocale(ctx, cfg.DiscordGuildID)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to get locale", "error", err, "guild", cfg.DiscordGuildID)
+			return i18n.English
+		}
+		return l
+	})
+
+	webhooks, err := loadWebhooks(cfg.Webhooks)
+	if err != nil {
+		slog.ErrorContext(ctx, "error loading webhooks", "error", err)
+		panic(err)
+	}
+
+	threadMode, err := threads.ParseMode(cfg.Threads)
+	if err != nil {
+		slog.ErrorContext(ctx, "error loading config", "error", err)
+		panic(err)
+	}
+
+	// Channel messages go through the outbox so bursts of updates don't hit Discord's rate limits
+	discordSender := discord.NewDiscordSender(d)
+	threadRepo := db.NewThreadRepo(database)
+	messageSender := outbox.NewQueue(discord.NewWebhookSender(discordSender, d, webhooks, threadRepo),
+		db.NewOutboundMessageRepo(database), &outbox.RealTimeProvider{})
+
+	announcementChannelID := cfg.AnnouncementChannelID
+	if announcementChannelID == "" {
+		announcementChannelID = cfg.DiscordChannelID
+	}
+	vaultService := vault.NewService(characterRepo, blizzardClient, messageSender, &AnnouncementBuilder{},
+		&vault.RealTimeProvider{})
+	affixService := affixes.NewService(raiderIOClient, messageSender, discordSender,
+		db.NewGuildSettingsRepo(database), &affixes.RealTimeProvider{})
+	guildService := guild.NewService(cfg.GuildName, cfg.GuildRealm, characterRepo, db.NewGuildRankRepo(database),
+		raiderIOClient, messageSender, &AnnouncementBuilder{}, &guild.RealTimeProvider{})
+	linkRepo := db.NewLinkRepo(database)
+	milestoneService := milestone.NewService(milestones(cfg.Milestones), cfg.DiscordGuildID, linkRepo, messageSender)
+	threadService := threads.NewService(threadMode, threadRepo, linkRepo, discordSender)
+
+	// Score changes are sent in the background so slow endpoints don't hold up updates
+	notifier := notify.NewQueue(createNotifiers(cfg.NotifyWebhooks, httpClient))
+
+	updaterService := createUpdaterService(database, characterRepo, historyRepo, blizzardClient, raiderIOClient, messageSender,
+		templates, milestoneService, notifier, threadService)
+
+	characterService := &BotCharacterService{
+		database:      database,
+		repo:          characterRepo,
+		history:       historyRepo,
+		runs:          db.NewDungeonRunRepo(database),
+		characterRuns: db.NewCharacterRunRepo(database),
+		specScores:    db.NewSpecScoreRepo(database),
+		links:         linkRepo,
+		milestones:    milestoneService,
+		bClient:       blizzardClient,
+		rClient:       raiderIOClient,
+	}
+	// Live leaderboards are edited later, so are sent by the bot instead of through the outbox
+	leaderboardService := leaderboard.NewService(characterService, db.NewLiveLeaderboardRepo(database), discordSender,
+		&leaderboard.RealTimeProvider{})
 
 	// Create services with dependency injection
 	botService := bot.NewBot(
 		messageSender,
 		&BotUpdaterService{
-			updaterService: createUpdaterService(characterRepo, blizzardClient, raiderIOClient, messageSender),
-			channelID:      cfg.DiscordChannelID,
+			updaterService:        updaterService,
+			channelID:             announcementChannelID,
+			leaderboards:          leaderboardService,
+			leaderboardChannelIDs: cfg.LiveLeaderboardChannelIDs,
 		},
-		&BotCharacterService{repo: characterRepo, bClient: blizzardClient, rClient: raiderIOClient},
+		characterService,
+		rosterService,
+		vaultService,
+		&BotCutoffService{repo: characterRepo, rClient: raiderIOClient},
+		raiderIOClient,
+		guildService,
+		templates,
+		localeService,
+		cfg.AdminUserIDs,
+		time.Duration(cfg.LeaderboardExpiry)*time.Minute,
+		&bot.RealTimeProvider{},
 	)
 
 	// Add Discord message handler
@@ -87,31 +218,57 @@ func main() {
 			return
 		}
 
-		if err := botService.HandleMessage(ctx, m.Content, m.ChannelID); err != nil {
+		msg := bot.Message{Content: m.Content, ChannelID: m.ChannelID, AuthorID: m.Author.ID, GuildID: m.GuildID}
+		if err := botService.HandleMessage(ctx, msg); err != nil {
 			slog.ErrorContext(ctx, "failed to handle message", "error", err)
 		}
 	})
 
+	// Page the scores leaderboard when its buttons are pressed
+	d.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if i.ChannelID != cfg.DiscordChannelID {
+			return
+		}
+
+		if err := botService.HandleComponent(ctx, i.Interaction); err != nil {
+			slog.ErrorContext(ctx, "failed to handle interaction", "error", err)
+		}
+	})
+
 	slog.DebugContext(ctx, "opening discord session")
 	if err := d.Open(); err != nil {
 		slog.ErrorContext(ctx, "error opening discord session", "error", err)
 		panic(err)
 	}
 	slog.InfoContext(ctx, "listening for messages")
+	go messageSender.Run(ctx)
+	go notifier.Run(ctx)
 
-	updaterService := createUpdaterService(characterRepo, blizzardClient, raiderIOClient, messageSender)
 	ticker := time.NewTicker(time.Duration(cfg.UpdaterFrequency) * time.Minute)
 	go func() {
 		for range ticker.C {
-			if err := updaterService.Update(ctx, cfg.DiscordChannelID); err != nil {
+			if err := updaterService.Update(ctx, announcementChannelID); err != nil {
 				slog.ErrorContext(ctx, "updater failed", "error", err)
 			}
+			refreshLeaderboards(ctx, leaderboardService, cfg.LiveLeaderboardChannelIDs)
+			checkGuildRank(ctx, guildService, announcementChannelID)
 		}
 	}()
 
-	if err := updaterService.Update(ctx, cfg.DiscordChannelID); err != nil {
+	if err := updaterService.Update(ctx, announcementChannelID); err != nil {
 		panic(err)
 	}
+	refreshLeaderboards(ctx, leaderboardService, cfg.LiveLeaderboardChannelIDs)
+	checkGuildRank(ctx, guildService, announcementChannelID)
+
+	go affixService.RunWeeklyPosts(ctx, announcementChannelID)
+	if cfg.VaultReminderHours > 0 {
+		go vaultService.RunReminders(ctx, announcementChannelID, time.Duration(cfg.VaultReminderHours)*time.Hour)
+	}
+
+	if cfg.BackupDirectory != "" && backupManager != nil {
+		go backupManager.Run(ctx, time.Duration(cfg.BackupFrequency)*time.Minute)
+	}
 
 	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
@@ -123,14 +280,157 @@ func main() {
 	}
 
 	ticker.Stop()
+	// Responses cached by commands since the last update would otherwise be lost
+	blizzardClient.FlushCache(ctx)
+}
+
+// checkGuildRank announces when the home guild's rank has moved, if a home guild is configured.
+func checkGuildRank(ctx context.Context, guildService *guild.Service, channelID string) {
+	if err := guildService.CheckRank(ctx, channelID); err != nil && !errors.Is(err, guild.ErrNoGuild) {
+		slog.ErrorContext(ctx, "failed to check guild rank", "error", err)
+	}
+}
+
+func refreshLeaderboards(ctx context.Context, leaderboardService *leaderboard.Service, channelIDs []string) {
+	for _, channelID := range channelIDs {
+		if err := leaderboardService.Refresh(ctx, channelID); err != nil {
+			slog.ErrorContext(ctx, "failed to refresh live leaderboard", "error", err, "channel", channelID)
+		}
+	}
+}
+
+// loadTemplates reads the configured template files and checks they render, parts without a file use the default.
+func loadTemplates(files config.Templates) (*discord.Templates, error) {
+	var text discord.TemplateText
+	parts := []struct {
+		path string
+		text *string
+	}{
+		{files.Content, &text.Content},
+		{files.Title, &text.Title},
+		{files.Description, &text.Description},
+		{files.Footer, &text.Footer},
+	}
+	for _, p := range parts {
+		if p.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(p.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		*p.text = string(data)
+	}
+
+	return discord.ParseTemplates(text)
+}
+
+// milestones converts the configured milestones for the milestone service.
+func milestones(configured []config.Milestone) []milestone.Milestone {
+	converted := make([]milestone.Milestone, 0, len(configured))
+	for _, m := range configured {
+		converted = append(converted, milestone.Milestone{
+			Name:        m.Name,
+			Score:       m.Score,
+			TitleCutoff: m.TitleCutoff,
+			RoleID:      m.RoleID,
+		})
+	}
+	return converted
+}
+
+// loadWebhooks maps each configured channel to its webhook.
+func loadWebhooks(configured []config.Webhook) (map[string]discord.Webhook, error) {
+	webhooks := make(map[string]discord.Webhook, len(configured))
+	for _, w := range configured {
+		webhook, err := discord.ParseWebhookURL(w.URL)
+		if err != nil {
+			return nil, fmt.Errorf("webhook for channel %s: %w", w.ChannelID, err)
+		}
+		webhook.Username, webhook.AvatarURL = w.Username, w.AvatarURL
+		webhooks[w.ChannelID] = webhook
+	}
+	return webhooks, nil
 }
 
-func createUpdaterService(characterRepo *db.CharacterRepo, blizzardClient *blizzard.Client, raiderIOClient *raiderio.Client, messageSender discord.SenderIface) *updater.Service {
+// createNotifiers creates a notifier for each configured webhook.
+func createNotifiers(configured []config.NotifyWebhook, httpClient *http.Client) notify.Notifiers {
+	notifiers := make(notify.Notifiers, 0, len(configured))
+	for _, w := range configured {
+		notifiers = append(notifiers, notify.NewWebhookNotifier(httpClient, notify.Webhook{
+			URL:         w.URL,
+			Headers:     w.Headers,
+			Secret:      w.Secret,
+			MaxAttempts: w.MaxAttempts,
+		}, &notify.RealSleeper{}))
+	}
+	return notifiers
+}
+
+// storage is a database backend the bot can run against
+type storage interface {
+	db.Database
+	Init(ctx context.Context) error
+}
+
+// openDatabase opens the database for the configured driver. SQLite databases are integrity checked first so a
+// corrupt file is caught before the bot writes to it.
+func openDatabase(ctx context.Context, cfg config.Config) (storage, error) {
+	switch db.Dialect(cfg.DatabaseDriver) {
+	case db.DialectSQLite:
+		database, err := db.NewSQLiteDB(cfg.DatabaseLocation)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := database.CheckIntegrity(ctx); err != nil {
+			database.Close()
+			return nil, fmt.Errorf("database is corrupt, restore a backup with `mythicplusbot restore <backup>`: %w", err)
+		}
+		return database, nil
+	case db.DialectPostgres:
+		database, err := db.NewPostgresDB(cfg.DatabaseURL)
+		if err != nil {
+			return nil, err
+		}
+		return database, nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q (expected sqlite3 or postgres)", cfg.DatabaseDriver)
+	}
+}
+
+// createBlizzardCache returns a cache kept on disk at path, falling back to memory when there is no path or the
+// file can't be read.
+func createBlizzardCache(ctx context.Context, path string) blizzard.Cache {
+	if path == "" {
+		return blizzard.NewMemoryCache()
+	}
+
+	cache, err := blizzard.NewFileCache(path)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to load blizzard cache, starting with an empty one", "path", path, "error", err)
+		return blizzard.NewMemoryCache()
+	}
+	return cache
+}
+
+func createUpdaterService(database db.Database, characterRepo *db.CharacterRepo, historyRepo *db.ScoreHistoryRepo, blizzardClient *blizzard.Client, raiderIOClient *raiderio.Client, messageSender discord.SenderIface, templates *discord.Templates, milestoneService *milestone.Service, notifier updater.ScoreNotifier, threadService *threads.Service) *updater.Service {
 	return updater.NewService(
-		&UpdaterCharacterRepository{repo: characterRepo},
+		&UpdaterCharacterRepository{
+			database: database,
+			repo:     characterRepo,
+			history:  historyRepo,
+			runs:     db.NewDungeonRunRepo(database),
+			charRuns: db.NewCharacterRunRepo(database),
+			specs:    db.NewSpecScoreRepo(database),
+		},
 		&UpdaterBlizzardClient{client: blizzardClient},
 		&UpdaterRaiderIOClient{client: raiderIOClient},
 		messageSender,
+		templates,
+		milestoneService,
+		notifier,
+		threadService,
 		&updater.RealSleeper{},
 	)
 }
@@ -139,19 +439,32 @@ func createUpdaterService(characterRepo *db.CharacterRepo, blizzardClient *blizz
 
 // BotUpdaterService adapts the updater service for the bot
 type BotUpdaterService struct {
-	updaterService *updater.Service
-	channelID      string
+	updaterService        *updater.Service
+	channelID             string
+	leaderboards          *leaderboard.Service
+	leaderboardChannelIDs []string
 }
 
 func (b *BotUpdaterService) Update(ctx context.Context, channelID string) error {
 	// Use the configured channel ID for updates
-	return b.updaterService.Update(ctx, b.channelID)
+	if err := b.updaterService.Update(ctx, b.channelID); err != nil {
+		return err
+	}
+	refreshLeaderboards(ctx, b.leaderboards, b.leaderboardChannelIDs)
+	return nil
 }
 
 type BotCharacterService struct {
-	repo    *db.CharacterRepo
-	bClient *blizzard.Client
-	rClient *raiderio.Client
+	database      db.Database
+	repo          *db.CharacterRepo
+	history       *db.ScoreHistoryRepo
+	runs          *db.DungeonRunRepo
+	characterRuns *db.CharacterRunRepo
+	specScores    *db.SpecScoreRepo
+	links         *db.LinkRepo
+	milestones    *milestone.Service
+	bClient       *blizzard.Client
+	rClient       *raiderio.Client
 }
 
 func (b *BotCharacterService) AddCharacter(ctx context.Context, name, realm string) error {
@@ -182,7 +495,200 @@ func (b *BotCharacterService) AddCharacter(ctx context.Context, name, realm stri
 		DateCreated:  time.Now().Unix(),
 		DateUpdated:  time.Now().Unix(),
 	}
-	return b.repo.Insert(ctx, &character)
+
+	// The updater fills the profile in later if it can't be fetched now
+	if cProfile, err := b.bClient.GetCharacterProfile(ctx, realm, name); err != nil {
+		slog.WarnContext(ctx, "failed to get character profile", "error", err, "character", name, "realm", realm)
+	} else {
+		updater.ApplyProfile(&character, cProfile)
+	}
+
+	// The character is only tracked once all of their data is saved, so a failure can't leave them half added
+	return b.database.WithTx(ctx, func(tx db.Database) error {
+		if err := db.NewCharacterRepo(tx).Insert(ctx, &character); err != nil {
+			return err
+		}
+
+		// Record the starting point so score history covers the whole time the character is tracked
+		if err := db.NewScoreHistoryRepo(tx).Insert(ctx, &db.ScoreHistory{
+			CharacterID:  character.ID,
+			OverallScore: character.OverallScore,
+			TankScore:    character.TankScore,
+			DPSScore:     character.DPSScore,
+			HealScore:    character.HealScore,
+			DateRecorded: character.DateCreated,
+		}); err != nil {
+			return err
+		}
+
+		if err := db.NewCharacterRunRepo(tx).Replace(ctx, character.ID, updater.CharacterRuns(character.ID, rProfile)); err != nil {
+			return err
+		}
+
+		return db.NewSpecScoreRepo(tx).Replace(ctx, character.ID,
+			updater.SpecScores(character.ID, character.Class, current))
+	})
+}
+
+// GetProfile fetches the character's current profile and equipment, along with their score if they are tracked.
+func (b *BotCharacterService) GetProfile(ctx context.Context, name, realm string) (db.Character, blizzard.CharacterEquipment, error) {
+	cProfile, err := b.bClient.GetCharacterProfile(ctx, realm, name)
+	if err != nil {
+		return db.Character{}, blizzard.CharacterEquipment{}, err
+	}
+
+	equipment, err := b.bClient.GetCharacterEquipment(ctx, realm, name)
+	if err != nil {
+		return db.Character{}, blizzard.CharacterEquipment{}, err
+	}
+
+	character, err := b.repo.GetCharacter(ctx, name, realm)
+	if err != nil {
+		return db.Character{}, blizzard.CharacterEquipment{}, err
+	}
+
+	// Characters that aren't tracked only have what the profile tells us
+	if character.IsEmpty() {
+		character.Name = cProfile.Name
+		character.Realm = cProfile.Realm.Slug
+		character.Class = cProfile.ClassName()
+	}
+	updater.ApplyProfile(&character, cProfile)
+
+	return character, *equipment, nil
+}
+
+// GetDungeons fetches the character's results for the latest season they have played.
+func (b *BotCharacterService) GetDungeons(ctx context.Context, name, realm string) (*blizzard.MythicKeystoneSeason, error) {
+	profile, err := b.bClient.GetMythicKeystoneProfile(ctx, realm, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := profile.SeasonIDs()
+	if len(ids) == 0 {
+		// Characters that have never run a key have no seasons, show them with no runs
+		s := &blizzard.MythicKeystoneSeason{}
+		s.Character.Name = profile.Character.Name
+		s.Character.Realm = profile.Character.Realm
+		return s, nil
+	}
+
+	return b.bClient.GetMythicKeystoneSeason(ctx, realm, name, ids[len(ids)-1])
+}
+
+func (b *BotCharacterService) ListDungeons(ctx context.Context) ([]db.Dungeon, error) {
+	return b.runs.ListDungeons(ctx)
+}
+
+func (b *BotCharacterService) GetDungeonLeaderboard(ctx context.Context, dungeonID, limit int) ([]db.DungeonRunEntry, error) {
+	return b.runs.Leaderboard(ctx, dungeonID, limit)
+}
+
+func (b *BotCharacterService) GetRuns(ctx context.Context, name, realm string, kind db.RunKind) (db.Character, []db.CharacterRun, error) {
+	character, err := b.repo.GetCharacter(ctx, name, realm)
+	if err != nil || character.IsEmpty() {
+		return character, nil, err
+	}
+
+	runs, err := b.characterRuns.ListForCharacter(ctx, character.ID, kind)
+	if err != nil {
+		return db.Character{}, nil, err
+	}
+
+	if kind == db.RunKindBest {
+		alternates, err := b.characterRuns.ListForCharacter(ctx, character.ID, db.RunKindAlternate)
+		if err != nil {
+			return db.Character{}, nil, err
+		}
+		runs = append(runs, alternates...)
+	}
+
+	return character, runs, nil
+}
+
+func (b *BotCharacterService) LinkCharacter(ctx context.Context, name, realm, userID string) (db.Character, error) {
+	character, err := b.repo.GetCharacter(ctx, name, realm)
+	if err != nil || character.IsEmpty() {
+		return character, err
+	}
+
+	if err := b.links.Link(ctx, character.ID, userID); err != nil {
+		return db.Character{}, err
+	}
+
+	// The user gets the roles the character already qualifies for, rather than waiting for its next milestone
+	if err := b.milestones.SyncRoles(ctx, character, b.seasonCutoffs(ctx, character)); err != nil {
+		slog.WarnContext(ctx, "failed to sync milestone roles", "error", err, "character", name, "realm", realm)
+	}
+	return character, nil
+}
+
+func (b *BotCharacterService) UnlinkCharacter(ctx context.Context, name, realm, userID string, override bool,
+) (db.Character, error) {
+	character, err := b.repo.GetCharacter(ctx, name, realm)
+	if err != nil || character.IsEmpty() {
+		return character, err
+	}
+
+	linked, err := b.links.GetUserID(ctx, character.ID)
+	if err != nil {
+		return db.Character{}, err
+	}
+	if linked == "" {
+		return db.Character{}, db.ErrNotLinked
+	}
+	if linked != userID && !override {
+		return db.Character{}, db.ErrLinkedToOtherUser
+	}
+
+	if err := b.links.Unlink(ctx, character.ID); err != nil {
+		return db.Character{}, err
+	}
+
+	// The user loses the character's roles unless another of their characters has reached the same milestones
+	if err := b.milestones.SyncUserRoles(ctx, linked, b.seasonCutoffs(ctx, character)); err != nil {
+		slog.WarnContext(ctx, "failed to sync milestone roles", "error", err, "user", linked)
+	}
+	return character, nil
+}
+
+// seasonCutoffs returns the cutoffs for the character's current season, or nil if they can't be found.
+func (b *BotCharacterService) seasonCutoffs(ctx context.Context, character db.Character) *raiderio.Cutoffs {
+	rc, err := b.rClient.GetCharacter(ctx, character.Realm, character.Name)
+	if err != nil || len(rc.MythicPlusScoresBySeason) == 0 {
+		slog.WarnContext(ctx, "failed to get current season", "error", err, "character", character.Name)
+		return nil
+	}
+
+	cutoffs, err := b.rClient.GetSeasonCutoffs(ctx, rc.MythicPlusScoresBySeason[0].Season)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get season cutoffs", "error", err)
+		return nil
+	}
+	return cutoffs
+}
+
+func (b *BotCharacterService) GetComparison(ctx context.Context, name, realm string) (discord.Comparison, error) {
+	rc, err := b.rClient.GetCharacter(ctx, realm, name)
+	if err != nil {
+		return discord.Comparison{}, err
+	}
+
+	character, err := b.repo.GetCharacter(ctx, name, realm)
+	if err != nil {
+		return discord.Comparison{}, err
+	}
+	if character.IsEmpty() {
+		return discord.Comparison{Character: db.Character{Name: name, Realm: realm}, RaiderIO: *rc}, nil
+	}
+
+	// All of the history is needed as the score a week ago may have been recorded well before then
+	history, err := b.history.ListForCharacter(ctx, character.ID, 0)
+	if err != nil {
+		return discord.Comparison{}, err
+	}
+	return discord.Comparison{Character: character, RaiderIO: *rc, History: history}, nil
 }
 
 func (b *BotCharacterService) RemoveCharacter(ctx context.Context, name, realm string) error {
@@ -196,11 +702,105 @@ func (b *BotCharacterService) ListCharacters(ctx context.Context, limit int) ([]
 		return nil, err
 	}
 
+	specScores, err := b.specScores.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range characters {
+		characters[i].SpecScores = specScores[characters[i].ID]
+	}
+
 	return characters, nil
 }
 
+// BotCutoffService looks up Raider.IO season cutoffs for the bot
+type BotCutoffService struct {
+	repo    *db.CharacterRepo
+	rClient *raiderio.Client
+}
+
+// BotLocaleService stores the language each Discord server has chosen for the bot
+type BotLocaleService struct {
+	repo *db.GuildSettingsRepo
+}
+
+// GetLocale returns English for servers that haven't chosen a language, or chose one that is no longer supported.
+func (b *BotLocaleService) GetLocale(ctx context.Context, guildID string) (i18n.Locale, error) {
+	code, err := b.repo.GetLocale(ctx, guildID)
+	if err != nil || code == "" {
+		return i18n.English, err
+	}
+
+	l, err := i18n.Parse(code)
+	if err != nil {
+		slog.WarnContext(ctx, "unknown locale stored for guild", "guild", guildID, "locale", code)
+		return i18n.English, nil
+	}
+	return l, nil
+}
+
+func (b *BotLocaleService) SetLocale(ctx context.Context, guildID string, locale i18n.Locale) error {
+	return b.repo.SetLocale(ctx, guildID, string(locale))
+}
+
+// GetCutoffs uses the season the top tracked character is playing when no season is given.
+func (b *BotCutoffService) GetCutoffs(ctx context.Context, season string) (string, *raiderio.Cutoffs, error) {
+	if season == "" {
+		characters, err := b.repo.ListCharacters(ctx, 1)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(characters) == 0 {
+			return "", nil, errors.New("no characters are being tracked to find the current season from")
+		}
+
+		profile, err := b.rClient.GetCharacter(ctx, characters[0].Realm, characters[0].Name)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(profile.MythicPlusScoresBySeason) == 0 {
+			return "", nil, errors.New("no current season found on Raider.IO")
+		}
+		season = profile.MythicPlusScoresBySeason[0].Season
+	}
+
+	cutoffs, err := b.rClient.GetSeasonCutoffs(ctx, season)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return season, cutoffs, nil
+}
+
+// AnnouncementBuilder builds the vault and guild announcements, those packages can't import discord themselves
+type AnnouncementBuilder struct{}
+
+func (a *AnnouncementBuilder) BuildVaultReminderMessage(l i18n.Locale, reset time.Time, characters []string) string {
+	return discord.BuildVaultReminderMessage(l, reset, characters)
+}
+
+func (a *AnnouncementBuilder) BuildGuildRankMessage(l i18n.Locale, g raiderio.Guild, previousRealmRank int) string {
+	return discord.BuildGuildRankMessage(l, g, previousRealmRank)
+}
+
+// RosterStore gives roster imports repositories that write in a single transaction
+type RosterStore struct {
+	database db.Database
+}
+
+func (r *RosterStore) WithTx(ctx context.Context, fn func(characters roster.CharacterRepository, history roster.ScoreHistoryRepository) error) error {
+	return r.database.WithTx(ctx, func(tx db.Database) error {
+		return fn(db.NewCharacterRepo(tx), db.NewScoreHistoryRepo(tx))
+	})
+}
+
 type UpdaterCharacterRepository struct {
-	repo *db.CharacterRepo
+	database db.Database
+	repo     *db.CharacterRepo
+	history  *db.ScoreHistoryRepo
+	runs     *db.DungeonRunRepo
+	charRuns *db.CharacterRunRepo
+	specs    *db.SpecScoreRepo
 }
 
 func (u *UpdaterCharacterRepository) ListCharacters(ctx context.Context, limit int) ([]db.Character, error) {
@@ -211,6 +811,39 @@ func (u *UpdaterCharacterRepository) UpdateCharacter(ctx context.Context, charac
 	return u.repo.Update(ctx, character)
 }
 
+func (u *UpdaterCharacterRepository) AddScoreHistory(ctx context.Context, entry *db.ScoreHistory) error {
+	return u.history.Insert(ctx, entry)
+}
+
+func (u *UpdaterCharacterRepository) ListDungeonRuns(ctx context.Context, characterID, seasonID int) ([]db.DungeonRun, error) {
+	return u.runs.ListForCharacter(ctx, characterID, seasonID)
+}
+
+func (u *UpdaterCharacterRepository) UpsertDungeonRun(ctx context.Context, run *db.DungeonRun) error {
+	return u.runs.Upsert(ctx, run)
+}
+
+func (u *UpdaterCharacterRepository) ReplaceCharacterRuns(ctx context.Context, characterID int, runs []db.CharacterRun) error {
+	return u.charRuns.Replace(ctx, characterID, runs)
+}
+
+func (u *UpdaterCharacterRepository) ReplaceSpecScores(ctx context.Context, characterID int, scores []db.SpecScore) error {
+	return u.specs.Replace(ctx, characterID, scores)
+}
+
+func (u *UpdaterCharacterRepository) WithTx(ctx context.Context, fn func(repo updater.CharacterRepository) error) error {
+	return u.database.WithTx(ctx, func(tx db.Database) error {
+		return fn(&UpdaterCharacterRepository{
+			database: tx,
+			repo:     db.NewCharacterRepo(tx),
+			history:  db.NewScoreHistoryRepo(tx),
+			runs:     db.NewDungeonRunRepo(tx),
+			charRuns: db.NewCharacterRunRepo(tx),
+			specs:    db.NewSpecScoreRepo(tx),
+		})
+	})
+}
+
 type UpdaterBlizzardClient struct {
 	client *blizzard.Client
 }
@@ -219,6 +852,18 @@ func (u *UpdaterBlizzardClient) GetMythicKeystoneProfile(ctx context.Context, re
 	return u.client.GetMythicKeystoneProfile(ctx, realm, character)
 }
 
+func (u *UpdaterBlizzardClient) GetCharacterProfile(ctx context.Context, realm, character string) (*blizzard.CharacterProfile, error) {
+	return u.client.GetCharacterProfile(ctx, realm, character)
+}
+
+func (u *UpdaterBlizzardClient) GetMythicKeystoneSeason(ctx context.Context, realm, character string, seasonID int) (*blizzard.MythicKeystoneSeason, error) {
+	return u.client.GetMythicKeystoneSeason(ctx, realm, character, seasonID)
+}
+
+func (u *UpdaterBlizzardClient) FlushCache(ctx context.Context) {
+	u.client.FlushCache(ctx)
+}
+
 type UpdaterRaiderIOClient struct {
 	client *raiderio.Client
 }
@@ -226,3 +871,7 @@ type UpdaterRaiderIOClient struct {
 func (u *UpdaterRaiderIOClient) GetCharacter(ctx context.Context, realm, character string) (*raiderio.Character, error) {
 	return u.client.GetCharacter(ctx, realm, character)
 }
+
+func (u *UpdaterRaiderIOClient) GetSeasonCutoffs(ctx context.Context, season string) (*raiderio.Cutoffs, error) {
+	return u.client.GetSeasonCutoffs(ctx, season)
+}
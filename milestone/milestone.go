@@ -0,0 +1,170 @@
+// Package milestone announces characters passing score milestones and rewards their linked Discord users with roles.
+package milestone
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/discord"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/bwmarrin/discordgo"
+)
+
+type (
+	LinkRepository interface {
+		GetUserID(ctx context.Context, characterID int) (string, error)
+		ListCharacters(ctx context.Context, userID string) ([]db.Character, error)
+	}
+
+	MessageSender interface {
+		SendComplexMessage(ctx context.Context, channelID string, message discordgo.MessageSend) error
+		AddRole(ctx context.Context, guildID, userID, roleID string) error
+		RemoveRole(ctx context.Context, guildID, userID, roleID string) error
+	}
+
+	// Milestone is a score worth celebrating, TitleCutoff milestones use the season's title cutoff as their score.
+	Milestone struct {
+		Name        string
+		Score       float64
+		TitleCutoff bool
+		RoleID      string
+	}
+)
+
+// Service handles milestones with injected dependencies
+type Service struct {
+	milestones    []Milestone
+	guildID       string
+	linkRepo      LinkRepository
+	messageSender MessageSender
+}
+
+// NewService creates a new milestone service for the milestones with dependencies, roles are granted in guildID
+func NewService(milestones []Milestone, guildID string, linkRepo LinkRepository, messageSender MessageSender) *Service {
+	return &Service{
+		milestones:    milestones,
+		guildID:       guildID,
+		linkRepo:      linkRepo,
+		messageSender: messageSender,
+	}
+}
+
+// Check announces each milestone the character passed going from oldScore to their current score.
+//
+// If the character is linked to a Discord user, their roles are synced as in SyncRoles. Title cutoff milestones are skipped when cutoffs is nil.
+func (s *Service) Check(ctx context.Context, channelID string, character db.Character, oldScore float64, cutoffs *raiderio.Cutoffs) error {
+	var crossed bool
+	for _, m := range s.milestones {
+		threshold, ok := score(m, cutoffs)
+		if !ok || oldScore >= threshold || character.OverallScore < threshold {
+			continue
+		}
+
+		crossed = true
+		msg := discord.BuildMilestoneMessage(i18n.FromContext(ctx), character, m.Name, threshold)
+		if err := s.messageSender.SendComplexMessage(ctx, channelID, msg); err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+	}
+
+	if !crossed {
+		return nil
+	}
+
+	return s.SyncRoles(ctx, character, cutoffs)
+}
+
+// SyncRoles gives the character's linked Discord user the role of the highest milestone any of their linked characters
+// has reached, e.g. when the character has just been linked. Nothing is done if roles aren't granted in a guild.
+func (s *Service) SyncRoles(ctx context.Context, character db.Character, cutoffs *raiderio.Cutoffs) error {
+	if s.guildID == "" {
+		return nil
+	}
+
+	userID, err := s.linkRepo.GetUserID(ctx, character.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get linked user: %w", err)
+	}
+	if userID == "" {
+		return nil
+	}
+
+	return s.updateRoles(ctx, userID, &character, cutoffs)
+}
+
+// SyncUserRoles gives the Discord user the role of the highest milestone any of their linked characters has reached,
+// and takes the others away, e.g. when one of their characters has just been unlinked. Nothing is done if roles aren't
+// granted in a guild.
+func (s *Service) SyncUserRoles(ctx context.Context, userID string, cutoffs *raiderio.Cutoffs) error {
+	if s.guildID == "" {
+		return nil
+	}
+
+	return s.updateRoles(ctx, userID, nil, cutoffs)
+}
+
+// updateRoles syncs the user's roles with their best linked character. current is used in place of the stored
+// character with its ID, as its new score may not be saved yet.
+func (s *Service) updateRoles(ctx context.Context, userID string, current *db.Character, cutoffs *raiderio.Cutoffs) error {
+	characters, err := s.linkRepo.ListCharacters(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list linked characters: %w", err)
+	}
+
+	best := -1.0
+	if current != nil {
+		best = current.OverallScore
+	}
+	for _, c := range characters {
+		if (current == nil || c.ID != current.ID) && c.OverallScore > best {
+			best = c.OverallScore
+		}
+	}
+
+	// Find the highest milestone with a role the user's best character has reached
+	highest := -1
+	var highestScore float64
+	for i, m := range s.milestones {
+		threshold, ok := score(m, cutoffs)
+		if !ok || m.RoleID == "" || best < threshold {
+			continue
+		}
+		if highest == -1 || threshold > highestScore {
+			highest, highestScore = i, threshold
+		}
+	}
+
+	if highest != -1 {
+		if err := s.messageSender.AddRole(ctx, s.guildID, userID, s.milestones[highest].RoleID); err != nil {
+			return fmt.Errorf("failed to add role: %w", err)
+		}
+	}
+
+	// Every other role goes, including higher ones a character the user no longer has linked reached
+	for i, m := range s.milestones {
+		_, ok := score(m, cutoffs)
+		if i == highest || !ok || m.RoleID == "" || (highest != -1 && m.RoleID == s.milestones[highest].RoleID) {
+			continue
+		}
+		// A missing role shouldn't stop the others from being removed
+		if err := s.messageSender.RemoveRole(ctx, s.guildID, userID, m.RoleID); err != nil {
+			slog.WarnContext(ctx, "failed to remove role", "error", err, "role", m.RoleID, "user", userID)
+		}
+	}
+
+	return nil
+}
+
+// score returns the milestone's score, or false if it depends on cutoffs we don't have.
+func score(m Milestone, cutoffs *raiderio.Cutoffs) (float64, bool) {
+	if !m.TitleCutoff {
+		return m.Score, true
+	}
+	if cutoffs == nil || cutoffs.P999.All.QuantileMinValue == 0 {
+		return 0, false
+	}
+	return cutoffs.P999.All.QuantileMinValue, true
+}
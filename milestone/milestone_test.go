@@ -0,0 +1,259 @@
+package milestone
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mock implementations for testing
+
+type MockLinkRepository struct {
+	mock.Mock
+}
+
+func (m *MockLinkRepository) GetUserID(ctx context.Context, characterID int) (string, error) {
+	args := m.Called(ctx, characterID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockLinkRepository) ListCharacters(ctx context.Context, userID string) ([]db.Character, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]db.Character), args.Error(1)
+}
+
+type MockMessageSender struct {
+	mock.Mock
+}
+
+func (m *MockMessageSender) SendComplexMessage(ctx context.Context, channelID string, message discordgo.MessageSend) error {
+	args := m.Called(ctx, channelID, message)
+	return args.Error(0)
+}
+
+func (m *MockMessageSender) AddRole(ctx context.Context, guildID, userID, roleID string) error {
+	args := m.Called(ctx, guildID, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockMessageSender) RemoveRole(ctx context.Context, guildID, userID, roleID string) error {
+	args := m.Called(ctx, guildID, userID, roleID)
+	return args.Error(0)
+}
+
+// Test helpers
+
+var testMilestones = []Milestone{
+	{Name: "Keystone Master", Score: 2000, RoleID: "master"},
+	{Name: "Keystone Hero", Score: 2500, RoleID: "hero"},
+	{Name: "the title cutoff", TitleCutoff: true, RoleID: "title"},
+}
+
+func setupService() (*Service, *MockLinkRepository, *MockMessageSender) {
+	linkRepo := &MockLinkRepository{}
+	messageSender := &MockMessageSender{}
+	return NewService(testMilestones, "guild", linkRepo, messageSender), linkRepo, messageSender
+}
+
+func testCharacter(score float64) db.Character {
+	return db.Character{ID: 1, Name: "Tester", Realm: "tichondrius", Class: "Mage", OverallScore: score}
+}
+
+func testCutoffs(title float64) *raiderio.Cutoffs {
+	cutoffs := &raiderio.Cutoffs{}
+	cutoffs.P999.All.QuantileMinValue = title
+	return cutoffs
+}
+
+func isMilestone(name string) interface{} {
+	return mock.MatchedBy(func(msg discordgo.MessageSend) bool {
+		return len(msg.Embeds) == 1 && msg.Embeds[0].Title == "Tester-tichondrius reached "+name+"!"
+	})
+}
+
+// Tests
+
+func TestService_Check_NoMilestoneCrossed(t *testing.T) {
+	service, linkRepo, messageSender := setupService()
+	ctx := context.Background()
+
+	err := service.Check(ctx, "channel", testCharacter(2400), 2100, testCutoffs(3400))
+
+	assert.NoError(t, err)
+	linkRepo.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestService_Check_GrantsRoleAndRemovesLower(t *testing.T) {
+	service, linkRepo, messageSender := setupService()
+	ctx := context.Background()
+
+	messageSender.On("SendComplexMessage", ctx, "channel", isMilestone("Keystone Hero")).Return(nil)
+	linkRepo.On("GetUserID", ctx, 1).Return("user", nil)
+	// The stored character still has its old score
+	linkRepo.On("ListCharacters", ctx, "user").Return([]db.Character{testCharacter(2480)}, nil)
+	messageSender.On("AddRole", ctx, "guild", "user", "hero").Return(nil)
+	messageSender.On("RemoveRole", ctx, "guild", "user", "master").Return(nil)
+	messageSender.On("RemoveRole", ctx, "guild", "user", "title").Return(nil)
+
+	err := service.Check(ctx, "channel", testCharacter(2512.4), 2480, testCutoffs(3400))
+
+	assert.NoError(t, err)
+	linkRepo.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestService_Check_CrossesSeveralMilestones(t *testing.T) {
+	service, linkRepo, messageSender := setupService()
+	ctx := context.Background()
+
+	messageSender.On("SendComplexMessage", ctx, "channel", isMilestone("Keystone Hero")).Return(nil)
+	messageSender.On("SendComplexMessage", ctx, "channel", isMilestone("the title cutoff")).Return(nil)
+	linkRepo.On("GetUserID", ctx, 1).Return("user", nil)
+	linkRepo.On("ListCharacters", ctx, "user").Return([]db.Character{testCharacter(2400)}, nil)
+	messageSender.On("AddRole", ctx, "guild", "user", "title").Return(nil)
+	messageSender.On("RemoveRole", ctx, "guild", "user", "master").Return(nil)
+	messageSender.On("RemoveRole", ctx, "guild", "user", "hero").Return(nil)
+
+	err := service.Check(ctx, "channel", testCharacter(3410), 2400, testCutoffs(3400))
+
+	assert.NoError(t, err)
+	linkRepo.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestService_Check_SkipsTitleWithoutCutoffs(t *testing.T) {
+	service, linkRepo, messageSender := setupService()
+	ctx := context.Background()
+
+	err := service.Check(ctx, "channel", testCharacter(3410), 3000, nil)
+
+	assert.NoError(t, err)
+	linkRepo.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestService_Check_UnlinkedCharacter(t *testing.T) {
+	service, linkRepo, messageSender := setupService()
+	ctx := context.Background()
+
+	messageSender.On("SendComplexMessage", ctx, "channel", isMilestone("Keystone Master")).Return(nil)
+	linkRepo.On("GetUserID", ctx, 1).Return("", nil)
+
+	err := service.Check(ctx, "channel", testCharacter(2050), 1990, nil)
+
+	assert.NoError(t, err)
+	linkRepo.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestService_Check_SendError(t *testing.T) {
+	service, linkRepo, messageSender := setupService()
+	ctx := context.Background()
+
+	messageSender.On("SendComplexMessage", ctx, "channel", mock.Anything).Return(errors.New("discord error"))
+
+	err := service.Check(ctx, "channel", testCharacter(2050), 1990, nil)
+
+	assert.ErrorContains(t, err, "failed to send message")
+	linkRepo.AssertExpectations(t)
+}
+
+func TestService_Check_AddRoleError(t *testing.T) {
+	service, linkRepo, messageSender := setupService()
+	ctx := context.Background()
+
+	messageSender.On("SendComplexMessage", ctx, "channel", mock.Anything).Return(nil)
+	linkRepo.On("GetUserID", ctx, 1).Return("user", nil)
+	linkRepo.On("ListCharacters", ctx, "user").Return([]db.Character{testCharacter(1990)}, nil)
+	messageSender.On("AddRole", ctx, "guild", "user", "master").Return(errors.New("missing permissions"))
+
+	err := service.Check(ctx, "channel", testCharacter(2050), 1990, nil)
+
+	assert.ErrorContains(t, err, "failed to add role")
+}
+
+func TestService_SyncRoles(t *testing.T) {
+	service, linkRepo, messageSender := setupService()
+	ctx := context.Background()
+
+	// Nothing is announced, the user just gets the role for the score the character already has
+	linkRepo.On("GetUserID", ctx, 1).Return("user", nil)
+	linkRepo.On("ListCharacters", ctx, "user").Return([]db.Character{testCharacter(3450)}, nil)
+	messageSender.On("AddRole", ctx, "guild", "user", "title").Return(nil)
+	messageSender.On("RemoveRole", ctx, "guild", "user", "master").Return(nil)
+	messageSender.On("RemoveRole", ctx, "guild", "user", "hero").Return(nil)
+
+	err := service.SyncRoles(ctx, testCharacter(3450), testCutoffs(3400))
+
+	assert.NoError(t, err)
+	linkRepo.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+	messageSender.AssertNotCalled(t, "SendComplexMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_Check_AltBelowMain(t *testing.T) {
+	service, linkRepo, messageSender := setupService()
+	ctx := context.Background()
+
+	// The alt reaching Keystone Master doesn't cost the user the hero role their main has
+	messageSender.On("SendComplexMessage", ctx, "channel", isMilestone("Keystone Master")).Return(nil)
+	linkRepo.On("GetUserID", ctx, 1).Return("user", nil)
+	linkRepo.On("ListCharacters", ctx, "user").Return([]db.Character{
+		testCharacter(1990), {ID: 2, Name: "Main", Realm: "tichondrius", OverallScore: 2600},
+	}, nil)
+	messageSender.On("AddRole", ctx, "guild", "user", "hero").Return(nil)
+	messageSender.On("RemoveRole", ctx, "guild", "user", "master").Return(nil)
+
+	err := service.Check(ctx, "channel", testCharacter(2050), 1990, nil)
+
+	assert.NoError(t, err)
+	linkRepo.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestService_SyncUserRoles(t *testing.T) {
+	service, linkRepo, messageSender := setupService()
+	ctx := context.Background()
+
+	// Their only character above a milestone was unlinked, so every role goes
+	linkRepo.On("ListCharacters", ctx, "user").Return([]db.Character{testCharacter(1500)}, nil)
+	messageSender.On("RemoveRole", ctx, "guild", "user", "master").Return(nil)
+	messageSender.On("RemoveRole", ctx, "guild", "user", "hero").Return(nil)
+	messageSender.On("RemoveRole", ctx, "guild", "user", "title").Return(nil)
+
+	err := service.SyncUserRoles(ctx, "user", testCutoffs(3400))
+
+	assert.NoError(t, err)
+	linkRepo.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+	messageSender.AssertNotCalled(t, "AddRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_SyncRoles_ListError(t *testing.T) {
+	service, linkRepo, _ := setupService()
+	ctx := context.Background()
+
+	linkRepo.On("GetUserID", ctx, 1).Return("user", nil)
+	linkRepo.On("ListCharacters", ctx, "user").Return([]db.Character(nil), errors.New("database error"))
+
+	err := service.SyncRoles(ctx, testCharacter(2600), nil)
+
+	assert.ErrorContains(t, err, "failed to list linked characters")
+}
+
+func TestService_SyncRoles_NoGuild(t *testing.T) {
+	linkRepo := &MockLinkRepository{}
+	service := NewService(testMilestones, "", linkRepo, &MockMessageSender{})
+
+	err := service.SyncRoles(context.Background(), testCharacter(2600), nil)
+
+	assert.NoError(t, err)
+	linkRepo.AssertNotCalled(t, "GetUserID", mock.Anything, mock.Anything)
+}
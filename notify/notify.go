@@ -0,0 +1,85 @@
+// Package notify tells integrations outside Discord about score changes, e.g. Slack or Matrix through a bridge.
+//
+// Events are structured so subscribers don't need to know anything about the bot to use them.
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+)
+
+// EventScoreChange is the type of a ScoreChange event.
+const EventScoreChange = "score_change"
+
+type (
+	// Notifier is told about each score change, e.g. a WebhookNotifier.
+	Notifier interface {
+		NotifyScoreChange(ctx context.Context, change ScoreChange) error
+	}
+
+	// ScoreChange is sent when a character's overall score changes.
+	ScoreChange struct {
+		Event     string    `json:"event"`
+		Timestamp time.Time `json:"timestamp"`
+		Character Character `json:"character"`
+		OldScore  float64   `json:"old_score"`
+		NewScore  float64   `json:"new_score"`
+		Scores    Scores    `json:"scores"`
+	}
+
+	Character struct {
+		Name         string `json:"name"`
+		Realm        string `json:"realm"`
+		Class        string `json:"class"`
+		Spec         string `json:"spec,omitempty"`
+		ProfileURL   string `json:"profile_url,omitempty"`
+		ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	}
+
+	// Scores are the character's new score for each role.
+	Scores struct {
+		Tank   float64 `json:"tank"`
+		Healer float64 `json:"healer"`
+		DPS    float64 `json:"dps"`
+	}
+)
+
+// NewScoreChange creates the event for a character whose score was oldScore, character holds the new scores.
+func NewScoreChange(character db.Character, rc raiderio.Character, oldScore float64, at time.Time) ScoreChange {
+	return ScoreChange{
+		Event:     EventScoreChange,
+		Timestamp: at.UTC(),
+		Character: Character{
+			Name:         character.Name,
+			Realm:        character.Realm,
+			Class:        character.Class,
+			Spec:         character.Spec,
+			ProfileURL:   rc.ProfileUrl,
+			ThumbnailURL: rc.ThumbnailUrl,
+		},
+		OldScore: oldScore,
+		NewScore: character.OverallScore,
+		Scores: Scores{
+			Tank:   character.TankScore,
+			Healer: character.HealScore,
+			DPS:    character.DPSScore,
+		},
+	}
+}
+
+// Notifiers tells each of its notifiers, one failing doesn't stop the others being told.
+type Notifiers []Notifier
+
+func (n Notifiers) NotifyScoreChange(ctx context.Context, change ScoreChange) error {
+	var errs []error
+	for _, notifier := range n {
+		if err := notifier.NotifyScoreChange(ctx, change); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/raiderio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) NotifyScoreChange(ctx context.Context, change ScoreChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+var testNow = time.Date(2024, time.September, 17, 15, 0, 0, 0, time.UTC)
+
+func testScoreChange() ScoreChange {
+	character := db.Character{
+		Name: "Testchar", Realm: "testrealm", Class: "Mage", Spec: "Frost",
+		OverallScore: 2600, TankScore: 0, HealScore: 0, DPSScore: 2600,
+	}
+	var rc raiderio.Character
+	rc.ProfileUrl = "https://raider.io/characters/us/testrealm/Testchar"
+	rc.ThumbnailUrl = "https://render.worldofwarcraft.com/testchar.jpg"
+
+	return NewScoreChange(character, rc, 2450, testNow)
+}
+
+func TestNewScoreChange_JSON(t *testing.T) {
+	body, err := json.Marshal(testScoreChange())
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"event": "score_change",
+		"timestamp": "2024-09-17T15:00:00Z",
+		"character": {
+			"name": "Testchar",
+			"realm": "testrealm",
+			"class": "Mage",
+			"spec": "Frost",
+			"profile_url": "https://raider.io/characters/us/testrealm/Testchar",
+			"thumbnail_url": "https://render.worldofwarcraft.com/testchar.jpg"
+		},
+		"old_score": 2450,
+		"new_score": 2600,
+		"scores": {"tank": 0, "healer": 0, "dps": 2600}
+	}`, string(body))
+}
+
+func TestNotifiers_NotifyScoreChange(t *testing.T) {
+	ctx := context.Background()
+	change := testScoreChange()
+	failing, working := &MockNotifier{}, &MockNotifier{}
+	failing.On("NotifyScoreChange", ctx, change).Return(errors.New("slack is down"))
+	working.On("NotifyScoreChange", ctx, change).Return(nil)
+
+	err := Notifiers{failing, working}.NotifyScoreChange(ctx, change)
+
+	// One failing doesn't stop the others
+	assert.ErrorContains(t, err, "slack is down")
+	failing.AssertExpectations(t)
+	working.AssertExpectations(t)
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+const (
+	// queueSize is how many score changes can wait to be sent before new ones are dropped
+	queueSize = 100
+	// eventTimeout is how long a score change has to be sent, including retries
+	eventTimeout = time.Minute
+)
+
+// ErrQueueFull is returned when a score change is dropped because too many are waiting to be sent.
+var ErrQueueFull = errors.New("too many score changes waiting to be sent")
+
+// Queue tells its notifier about score changes in the background, so slow endpoints don't hold up the updater.
+type Queue struct {
+	notifier Notifier
+	changes  chan ScoreChange
+}
+
+// NewQueue creates a queue for the notifier, Run sends the changes queued on it.
+func NewQueue(notifier Notifier) *Queue {
+	return &Queue{notifier: notifier, changes: make(chan ScoreChange, queueSize)}
+}
+
+// NotifyScoreChange queues the change to be sent, returning ErrQueueFull if it can't be.
+func (q *Queue) NotifyScoreChange(_ context.Context, change ScoreChange) error {
+	select {
+	case q.changes <- change:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Run sends queued score changes one at a time until ctx is done.
+func (q *Queue) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case change := <-q.changes:
+			q.send(ctx, change)
+		}
+	}
+}
+
+func (q *Queue) send(ctx context.Context, change ScoreChange) {
+	ctx, cancel := context.WithTimeout(ctx, eventTimeout)
+	defer cancel()
+
+	if err := q.notifier.NotifyScoreChange(ctx, change); err != nil {
+		slog.WarnContext(ctx, "failed to notify score change", "error", err,
+			"character", change.Character.Name, "realm", change.Character.Realm)
+	}
+}
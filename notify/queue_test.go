@@ -0,0 +1,77 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue_NotifyScoreChange(t *testing.T) {
+	notifier := &MockNotifier{}
+	queue := NewQueue(notifier)
+	change := testScoreChange()
+
+	sent := make(chan struct{})
+	notifier.On("NotifyScoreChange", mock.Anything, change).Return(nil).Run(func(mock.Arguments) {
+		close(sent)
+	})
+
+	// Queueing doesn't wait for the change to be sent
+	require.NoError(t, queue.NotifyScoreChange(context.Background(), change))
+	notifier.AssertNotCalled(t, "NotifyScoreChange", mock.Anything, mock.Anything)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("score change wasn't sent")
+	}
+	notifier.AssertExpectations(t)
+}
+
+func TestQueue_NotifyScoreChange_Full(t *testing.T) {
+	queue := NewQueue(&MockNotifier{})
+
+	for range queueSize {
+		require.NoError(t, queue.NotifyScoreChange(context.Background(), testScoreChange()))
+	}
+	err := queue.NotifyScoreChange(context.Background(), testScoreChange())
+	assert.ErrorIs(t, err, ErrQueueFull)
+}
+
+func TestQueue_Run_Deadline(t *testing.T) {
+	notifier := &MockNotifier{}
+	queue := NewQueue(notifier)
+
+	// Each change is sent with a deadline, a failure is logged and the next one is still sent
+	done := make(chan struct{})
+	notifier.On("NotifyScoreChange", mock.MatchedBy(func(ctx context.Context) bool {
+		_, ok := ctx.Deadline()
+		return ok
+	}), mock.Anything).Return(errors.New("endpoint down")).Once()
+	notifier.On("NotifyScoreChange", mock.Anything, mock.Anything).Return(nil).Once().Run(func(mock.Arguments) {
+		close(done)
+	})
+
+	require.NoError(t, queue.NotifyScoreChange(context.Background(), testScoreChange()))
+	require.NoError(t, queue.NotifyScoreChange(context.Background(), testScoreChange()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("second score change wasn't sent")
+	}
+	notifier.AssertExpectations(t)
+}
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// SignatureHeader holds the hex HMAC-SHA256 of the body, prefixed with sha256=, when a secret is configured
+	SignatureHeader = "X-Mythicplusbot-Signature-256"
+	EventHeader     = "X-Mythicplusbot-Event"
+
+	defaultMaxAttempts = 3
+	// retryDelay is doubled after each failed attempt
+	retryDelay = time.Second
+)
+
+type (
+	HTTPClient interface {
+		Do(req *http.Request) (*http.Response, error)
+	}
+
+	Sleeper interface {
+		Sleep(duration time.Duration)
+	}
+)
+
+type RealSleeper struct{}
+
+func (r *RealSleeper) Sleep(duration time.Duration) {
+	time.Sleep(duration)
+}
+
+// Webhook is an endpoint events are POSTed to as JSON.
+type Webhook struct {
+	URL string
+	// Headers are added to each request, e.g. for authentication
+	Headers map[string]string
+	// Secret signs each request so the endpoint can check it came from the bot, leave empty to not sign
+	Secret string
+	// MaxAttempts is how many times to try sending an event, 0 uses the default
+	MaxAttempts int
+}
+
+// WebhookNotifier sends events to a generic JSON webhook.
+type WebhookNotifier struct {
+	client  HTTPClient
+	webhook Webhook
+	sleeper Sleeper
+}
+
+// NewWebhookNotifier creates a notifier that sends events to webhook.
+func NewWebhookNotifier(client HTTPClient, webhook Webhook, sleeper Sleeper) *WebhookNotifier {
+	if webhook.MaxAttempts <= 0 {
+		webhook.MaxAttempts = defaultMaxAttempts
+	}
+	return &WebhookNotifier{client: client, webhook: webhook, sleeper: sleeper}
+}
+
+// NotifyScoreChange sends the event, retrying with a backoff if the endpoint can't be reached or has a server error.
+func (w *WebhookNotifier) NotifyScoreChange(ctx context.Context, change ScoreChange) error {
+	body, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	for attempt := range w.webhook.MaxAttempts {
+		if attempt > 0 {
+			w.sleeper.Sleep(retryDelay << (attempt - 1))
+		}
+
+		var retry bool
+		if retry, err = w.send(ctx, change.Event, body); err == nil || !retry {
+			return err
+		}
+		slog.WarnContext(ctx, "failed to send webhook", "error", err, "attempt", attempt+1)
+	}
+	return err
+}
+
+// send makes one attempt at sending the event, returning whether a failure is worth retrying.
+func (w *WebhookNotifier) send(ctx context.Context, event string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	for name, value := range w.webhook.Headers {
+		req.Header.Set(name, value)
+	}
+	if w.webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.webhook.Secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Other client errors won't be fixed by sending the same request again
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// Sign returns the signature header value for body, endpoints can compare it with hmac.Equal to check the request.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockHTTPClient struct {
+	mock.Mock
+}
+
+func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	args := m.Called(req)
+	if resp, ok := args.Get(0).(*http.Response); ok {
+		return resp, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockSleeper struct {
+	mock.Mock
+}
+
+func (m *MockSleeper) Sleep(duration time.Duration) {
+	m.Called(duration)
+}
+
+func createHTTPResponse(statusCode int) *http.Response {
+	return &http.Response{StatusCode: statusCode, Body: io.NopCloser(strings.NewReader(""))}
+}
+
+func TestWebhookNotifier_NotifyScoreChange(t *testing.T) {
+	client := &MockHTTPClient{}
+	notifier := NewWebhookNotifier(client, Webhook{
+		URL:     "https://example.com/events",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		Secret:  "shh",
+	}, &MockSleeper{})
+
+	var body []byte
+	client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		// The matcher can run more than once, so the body is read from a copy
+		r, err := req.GetBody()
+		if err != nil {
+			return false
+		}
+		body, _ = io.ReadAll(r)
+		return req.Method == http.MethodPost && req.URL.String() == "https://example.com/events" &&
+			req.Header.Get("Content-Type") == "application/json" &&
+			req.Header.Get("Authorization") == "Bearer token" &&
+			req.Header.Get(EventHeader) == EventScoreChange &&
+			hmac.Equal([]byte(req.Header.Get(SignatureHeader)), []byte(Sign("shh", body)))
+	})).Return(createHTTPResponse(http.StatusNoContent), nil)
+
+	err := notifier.NotifyScoreChange(context.Background(), testScoreChange())
+
+	require.NoError(t, err)
+	client.AssertExpectations(t)
+	assert.Contains(t, string(body), `"new_score":2600`)
+}
+
+func TestWebhookNotifier_Unsigned(t *testing.T) {
+	client := &MockHTTPClient{}
+	notifier := NewWebhookNotifier(client, Webhook{URL: "https://example.com/events"}, &MockSleeper{})
+
+	client.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get(SignatureHeader) == ""
+	})).Return(createHTTPResponse(http.StatusOK), nil)
+
+	require.NoError(t, notifier.NotifyScoreChange(context.Background(), testScoreChange()))
+	client.AssertExpectations(t)
+}
+
+func TestWebhookNotifier_Retries(t *testing.T) {
+	client := &MockHTTPClient{}
+	sleeper := &MockSleeper{}
+	notifier := NewWebhookNotifier(client, Webhook{URL: "https://example.com/events"}, sleeper)
+
+	client.On("Do", mock.Anything).Return(nil, errors.New("connection refused")).Once()
+	client.On("Do", mock.Anything).Return(createHTTPResponse(http.StatusBadGateway), nil).Once()
+	client.On("Do", mock.Anything).Return(createHTTPResponse(http.StatusOK), nil).Once()
+	// The delay doubles after each attempt
+	sleeper.On("Sleep", time.Second).Once()
+	sleeper.On("Sleep", 2*time.Second).Once()
+
+	require.NoError(t, notifier.NotifyScoreChange(context.Background(), testScoreChange()))
+	client.AssertExpectations(t)
+	sleeper.AssertExpectations(t)
+}
+
+func TestWebhookNotifier_GivesUp(t *testing.T) {
+	client := &MockHTTPClient{}
+	sleeper := &MockSleeper{}
+	notifier := NewWebhookNotifier(client, Webhook{URL: "https://example.com/events", MaxAttempts: 2}, sleeper)
+
+	client.On("Do", mock.Anything).Return(createHTTPResponse(http.StatusServiceUnavailable), nil)
+	sleeper.On("Sleep", mock.Anything)
+
+	err := notifier.NotifyScoreChange(context.Background(), testScoreChange())
+
+	assert.ErrorContains(t, err, "status 503")
+	client.AssertNumberOfCalls(t, "Do", 2)
+}
+
+func TestWebhookNotifier_ClientErrorNotRetried(t *testing.T) {
+	client := &MockHTTPClient{}
+	sleeper := &MockSleeper{}
+	notifier := NewWebhookNotifier(client, Webhook{URL: "https://example.com/events"}, sleeper)
+
+	client.On("Do", mock.Anything).Return(createHTTPResponse(http.StatusUnauthorized), nil)
+
+	err := notifier.NotifyScoreChange(context.Background(), testScoreChange())
+
+	assert.ErrorContains(t, err, "status 401")
+	client.AssertNumberOfCalls(t, "Do", 1)
+	sleeper.AssertNotCalled(t, "Sleep", mock.Anything)
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{}' | openssl dgst -sha256 -hmac shh
+	assert.Equal(t, "sha256=9b7038c05edccf643d722b52dbaf2cea2b159caf339a5e12c0356e0b8b7b0794", Sign("shh", []byte("{}")))
+}
@@ -0,0 +1,350 @@
+// Package outbox queues messages to Discord so bursts of announcements are sent at a pace Discord's rate limits
+// allow, and aren't lost if the bot restarts before they are sent.
+//
+// Queued messages are stored in the database and sent by Run in the order they were queued. Each channel is sent at
+// most one message a second, and messages waiting for the same channel are combined into as few as possible. A
+// failed send is retried with a backoff and dropped after a few attempts.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/discord"
+	"github.com/bwmarrin/discordgo"
+)
+
+type (
+	Repository interface {
+		Insert(ctx context.Context, message *db.OutboundMessage) error
+		List(ctx context.Context) ([]db.OutboundMessage, error)
+		Delete(ctx context.Context, id int64) error
+		Retry(ctx context.Context, id int64, attempts int, nextAttempt int64) error
+	}
+
+	TimeProvider interface {
+		Now() time.Time
+	}
+
+	// storedMessage is how a message is stored, components are interfaces so are kept as raw JSON.
+	storedMessage struct {
+		Content    string                    `json:"content,omitempty"`
+		Embeds     []*discordgo.MessageEmbed `json:"embeds,omitempty"`
+		Components []json.RawMessage         `json:"components,omitempty"`
+	}
+)
+
+const (
+	// channelInterval keeps each channel under Discord's limit of 5 messages every 5 seconds
+	channelInterval = time.Second
+	pollInterval    = time.Second
+	// retryDelay is doubled after each failed attempt
+	retryDelay  = 5 * time.Second
+	maxAttempts = 5
+
+	maxContentChars = 2000
+	maxEmbeds       = 10
+	maxEmbedChars   = 6000
+)
+
+type RealTimeProvider struct{}
+
+func (r *RealTimeProvider) Now() time.Time {
+	return time.Now()
+}
+
+// Queue sends channel messages through the outbox, everything else goes straight to the sender.
+type Queue struct {
+	sender       discord.SenderIface
+	repo         Repository
+	timeProvider TimeProvider
+	wake         chan struct{}
+	// lastSent is when a message was last sent to each channel, it is only used by Flush
+	lastSent map[string]time.Time
+}
+
+var _ discord.SenderIface = (*Queue)(nil)
+
+// NewQueue creates a queue that sends messages with sender.
+func NewQueue(sender discord.SenderIface, repo Repository, timeProvider TimeProvider) *Queue {
+	return &Queue{
+		sender:       sender,
+		repo:         repo,
+		timeProvider: timeProvider,
+		wake:         make(chan struct{}, 1),
+		lastSent:     make(map[string]time.Time),
+	}
+}
+
+func (q *Queue) SendMessage(ctx context.Context, channelID, content string) error {
+	return q.SendComplexMessage(ctx, channelID, discordgo.MessageSend{Content: content})
+}
+
+// SendComplexMessage queues the message, it only fails if the message couldn't be stored.
+//
+// Files can't be stored, so messages with them are sent straight away.
+func (q *Queue) SendComplexMessage(ctx context.Context, channelID string, message discordgo.MessageSend) error {
+	if len(message.Files) > 0 || message.File != nil {
+		return q.sender.SendComplexMessage(ctx, channelID, message)
+	}
+
+	payload, err := encode(message)
+	if err != nil {
+		return err
+	}
+
+	now := q.timeProvider.Now().Unix()
+	if err := q.repo.Insert(ctx, &db.OutboundMessage{
+		ChannelID:   channelID,
+		Payload:     payload,
+		NextAttempt: now,
+		DateCreated: now,
+	}); err != nil {
+		return fmt.Errorf("failed to queue message: %w", err)
+	}
+
+	// Don't block if Run has already been woken
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// AddRole isn't a channel message, so is sent straight away.
+func (q *Queue) AddRole(ctx context.Context, guildID, userID, roleID string) error {
+	return q.sender.AddRole(ctx, guildID, userID, roleID)
+}
+
+// RemoveRole isn't a channel message, so is sent straight away.
+func (q *Queue) RemoveRole(ctx context.Context, guildID, userID, roleID string) error {
+	return q.sender.RemoveRole(ctx, guildID, userID, roleID)
+}
+
+// UpdateInteractionMessage is sent straight away, Discord needs interactions answered within 3 seconds.
+func (q *Queue) UpdateInteractionMessage(ctx context.Context, interaction *discordgo.Interaction, message discordgo.MessageSend) error {
+	return q.sender.UpdateInteractionMessage(ctx, interaction, message)
+}
+
+// RespondEphemeral is sent straight away, Discord needs interactions answered within 3 seconds.
+func (q *Queue) RespondEphemeral(ctx context.Context, interaction *discordgo.Interaction, content string) error {
+	return q.sender.RespondEphemeral(ctx, interaction, content)
+}
+
+// Run sends queued messages until the context is cancelled, starting with any left over from before a restart.
+func (q *Queue) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := q.Flush(ctx); err != nil {
+			slog.ErrorContext(ctx, "failed to flush outbox", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// Flush sends each channel the messages that are due, combined into one where they fit.
+//
+// It must not be called concurrently, Run calls it whenever a message is queued and every second.
+func (q *Queue) Flush(ctx context.Context) error {
+	messages, err := q.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list queued messages: %w", err)
+	}
+
+	now := q.timeProvider.Now()
+	channels, pending := groupByChannel(messages)
+	for _, channelID := range channels {
+		if now.Before(q.lastSent[channelID].Add(channelInterval)) {
+			continue
+		}
+		// Messages are sent in order, so the channel waits while its oldest message is waiting to be retried
+		if pending[channelID][0].NextAttempt > now.Unix() {
+			continue
+		}
+
+		batch, message := q.coalesce(ctx, pending[channelID], now)
+		if len(batch) == 0 {
+			continue
+		}
+
+		q.lastSent[channelID] = now
+		if err := q.sender.SendComplexMessage(ctx, channelID, message); err != nil {
+			slog.WarnContext(ctx, "failed to send queued message", "error", err, "channel", channelID)
+			q.retry(ctx, batch, err, now)
+			continue
+		}
+
+		q.delete(ctx, batch)
+	}
+
+	return nil
+}
+
+// groupByChannel splits the messages up by channel, keeping the order they were queued in.
+func groupByChannel(messages []db.OutboundMessage) ([]string, map[string][]db.OutboundMessage) {
+	var channels []string
+	pending := make(map[string][]db.OutboundMessage)
+	for _, m := range messages {
+		if _, ok := pending[m.ChannelID]; !ok {
+			channels = append(channels, m.ChannelID)
+		}
+		pending[m.ChannelID] = append(pending[m.ChannelID], m)
+	}
+	return channels, pending
+}
+
+// coalesce combines the oldest due messages into one message, returning the messages it is made from.
+//
+// Messages with components aren't combined, as the components belong to their message. Messages that can't be
+// decoded are dropped.
+func (q *Queue) coalesce(ctx context.Context, pending []db.OutboundMessage, now time.Time) ([]db.OutboundMessage, discordgo.MessageSend) {
+	var (
+		batch    []db.OutboundMessage
+		combined discordgo.MessageSend
+	)
+	for _, m := range pending {
+		if m.NextAttempt > now.Unix() {
+			break
+		}
+
+		message, err := decode(m.Payload)
+		if err != nil {
+			slog.ErrorContext(ctx, "dropping queued message that can't be decoded", "error", err, "id", m.ID)
+			q.delete(ctx, []db.OutboundMessage{m})
+			continue
+		}
+
+		if len(batch) == 0 {
+			batch, combined = append(batch, m), message
+			if len(message.Components) > 0 {
+				break
+			}
+			continue
+		}
+		if !fits(combined, message) {
+			break
+		}
+
+		batch = append(batch, m)
+		combined.Content = joinContent(combined.Content, message.Content)
+		combined.Embeds = append(combined.Embeds, message.Embeds...)
+	}
+
+	return batch, combined
+}
+
+// fits reports whether next can be added to message without going over Discord's limits.
+func fits(message, next discordgo.MessageSend) bool {
+	return len(next.Components) == 0 &&
+		len(joinContent(message.Content, next.Content)) <= maxContentChars &&
+		len(message.Embeds)+len(next.Embeds) <= maxEmbeds &&
+		embedChars(message.Embeds)+embedChars(next.Embeds) <= maxEmbedChars
+}
+
+func joinContent(a, b string) string {
+	if a == "" || b == "" {
+		return a + b
+	}
+	return a + "\n" + b
+}
+
+// embedChars counts the characters Discord limits across a message's embeds.
+func embedChars(embeds []*discordgo.MessageEmbed) int {
+	n := 0
+	for _, e := range embeds {
+		n += len(e.Title) + len(e.Description)
+		if e.Footer != nil {
+			n += len(e.Footer.Text)
+		}
+		if e.Author != nil {
+			n += len(e.Author.Name)
+		}
+		for _, f := range e.Fields {
+			n += len(f.Name) + len(f.Value)
+		}
+	}
+	return n
+}
+
+// retry schedules the messages to be sent again, dropping those that have run out of attempts.
+//
+// Being rate limited isn't the message's fault, so it waits as long as Discord asks without using up an attempt.
+func (q *Queue) retry(ctx context.Context, batch []db.OutboundMessage, err error, now time.Time) {
+	var rateLimited *discordgo.RateLimitError
+	for _, m := range batch {
+		attempts := m.Attempts + 1
+		next := now.Add(retryDelay << m.Attempts)
+		if errors.As(err, &rateLimited) && rateLimited.RateLimit != nil && rateLimited.TooManyRequests != nil {
+			attempts, next = m.Attempts, now.Add(rateLimited.RetryAfter)
+		}
+
+		if attempts >= maxAttempts {
+			slog.ErrorContext(ctx, "dropping queued message after too many attempts", "id", m.ID, "channel", m.ChannelID,
+				"attempts", attempts)
+			q.delete(ctx, []db.OutboundMessage{m})
+			continue
+		}
+
+		if err := q.repo.Retry(ctx, m.ID, attempts, next.Unix()); err != nil {
+			slog.ErrorContext(ctx, "failed to reschedule queued message", "error", err, "id", m.ID)
+		}
+	}
+}
+
+// delete removes sent messages from the queue.
+//
+// A message that fails to be removed will be sent again, which is better than losing a message.
+func (q *Queue) delete(ctx context.Context, batch []db.OutboundMessage) {
+	for _, m := range batch {
+		if err := q.repo.Delete(ctx, m.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to remove queued message", "error", err, "id", m.ID)
+		}
+	}
+}
+
+func encode(message discordgo.MessageSend) (string, error) {
+	stored := storedMessage{Content: message.Content, Embeds: message.Embeds}
+	for _, c := range message.Components {
+		raw, err := json.Marshal(c)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode message component: %w", err)
+		}
+		stored.Components = append(stored.Components, raw)
+	}
+
+	payload, err := json.Marshal(stored)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode message: %w", err)
+	}
+	return string(payload), nil
+}
+
+func decode(payload string) (discordgo.MessageSend, error) {
+	var stored storedMessage
+	if err := json.Unmarshal([]byte(payload), &stored); err != nil {
+		return discordgo.MessageSend{}, err
+	}
+
+	message := discordgo.MessageSend{Content: stored.Content, Embeds: stored.Embeds}
+	for _, raw := range stored.Components {
+		c, err := discordgo.MessageComponentFromJSON(raw)
+		if err != nil {
+			return discordgo.MessageSend{}, err
+		}
+		message.Components = append(message.Components, c)
+	}
+	return message, nil
+}
@@ -0,0 +1,348 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSender struct {
+	mock.Mock
+}
+
+func (m *MockSender) SendMessage(ctx context.Context, channelID, content string) error {
+	args := m.Called(ctx, channelID, content)
+	return args.Error(0)
+}
+
+func (m *MockSender) SendComplexMessage(ctx context.Context, channelID string, message discordgo.MessageSend) error {
+	args := m.Called(ctx, channelID, message)
+	return args.Error(0)
+}
+
+func (m *MockSender) AddRole(ctx context.Context, guildID, userID, roleID string) error {
+	args := m.Called(ctx, guildID, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockSender) RemoveRole(ctx context.Context, guildID, userID, roleID string) error {
+	args := m.Called(ctx, guildID, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockSender) UpdateInteractionMessage(ctx context.Context, interaction *discordgo.Interaction, message discordgo.MessageSend) error {
+	args := m.Called(ctx, interaction, message)
+	return args.Error(0)
+}
+
+func (m *MockSender) RespondEphemeral(ctx context.Context, interaction *discordgo.Interaction, content string) error {
+	args := m.Called(ctx, interaction, content)
+	return args.Error(0)
+}
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Insert(ctx context.Context, message *db.OutboundMessage) error {
+	args := m.Called(ctx, message)
+	return args.Error(0)
+}
+
+func (m *MockRepository) List(ctx context.Context) ([]db.OutboundMessage, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]db.OutboundMessage), args.Error(1)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Retry(ctx context.Context, id int64, attempts int, nextAttempt int64) error {
+	args := m.Called(ctx, id, attempts, nextAttempt)
+	return args.Error(0)
+}
+
+type MockTimeProvider struct {
+	now time.Time
+}
+
+func (m *MockTimeProvider) Now() time.Time {
+	return m.now
+}
+
+var testNow = time.Date(2024, time.September, 17, 15, 0, 0, 0, time.UTC)
+
+func setupQueue() (*Queue, *MockSender, *MockRepository, *MockTimeProvider) {
+	sender := &MockSender{}
+	repo := &MockRepository{}
+	timeProvider := &MockTimeProvider{now: testNow}
+	return NewQueue(sender, repo, timeProvider), sender, repo, timeProvider
+}
+
+// queued returns a stored message that is due to be sent.
+func queued(t *testing.T, id int64, channelID string, message discordgo.MessageSend) db.OutboundMessage {
+	t.Helper()
+	payload, err := encode(message)
+	require.NoError(t, err)
+
+	return db.OutboundMessage{
+		ID: id, ChannelID: channelID, Payload: payload, NextAttempt: testNow.Unix(), DateCreated: testNow.Unix(),
+	}
+}
+
+func embed(title string) discordgo.MessageSend {
+	return discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{{Title: title}}}
+}
+
+func TestQueue_SendComplexMessage_Queues(t *testing.T) {
+	queue, sender, repo, _ := setupQueue()
+	ctx := context.Background()
+
+	repo.On("Insert", ctx, mock.MatchedBy(func(m *db.OutboundMessage) bool {
+		message, err := decode(m.Payload)
+		return err == nil && m.ChannelID == "channel1" && m.NextAttempt == testNow.Unix() &&
+			assert.ObjectsAreEqual(embed("Score Update"), message)
+	})).Return(nil)
+
+	err := queue.SendComplexMessage(ctx, "channel1", embed("Score Update"))
+	assert.NoError(t, err)
+
+	repo.AssertExpectations(t)
+	sender.AssertNotCalled(t, "SendComplexMessage", mock.Anything, mock.Anything, mock.Anything)
+	// Run is woken to send it
+	assert.Len(t, queue.wake, 1)
+}
+
+func TestQueue_SendComplexMessage_InsertError(t *testing.T) {
+	queue, _, repo, _ := setupQueue()
+	ctx := context.Background()
+
+	repo.On("Insert", ctx, mock.Anything).Return(errors.New("db error"))
+
+	err := queue.SendMessage(ctx, "channel1", "hello")
+	assert.ErrorContains(t, err, "failed to queue message")
+}
+
+func TestQueue_SendComplexMessage_FilesSentDirectly(t *testing.T) {
+	queue, sender, repo, _ := setupQueue()
+	ctx := context.Background()
+
+	message := discordgo.MessageSend{Files: []*discordgo.File{{Name: "roster.json", Reader: io.Reader(strings.NewReader("{}"))}}}
+	sender.On("SendComplexMessage", ctx, "channel1", message).Return(nil)
+
+	err := queue.SendComplexMessage(ctx, "channel1", message)
+	assert.NoError(t, err)
+
+	sender.AssertExpectations(t)
+	repo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+}
+
+func TestQueue_Flush_Coalesces(t *testing.T) {
+	queue, sender, repo, _ := setupQueue()
+	ctx := context.Background()
+
+	buttons := embed("Scores")
+	buttons.Components = []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "Next", CustomID: "scores:1:20:1700000000"},
+	}}}
+	repo.On("List", ctx).Return([]db.OutboundMessage{
+		queued(t, 1, "channel1", discordgo.MessageSend{Content: "first", Embeds: embed("One").Embeds}),
+		queued(t, 2, "channel2", embed("Other channel")),
+		queued(t, 3, "channel1", discordgo.MessageSend{Content: "second", Embeds: embed("Two").Embeds}),
+		queued(t, 4, "channel1", buttons),
+	}, nil)
+	sender.On("SendComplexMessage", ctx, "channel1", discordgo.MessageSend{
+		Content: "first\nsecond",
+		Embeds:  []*discordgo.MessageEmbed{{Title: "One"}, {Title: "Two"}},
+	}).Return(nil)
+	sender.On("SendComplexMessage", ctx, "channel2", embed("Other channel")).Return(nil)
+	repo.On("Delete", ctx, int64(1)).Return(nil)
+	repo.On("Delete", ctx, int64(2)).Return(nil)
+	repo.On("Delete", ctx, int64(3)).Return(nil)
+
+	err := queue.Flush(ctx)
+	require.NoError(t, err)
+
+	sender.AssertExpectations(t)
+	repo.AssertExpectations(t)
+	// The message with buttons is sent on its own
+	repo.AssertNotCalled(t, "Delete", ctx, int64(4))
+}
+
+func TestQueue_Flush_ChannelInterval(t *testing.T) {
+	queue, sender, repo, timeProvider := setupQueue()
+	ctx := context.Background()
+
+	first := queued(t, 1, "channel1", embed("One"))
+	repo.On("List", ctx).Return([]db.OutboundMessage{first}, nil).Once()
+	sender.On("SendComplexMessage", ctx, "channel1", embed("One")).Return(nil).Once()
+	repo.On("Delete", ctx, int64(1)).Return(nil)
+	require.NoError(t, queue.Flush(ctx))
+
+	// A message queued straight after has to wait for the channel
+	third := queued(t, 3, "channel1", embed("Three"))
+	repo.On("List", ctx).Return([]db.OutboundMessage{third}, nil)
+	require.NoError(t, queue.Flush(ctx))
+	sender.AssertNumberOfCalls(t, "SendComplexMessage", 1)
+
+	timeProvider.now = testNow.Add(channelInterval)
+	sender.On("SendComplexMessage", ctx, "channel1", embed("Three")).Return(nil).Once()
+	repo.On("Delete", ctx, int64(3)).Return(nil)
+	require.NoError(t, queue.Flush(ctx))
+
+	sender.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestQueue_Flush_Limits(t *testing.T) {
+	queue, sender, repo, _ := setupQueue()
+	ctx := context.Background()
+
+	long := strings.Repeat("a", maxContentChars)
+	repo.On("List", ctx).Return([]db.OutboundMessage{
+		queued(t, 1, "channel1", discordgo.MessageSend{Content: long}),
+		queued(t, 2, "channel1", discordgo.MessageSend{Content: "b"}),
+	}, nil)
+	// The second message would take the content over Discord's limit, so waits for the next send
+	sender.On("SendComplexMessage", ctx, "channel1", discordgo.MessageSend{Content: long}).Return(nil)
+	repo.On("Delete", ctx, int64(1)).Return(nil)
+
+	require.NoError(t, queue.Flush(ctx))
+
+	sender.AssertExpectations(t)
+	repo.AssertNotCalled(t, "Delete", ctx, int64(2))
+}
+
+func TestQueue_Flush_NotDue(t *testing.T) {
+	queue, sender, repo, _ := setupQueue()
+	ctx := context.Background()
+
+	waiting := queued(t, 1, "channel1", embed("One"))
+	waiting.NextAttempt = testNow.Add(time.Minute).Unix()
+	repo.On("List", ctx).Return([]db.OutboundMessage{waiting, queued(t, 2, "channel1", embed("Two"))}, nil)
+
+	require.NoError(t, queue.Flush(ctx))
+
+	// Later messages wait behind it so the channel's messages stay in order
+	sender.AssertNotCalled(t, "SendComplexMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestQueue_Flush_Retries(t *testing.T) {
+	queue, sender, repo, _ := setupQueue()
+	ctx := context.Background()
+
+	failed := queued(t, 1, "channel1", embed("One"))
+	failed.Attempts = 2
+	repo.On("List", ctx).Return([]db.OutboundMessage{failed}, nil)
+	sender.On("SendComplexMessage", ctx, "channel1", embed("One")).Return(errors.New("discord error"))
+	// The delay doubles with each attempt
+	repo.On("Retry", ctx, int64(1), 3, testNow.Add(4*retryDelay).Unix()).Return(nil)
+
+	require.NoError(t, queue.Flush(ctx))
+
+	repo.AssertExpectations(t)
+}
+
+func TestQueue_Flush_RateLimited(t *testing.T) {
+	queue, sender, repo, _ := setupQueue()
+	ctx := context.Background()
+
+	rateLimited := &discordgo.RateLimitError{RateLimit: &discordgo.RateLimit{
+		TooManyRequests: &discordgo.TooManyRequests{RetryAfter: 30 * time.Second},
+	}}
+	repo.On("List", ctx).Return([]db.OutboundMessage{queued(t, 1, "channel1", embed("One"))}, nil)
+	sender.On("SendComplexMessage", ctx, "channel1", embed("One")).Return(rateLimited)
+	// Waiting for the rate limit doesn't use up an attempt
+	repo.On("Retry", ctx, int64(1), 0, testNow.Add(30*time.Second).Unix()).Return(nil)
+
+	require.NoError(t, queue.Flush(ctx))
+
+	repo.AssertExpectations(t)
+}
+
+func TestQueue_Flush_DropsAfterMaxAttempts(t *testing.T) {
+	queue, sender, repo, _ := setupQueue()
+	ctx := context.Background()
+
+	failed := queued(t, 1, "channel1", embed("One"))
+	failed.Attempts = maxAttempts - 1
+	repo.On("List", ctx).Return([]db.OutboundMessage{failed}, nil)
+	sender.On("SendComplexMessage", ctx, "channel1", embed("One")).Return(errors.New("discord error"))
+	repo.On("Delete", ctx, int64(1)).Return(nil)
+
+	require.NoError(t, queue.Flush(ctx))
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "Retry", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestQueue_Flush_DropsUndecodable(t *testing.T) {
+	queue, sender, repo, _ := setupQueue()
+	ctx := context.Background()
+
+	corrupt := queued(t, 1, "channel1", embed("One"))
+	corrupt.Payload = "{"
+	repo.On("List", ctx).Return([]db.OutboundMessage{corrupt, queued(t, 2, "channel1", embed("Two"))}, nil)
+	repo.On("Delete", ctx, int64(1)).Return(nil)
+	sender.On("SendComplexMessage", ctx, "channel1", embed("Two")).Return(nil)
+	repo.On("Delete", ctx, int64(2)).Return(nil)
+
+	require.NoError(t, queue.Flush(ctx))
+
+	sender.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestQueue_Flush_ListError(t *testing.T) {
+	queue, _, repo, _ := setupQueue()
+	ctx := context.Background()
+
+	repo.On("List", ctx).Return([]db.OutboundMessage(nil), errors.New("db error"))
+
+	assert.Error(t, queue.Flush(ctx))
+}
+
+func TestQueue_AddRole_SentDirectly(t *testing.T) {
+	queue, sender, _, _ := setupQueue()
+	ctx := context.Background()
+
+	sender.On("AddRole", ctx, "guild1", "user1", "role1").Return(nil)
+
+	assert.NoError(t, queue.AddRole(ctx, "guild1", "user1", "role1"))
+	sender.AssertExpectations(t)
+}
+
+func TestEncode_Components(t *testing.T) {
+	message := embed("Scores")
+	message.Components = []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "Previous", Style: discordgo.SecondaryButton, Disabled: true, CustomID: "scores:0:20:1"},
+		discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: "scores:1:20:1"},
+	}}}
+
+	payload, err := encode(message)
+	require.NoError(t, err)
+	decoded, err := decode(payload)
+	require.NoError(t, err)
+
+	// Components decode as pointers, which Discord sends the same way
+	require.Len(t, decoded.Components, 1)
+	row, ok := decoded.Components[0].(*discordgo.ActionsRow)
+	require.True(t, ok)
+	require.Len(t, row.Components, 2)
+	assert.Equal(t, &discordgo.Button{Label: "Next", Style: discordgo.SecondaryButton, CustomID: "scores:1:20:1"}, row.Components[1])
+
+	reencoded, err := encode(decoded)
+	require.NoError(t, err)
+	assert.JSONEq(t, payload, reencoded)
+}
@@ -0,0 +1,35 @@
+package raiderio
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+
+	"github.com/DylanNZL/mythicplusbot/i18n"
+)
+
+// Affixes are the mythic keystone affixes active in a region this week.
+type Affixes struct {
+	Region         string  `json:"region"`
+	Title          string  `json:"title"`
+	LeaderboardURL string  `json:"leaderboard_url"`
+	AffixDetails   []Affix `json:"affix_details"`
+}
+
+// GetAffixes returns this week's US affixes, described in the context's locale.
+//
+// docs: https://raider.io/api#/mythic_plus/getApiV1MythicplusAffixes.
+func (c *Client) GetAffixes(ctx context.Context) (*Affixes, error) {
+	query := url.Values{
+		"region": []string{"us"},
+		"locale": []string{string(i18n.FromContext(ctx))},
+	}
+	slog.DebugContext(ctx, "fetching affixes from raider.io")
+
+	var affixes Affixes
+	if err := c.get(ctx, "/api/v1/mythic-plus/affixes", query, &affixes); err != nil {
+		return nil, err
+	}
+
+	return &affixes, nil
+}
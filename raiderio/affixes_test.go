@@ -0,0 +1,85 @@
+package raiderio
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createSuccessfulAffixesResponse() string {
+	return `{
+		"region": "us",
+		"title": "Tyrannical, Xal'atath's Bargain: Ascendant",
+		"leaderboard_url": "https://raider.io/mythic-plus/affixes",
+		"affix_details": [
+			{
+				"id": 9,
+				"name": "Tyrannical",
+				"description": "Bosses have 30% more health.",
+				"icon": "achievement_boss_archaedas",
+				"wowhead_url": "https://wowhead.com/affix=9"
+			},
+			{
+				"id": 148,
+				"name": "Xal'atath's Bargain: Ascendant",
+				"description": "Xal'atath bargains with players.",
+				"icon": "ability_mage_netherwindpresence",
+				"wowhead_url": "https://wowhead.com/affix=148"
+			}
+		]
+	}`
+}
+
+func TestClient_GetAffixes_Success(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	client := NewClient("test-token", httpClient)
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		query := req.URL.Query()
+		return strings.HasPrefix(req.URL.String(), "https://raider.io/api/v1/mythic-plus/affixes?") &&
+			query.Get("access_key") == "test-token" &&
+			query.Get("region") == "us" &&
+			query.Get("locale") == "en"
+	})).Return(createHTTPResponse(200, createSuccessfulAffixesResponse()), nil)
+
+	affixes, err := client.GetAffixes(t.Context())
+
+	require.NoError(t, err)
+	assert.Equal(t, "Tyrannical, Xal'atath's Bargain: Ascendant", affixes.Title)
+	require.Len(t, affixes.AffixDetails, 2)
+	assert.Equal(t, 9, affixes.AffixDetails[0].Id)
+	assert.Equal(t, "Tyrannical", affixes.AffixDetails[0].Name)
+	assert.Equal(t, "https://wowhead.com/affix=148", affixes.AffixDetails[1].WowheadUrl)
+	httpClient.AssertExpectations(t)
+}
+
+func TestClient_GetAffixes_Locale(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	client := NewClient("test-token", httpClient)
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("locale") == "fr"
+	})).Return(createHTTPResponse(200, createSuccessfulAffixesResponse()), nil)
+
+	_, err := client.GetAffixes(i18n.WithLocale(t.Context(), i18n.French))
+
+	require.NoError(t, err)
+	httpClient.AssertExpectations(t)
+}
+
+func TestClient_GetAffixes_BadStatusCode(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	client := NewClient("test-token", httpClient)
+
+	httpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(createHTTPResponse(500, `{}`), nil)
+
+	affixes, err := client.GetAffixes(t.Context())
+
+	assert.Nil(t, affixes)
+	assert.ErrorContains(t, err, "unexpected status code: 500")
+}
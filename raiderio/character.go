@@ -26,20 +26,14 @@ type (
 		MythicPlusRanks          Ranks             `json:"mythic_plus_ranks"`
 		PreviousMythicPlusRanks  []json.RawMessage `json:"previous_mythic_plus_ranks"`
 		MythicPlusRecentRuns     []Run             `json:"mythic_plus_recent_runs"`
-		MythicPlusBestRuns       []json.RawMessage `json:"mythic_plus_best_runs"`
-		MythicPlusAlternateRuns  []json.RawMessage `json:"mythic_plus_alternate_runs"`
+		MythicPlusBestRuns       []Run             `json:"mythic_plus_best_runs"`
+		MythicPlusAlternateRuns  []Run             `json:"mythic_plus_alternate_runs"`
 	}
 
 	Season struct {
-		Season   string `json:"season"`
-		Scores   Scores `json:"scores"`
-		Segments struct {
-			All    ScoreSegment `json:"all"`
-			Dps    ScoreSegment `json:"dps"`
-			Healer ScoreSegment `json:"healer"`
-			Tank   ScoreSegment `json:"tank"`
-			// Note we may want to add some special unwrapping here as we each spec will have its own spec# attribute unique to the class
-		} `json:"segments"`
+		Season   string   `json:"season"`
+		Scores   Scores   `json:"scores"`
+		Segments Segments `json:"segments"`
 	}
 
 	Scores struct {
@@ -47,7 +41,17 @@ type (
 		Dps    float64 `json:"dps"`
 		Healer float64 `json:"healer"`
 		Tank   float64 `json:"tank"`
-		// Note we may want to add some special unwrapping here as we each spec will have its own spec# attribute unique to the class
+		// Specs holds the spec_0 to spec_3 scores, in the class's spec order, see SpecName.
+		Specs [4]float64 `json:"-"`
+	}
+
+	Segments struct {
+		All    ScoreSegment `json:"all"`
+		Dps    ScoreSegment `json:"dps"`
+		Healer ScoreSegment `json:"healer"`
+		Tank   ScoreSegment `json:"tank"`
+		// Specs holds the spec_0 to spec_3 segments, in the class's spec order, see SpecName.
+		Specs [4]ScoreSegment `json:"-"`
 	}
 
 	Run struct {
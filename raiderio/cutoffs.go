@@ -0,0 +1,61 @@
+package raiderio
+
+import "time"
+
+type (
+	// Cutoffs are the lowest scores needed to be in the top percentiles of the region for a season.
+	Cutoffs struct {
+		UpdatedAt time.Time `json:"updatedAt"`
+		P999      Cutoff    `json:"p999"`
+		P990      Cutoff    `json:"p990"`
+		P900      Cutoff    `json:"p900"`
+		P750      Cutoff    `json:"p750"`
+		P600      Cutoff    `json:"p600"`
+	}
+
+	// Cutoff is a percentile cutoff, split by faction. We only use the cutoff across both factions.
+	Cutoff struct {
+		All CutoffSegment `json:"all"`
+	}
+
+	CutoffSegment struct {
+		QuantileMinValue        float64 `json:"quantileMinValue"`
+		QuantilePopulationCount int     `json:"quantilePopulationCount"`
+		TotalPopulationCount    int     `json:"totalPopulationCount"`
+	}
+
+	// Tier is a named percentile cutoff, e.g. the top 1% need a score of at least Score.
+	Tier struct {
+		Name       string
+		Score      float64
+		Population int
+	}
+)
+
+// Tiers returns the percentile cutoffs from the top 0.1% down. The top 0.1% is roughly the cutoff for the season title.
+func (c *Cutoffs) Tiers() []Tier {
+	return []Tier{
+		{Name: "0.1%", Score: c.P999.All.QuantileMinValue, Population: c.P999.All.QuantilePopulationCount},
+		{Name: "1%", Score: c.P990.All.QuantileMinValue, Population: c.P990.All.QuantilePopulationCount},
+		{Name: "10%", Score: c.P900.All.QuantileMinValue, Population: c.P900.All.QuantilePopulationCount},
+		{Name: "25%", Score: c.P750.All.QuantileMinValue, Population: c.P750.All.QuantilePopulationCount},
+		{Name: "40%", Score: c.P600.All.QuantileMinValue, Population: c.P600.All.QuantilePopulationCount},
+	}
+}
+
+// Standing returns the highest tier the score reaches and the next tier above it. Either is nil if there isn't one,
+// reached is nil below the top 40% and next is nil above the top 0.1%. Tiers Raider.IO hasn't calculated are skipped.
+func (c *Cutoffs) Standing(score float64) (reached, next *Tier) {
+	tiers := c.Tiers()
+	for i := range tiers {
+		if tiers[i].Score == 0 {
+			continue
+		}
+		if score >= tiers[i].Score {
+			return &tiers[i], next
+		}
+		next = &tiers[i]
+	}
+
+	return nil, next
+}
@@ -0,0 +1,137 @@
+package raiderio
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createSuccessfulCutoffsResponse() string {
+	return `{
+		"cutoffs": {
+			"updatedAt": "2024-01-01T12:00:00.000Z",
+			"p999": {"all": {"quantileMinValue": 3450.5, "quantilePopulationCount": 150, "totalPopulationCount": 150000}},
+			"p990": {"all": {"quantileMinValue": 3100, "quantilePopulationCount": 1500}},
+			"p900": {"all": {"quantileMinValue": 2700, "quantilePopulationCount": 15000}},
+			"p750": {"all": {"quantileMinValue": 2300, "quantilePopulationCount": 37500}},
+			"p600": {"all": {"quantileMinValue": 2000, "quantilePopulationCount": 60000}}
+		}
+	}`
+}
+
+func TestClient_GetSeasonCutoffs_Success(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	client := NewClient("test-token", httpClient)
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		query := req.URL.Query()
+		return strings.HasPrefix(req.URL.String(), "https://raider.io/api/v1/mythic-plus/season-cutoffs?") &&
+			query.Get("access_key") == "test-token" &&
+			query.Get("season") == "season-tww-2" &&
+			query.Get("region") == "us"
+	})).Return(createHTTPResponse(200, createSuccessfulCutoffsResponse()), nil)
+
+	cutoffs, err := client.GetSeasonCutoffs(t.Context(), "season-tww-2")
+
+	require.NoError(t, err)
+	assert.InDelta(t, 3450.5, cutoffs.P999.All.QuantileMinValue, 0.001)
+	assert.Equal(t, 150, cutoffs.P999.All.QuantilePopulationCount)
+	assert.InDelta(t, 2000, cutoffs.P600.All.QuantileMinValue, 0.001)
+	httpClient.AssertExpectations(t)
+}
+
+func TestClient_GetSeasonCutoffs_Cached(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	client := NewClient("test-token", httpClient)
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("season") == "season-tww-2"
+	})).Return(createHTTPResponse(200, createSuccessfulCutoffsResponse()), nil).Once()
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		return req.URL.Query().Get("season") == "season-tww-1"
+	})).Return(createHTTPResponse(200, createSuccessfulCutoffsResponse()), nil).Once()
+
+	first, err := client.GetSeasonCutoffs(t.Context(), "season-tww-2")
+	require.NoError(t, err)
+
+	// The same season is served from the cache, other seasons are cached separately
+	now = now.Add(cutoffsTTL - time.Minute)
+	second, err := client.GetSeasonCutoffs(t.Context(), "season-tww-2")
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+
+	_, err = client.GetSeasonCutoffs(t.Context(), "season-tww-1")
+	require.NoError(t, err)
+	httpClient.AssertExpectations(t)
+
+	// Once the cache expires the cutoffs are fetched again
+	httpClient.On("Do", mock.Anything).Return(createHTTPResponse(200, createSuccessfulCutoffsResponse()), nil).Once()
+	now = now.Add(time.Minute)
+	third, err := client.GetSeasonCutoffs(t.Context(), "season-tww-2")
+	require.NoError(t, err)
+	assert.NotSame(t, first, third)
+	httpClient.AssertNumberOfCalls(t, "Do", 3)
+}
+
+func TestClient_GetSeasonCutoffs_BadStatusCode(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	client := NewClient("test-token", httpClient)
+
+	httpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(createHTTPResponse(400, `{}`), nil)
+
+	cutoffs, err := client.GetSeasonCutoffs(t.Context(), "season-unknown")
+
+	assert.Nil(t, cutoffs)
+	assert.ErrorContains(t, err, "unexpected status code: 400")
+}
+
+func TestCutoffs_Standing(t *testing.T) {
+	cutoffs := &Cutoffs{}
+	cutoffs.P999.All.QuantileMinValue = 3450
+	cutoffs.P990.All.QuantileMinValue = 3100
+	cutoffs.P900.All.QuantileMinValue = 2700
+	cutoffs.P750.All.QuantileMinValue = 2300
+	cutoffs.P600.All.QuantileMinValue = 2000
+
+	tests := []struct {
+		name    string
+		score   float64
+		reached string
+		next    string
+	}{
+		{name: "above the title cutoff", score: 3500, reached: "0.1%"},
+		{name: "between cutoffs", score: 2800, reached: "10%", next: "1%"},
+		{name: "exactly on a cutoff", score: 3100, reached: "1%", next: "0.1%"},
+		{name: "below every cutoff", score: 1500, next: "40%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached, next := cutoffs.Standing(tt.score)
+			assert.Equal(t, tt.reached, tierName(reached))
+			assert.Equal(t, tt.next, tierName(next))
+		})
+	}
+}
+
+func TestCutoffs_Standing_NoCutoffs(t *testing.T) {
+	// Early in a season there aren't enough runs for Raider.IO to calculate cutoffs
+	reached, next := (&Cutoffs{}).Standing(2500)
+
+	assert.Nil(t, reached)
+	assert.Nil(t, next)
+}
+
+func tierName(t *Tier) string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
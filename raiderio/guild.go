@@ -0,0 +1,55 @@
+package raiderio
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"time"
+)
+
+// Guild is the partial guild profile response from Raider.IO.
+//
+//nolint:all
+type Guild struct {
+	Name            string    `json:"name"`
+	Faction         string    `json:"faction"`
+	Region          string    `json:"region"`
+	Realm           string    `json:"realm"`
+	LastCrawledAt   time.Time `json:"last_crawled_at"`
+	ProfileUrl      string    `json:"profile_url"`
+	MythicPlusRanks Rank      `json:"mythic_plus_ranks"`
+	// Members is the guild's roster as of Raider.IO's last crawl of the guild
+	Members []GuildMember `json:"members"`
+}
+
+// GuildMember is a character on a guild's roster.
+type GuildMember struct {
+	Rank      int                  `json:"rank"`
+	Character GuildMemberCharacter `json:"character"`
+}
+
+// GuildMemberCharacter is the part of a guild member's character we use, the realm is its name rather than its slug.
+type GuildMemberCharacter struct {
+	Name  string `json:"name"`
+	Realm string `json:"realm"`
+}
+
+// GetGuild returns the raider.io profile of a guild, including its mythic+ ranks and roster.
+//
+// docs: https://raider.io/api#/guild/getApiV1GuildsProfile.
+func (c *Client) GetGuild(ctx context.Context, realm string, name string) (*Guild, error) {
+	query := url.Values{
+		"region": []string{"us"},
+		"realm":  []string{realm},
+		"name":   []string{name},
+		"fields": []string{"mythic_plus_ranks,members"},
+	}
+	slog.DebugContext(ctx, "fetching guild from raider.io", slog.String("guild", name), slog.String("realm", realm))
+
+	var guild Guild
+	if err := c.get(ctx, "/api/v1/guilds/profile", query, &guild); err != nil {
+		return nil, err
+	}
+
+	return &guild, nil
+}
@@ -0,0 +1,54 @@
+package raiderio
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetGuild_Success(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	client := NewClient("test-token", httpClient)
+
+	httpClient.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+		query := req.URL.Query()
+		return strings.HasPrefix(req.URL.String(), "https://raider.io/api/v1/guilds/profile?") &&
+			query.Get("access_key") == "test-token" &&
+			query.Get("region") == "us" &&
+			query.Get("realm") == "tichondrius" &&
+			query.Get("name") == "Test Guild" &&
+			query.Get("fields") == "mythic_plus_ranks,members"
+	})).Return(createHTTPResponse(200, `{
+		"name": "Test Guild",
+		"faction": "horde",
+		"region": "us",
+		"realm": "Tichondrius",
+		"profile_url": "https://raider.io/guilds/us/tichondrius/Test%20Guild",
+		"mythic_plus_ranks": {"world": 1200, "region": 450, "realm": 12},
+		"members": [{"rank": 0, "character": {"name": "Paladylan", "class": "Paladin", "realm": "Area 52"}}]
+	}`), nil)
+
+	guild, err := client.GetGuild(t.Context(), "tichondrius", "Test Guild")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Test Guild", guild.Name)
+	assert.Equal(t, Rank{World: 1200, Region: 450, Realm: 12}, guild.MythicPlusRanks)
+	assert.Equal(t, []GuildMember{{Character: GuildMemberCharacter{Name: "Paladylan", Realm: "Area 52"}}}, guild.Members)
+	httpClient.AssertExpectations(t)
+}
+
+func TestClient_GetGuild_BadStatusCode(t *testing.T) {
+	httpClient := &MockHTTPClient{}
+	client := NewClient("test-token", httpClient)
+
+	httpClient.On("Do", mock.AnythingOfType("*http.Request")).Return(createHTTPResponse(404, `{}`), nil)
+
+	guild, err := client.GetGuild(t.Context(), "tichondrius", "Unknown")
+
+	assert.Nil(t, guild)
+	assert.ErrorContains(t, err, "unexpected status code: 404")
+}
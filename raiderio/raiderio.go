@@ -9,6 +9,8 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
 )
 
 // HTTPClient defines the interface for making HTTP requests
@@ -19,13 +21,27 @@ type HTTPClient interface {
 // APIClient defines the interface for Raider.IO API operations
 type APIClient interface {
 	GetCharacter(ctx context.Context, name, realm string) (*Character, error)
+	GetSeasonCutoffs(ctx context.Context, season string) (*Cutoffs, error)
+	GetAffixes(ctx context.Context) (*Affixes, error)
+	GetGuild(ctx context.Context, realm, name string) (*Guild, error)
 }
 
+const cutoffsTTL = time.Hour
+
 // Client handles Raider.IO API requests with injected dependencies
 type Client struct {
 	AccessToken string
 	httpClient  HTTPClient
 	baseURL     string
+	now         func() time.Time
+
+	cutoffsMu sync.Mutex
+	cutoffs   map[string]cachedCutoffs
+}
+
+type cachedCutoffs struct {
+	cutoffs   *Cutoffs
+	fetchedAt time.Time
 }
 
 // NewClient creates a new Raider.IO API client
@@ -34,6 +50,8 @@ func NewClient(accessToken string, httpClient HTTPClient) *Client {
 		AccessToken: accessToken,
 		httpClient:  httpClient,
 		baseURL:     "https://raider.io",
+		now:         time.Now,
+		cutoffs:     make(map[string]cachedCutoffs),
 	}
 }
 
@@ -41,45 +59,81 @@ func NewClient(accessToken string, httpClient HTTPClient) *Client {
 //
 // docs: https://raider.io/api#/character/getApiV1CharactersProfile.
 func (c *Client) GetCharacter(ctx context.Context, realm string, name string) (*Character, error) {
-	u := url.URL{
-		Scheme: "https",
-		Host:   "raider.io",
-		Path:   "/api/v1/characters/profile",
+	query := url.Values{
+		"region": []string{"us"},
+		"realm":  []string{realm},
+		"name":   []string{name},
+		"fields": []string{"mythic_plus_scores_by_season:current,mythic_plus_ranks,mythic_plus_recent_runs," +
+			"mythic_plus_best_runs,mythic_plus_alternate_runs"},
+	}
+	slog.DebugContext(ctx, "fetching character from raider.io", slog.String("character", name), slog.String("realm", realm))
+
+	var char Character
+	if err := c.get(ctx, "/api/v1/characters/profile", query, &char); err != nil {
+		return nil, err
 	}
+
+	return &char, nil
+}
+
+// GetSeasonCutoffs returns the US score cutoffs for the season, e.g. season-tww-2.
+//
+// Raider.IO only recalculates the cutoffs periodically, so they are cached for each season for cutoffsTTL.
+//
+// docs: https://raider.io/api#/mythic_plus/getApiV1MythicplusSeasoncutoffs.
+func (c *Client) GetSeasonCutoffs(ctx context.Context, season string) (*Cutoffs, error) {
+	c.cutoffsMu.Lock()
+	defer c.cutoffsMu.Unlock()
+
+	if cached, ok := c.cutoffs[season]; ok && c.now().Sub(cached.fetchedAt) < cutoffsTTL {
+		return cached.cutoffs, nil
+	}
+
 	query := url.Values{
-		"access_key": []string{c.AccessToken},
-		"region":     []string{"us"},
-		"realm":      []string{realm},
-		"name":       []string{name},
-		"fields":     []string{"mythic_plus_scores_by_season:current,mythic_plus_ranks,mythic_plus_recent_runs"},
+		"region": []string{"us"},
+		"season": []string{season},
+	}
+	slog.DebugContext(ctx, "fetching season cutoffs from raider.io", slog.String("season", season))
+
+	var resp struct {
+		Cutoffs Cutoffs `json:"cutoffs"`
+	}
+	if err := c.get(ctx, "/api/v1/mythic-plus/season-cutoffs", query, &resp); err != nil {
+		return nil, err
 	}
 
-	u.RawQuery = query.Encode()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	c.cutoffs[season] = cachedCutoffs{cutoffs: &resp.Cutoffs, fetchedAt: c.now()}
+	return &resp.Cutoffs, nil
+}
+
+// get requests an API path and decodes the JSON response into v.
+func (c *Client) get(ctx context.Context, path string, query url.Values, v any) error {
+	query.Set("access_key", c.AccessToken)
+	u := c.baseURL + path + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
-	slog.DebugContext(ctx, "fetching character from raider.io", slog.String("character", name), slog.String("realm", realm))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	var char Character
-	if err := json.Unmarshal(body, &char); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	return &char, nil
+	return nil
 }
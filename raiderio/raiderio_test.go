@@ -60,7 +60,26 @@ func createSuccessfulCharacterResponse() string {
 				"region": 567,
 				"realm": 12
 			}
-		}
+		},
+		"mythic_plus_best_runs": [{
+			"dungeon": "The Stonevault",
+			"short_name": "SV",
+			"mythic_level": 12,
+			"keystone_run_id": 100,
+			"completed_at": "2024-01-01T12:00:00.000Z",
+			"clear_time_ms": 1500000,
+			"par_time_ms": 1980000,
+			"num_keystone_upgrades": 2,
+			"score": 310.5,
+			"url": "https://raider.io/mythic-plus-runs/100"
+		}],
+		"mythic_plus_alternate_runs": [{
+			"dungeon": "The Stonevault",
+			"short_name": "SV",
+			"mythic_level": 11,
+			"keystone_run_id": 101,
+			"score": 290
+		}]
 	}`
 }
 
@@ -109,6 +128,13 @@ func TestClient_GetCharacter_Success(t *testing.T) {
 	assert.Equal(t, "testchar", character.Name)
 	assert.Equal(t, "human", character.Race)
 	assert.Equal(t, "paladin", character.Class)
+	require.Len(t, character.MythicPlusBestRuns, 1)
+	assert.Equal(t, "SV", character.MythicPlusBestRuns[0].ShortName)
+	assert.Equal(t, 12, character.MythicPlusBestRuns[0].MythicLevel)
+	assert.Equal(t, 2, character.MythicPlusBestRuns[0].NumKeystoneUpgrades)
+	assert.InDelta(t, 310.5, character.MythicPlusBestRuns[0].Score, 0.001)
+	require.Len(t, character.MythicPlusAlternateRuns, 1)
+	assert.Equal(t, 101, character.MythicPlusAlternateRuns[0].KeystoneRunId)
 	httpClient.AssertExpectations(t)
 }
 
@@ -191,6 +217,7 @@ func TestClient_GetCharacter_URLConstruction(t *testing.T) {
 	assert.Contains(t, capturedURL, "realm=Test-Realm")
 	assert.Contains(t, capturedURL, "region=us")
 	assert.Contains(t, capturedURL, "fields=mythic_plus_scores_by_season%3Acurrent%2Cmythic_plus_ranks")
+	assert.Contains(t, capturedURL, "mythic_plus_best_runs%2Cmythic_plus_alternate_runs")
 	httpClient.AssertExpectations(t)
 }
 
@@ -0,0 +1,72 @@
+package raiderio
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// classSpecs lists each class's specs in the order Raider.IO numbers them, which is the in-game spec order.
+var classSpecs = map[string][]string{
+	"DeathKnight": {"Blood", "Frost", "Unholy"},
+	"DemonHunter": {"Havoc", "Vengeance"},
+	"Druid":       {"Balance", "Feral", "Guardian", "Restoration"},
+	"Evoker":      {"Devastation", "Preservation", "Augmentation"},
+	"Hunter":      {"Beast Mastery", "Marksmanship", "Survival"},
+	"Mage":        {"Arcane", "Fire", "Frost"},
+	"Monk":        {"Brewmaster", "Mistweaver", "Windwalker"},
+	"Paladin":     {"Holy", "Protection", "Retribution"},
+	"Priest":      {"Discipline", "Holy", "Shadow"},
+	"Rogue":       {"Assassination", "Outlaw", "Subtlety"},
+	"Shaman":      {"Elemental", "Enhancement", "Restoration"},
+	"Warlock":     {"Affliction", "Demonology", "Destruction"},
+	"Warrior":     {"Arms", "Fury", "Protection"},
+}
+
+// SpecName returns the name of the class's spec with the given spec_N index, or "" if the class doesn't have one.
+//
+// The class can be written with or without spaces, e.g. Death Knight or DeathKnight.
+func SpecName(class string, index int) string {
+	specs := classSpecs[strings.ReplaceAll(class, " ", "")]
+	if index < 0 || index >= len(specs) {
+		return ""
+	}
+	return specs[index]
+}
+
+// UnmarshalJSON reads the spec_0 to spec_3 scores into Specs as well as the role scores.
+func (s *Scores) UnmarshalJSON(data []byte) error {
+	type scores Scores
+	var aux struct {
+		scores
+		Spec0 float64 `json:"spec_0"`
+		Spec1 float64 `json:"spec_1"`
+		Spec2 float64 `json:"spec_2"`
+		Spec3 float64 `json:"spec_3"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*s = Scores(aux.scores)
+	s.Specs = [4]float64{aux.Spec0, aux.Spec1, aux.Spec2, aux.Spec3}
+	return nil
+}
+
+// UnmarshalJSON reads the spec_0 to spec_3 segments into Specs as well as the role segments.
+func (s *Segments) UnmarshalJSON(data []byte) error {
+	type segments Segments
+	var aux struct {
+		segments
+		Spec0 ScoreSegment `json:"spec_0"`
+		Spec1 ScoreSegment `json:"spec_1"`
+		Spec2 ScoreSegment `json:"spec_2"`
+		Spec3 ScoreSegment `json:"spec_3"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*s = Segments(aux.segments)
+	s.Specs = [4]ScoreSegment{aux.Spec0, aux.Spec1, aux.Spec2, aux.Spec3}
+	return nil
+}
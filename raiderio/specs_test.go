@@ -0,0 +1,41 @@
+package raiderio
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeason_UnmarshalSpecs(t *testing.T) {
+	data := `{
+		"season": "season-tww-2",
+		"scores": {"all": 2950, "dps": 2900, "healer": 0, "tank": 2100, "spec_0": 2100, "spec_1": 2900, "spec_2": 2650, "spec_3": 0},
+		"segments": {
+			"all": {"score": 2950, "color": "#ff8000"},
+			"spec_1": {"score": 2900, "color": "#ff8000"},
+			"spec_2": {"score": 2650, "color": "#a335ee"}
+		}
+	}`
+
+	var season Season
+	require.NoError(t, json.Unmarshal([]byte(data), &season))
+
+	assert.InDelta(t, 2950, season.Scores.All, 0.001)
+	assert.InDelta(t, 2900, season.Scores.Dps, 0.001)
+	assert.Equal(t, [4]float64{2100, 2900, 2650, 0}, season.Scores.Specs)
+	assert.Equal(t, "#ff8000", season.Segments.All.Color)
+	assert.Equal(t, ScoreSegment{Score: 2650, Color: "#a335ee"}, season.Segments.Specs[2])
+	assert.Equal(t, ScoreSegment{}, season.Segments.Specs[0])
+}
+
+func TestSpecName(t *testing.T) {
+	assert.Equal(t, "Frost", SpecName("Death Knight", 1))
+	assert.Equal(t, "Unholy", SpecName("DeathKnight", 2))
+	assert.Equal(t, "Mistweaver", SpecName("Monk", 1))
+	assert.Equal(t, "Restoration", SpecName("Druid", 3))
+	assert.Empty(t, SpecName("Mage", 3))
+	assert.Empty(t, SpecName("Unknown", 0))
+	assert.Empty(t, SpecName("Mage", -1))
+}
@@ -0,0 +1,249 @@
+package roster
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+)
+
+const (
+	jsonFileName       = "roster.json"
+	charactersFileName = "characters.csv"
+	historyFileName    = "score_history.csv"
+
+	jsonContentType = "application/json"
+	csvContentType  = "text/csv"
+)
+
+// The CSV headers reuse the JSON names so both formats read the same.
+var (
+	characterColumns = []string{"id", "name", "realm", "class", "score", "tank_score", "dps_score", "heal_score",
+		"date_updated", "date_created"}
+	historyColumns = []string{"character_id", "score", "tank_score", "dps_score", "heal_score", "date_recorded"}
+)
+
+func encodeJSON(doc Document) ([]File, error) {
+	if doc.Characters == nil {
+		doc.Characters = []db.Character{}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode roster: %w", err)
+	}
+
+	return []File{{Name: jsonFileName, ContentType: jsonContentType, Data: data}}, nil
+}
+
+func decodeJSON(r io.Reader) ([]record, []db.ScoreHistory, error) {
+	var doc Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse roster: %w", err)
+	}
+
+	records := make([]record, len(doc.Characters))
+	for i, c := range doc.Characters {
+		records[i] = record{row: i + 1, character: c}
+	}
+
+	return records, doc.History, nil
+}
+
+func encodeCSV(characters []db.Character, history []db.ScoreHistory, includeHistory bool) ([]File, error) {
+	rows := make([][]string, 0, len(characters))
+	for _, c := range characters {
+		rows = append(rows, []string{strconv.Itoa(c.ID), c.Name, c.Realm, c.Class, formatFloat(c.OverallScore),
+			formatFloat(c.TankScore), formatFloat(c.DPSScore), formatFloat(c.HealScore),
+			strconv.FormatInt(c.DateUpdated, 10), strconv.FormatInt(c.DateCreated, 10)})
+	}
+
+	data, err := writeCSV(characterColumns, rows)
+	if err != nil {
+		return nil, err
+	}
+	files := []File{{Name: charactersFileName, ContentType: csvContentType, Data: data}}
+
+	if !includeHistory {
+		return files, nil
+	}
+
+	rows = make([][]string, 0, len(history))
+	for _, h := range history {
+		rows = append(rows, []string{strconv.Itoa(h.CharacterID), formatFloat(h.OverallScore), formatFloat(h.TankScore),
+			formatFloat(h.DPSScore), formatFloat(h.HealScore), strconv.FormatInt(h.DateRecorded, 10)})
+	}
+
+	if data, err = writeCSV(historyColumns, rows); err != nil {
+		return nil, err
+	}
+
+	return append(files, File{Name: historyFileName, ContentType: csvContentType, Data: data}), nil
+}
+
+func writeCSV(header []string, rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write csv: %w", err)
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write csv: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeCSV reads a characters CSV, matching columns by header so they can be in any order.
+//
+// Rows that can't be parsed are returned as RowErrors rather than failing the whole import.
+func decodeCSV(r io.Reader) ([]record, []RowError, error) {
+	var records []record
+	invalid, err := readCSV(r, []string{"id", "name", "realm"}, func(row int, p *fieldParser) error {
+		c, err := parseCharacter(p)
+		if err == nil {
+			records = append(records, record{row: row, character: c})
+		}
+		return err
+	})
+
+	return records, invalid, err
+}
+
+// decodeHistoryCSV reads a score history CSV, like decodeCSV.
+func decodeHistoryCSV(r io.Reader) ([]db.ScoreHistory, []RowError, error) {
+	var history []db.ScoreHistory
+	invalid, err := readCSV(r, []string{"character_id", "date_recorded"}, func(_ int, p *fieldParser) error {
+		h, err := parseHistory(p)
+		if err == nil {
+			history = append(history, h)
+		}
+		return err
+	})
+
+	return history, invalid, err
+}
+
+// readCSV calls parse with each row of a CSV, matching columns by header so they can be in any order. Rows that can't
+// be read or parsed are returned as RowErrors.
+func readCSV(r io.Reader, required []string, parse func(row int, p *fieldParser) error) ([]RowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, column := range required {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, column)
+		}
+	}
+
+	var invalid []RowError
+	// Row numbers start at 2 so they line up with the line in the file after the header.
+	for row := 2; ; row++ {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil {
+			err = parse(row, &fieldParser{columns: columns, fields: fields})
+		}
+		if err != nil {
+			invalid = append(invalid, RowError{Row: row, Err: err})
+		}
+	}
+
+	return invalid, nil
+}
+
+func parseCharacter(p *fieldParser) (db.Character, error) {
+	c := db.Character{
+		ID:           p.int("id"),
+		Name:         p.string("name"),
+		Realm:        p.string("realm"),
+		Class:        p.string("class"),
+		OverallScore: p.float("score"),
+		TankScore:    p.float("tank_score"),
+		DPSScore:     p.float("dps_score"),
+		HealScore:    p.float("heal_score"),
+		DateUpdated:  p.int64("date_updated"),
+		DateCreated:  p.int64("date_created"),
+	}
+
+	return c, p.err
+}
+
+func parseHistory(p *fieldParser) (db.ScoreHistory, error) {
+	h := db.ScoreHistory{
+		CharacterID:  p.int("character_id"),
+		OverallScore: p.float("score"),
+		TankScore:    p.float("tank_score"),
+		DPSScore:     p.float("dps_score"),
+		HealScore:    p.float("heal_score"),
+		DateRecorded: p.int64("date_recorded"),
+	}
+
+	return h, p.err
+}
+
+// fieldParser reads optional columns from a CSV row, keeping the first parse error.
+type fieldParser struct {
+	columns map[string]int
+	fields  []string
+	err     error
+}
+
+func (p *fieldParser) string(column string) string {
+	i, ok := p.columns[column]
+	if !ok || i >= len(p.fields) {
+		return ""
+	}
+	return strings.TrimSpace(p.fields[i])
+}
+
+func (p *fieldParser) int(column string) int {
+	return int(p.int64(column))
+}
+
+func (p *fieldParser) int64(column string) int64 {
+	v := p.string(column)
+	if v == "" {
+		return 0
+	}
+
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("%w: %s is not a whole number", ErrInvalidRow, column)
+	}
+	return n
+}
+
+func (p *fieldParser) float(column string) float64 {
+	v := p.string(column)
+	if v == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("%w: %s is not a number", ErrInvalidRow, column)
+	}
+	return f
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
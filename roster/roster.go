@@ -0,0 +1,319 @@
+// Package roster handles exporting and importing the tracked characters.
+//
+// Exports can be used as a human-readable backup or to move the roster between bot instances. Imports validate each
+// row and upsert it by character ID, reporting any rows that conflict with characters already being tracked.
+package roster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+)
+
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format")
+	ErrInvalidRow    = errors.New("invalid row")
+	ErrMissingColumn = errors.New("missing required column")
+)
+
+type (
+	CharacterRepository interface {
+		ListCharacters(ctx context.Context, limit int) ([]db.Character, error)
+		GetCharacter(ctx context.Context, name, realm string) (db.Character, error)
+		GetCharacterByID(ctx context.Context, id int) (db.Character, error)
+		Upsert(ctx context.Context, character *db.Character) error
+	}
+
+	ScoreHistoryRepository interface {
+		List(ctx context.Context) ([]db.ScoreHistory, error)
+		Insert(ctx context.Context, entry *db.ScoreHistory) error
+	}
+
+	// Store runs imports in a transaction, the repositories it passes to fn write in it so the whole import is rolled
+	// back if fn returns an error.
+	Store interface {
+		WithTx(ctx context.Context, fn func(characters CharacterRepository, history ScoreHistoryRepository) error) error
+	}
+
+	// File is a single exported file, ready to be written to disk or attached to a message.
+	File struct {
+		Name        string
+		ContentType string
+		Data        []byte
+	}
+
+	// Document is the JSON representation of an export.
+	Document struct {
+		Characters []db.Character    `json:"characters"`
+		History    []db.ScoreHistory `json:"history,omitempty"`
+	}
+
+	// RowError describes a row that failed validation and was skipped.
+	RowError struct {
+		Row int
+		Err error
+	}
+
+	// Conflict describes a row that was skipped because it clashes with a character that is already tracked.
+	Conflict struct {
+		Row      int
+		Imported db.Character
+		Existing db.Character
+		Reason   string
+	}
+
+	// record is a decoded character along with where it came from in the import, for reporting.
+	record struct {
+		row       int
+		character db.Character
+	}
+
+	// Result reports what an import did.
+	Result struct {
+		Inserted        int
+		Updated         int
+		HistoryImported int
+		Conflicts       []Conflict
+		Invalid         []RowError
+		// InvalidHistory holds the rows of a CSV score history file that couldn't be parsed
+		InvalidHistory []RowError
+	}
+)
+
+// Service exports and imports the roster using the injected repositories.
+type Service struct {
+	characters CharacterRepository
+	history    ScoreHistoryRepository
+	store      Store
+}
+
+// NewService creates a new roster service
+func NewService(characters CharacterRepository, history ScoreHistoryRepository, store Store) *Service {
+	return &Service{
+		characters: characters,
+		history:    history,
+		store:      store,
+	}
+}
+
+// ParseFormat converts user input into a Format.
+func ParseFormat(format string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(format))); f {
+	case FormatJSON, FormatCSV:
+		return f, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// Export returns the tracked characters, and optionally their score history, encoded in the requested format.
+//
+// JSON exports are a single file, CSV exports have one file per table.
+func (s *Service) Export(ctx context.Context, format Format, includeHistory bool) ([]File, error) {
+	characters, err := s.characters.ListCharacters(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list characters: %w", err)
+	}
+
+	var history []db.ScoreHistory
+	if includeHistory {
+		if history, err = s.history.List(ctx); err != nil {
+			return nil, fmt.Errorf("failed to list score history: %w", err)
+		}
+	}
+
+	switch format {
+	case FormatJSON:
+		return encodeJSON(Document{Characters: characters, History: history})
+	case FormatCSV:
+		return encodeCSV(characters, history, includeHistory)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// Import reads an export and upserts every valid character by ID.
+//
+// CSV exports keep the score history in a separate file, which is read from historyReader if it isn't nil. JSON exports
+// include it in the one file.
+//
+// Rows that fail validation or conflict with an existing character are skipped and reported in the Result, only
+// database errors abort the import. The import is written in one transaction, so nothing is imported if it aborts.
+func (s *Service) Import(ctx context.Context, format Format, r io.Reader, historyReader io.Reader) (Result, error) {
+	var (
+		records        []record
+		history        []db.ScoreHistory
+		invalid        []RowError
+		invalidHistory []RowError
+		err            error
+	)
+	switch format {
+	case FormatJSON:
+		records, history, err = decodeJSON(r)
+	case FormatCSV:
+		records, invalid, err = decodeCSV(r)
+		if err == nil && historyReader != nil {
+			history, invalidHistory, err = decodeHistoryCSV(historyReader)
+		}
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Invalid: invalid, InvalidHistory: invalidHistory}
+	err = s.store.WithTx(ctx, func(characters CharacterRepository, historyRepo ScoreHistoryRepository) error {
+		return importRecords(ctx, characters, historyRepo, records, history, &result)
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+// importRecords upserts the valid records and the history for the characters it wrote, adding what it did to result.
+func importRecords(ctx context.Context, characters CharacterRepository, historyRepo ScoreHistoryRepository,
+	records []record, history []db.ScoreHistory, result *Result,
+) error {
+	seen := make(map[int]int, len(records))
+	imported := make(map[int]bool, len(records))
+	for _, rec := range records {
+		c := normalise(rec.character)
+		if err := validate(c); err != nil {
+			result.Invalid = append(result.Invalid, RowError{Row: rec.row, Err: err})
+			continue
+		}
+
+		if first, ok := seen[c.ID]; ok {
+			result.Conflicts = append(result.Conflicts, Conflict{
+				Row:      rec.row,
+				Imported: c,
+				Reason:   fmt.Sprintf("duplicate of row %d", first),
+			})
+			continue
+		}
+		seen[c.ID] = rec.row
+
+		ok, err := importCharacter(ctx, characters, rec.row, c, result)
+		if err != nil {
+			return err
+		}
+		imported[c.ID] = ok
+	}
+
+	// History is only kept for characters this import wrote, so rows that were skipped don't leave orphaned history
+	// or add history to a different character tracked under the same ID
+	for _, h := range history {
+		if !imported[h.CharacterID] || h.DateRecorded <= 0 {
+			continue
+		}
+		if err := historyRepo.Insert(ctx, &h); err != nil {
+			return fmt.Errorf("failed to import score history: %w", err)
+		}
+		result.HistoryImported++
+	}
+
+	return nil
+}
+
+// importCharacter upserts the character, returning false if it was skipped as a conflict.
+func importCharacter(ctx context.Context, characters CharacterRepository, row int, c db.Character,
+	result *Result,
+) (bool, error) {
+	// The same character tracked under a different ID would leave two rows that name/realm commands can't tell apart.
+	byName, err := characters.GetCharacter(ctx, c.Name, c.Realm)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up %s-%s: %w", c.Name, c.Realm, err)
+	}
+	if !byName.IsEmpty() && byName.ID != c.ID {
+		result.Conflicts = append(result.Conflicts, Conflict{
+			Row:      row,
+			Imported: c,
+			Existing: byName,
+			Reason:   fmt.Sprintf("already tracked with id %d", byName.ID),
+		})
+		return false, nil
+	}
+
+	existing, err := characters.GetCharacterByID(ctx, c.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up character %d: %w", c.ID, err)
+	}
+
+	now := time.Now().Unix()
+	if c.DateCreated == 0 {
+		c.DateCreated = now
+	}
+	if c.DateUpdated == 0 {
+		c.DateUpdated = now
+	}
+
+	if err := characters.Upsert(ctx, &c); err != nil {
+		return false, fmt.Errorf("failed to import %s-%s: %w", c.Name, c.Realm, err)
+	}
+
+	if existing.IsEmpty() {
+		result.Inserted++
+	} else {
+		result.Updated++
+	}
+	return true, nil
+}
+
+// normalise tidies up hand-edited rows so they match what the bot stores.
+func normalise(c db.Character) db.Character {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Realm = strings.ToLower(strings.TrimSpace(c.Realm))
+	c.Class = strings.TrimSpace(c.Class)
+	return c
+}
+
+func validate(c db.Character) error {
+	switch {
+	case c.ID <= 0:
+		return fmt.Errorf("%w: id must be positive", ErrInvalidRow)
+	case c.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidRow)
+	case c.Realm == "":
+		return fmt.Errorf("%w: realm is required", ErrInvalidRow)
+	case c.OverallScore < 0 || c.TankScore < 0 || c.DPSScore < 0 || c.HealScore < 0:
+		return fmt.Errorf("%w: scores can't be negative", ErrInvalidRow)
+	}
+	return nil
+}
+
+// Summary formats the result for displaying to a user.
+func (r Result) Summary() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "Imported %d new and updated %d existing characters", r.Inserted, r.Updated)
+	if r.HistoryImported > 0 {
+		fmt.Fprintf(&s, " with %d score history entries", r.HistoryImported)
+	}
+	s.WriteString(".")
+
+	for _, c := range r.Conflicts {
+		fmt.Fprintf(&s, "\nConflict on row %d (%s-%s): %s", c.Row, c.Imported.Name, c.Imported.Realm, c.Reason)
+	}
+	for _, e := range r.Invalid {
+		fmt.Fprintf(&s, "\nSkipped row %d: %s", e.Row, e.Err)
+	}
+	for _, e := range r.InvalidHistory {
+		fmt.Fprintf(&s, "\nSkipped score history row %d: %s", e.Row, e.Err)
+	}
+
+	return s.String()
+}
@@ -0,0 +1,343 @@
+package roster
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations for testing
+
+type MockCharacterRepository struct {
+	mock.Mock
+}
+
+func (m *MockCharacterRepository) ListCharacters(ctx context.Context, limit int) ([]db.Character, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]db.Character), args.Error(1)
+}
+
+func (m *MockCharacterRepository) GetCharacter(ctx context.Context, name, realm string) (db.Character, error) {
+	args := m.Called(ctx, name, realm)
+	return args.Get(0).(db.Character), args.Error(1)
+}
+
+func (m *MockCharacterRepository) GetCharacterByID(ctx context.Context, id int) (db.Character, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(db.Character), args.Error(1)
+}
+
+func (m *MockCharacterRepository) Upsert(ctx context.Context, character *db.Character) error {
+	args := m.Called(ctx, character)
+	return args.Error(0)
+}
+
+type MockScoreHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockScoreHistoryRepository) List(ctx context.Context) ([]db.ScoreHistory, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]db.ScoreHistory), args.Error(1)
+}
+
+func (m *MockScoreHistoryRepository) Insert(ctx context.Context, entry *db.ScoreHistory) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+// MockStore runs fn with the mock repositories, returning its error as a rolled back transaction would.
+type MockStore struct {
+	characters *MockCharacterRepository
+	history    *MockScoreHistoryRepository
+	calls      int
+}
+
+func (m *MockStore) WithTx(_ context.Context, fn func(characters CharacterRepository, history ScoreHistoryRepository) error) error {
+	m.calls++
+	return fn(m.characters, m.history)
+}
+
+func setupService() (*Service, *MockCharacterRepository, *MockScoreHistoryRepository) {
+	characters := &MockCharacterRepository{}
+	history := &MockScoreHistoryRepository{}
+	return NewService(characters, history, &MockStore{characters: characters, history: history}), characters, history
+}
+
+var testCharacters = []db.Character{
+	{ID: 1, Name: "Paladylan", Realm: "tichondrius", Class: "Paladin", OverallScore: 2500.5, TankScore: 2400,
+		DateUpdated: 1700000000, DateCreated: 1600000000},
+	{ID: 2, Name: "Magedylan", Realm: "area-52", Class: "Mage", OverallScore: 2300, DPSScore: 2300,
+		DateUpdated: 1700000000, DateCreated: 1600000000},
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat(" JSON ")
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSON, f)
+
+	f, err = ParseFormat("csv")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, f)
+
+	_, err = ParseFormat("xml")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestService_Export_JSON(t *testing.T) {
+	service, characters, history := setupService()
+	ctx := context.Background()
+
+	entries := []db.ScoreHistory{{CharacterID: 1, OverallScore: 2400, DateRecorded: 1650000000}}
+	characters.On("ListCharacters", ctx, 0).Return(testCharacters, nil)
+	history.On("List", ctx).Return(entries, nil)
+
+	files, err := service.Export(ctx, FormatJSON, true)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "roster.json", files[0].Name)
+	assert.Equal(t, "application/json", files[0].ContentType)
+	assert.Contains(t, string(files[0].Data), `"name": "Paladylan"`)
+	assert.Contains(t, string(files[0].Data), `"date_recorded": 1650000000`)
+	characters.AssertExpectations(t)
+	history.AssertExpectations(t)
+}
+
+func TestService_Export_CSVWithoutHistory(t *testing.T) {
+	service, characters, history := setupService()
+	ctx := context.Background()
+
+	characters.On("ListCharacters", ctx, 0).Return(testCharacters, nil)
+
+	files, err := service.Export(ctx, FormatCSV, false)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "characters.csv", files[0].Name)
+	assert.Equal(t, "id,name,realm,class,score,tank_score,dps_score,heal_score,date_updated,date_created\n"+
+		"1,Paladylan,tichondrius,Paladin,2500.5,2400,0,0,1700000000,1600000000\n"+
+		"2,Magedylan,area-52,Mage,2300,0,2300,0,1700000000,1600000000\n", string(files[0].Data))
+	history.AssertNotCalled(t, "List")
+}
+
+func TestService_Export_CSVWithHistory(t *testing.T) {
+	service, characters, history := setupService()
+	ctx := context.Background()
+
+	characters.On("ListCharacters", ctx, 0).Return(testCharacters, nil)
+	history.On("List", ctx).Return([]db.ScoreHistory{{CharacterID: 1, OverallScore: 2400, DateRecorded: 1650000000}}, nil)
+
+	files, err := service.Export(ctx, FormatCSV, true)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "score_history.csv", files[1].Name)
+	assert.Equal(t, "character_id,score,tank_score,dps_score,heal_score,date_recorded\n1,2400,0,0,0,1650000000\n",
+		string(files[1].Data))
+}
+
+func TestService_Export_ListError(t *testing.T) {
+	service, characters, _ := setupService()
+	ctx := context.Background()
+
+	characters.On("ListCharacters", ctx, 0).Return([]db.Character(nil), errors.New("database error"))
+
+	files, err := service.Export(ctx, FormatJSON, false)
+	assert.Error(t, err)
+	assert.Nil(t, files)
+}
+
+func TestService_Import_JSONRoundTrip(t *testing.T) {
+	service, characters, history := setupService()
+	ctx := context.Background()
+
+	characters.On("ListCharacters", ctx, 0).Return(testCharacters, nil)
+	history.On("List", ctx).Return([]db.ScoreHistory{{CharacterID: 1, OverallScore: 2400, DateRecorded: 1650000000}}, nil)
+	files, err := service.Export(ctx, FormatJSON, true)
+	require.NoError(t, err)
+
+	// Character 1 is new, character 2 is already tracked
+	characters.On("GetCharacter", ctx, "Paladylan", "tichondrius").Return(db.Character{}, nil)
+	characters.On("GetCharacter", ctx, "Magedylan", "area-52").Return(testCharacters[1], nil)
+	characters.On("GetCharacterByID", ctx, 1).Return(db.Character{}, nil)
+	characters.On("GetCharacterByID", ctx, 2).Return(testCharacters[1], nil)
+	characters.On("Upsert", ctx, mock.AnythingOfType("*db.Character")).Return(nil).Twice()
+	history.On("Insert", ctx, mock.MatchedBy(func(h *db.ScoreHistory) bool {
+		return h.CharacterID == 1 && h.DateRecorded == 1650000000
+	})).Return(nil)
+
+	result, err := service.Import(ctx, FormatJSON, strings.NewReader(string(files[0].Data)), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Inserted)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.HistoryImported)
+	assert.Empty(t, result.Conflicts)
+	assert.Empty(t, result.Invalid)
+	characters.AssertExpectations(t)
+	history.AssertExpectations(t)
+}
+
+func TestService_Import_CSVValidationAndConflicts(t *testing.T) {
+	service, characters, _ := setupService()
+	ctx := context.Background()
+
+	input := "name,realm,id,score\n" +
+		"Paladylan, Tichondrius ,1,2500\n" + // valid, realm is normalised
+		"Nobody,tichondrius,0,100\n" + // invalid id
+		"Broken,tichondrius,3,lots\n" + // unparseable score
+		"Paladylan,tichondrius,1,2600\n" + // duplicate id in the file
+		"Magedylan,area-52,4,2300\n" // tracked under a different id
+
+	characters.On("GetCharacter", ctx, "Paladylan", "tichondrius").Return(db.Character{}, nil)
+	characters.On("GetCharacterByID", ctx, 1).Return(db.Character{}, nil)
+	characters.On("Upsert", ctx, mock.MatchedBy(func(c *db.Character) bool {
+		return c.ID == 1 && c.Realm == "tichondrius" && c.OverallScore == 2500 && c.DateCreated != 0
+	})).Return(nil).Once()
+	characters.On("GetCharacter", ctx, "Magedylan", "area-52").Return(testCharacters[1], nil)
+
+	result, err := service.Import(ctx, FormatCSV, strings.NewReader(input), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Inserted)
+	assert.Equal(t, 0, result.Updated)
+
+	require.Len(t, result.Invalid, 2)
+	assert.Equal(t, 4, result.Invalid[0].Row) // parse errors are found while reading the file
+	assert.Equal(t, 3, result.Invalid[1].Row)
+	assert.ErrorIs(t, result.Invalid[1].Err, ErrInvalidRow)
+
+	require.Len(t, result.Conflicts, 2)
+	assert.Equal(t, 5, result.Conflicts[0].Row)
+	assert.Equal(t, "duplicate of row 2", result.Conflicts[0].Reason)
+	assert.Equal(t, 6, result.Conflicts[1].Row)
+	assert.Equal(t, "already tracked with id 2", result.Conflicts[1].Reason)
+	characters.AssertExpectations(t)
+}
+
+func TestService_Import_CSVRoundTripWithHistory(t *testing.T) {
+	service, characters, history := setupService()
+	ctx := context.Background()
+
+	characters.On("ListCharacters", ctx, 0).Return(testCharacters[:1], nil)
+	history.On("List", ctx).Return([]db.ScoreHistory{{CharacterID: 1, OverallScore: 2400, DateRecorded: 1650000000}}, nil)
+	files, err := service.Export(ctx, FormatCSV, true)
+	require.NoError(t, err)
+
+	characters.On("GetCharacter", ctx, "Paladylan", "tichondrius").Return(db.Character{}, nil)
+	characters.On("GetCharacterByID", ctx, 1).Return(db.Character{}, nil)
+	characters.On("Upsert", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+	history.On("Insert", ctx, &db.ScoreHistory{CharacterID: 1, OverallScore: 2400, DateRecorded: 1650000000}).Return(nil)
+
+	// The broken history row is reported without stopping the rest of the import
+	historyCSV := string(files[1].Data) + "1,lots,0,0,0,1660000000\n"
+	result, err := service.Import(ctx, FormatCSV, strings.NewReader(string(files[0].Data)),
+		strings.NewReader(historyCSV))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Inserted)
+	assert.Equal(t, 1, result.HistoryImported)
+	require.Len(t, result.InvalidHistory, 1)
+	assert.Equal(t, 3, result.InvalidHistory[0].Row)
+	history.AssertExpectations(t)
+}
+
+func TestService_Import_CSVHistoryMissingColumn(t *testing.T) {
+	service, _, _ := setupService()
+
+	_, err := service.Import(context.Background(), FormatCSV, strings.NewReader("id,name,realm\n"),
+		strings.NewReader("score\n2400\n"))
+	assert.ErrorIs(t, err, ErrMissingColumn)
+}
+
+func TestService_Import_HistoryOnlyForImportedCharacters(t *testing.T) {
+	service, characters, history := setupService()
+	ctx := context.Background()
+
+	input := `{"characters": [
+		{"id": 1, "name": "Paladylan", "realm": "tichondrius"},
+		{"id": 0, "name": "Nobody", "realm": "tichondrius"},
+		{"id": 4, "name": "Magedylan", "realm": "area-52"}
+	], "history": [
+		{"character_id": 1, "score": 2400, "date_recorded": 1650000000},
+		{"character_id": 0, "score": 100, "date_recorded": 1650000000},
+		{"character_id": 4, "score": 2200, "date_recorded": 1650000000},
+		{"character_id": 9, "score": 2000, "date_recorded": 1650000000}
+	]}`
+
+	characters.On("GetCharacter", ctx, "Paladylan", "tichondrius").Return(db.Character{}, nil)
+	characters.On("GetCharacterByID", ctx, 1).Return(db.Character{}, nil)
+	characters.On("Upsert", ctx, mock.AnythingOfType("*db.Character")).Return(nil).Once()
+	// Magedylan is tracked under a different id, so is skipped along with their history
+	characters.On("GetCharacter", ctx, "Magedylan", "area-52").Return(testCharacters[1], nil)
+	history.On("Insert", ctx, mock.MatchedBy(func(h *db.ScoreHistory) bool {
+		return h.CharacterID == 1
+	})).Return(nil).Once()
+
+	result, err := service.Import(ctx, FormatJSON, strings.NewReader(input), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.HistoryImported)
+	history.AssertExpectations(t)
+}
+
+func TestService_Import_CSVMissingColumn(t *testing.T) {
+	service, _, _ := setupService()
+
+	_, err := service.Import(context.Background(), FormatCSV, strings.NewReader("name,realm\nPaladylan,tichondrius\n"),
+		nil)
+	assert.ErrorIs(t, err, ErrMissingColumn)
+}
+
+func TestService_Import_UpsertError(t *testing.T) {
+	service, characters, _ := setupService()
+	ctx := context.Background()
+
+	characters.On("GetCharacter", ctx, "Paladylan", "tichondrius").Return(db.Character{}, nil)
+	characters.On("GetCharacterByID", ctx, 1).Return(db.Character{}, nil)
+	characters.On("Upsert", ctx, mock.AnythingOfType("*db.Character")).Return(errors.New("database error"))
+
+	_, err := service.Import(ctx, FormatCSV, strings.NewReader("id,name,realm\n1,Paladylan,tichondrius\n"), nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to import Paladylan-tichondrius")
+}
+
+func TestService_Import_HistoryErrorAbortsImport(t *testing.T) {
+	service, characters, history := setupService()
+	ctx := context.Background()
+
+	input := `{"characters": [{"id": 1, "name": "Paladylan", "realm": "tichondrius"}],
+		"history": [{"character_id": 1, "score": 2400, "date_recorded": 1650000000}]}`
+
+	characters.On("GetCharacter", ctx, "Paladylan", "tichondrius").Return(db.Character{}, nil)
+	characters.On("GetCharacterByID", ctx, 1).Return(db.Character{}, nil)
+	characters.On("Upsert", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+	history.On("Insert", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(errors.New("database error"))
+
+	// The character and history are written in the same transaction, so the error rolls back the character too
+	result, err := service.Import(ctx, FormatJSON, strings.NewReader(input), nil)
+	assert.ErrorContains(t, err, "failed to import score history")
+	assert.Equal(t, Result{}, result)
+	assert.Equal(t, 1, service.store.(*MockStore).calls)
+}
+
+func TestResult_Summary(t *testing.T) {
+	result := Result{
+		Inserted:        2,
+		Updated:         1,
+		HistoryImported: 5,
+		Conflicts: []Conflict{{
+			Row:      3,
+			Imported: db.Character{Name: "Paladylan", Realm: "tichondrius"},
+			Reason:   "already tracked with id 7",
+		}},
+		Invalid:        []RowError{{Row: 4, Err: ErrInvalidRow}},
+		InvalidHistory: []RowError{{Row: 2, Err: ErrInvalidRow}},
+	}
+
+	assert.Equal(t, "Imported 2 new and updated 1 existing characters with 5 score history entries.\n"+
+		"Conflict on row 3 (Paladylan-tichondrius): already tracked with id 7\n"+
+		"Skipped row 4: invalid row\n"+
+		"Skipped score history row 2: invalid row", result.Summary())
+}
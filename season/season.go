@@ -0,0 +1,127 @@
+// Package season handles results from previous mythic+ seasons.
+//
+// Blizzard keeps a character's results for every season they have played, the backfiller copies the final rating
+// from each finished season into the database so they aren't lost once the character stops playing.
+package season
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/blizzard"
+	"github.com/DylanNZL/mythicplusbot/db"
+)
+
+type (
+	CharacterRepository interface {
+		ListCharacters(ctx context.Context, limit int) ([]db.Character, error)
+	}
+
+	SeasonRatingRepository interface {
+		Upsert(ctx context.Context, rating *db.SeasonRating) error
+	}
+
+	BlizzardClient interface {
+		GetMythicKeystoneProfile(ctx context.Context, realm string, character string) (*blizzard.MythicKeystoneProfile, error)
+		GetMythicKeystoneSeason(ctx context.Context, realm string, character string, seasonID int) (*blizzard.MythicKeystoneSeason, error)
+		GetCurrentSeasonID(ctx context.Context) (int, error)
+	}
+
+	// Result summarises a backfill.
+	Result struct {
+		Characters int
+		Seasons    int
+		Failed     int
+	}
+)
+
+// Summary describes the result for the command line.
+func (r Result) Summary() string {
+	return fmt.Sprintf("backfilled %d seasons for %d characters, %d characters failed", r.Seasons, r.Characters,
+		r.Failed)
+}
+
+// Backfiller records the final ratings of previous seasons.
+type Backfiller struct {
+	characterRepo  CharacterRepository
+	ratingRepo     SeasonRatingRepository
+	blizzardClient BlizzardClient
+	now            func() time.Time
+}
+
+// NewBackfiller creates a new backfiller with dependencies
+func NewBackfiller(characterRepo CharacterRepository, ratingRepo SeasonRatingRepository,
+	blizzardClient BlizzardClient,
+) *Backfiller {
+	return &Backfiller{
+		characterRepo:  characterRepo,
+		ratingRepo:     ratingRepo,
+		blizzardClient: blizzardClient,
+		now:            time.Now,
+	}
+}
+
+// Backfill stores the final rating of every finished season for each tracked character.
+//
+// The season that is currently running is skipped as its rating can still change. Characters that fail are logged
+// and counted, the rest are still backfilled.
+func (b *Backfiller) Backfill(ctx context.Context) (Result, error) {
+	current, err := b.blizzardClient.GetCurrentSeasonID(ctx)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to get current season: %w", err)
+	}
+
+	characters, err := b.characterRepo.ListCharacters(ctx, 0)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, c := range characters {
+		seasons, err := b.backfillCharacter(ctx, c, current)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to backfill seasons", "error", err, "character", c.Name, "realm", c.Realm)
+			result.Failed++
+			continue
+		}
+
+		result.Characters++
+		result.Seasons += seasons
+	}
+
+	return result, nil
+}
+
+func (b *Backfiller) backfillCharacter(ctx context.Context, c db.Character, current int) (int, error) {
+	profile, err := b.blizzardClient.GetMythicKeystoneProfile(ctx, c.Realm, c.Name)
+	if err != nil {
+		return 0, err
+	}
+
+	var finished []int
+	for _, id := range profile.SeasonIDs() {
+		if id != current {
+			finished = append(finished, id)
+		}
+	}
+
+	for _, id := range finished {
+		s, err := b.blizzardClient.GetMythicKeystoneSeason(ctx, c.Realm, c.Name, id)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := b.ratingRepo.Upsert(ctx, &db.SeasonRating{
+			CharacterID:  c.ID,
+			SeasonID:     id,
+			Rating:       s.MythicRating.Rating,
+			DateRecorded: b.now().Unix(),
+		}); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(finished), nil
+}
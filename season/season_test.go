@@ -0,0 +1,218 @@
+package season
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/blizzard"
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations for testing
+
+type MockCharacterRepository struct {
+	mock.Mock
+}
+
+func (m *MockCharacterRepository) ListCharacters(ctx context.Context, limit int) ([]db.Character, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]db.Character), args.Error(1)
+}
+
+type MockSeasonRatingRepository struct {
+	mock.Mock
+}
+
+func (m *MockSeasonRatingRepository) Upsert(ctx context.Context, rating *db.SeasonRating) error {
+	args := m.Called(ctx, rating)
+	return args.Error(0)
+}
+
+type MockBlizzardClient struct {
+	mock.Mock
+}
+
+func (m *MockBlizzardClient) GetMythicKeystoneProfile(ctx context.Context, realm, character string) (*blizzard.MythicKeystoneProfile, error) {
+	args := m.Called(ctx, realm, character)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*blizzard.MythicKeystoneProfile), args.Error(1)
+}
+
+func (m *MockBlizzardClient) GetMythicKeystoneSeason(ctx context.Context, realm, character string, seasonID int) (*blizzard.MythicKeystoneSeason, error) {
+	args := m.Called(ctx, realm, character, seasonID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*blizzard.MythicKeystoneSeason), args.Error(1)
+}
+
+func (m *MockBlizzardClient) GetCurrentSeasonID(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+// Test helpers
+
+var testNow = time.Unix(1700000000, 0)
+
+func setupBackfiller() (*Backfiller, *MockCharacterRepository, *MockSeasonRatingRepository, *MockBlizzardClient) {
+	characterRepo := &MockCharacterRepository{}
+	ratingRepo := &MockSeasonRatingRepository{}
+	blizzardClient := &MockBlizzardClient{}
+
+	backfiller := NewBackfiller(characterRepo, ratingRepo, blizzardClient)
+	backfiller.now = func() time.Time { return testNow }
+
+	return backfiller, characterRepo, ratingRepo, blizzardClient
+}
+
+func createTestProfile(t *testing.T, seasonIDs ...int) *blizzard.MythicKeystoneProfile {
+	t.Helper()
+
+	seasons := make([]map[string]int, 0, len(seasonIDs))
+	for _, id := range seasonIDs {
+		seasons = append(seasons, map[string]int{"id": id})
+	}
+	data, err := json.Marshal(map[string]any{"seasons": seasons})
+	require.NoError(t, err)
+
+	var profile blizzard.MythicKeystoneProfile
+	require.NoError(t, json.Unmarshal(data, &profile))
+	return &profile
+}
+
+func createTestSeason(id int, rating float64) *blizzard.MythicKeystoneSeason {
+	s := &blizzard.MythicKeystoneSeason{MythicRating: blizzard.Rating{Rating: rating}}
+	s.Season.ID = id
+	return s
+}
+
+// Tests
+
+func TestBackfiller_Backfill_Success(t *testing.T) {
+	backfiller, characterRepo, ratingRepo, blizzardClient := setupBackfiller()
+	ctx := context.Background()
+
+	characters := []db.Character{
+		{ID: 1, Name: "Paladylan", Realm: "tichondrius"},
+		{ID: 2, Name: "Magedylan", Realm: "area-52"},
+	}
+	characterRepo.On("ListCharacters", ctx, 0).Return(characters, nil)
+	blizzardClient.On("GetCurrentSeasonID", ctx).Return(13, nil)
+
+	// The current season is still running so only 11 and 12 are backfilled
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "tichondrius", "Paladylan").
+		Return(createTestProfile(t, 13, 11, 12), nil)
+	blizzardClient.On("GetMythicKeystoneSeason", ctx, "tichondrius", "Paladylan", 11).
+		Return(createTestSeason(11, 2100), nil)
+	blizzardClient.On("GetMythicKeystoneSeason", ctx, "tichondrius", "Paladylan", 12).
+		Return(createTestSeason(12, 2800.5), nil)
+	ratingRepo.On("Upsert", ctx, &db.SeasonRating{CharacterID: 1, SeasonID: 11, Rating: 2100,
+		DateRecorded: testNow.Unix()}).Return(nil)
+	ratingRepo.On("Upsert", ctx, &db.SeasonRating{CharacterID: 1, SeasonID: 12, Rating: 2800.5,
+		DateRecorded: testNow.Unix()}).Return(nil)
+
+	// A character that has only played the current season has nothing to backfill
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "area-52", "Magedylan").
+		Return(createTestProfile(t, 13), nil)
+
+	result, err := backfiller.Backfill(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, Result{Characters: 2, Seasons: 2}, result)
+	characterRepo.AssertExpectations(t)
+	ratingRepo.AssertExpectations(t)
+	blizzardClient.AssertExpectations(t)
+}
+
+func TestBackfiller_Backfill_CharacterError(t *testing.T) {
+	backfiller, characterRepo, ratingRepo, blizzardClient := setupBackfiller()
+	ctx := context.Background()
+
+	characters := []db.Character{
+		{ID: 1, Name: "Paladylan", Realm: "tichondrius"},
+		{ID: 2, Name: "Magedylan", Realm: "area-52"},
+	}
+	characterRepo.On("ListCharacters", ctx, 0).Return(characters, nil)
+	blizzardClient.On("GetCurrentSeasonID", ctx).Return(13, nil)
+
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "tichondrius", "Paladylan").
+		Return(createTestProfile(t, 12, 13), nil)
+	blizzardClient.On("GetMythicKeystoneSeason", ctx, "tichondrius", "Paladylan", 12).
+		Return(nil, errors.New("API error"))
+
+	// One character failing doesn't stop the rest
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "area-52", "Magedylan").
+		Return(createTestProfile(t, 12, 13), nil)
+	blizzardClient.On("GetMythicKeystoneSeason", ctx, "area-52", "Magedylan", 12).
+		Return(createTestSeason(12, 3000), nil)
+	ratingRepo.On("Upsert", ctx, mock.AnythingOfType("*db.SeasonRating")).Return(nil)
+
+	result, err := backfiller.Backfill(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, Result{Characters: 1, Seasons: 1, Failed: 1}, result)
+	ratingRepo.AssertNumberOfCalls(t, "Upsert", 1)
+}
+
+func TestBackfiller_Backfill_StoppedPlaying(t *testing.T) {
+	backfiller, characterRepo, ratingRepo, blizzardClient := setupBackfiller()
+	ctx := context.Background()
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{{ID: 1, Name: "Paladylan", Realm: "tichondrius"}}, nil)
+	blizzardClient.On("GetCurrentSeasonID", ctx).Return(14, nil)
+
+	// The last season the character played has finished, so it is backfilled too
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "tichondrius", "Paladylan").
+		Return(createTestProfile(t, 12, 13), nil)
+	blizzardClient.On("GetMythicKeystoneSeason", ctx, "tichondrius", "Paladylan", 12).
+		Return(createTestSeason(12, 2800), nil)
+	blizzardClient.On("GetMythicKeystoneSeason", ctx, "tichondrius", "Paladylan", 13).
+		Return(createTestSeason(13, 2950), nil)
+	ratingRepo.On("Upsert", ctx, mock.AnythingOfType("*db.SeasonRating")).Return(nil)
+
+	result, err := backfiller.Backfill(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, Result{Characters: 1, Seasons: 2}, result)
+	blizzardClient.AssertExpectations(t)
+	ratingRepo.AssertNumberOfCalls(t, "Upsert", 2)
+}
+
+func TestBackfiller_Backfill_CurrentSeasonError(t *testing.T) {
+	backfiller, characterRepo, _, blizzardClient := setupBackfiller()
+	ctx := context.Background()
+
+	blizzardClient.On("GetCurrentSeasonID", ctx).Return(0, errors.New("API error"))
+
+	_, err := backfiller.Backfill(ctx)
+
+	assert.EqualError(t, err, "failed to get current season: API error")
+	characterRepo.AssertNotCalled(t, "ListCharacters", mock.Anything, mock.Anything)
+}
+
+func TestBackfiller_Backfill_ListError(t *testing.T) {
+	backfiller, characterRepo, _, blizzardClient := setupBackfiller()
+	ctx := context.Background()
+
+	blizzardClient.On("GetCurrentSeasonID", ctx).Return(13, nil)
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character(nil), errors.New("db error"))
+
+	_, err := backfiller.Backfill(ctx)
+
+	assert.EqualError(t, err, "db error")
+}
+
+func TestResult_Summary(t *testing.T) {
+	result := Result{Characters: 2, Seasons: 5, Failed: 1}
+
+	assert.Equal(t, "backfilled 5 seasons for 2 characters, 1 characters failed", result.Summary())
+}
@@ -0,0 +1,129 @@
+// Package threads keeps a Discord thread for each character's score updates, so a busy roster doesn't flood the
+// channel they are announced in.
+//
+// Threads are created the first time they are needed and reused after, until they are deleted or locked.
+package threads
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+)
+
+// Mode is what score updates get a thread for.
+type Mode string
+
+const (
+	// ModeOff posts score updates in the channel
+	ModeOff Mode = ""
+	// ModeCharacter gives each character a thread
+	ModeCharacter Mode = "character"
+	// ModeOwner gives each Discord user a thread for their linked characters, characters that aren't linked get
+	// their own thread
+	ModeOwner Mode = "owner"
+)
+
+type (
+	Repository interface {
+		GetThreadID(ctx context.Context, channelID, key string) (string, error)
+		SetThreadID(ctx context.Context, channelID, key, threadID string) error
+	}
+
+	LinkRepository interface {
+		GetUserID(ctx context.Context, characterID int) (string, error)
+	}
+
+	ThreadStarter interface {
+		StartThread(ctx context.Context, channelID, name string) (string, error)
+		ThreadUsable(ctx context.Context, threadID string) (bool, error)
+	}
+)
+
+// Service handles threads with injected dependencies
+type Service struct {
+	mode     Mode
+	repo     Repository
+	linkRepo LinkRepository
+	starter  ThreadStarter
+}
+
+// ParseMode checks the mode is one of the known modes.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(s); m {
+	case ModeOff, ModeCharacter, ModeOwner:
+		return m, nil
+	default:
+		return ModeOff, fmt.Errorf("unknown thread mode %q, expected %q or %q", s, ModeCharacter, ModeOwner)
+	}
+}
+
+// NewService creates a new thread service for the mode with dependencies
+func NewService(mode Mode, repo Repository, linkRepo LinkRepository, starter ThreadStarter) *Service {
+	return &Service{
+		mode:     mode,
+		repo:     repo,
+		linkRepo: linkRepo,
+		starter:  starter,
+	}
+}
+
+// ThreadID returns the thread under the channel to post the character's score updates in, starting one if it doesn't
+// have one yet. It returns "" when threads are off, so updates are posted in the channel.
+func (s *Service) ThreadID(ctx context.Context, channelID string, character db.Character) (string, error) {
+	if s.mode == ModeOff {
+		return "", nil
+	}
+
+	key, name, err := s.thread(ctx, character)
+	if err != nil {
+		return "", err
+	}
+
+	threadID, err := s.repo.GetThreadID(ctx, channelID, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to get thread: %w", err)
+	}
+
+	if threadID != "" {
+		usable, err := s.starter.ThreadUsable(ctx, threadID)
+		if err != nil {
+			return "", fmt.Errorf("failed to check thread %s: %w", threadID, err)
+		}
+		if usable {
+			return threadID, nil
+		}
+		slog.InfoContext(ctx, "thread was deleted or locked, starting a new one", "thread", threadID, "key", key)
+	}
+
+	threadID, err = s.starter.StartThread(ctx, channelID, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to start thread: %w", err)
+	}
+	if err := s.repo.SetThreadID(ctx, channelID, key, threadID); err != nil {
+		return "", fmt.Errorf("failed to save thread: %w", err)
+	}
+
+	return threadID, nil
+}
+
+// thread returns the key the character's thread is stored under and the name to start it with.
+func (s *Service) thread(ctx context.Context, character db.Character) (string, string, error) {
+	l := i18n.FromContext(ctx)
+	characterName := fmt.Sprintf("%s-%s", character.Name, character.Realm)
+
+	if s.mode == ModeOwner {
+		userID, err := s.linkRepo.GetUserID(ctx, character.ID)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get linked user: %w", err)
+		}
+		// The thread is named after the character it was started for, as the user's name isn't known
+		if userID != "" {
+			return "user:" + userID, l.T("Score updates for %s and alts", characterName), nil
+		}
+	}
+
+	return db.CharacterThreadKey(character.ID), l.T("Score updates for %s", characterName), nil
+}
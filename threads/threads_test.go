@@ -0,0 +1,172 @@
+package threads
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetThreadID(ctx context.Context, channelID, key string) (string, error) {
+	args := m.Called(ctx, channelID, key)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockRepository) SetThreadID(ctx context.Context, channelID, key, threadID string) error {
+	args := m.Called(ctx, channelID, key, threadID)
+	return args.Error(0)
+}
+
+type MockLinkRepository struct {
+	mock.Mock
+}
+
+func (m *MockLinkRepository) GetUserID(ctx context.Context, characterID int) (string, error) {
+	args := m.Called(ctx, characterID)
+	return args.String(0), args.Error(1)
+}
+
+type MockThreadStarter struct {
+	mock.Mock
+}
+
+func (m *MockThreadStarter) StartThread(ctx context.Context, channelID, name string) (string, error) {
+	args := m.Called(ctx, channelID, name)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockThreadStarter) ThreadUsable(ctx context.Context, threadID string) (bool, error) {
+	args := m.Called(ctx, threadID)
+	return args.Bool(0), args.Error(1)
+}
+
+var testCharacter = db.Character{ID: 7, Name: "Testchar", Realm: "testrealm"}
+
+func setupService(mode Mode) (*Service, *MockRepository, *MockLinkRepository, *MockThreadStarter) {
+	repo := &MockRepository{}
+	links := &MockLinkRepository{}
+	starter := &MockThreadStarter{}
+	return NewService(mode, repo, links, starter), repo, links, starter
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode("owner")
+	require.NoError(t, err)
+	assert.Equal(t, ModeOwner, mode)
+
+	mode, err = ParseMode("")
+	require.NoError(t, err)
+	assert.Equal(t, ModeOff, mode)
+
+	_, err = ParseMode("forum")
+	assert.Error(t, err)
+}
+
+func TestService_ThreadID_Off(t *testing.T) {
+	service, repo, _, starter := setupService(ModeOff)
+
+	threadID, err := service.ThreadID(context.Background(), "channel1", testCharacter)
+
+	require.NoError(t, err)
+	assert.Empty(t, threadID)
+	repo.AssertNotCalled(t, "GetThreadID", mock.Anything, mock.Anything, mock.Anything)
+	starter.AssertNotCalled(t, "StartThread", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_ThreadID_Reused(t *testing.T) {
+	service, repo, _, starter := setupService(ModeCharacter)
+	ctx := context.Background()
+
+	repo.On("GetThreadID", ctx, "channel1", "character:7").Return("thread1", nil)
+	starter.On("ThreadUsable", ctx, "thread1").Return(true, nil)
+
+	threadID, err := service.ThreadID(ctx, "channel1", testCharacter)
+
+	require.NoError(t, err)
+	assert.Equal(t, "thread1", threadID)
+	starter.AssertNotCalled(t, "StartThread", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_ThreadID_Started(t *testing.T) {
+	service, repo, _, starter := setupService(ModeCharacter)
+	ctx := context.Background()
+
+	repo.On("GetThreadID", ctx, "channel1", "character:7").Return("", nil)
+	starter.On("StartThread", ctx, "channel1", "Score updates for Testchar-testrealm").Return("thread1", nil)
+	repo.On("SetThreadID", ctx, "channel1", "character:7", "thread1").Return(nil)
+
+	threadID, err := service.ThreadID(ctx, "channel1", testCharacter)
+
+	require.NoError(t, err)
+	assert.Equal(t, "thread1", threadID)
+	repo.AssertExpectations(t)
+}
+
+func TestService_ThreadID_Replaced(t *testing.T) {
+	service, repo, _, starter := setupService(ModeCharacter)
+	ctx := context.Background()
+
+	// The stored thread was deleted, so a new one replaces it
+	repo.On("GetThreadID", ctx, "channel1", "character:7").Return("thread1", nil)
+	starter.On("ThreadUsable", ctx, "thread1").Return(false, nil)
+	starter.On("StartThread", ctx, "channel1", mock.Anything).Return("thread2", nil)
+	repo.On("SetThreadID", ctx, "channel1", "character:7", "thread2").Return(nil)
+
+	threadID, err := service.ThreadID(ctx, "channel1", testCharacter)
+
+	require.NoError(t, err)
+	assert.Equal(t, "thread2", threadID)
+	repo.AssertExpectations(t)
+}
+
+func TestService_ThreadID_Owner(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		key    string
+		thread string
+	}{
+		{name: "linked", userID: "user1", key: "user:user1", thread: "Score updates for Testchar-testrealm and alts"},
+		{name: "not linked", key: "character:7", thread: "Score updates for Testchar-testrealm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, links, starter := setupService(ModeOwner)
+			ctx := context.Background()
+
+			links.On("GetUserID", ctx, 7).Return(tt.userID, nil)
+			repo.On("GetThreadID", ctx, "channel1", tt.key).Return("", nil)
+			starter.On("StartThread", ctx, "channel1", tt.thread).Return("thread1", nil)
+			repo.On("SetThreadID", ctx, "channel1", tt.key, "thread1").Return(nil)
+
+			threadID, err := service.ThreadID(ctx, "channel1", testCharacter)
+
+			require.NoError(t, err)
+			assert.Equal(t, "thread1", threadID)
+			starter.AssertExpectations(t)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_ThreadID_StartError(t *testing.T) {
+	service, repo, _, starter := setupService(ModeCharacter)
+	ctx := context.Background()
+
+	repo.On("GetThreadID", ctx, "channel1", "character:7").Return("", nil)
+	starter.On("StartThread", ctx, "channel1", mock.Anything).Return("", errors.New("missing permissions"))
+
+	_, err := service.ThreadID(ctx, "channel1", testCharacter)
+
+	assert.ErrorContains(t, err, "missing permissions")
+	repo.AssertNotCalled(t, "SetThreadID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
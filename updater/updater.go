@@ -7,28 +7,63 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sort"
 	"time"
 
 	"github.com/DylanNZL/mythicplusbot/blizzard"
 	"github.com/DylanNZL/mythicplusbot/db"
 	"github.com/DylanNZL/mythicplusbot/discord"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/DylanNZL/mythicplusbot/notify"
 	"github.com/DylanNZL/mythicplusbot/raiderio"
 )
 
-const cooldownTime = time.Millisecond * 250
+const (
+	cooldownTime = time.Millisecond * 250
+
+	// detailsRefreshInterval is how often a character's gear, spec, guild and runs are refreshed while their score
+	// stays the same, a score change always refreshes them.
+	detailsRefreshInterval = 24 * time.Hour
+)
 
 type (
 	CharacterRepository interface {
 		ListCharacters(ctx context.Context, limit int) ([]db.Character, error)
 		UpdateCharacter(ctx context.Context, character *db.Character) error
+		AddScoreHistory(ctx context.Context, entry *db.ScoreHistory) error
+		ListDungeonRuns(ctx context.Context, characterID, seasonID int) ([]db.DungeonRun, error)
+		UpsertDungeonRun(ctx context.Context, run *db.DungeonRun) error
+		ReplaceCharacterRuns(ctx context.Context, characterID int, runs []db.CharacterRun) error
+		ReplaceSpecScores(ctx context.Context, characterID int, scores []db.SpecScore) error
+		// WithTx runs fn with a repository whose writes are committed together, or not at all if fn returns an error.
+		WithTx(ctx context.Context, fn func(repo CharacterRepository) error) error
 	}
 
 	BlizzardClient interface {
 		GetMythicKeystoneProfile(ctx context.Context, realm string, character string) (*blizzard.MythicKeystoneProfile, error)
+		GetCharacterProfile(ctx context.Context, realm string, character string) (*blizzard.CharacterProfile, error)
+		GetMythicKeystoneSeason(ctx context.Context, realm string, character string, seasonID int) (*blizzard.MythicKeystoneSeason, error)
+		FlushCache(ctx context.Context)
 	}
 
 	RaiderIOClient interface {
 		GetCharacter(ctx context.Context, realm string, character string) (*raiderio.Character, error)
+		GetSeasonCutoffs(ctx context.Context, season string) (*raiderio.Cutoffs, error)
+	}
+
+	// MilestoneChecker announces the score milestones a character passed in an update.
+	MilestoneChecker interface {
+		Check(ctx context.Context, channelID string, character db.Character, oldScore float64, cutoffs *raiderio.Cutoffs) error
+	}
+
+	// ThreadFinder returns the thread to post a character's score updates in, or "" to post them in the channel.
+	ThreadFinder interface {
+		ThreadID(ctx context.Context, channelID string, character db.Character) (string, error)
+	}
+
+	// ScoreNotifier tells integrations outside Discord about score changes.
+	ScoreNotifier interface {
+		NotifyScoreChange(ctx context.Context, change notify.ScoreChange) error
 	}
 
 	Sleeper interface {
@@ -48,6 +83,10 @@ type Service struct {
 	blizzardClient BlizzardClient
 	raiderioClient RaiderIOClient
 	messageSender  discord.SenderIface
+	templates      *discord.Templates
+	milestones     MilestoneChecker
+	notifier       ScoreNotifier
+	threads        ThreadFinder
 	sleeper        Sleeper
 }
 
@@ -57,6 +96,10 @@ func NewService(
 	blizzardClient BlizzardClient,
 	raiderIOClient RaiderIOClient,
 	messageSender discord.SenderIface,
+	templates *discord.Templates,
+	milestones MilestoneChecker,
+	notifier ScoreNotifier,
+	threads ThreadFinder,
 	sleeper Sleeper,
 ) *Service {
 	return &Service{
@@ -64,6 +107,10 @@ func NewService(
 		blizzardClient: blizzardClient,
 		raiderioClient: raiderIOClient,
 		messageSender:  messageSender,
+		templates:      templates,
+		milestones:     milestones,
+		notifier:       notifier,
+		threads:        threads,
 		sleeper:        sleeper,
 	}
 }
@@ -92,6 +139,9 @@ func (s *Service) Update(ctx context.Context, discordChannelID string) error {
 		s.sleeper.Sleep(cooldownTime)
 	}
 
+	// Save the responses cached during the cycle in one go
+	s.blizzardClient.FlushCache(ctx)
+
 	return nil
 }
 
@@ -101,13 +151,121 @@ func (s *Service) updateCharacter(ctx context.Context, discordChannelID string,
 		return fmt.Errorf("failed to get mythic profile for %s-%s: %w", character.Name, character.Realm, err)
 	}
 
+	// A 304 is served from the cache, so the rating is still compared in case our stored score is stale
 	if profile.CurrentMythicRating.Rating == character.OverallScore {
-		return nil
+		if time.Since(time.Unix(character.DateUpdated, 0)) < detailsRefreshInterval {
+			slog.DebugContext(ctx, "score not changed", "character", character.Name, "realm", character.Realm,
+				"not_modified", profile.NotModified)
+			return nil
+		}
+
+		// Gear, spec, guild and runs can change without the score moving, so they are refreshed now and then
+		rCharacter, season, err := s.fetchDetails(ctx, &character)
+		if err != nil {
+			return err
+		}
+		return s.refreshCharacter(ctx, character, rCharacter, season)
 	}
 
+	rCharacter, season, err := s.fetchDetails(ctx, &character)
+	if err != nil {
+		return err
+	}
+
+	// Dungeon bests are also only extra detail, so a failure here doesn't stop the score update
+	pbs, announcePBs, err := s.dungeonPBs(ctx, character, profile)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to check dungeon bests", "error", err,
+			"character", character.Name, "realm", character.Realm)
+	}
+
+	oldScore := character.OverallScore
+	character.OverallScore = profile.CurrentMythicRating.Rating
+	applySeason(&character, season)
+	// Write the new score and its history entry together so a failure can't leave one without the other
+	err = s.characterRepo.WithTx(ctx, func(repo CharacterRepository) error {
+		if err := repo.UpdateCharacter(ctx, &character); err != nil {
+			return fmt.Errorf("failed to update character score: %w", err)
+		}
+
+		if err := repo.AddScoreHistory(ctx, &db.ScoreHistory{
+			CharacterID:  character.ID,
+			OverallScore: character.OverallScore,
+			TankScore:    character.TankScore,
+			DPSScore:     character.DPSScore,
+			HealScore:    character.HealScore,
+			DateRecorded: time.Now().Unix(),
+		}); err != nil {
+			return fmt.Errorf("failed to record score history: %w", err)
+		}
+
+		for _, pb := range pbs {
+			if err := repo.UpsertDungeonRun(ctx, &pb.Run); err != nil {
+				return fmt.Errorf("failed to record dungeon best: %w", err)
+			}
+		}
+
+		return saveRuns(ctx, repo, character, rCharacter)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Other integrations don't depend on Discord, so are told even if the announcement fails
+	change := notify.NewScoreChange(character, *rCharacter, oldScore, time.Now())
+	if err := s.notifier.NotifyScoreChange(ctx, change); err != nil {
+		slog.WarnContext(ctx, "failed to notify score change", "error", err,
+			"character", character.Name, "realm", character.Realm)
+	}
+
+	l := i18n.FromContext(ctx)
+	update := discord.ScoreUpdate{Character: character, RaiderIO: *rCharacter, OldScore: oldScore}
+	if season.Season != "" {
+		// The cutoffs only add context to the message, so it is still sent without them
+		if update.Cutoffs, err = s.raiderioClient.GetSeasonCutoffs(ctx, season.Season); err != nil {
+			slog.WarnContext(ctx, "failed to get season cutoffs", "error", err, "season", season.Season)
+		}
+	}
+
+	// The update and its dungeon bests go in the character's thread if they have one, with a summary in the channel
+	target := discordChannelID
+	threadID, err := s.threads.ThreadID(ctx, discordChannelID, character)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get thread, posting in the channel", "error", err,
+			"character", character.Name, "realm", character.Realm)
+	} else if threadID != "" {
+		target = threadID
+	}
+
+	if err := s.messageSender.SendComplexMessage(ctx, target, s.templates.BuildScoreUpdateMessage(ctx, l, update)); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if target != discordChannelID {
+		if err := s.messageSender.SendComplexMessage(ctx, discordChannelID, discord.BuildThreadSummaryMessage(l, update, target)); err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+	}
+
+	// The score update has already gone out, so a failed milestone check is only logged
+	if err := s.milestones.Check(ctx, discordChannelID, character, oldScore, update.Cutoffs); err != nil {
+		slog.WarnContext(ctx, "failed to check score milestones", "error", err,
+			"character", character.Name, "realm", character.Realm)
+	}
+
+	if announcePBs && len(pbs) > 0 {
+		if err := s.messageSender.SendComplexMessage(ctx, target, discord.BuildDungeonPBMessage(l, character, pbs)); err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// fetchDetails returns the character's Raider.IO profile and current season, and applies their Blizzard profile.
+func (s *Service) fetchDetails(ctx context.Context, character *db.Character) (*raiderio.Character, raiderio.Season, error) {
 	rCharacter, err := s.raiderioClient.GetCharacter(ctx, character.Realm, character.Name)
 	if err != nil {
-		return fmt.Errorf("failed to get character %s-%s: %w", character.Name, character.Realm, err)
+		return nil, raiderio.Season{}, fmt.Errorf("failed to get character %s-%s: %w", character.Name, character.Realm, err)
 	}
 
 	season := raiderio.Season{}
@@ -115,18 +273,159 @@ func (s *Service) updateCharacter(ctx context.Context, discordChannelID string,
 		season = rCharacter.MythicPlusScoresBySeason[0]
 	}
 
-	oldScore := character.OverallScore
-	character.OverallScore = profile.CurrentMythicRating.Rating
+	// The profile only adds detail to the update, so keep the stored details if it can't be fetched
+	if cProfile, err := s.blizzardClient.GetCharacterProfile(ctx, character.Realm, character.Name); err != nil {
+		slog.WarnContext(ctx, "failed to get character profile", "error", err,
+			"character", character.Name, "realm", character.Realm)
+	} else {
+		ApplyProfile(character, cProfile)
+	}
+
+	return rCharacter, season, nil
+}
+
+// refreshCharacter saves the details that change without the character's overall score changing, such as their gear
+// and runs. No score history is recorded and nothing is announced.
+func (s *Service) refreshCharacter(ctx context.Context, character db.Character, rCharacter *raiderio.Character,
+	season raiderio.Season,
+) error {
+	applySeason(&character, season)
+	return s.characterRepo.WithTx(ctx, func(repo CharacterRepository) error {
+		if err := repo.UpdateCharacter(ctx, &character); err != nil {
+			return fmt.Errorf("failed to update character: %w", err)
+		}
+		return saveRuns(ctx, repo, character, rCharacter)
+	})
+}
+
+// applySeason copies the character's role and spec scores from their Raider.IO season.
+func applySeason(character *db.Character, season raiderio.Season) {
 	character.TankScore = season.Scores.Tank
 	character.HealScore = season.Scores.Healer
 	character.DPSScore = season.Scores.Dps
-	if err := s.characterRepo.UpdateCharacter(ctx, &character); err != nil {
-		return fmt.Errorf("failed to update character score: %w", err)
-	}
+	character.SpecScores = SpecScores(character.ID, character.Class, season)
+}
 
-	if err := s.messageSender.SendComplexMessage(ctx, discordChannelID, discord.BuildScoreUpdateMessage(ctx, character, *rCharacter, oldScore)); err != nil {
-		return fmt.Errorf("failed to send message: %w", err)
+// saveRuns replaces the character's stored Raider.IO runs and spec scores.
+func saveRuns(ctx context.Context, repo CharacterRepository, character db.Character, rCharacter *raiderio.Character) error {
+	if err := repo.ReplaceCharacterRuns(ctx, character.ID, CharacterRuns(character.ID, rCharacter)); err != nil {
+		return fmt.Errorf("failed to record runs: %w", err)
 	}
 
+	if err := repo.ReplaceSpecScores(ctx, character.ID, character.SpecScores); err != nil {
+		return fmt.Errorf("failed to record spec scores: %w", err)
+	}
 	return nil
 }
+
+// dungeonPBs returns the runs from the character's latest season that beat their stored best in the dungeon.
+//
+// The first time we see a character's runs for a season there is nothing to compare against, so they are returned to
+// be stored but shouldn't be announced.
+func (s *Service) dungeonPBs(ctx context.Context, character db.Character, profile *blizzard.MythicKeystoneProfile) (pbs []discord.DungeonPB, announce bool, err error) {
+	ids := profile.SeasonIDs()
+	if len(ids) == 0 {
+		return nil, false, nil
+	}
+	seasonID := ids[len(ids)-1]
+
+	season, err := s.blizzardClient.GetMythicKeystoneSeason(ctx, character.Realm, character.Name, seasonID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	stored, err := s.characterRepo.ListDungeonRuns(ctx, character.ID, seasonID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	previous := make(map[int]db.DungeonRun, len(stored))
+	for _, run := range stored {
+		previous[run.DungeonID] = run
+	}
+
+	for _, run := range season.BestRunsByDungeon() {
+		best, ok := previous[run.Dungeon.ID]
+		if ok && run.MapRating.Rating <= best.MapRating {
+			continue
+		}
+
+		pbs = append(pbs, discord.DungeonPB{Run: newDungeonRun(character.ID, seasonID, run), Previous: best})
+	}
+
+	return pbs, len(stored) > 0, nil
+}
+
+func newDungeonRun(characterID, seasonID int, run blizzard.BestRun) db.DungeonRun {
+	return db.DungeonRun{
+		CharacterID:        characterID,
+		SeasonID:           seasonID,
+		DungeonID:          run.Dungeon.ID,
+		DungeonName:        run.Dungeon.Name,
+		KeystoneLevel:      run.KeystoneLevel,
+		MapRating:          run.MapRating.Rating,
+		Duration:           run.Duration,
+		Timed:              run.IsCompletedWithinTime,
+		CompletedTimestamp: run.CompletedTimestamp,
+	}
+}
+
+// CharacterRuns returns the best, alternate and recent runs from the character's Raider.IO profile.
+func CharacterRuns(characterID int, rCharacter *raiderio.Character) []db.CharacterRun {
+	lists := []struct {
+		kind db.RunKind
+		runs []raiderio.Run
+	}{
+		{db.RunKindBest, rCharacter.MythicPlusBestRuns},
+		{db.RunKindAlternate, rCharacter.MythicPlusAlternateRuns},
+		{db.RunKindRecent, rCharacter.MythicPlusRecentRuns},
+	}
+
+	var runs []db.CharacterRun
+	for _, list := range lists {
+		for _, run := range list.runs {
+			runs = append(runs, db.CharacterRun{
+				CharacterID:         characterID,
+				Kind:                list.kind,
+				KeystoneRunID:       run.KeystoneRunId,
+				Dungeon:             run.Dungeon,
+				ShortName:           run.ShortName,
+				MythicLevel:         run.MythicLevel,
+				NumKeystoneUpgrades: run.NumKeystoneUpgrades,
+				ClearTimeMs:         int64(run.ClearTimeMs),
+				ParTimeMs:           int64(run.ParTimeMs),
+				Score:               run.Score,
+				CompletedAt:         run.CompletedAt.Unix(),
+				URL:                 run.Url,
+			})
+		}
+	}
+	return runs
+}
+
+// SpecScores returns the character's score in each spec they have played this season, highest first.
+func SpecScores(characterID int, class string, season raiderio.Season) []db.SpecScore {
+	var scores []db.SpecScore
+	for i, score := range season.Scores.Specs {
+		spec := raiderio.SpecName(class, i)
+		if score == 0 || spec == "" {
+			continue
+		}
+		scores = append(scores, db.SpecScore{CharacterID: characterID, Spec: spec, Score: score})
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+	return scores
+}
+
+// ApplyProfile copies the details we track from the character profile.
+func ApplyProfile(character *db.Character, profile *blizzard.CharacterProfile) {
+	character.Level = profile.Level
+	character.Spec = profile.SpecName()
+	character.Guild = profile.GuildName()
+	character.Faction = profile.Faction.Name
+	character.ItemLevel = profile.AverageItemLevel
+	character.EquippedItemLevel = profile.EquippedItemLevel
+}
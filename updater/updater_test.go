@@ -2,16 +2,21 @@ package updater
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DylanNZL/mythicplusbot/blizzard"
 	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/discord"
+	"github.com/DylanNZL/mythicplusbot/notify"
 	"github.com/DylanNZL/mythicplusbot/raiderio"
 	"github.com/bwmarrin/discordgo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // Mock implementations for testing
@@ -30,6 +35,39 @@ func (m *MockCharacterRepository) UpdateCharacter(ctx context.Context, character
 	return args.Error(0)
 }
 
+// WithTx runs fn against the mock itself, so the writes inside the transaction are asserted as usual.
+func (m *MockCharacterRepository) WithTx(ctx context.Context, fn func(repo CharacterRepository) error) error {
+	if err := m.Called(ctx).Error(0); err != nil {
+		return err
+	}
+	return fn(m)
+}
+
+func (m *MockCharacterRepository) AddScoreHistory(ctx context.Context, entry *db.ScoreHistory) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockCharacterRepository) ListDungeonRuns(ctx context.Context, characterID, seasonID int) ([]db.DungeonRun, error) {
+	args := m.Called(ctx, characterID, seasonID)
+	return args.Get(0).([]db.DungeonRun), args.Error(1)
+}
+
+func (m *MockCharacterRepository) UpsertDungeonRun(ctx context.Context, run *db.DungeonRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *MockCharacterRepository) ReplaceSpecScores(ctx context.Context, characterID int, scores []db.SpecScore) error {
+	args := m.Called(ctx, characterID, scores)
+	return args.Error(0)
+}
+
+func (m *MockCharacterRepository) ReplaceCharacterRuns(ctx context.Context, characterID int, runs []db.CharacterRun) error {
+	args := m.Called(ctx, characterID, runs)
+	return args.Error(0)
+}
+
 type MockBlizzardClient struct {
 	mock.Mock
 }
@@ -42,6 +80,26 @@ func (m *MockBlizzardClient) GetMythicKeystoneProfile(ctx context.Context, realm
 	return args.Get(0).(*blizzard.MythicKeystoneProfile), args.Error(1)
 }
 
+func (m *MockBlizzardClient) GetCharacterProfile(ctx context.Context, realm, character string) (*blizzard.CharacterProfile, error) {
+	args := m.Called(ctx, realm, character)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*blizzard.CharacterProfile), args.Error(1)
+}
+
+func (m *MockBlizzardClient) GetMythicKeystoneSeason(ctx context.Context, realm, character string, seasonID int) (*blizzard.MythicKeystoneSeason, error) {
+	args := m.Called(ctx, realm, character, seasonID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*blizzard.MythicKeystoneSeason), args.Error(1)
+}
+
+func (m *MockBlizzardClient) FlushCache(ctx context.Context) {
+	m.Called(ctx)
+}
+
 type MockRaiderIOClient struct {
 	mock.Mock
 }
@@ -54,6 +112,14 @@ func (m *MockRaiderIOClient) GetCharacter(ctx context.Context, realm, character
 	return args.Get(0).(*raiderio.Character), args.Error(1)
 }
 
+func (m *MockRaiderIOClient) GetSeasonCutoffs(ctx context.Context, season string) (*raiderio.Cutoffs, error) {
+	args := m.Called(ctx, season)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*raiderio.Cutoffs), args.Error(1)
+}
+
 type MockMessageSender struct {
 	mock.Mock
 }
@@ -68,6 +134,53 @@ func (m *MockMessageSender) SendComplexMessage(ctx context.Context, channelID st
 	return args.Error(0)
 }
 
+func (m *MockMessageSender) AddRole(ctx context.Context, guildID, userID, roleID string) error {
+	args := m.Called(ctx, guildID, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockMessageSender) RemoveRole(ctx context.Context, guildID, userID, roleID string) error {
+	args := m.Called(ctx, guildID, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockMessageSender) UpdateInteractionMessage(ctx context.Context, interaction *discordgo.Interaction, message discordgo.MessageSend) error {
+	args := m.Called(ctx, interaction, message)
+	return args.Error(0)
+}
+
+func (m *MockMessageSender) RespondEphemeral(ctx context.Context, interaction *discordgo.Interaction, content string) error {
+	args := m.Called(ctx, interaction, content)
+	return args.Error(0)
+}
+
+type MockMilestoneChecker struct {
+	mock.Mock
+}
+
+func (m *MockMilestoneChecker) Check(ctx context.Context, channelID string, character db.Character, oldScore float64, cutoffs *raiderio.Cutoffs) error {
+	args := m.Called(ctx, channelID, character, oldScore, cutoffs)
+	return args.Error(0)
+}
+
+type MockScoreNotifier struct {
+	mock.Mock
+}
+
+func (m *MockScoreNotifier) NotifyScoreChange(ctx context.Context, change notify.ScoreChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+type MockThreadFinder struct {
+	mock.Mock
+}
+
+func (m *MockThreadFinder) ThreadID(ctx context.Context, channelID string, character db.Character) (string, error) {
+	args := m.Called(ctx, channelID, character)
+	return args.String(0), args.Error(1)
+}
+
 type MockSleeper struct {
 	mock.Mock
 }
@@ -98,6 +211,46 @@ func createTestProfile(score float64) *blizzard.MythicKeystoneProfile {
 	}
 }
 
+// createTestSeasonProfile returns a profile for a character that has played the given seasons.
+func createTestSeasonProfile(t *testing.T, score float64, seasonIDs ...int) *blizzard.MythicKeystoneProfile {
+	t.Helper()
+
+	seasons := make([]map[string]int, 0, len(seasonIDs))
+	for _, id := range seasonIDs {
+		seasons = append(seasons, map[string]int{"id": id})
+	}
+	data, err := json.Marshal(map[string]any{"seasons": seasons})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile := createTestProfile(score)
+	if err := json.Unmarshal(data, profile); err != nil {
+		t.Fatal(err)
+	}
+	return profile
+}
+
+func createTestBestRun(dungeonID int, dungeon string, level int, rating float64) blizzard.BestRun {
+	return blizzard.BestRun{
+		KeystoneLevel:         level,
+		IsCompletedWithinTime: true,
+		Dungeon:               blizzard.NamedRef{ID: dungeonID, Name: dungeon},
+		MapRating:             blizzard.Rating{Rating: rating},
+	}
+}
+
+func createTestCharacterProfile() *blizzard.CharacterProfile {
+	return &blizzard.CharacterProfile{
+		Level:             80,
+		Faction:           blizzard.TypedName{Type: "HORDE", Name: "Horde"},
+		ActiveSpec:        blizzard.NamedRef{ID: 66, Name: "Protection"},
+		Guild:             &blizzard.Guild{Name: "Method"},
+		AverageItemLevel:  620,
+		EquippedItemLevel: 618,
+	}
+}
+
 func createTestRaiderIOCharacter(tankScore, healScore, dpsScore float64) *raiderio.Character {
 	return &raiderio.Character{
 		MythicPlusScoresBySeason: []raiderio.Season{
@@ -115,11 +268,22 @@ func createTestRaiderIOCharacter(tankScore, healScore, dpsScore float64) *raider
 func setupService() (*Service, *MockCharacterRepository, *MockBlizzardClient, *MockRaiderIOClient, *MockMessageSender, *MockSleeper) {
 	characterRepo := &MockCharacterRepository{}
 	blizzardClient := &MockBlizzardClient{}
+	blizzardClient.On("FlushCache", mock.Anything).Return().Maybe()
 	raiderIOClient := &MockRaiderIOClient{}
 	messageSender := &MockMessageSender{}
+	templates, _ := discord.ParseTemplates(discord.TemplateText{})
+	// Milestones have their own tests, so most tests don't care whether they are checked
+	milestones := &MockMilestoneChecker{}
+	milestones.On("Check", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	notifier := &MockScoreNotifier{}
+	notifier.On("NotifyScoreChange", mock.Anything, mock.Anything).Return(nil).Maybe()
+	// Updates are posted in the channel unless a test gives the character a thread
+	threads := &MockThreadFinder{}
+	threads.On("ThreadID", mock.Anything, mock.Anything, mock.Anything).Return("", nil).Maybe()
 	sleeper := &MockSleeper{}
 
-	service := NewService(characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper)
+	service := NewService(characterRepo, blizzardClient, raiderIOClient, messageSender, templates, milestones, notifier,
+		threads, sleeper)
 	return service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper
 }
 
@@ -130,15 +294,24 @@ func TestNewService(t *testing.T) {
 	blizzardClient := &MockBlizzardClient{}
 	raiderIOClient := &MockRaiderIOClient{}
 	messageSender := &MockMessageSender{}
+	templates, _ := discord.ParseTemplates(discord.TemplateText{})
+	milestones := &MockMilestoneChecker{}
+	notifier := &MockScoreNotifier{}
+	threads := &MockThreadFinder{}
 	sleeper := &MockSleeper{}
 
-	service := NewService(characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper)
+	service := NewService(characterRepo, blizzardClient, raiderIOClient, messageSender, templates, milestones, notifier,
+		threads, sleeper)
 
 	assert.NotNil(t, service)
 	assert.Equal(t, characterRepo, service.characterRepo)
 	assert.Equal(t, blizzardClient, service.blizzardClient)
 	assert.Equal(t, raiderIOClient, service.raiderioClient)
 	assert.Equal(t, messageSender, service.messageSender)
+	assert.Equal(t, templates, service.templates)
+	assert.Equal(t, milestones, service.milestones)
+	assert.Equal(t, notifier, service.notifier)
+	assert.Equal(t, threads, service.threads)
 	assert.Equal(t, sleeper, service.sleeper)
 }
 
@@ -151,18 +324,38 @@ func TestService_Update_Success_WithScoreChange(t *testing.T) {
 
 	// Setup test data
 	character := createTestCharacter("testchar", "testrealm", 2500.0)
+	character.Class = "DeathKnight"
 	characters := []db.Character{character}
 	newProfile := createTestProfile(2600.0)                             // Score improved by 100
 	raiderIOChar := createTestRaiderIOCharacter(2400.0, 2300.0, 2200.0) // Tank, Heal, DPS scores
+	raiderIOChar.MythicPlusBestRuns = []raiderio.Run{{Dungeon: "The Stonevault", KeystoneRunId: 100, MythicLevel: 12, Score: 310}}
+	raiderIOChar.MythicPlusRecentRuns = []raiderio.Run{{Dungeon: "Ara-Kara", KeystoneRunId: 101, MythicLevel: 10}}
+	raiderIOChar.MythicPlusScoresBySeason[0].Scores.Specs = [4]float64{2400.0, 2200.0, 2250.0, 0}
 
 	// Mock expectations
 	characterRepo.On("ListCharacters", ctx, 0).Return(characters, nil)
 	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(newProfile, nil)
 	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(raiderIOChar, nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
 	messageSender.On("SendComplexMessage", ctx, channelID, mock.AnythingOfType("discordgo.MessageSend")).Return(nil)
+	characterRepo.On("WithTx", ctx).Return(nil)
 	characterRepo.On("UpdateCharacter", ctx, mock.MatchedBy(func(char *db.Character) bool {
-		return char.Name == "testchar" && char.OverallScore == 2600.0
+		return char.Name == "testchar" && char.OverallScore == 2600.0 &&
+			char.Spec == "Protection" && char.Guild == "Method" && char.ItemLevel == 620
 	})).Return(nil)
+	characterRepo.On("AddScoreHistory", ctx, mock.MatchedBy(func(entry *db.ScoreHistory) bool {
+		return entry.CharacterID == 1 && entry.OverallScore == 2600.0 && entry.TankScore == 2400.0 && entry.DateRecorded > 0
+	})).Return(nil)
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.MatchedBy(func(runs []db.CharacterRun) bool {
+		return len(runs) == 2 &&
+			runs[0].Kind == db.RunKindBest && runs[0].KeystoneRunID == 100 && runs[0].MythicLevel == 12 &&
+			runs[1].Kind == db.RunKindRecent && runs[1].Dungeon == "Ara-Kara"
+	})).Return(nil)
+	characterRepo.On("ReplaceSpecScores", ctx, 1, []db.SpecScore{
+		{CharacterID: 1, Spec: "Blood", Score: 2400.0},
+		{CharacterID: 1, Spec: "Unholy", Score: 2250.0},
+		{CharacterID: 1, Spec: "Frost", Score: 2200.0},
+	}).Return(nil)
 	sleeper.On("Sleep", cooldownTime).Return()
 
 	err := service.Update(ctx, channelID)
@@ -175,32 +368,129 @@ func TestService_Update_Success_WithScoreChange(t *testing.T) {
 	sleeper.AssertExpectations(t)
 }
 
+func TestService_Update_ProfileError(t *testing.T) {
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	character := createTestCharacter("testchar", "testrealm", 2500.0)
+	character.ItemLevel = 610
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{character}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(createTestProfile(2600.0), nil)
+	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(createTestRaiderIOCharacter(2400.0, 0, 0), nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(nil, errors.New("api error"))
+	characterRepo.On("WithTx", ctx).Return(nil)
+	// The score is still updated, keeping the item level we already had
+	characterRepo.On("UpdateCharacter", ctx, mock.MatchedBy(func(char *db.Character) bool {
+		return char.OverallScore == 2600.0 && char.ItemLevel == 610
+	})).Return(nil)
+	characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil)
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
+	messageSender.On("SendComplexMessage", ctx, channelID, mock.AnythingOfType("discordgo.MessageSend")).Return(nil)
+	sleeper.On("Sleep", cooldownTime).Return()
+
+	err := service.Update(ctx, channelID)
+
+	assert.NoError(t, err)
+	characterRepo.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
 func TestService_Update_NoScoreChange(t *testing.T) {
-	service, characterRepo, blizzardClient, _, messageSender, sleeper := setupService()
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
 	ctx := context.Background()
 	channelID := "test-channel"
 
-	// Setup test data - same score
+	// The details were refreshed recently, so nothing more is fetched or written
 	character := createTestCharacter("testchar", "testrealm", 2500.0)
+	character.DateUpdated = time.Now().Add(-time.Hour).Unix()
+	notModified := createTestProfile(2500.0)
+	notModified.NotModified = true
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{character}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(notModified, nil)
+	sleeper.On("Sleep", cooldownTime).Return()
+
+	err := service.Update(ctx, channelID)
+
+	assert.NoError(t, err)
+	raiderIOClient.AssertNotCalled(t, "GetCharacter", mock.Anything, mock.Anything, mock.Anything)
+	blizzardClient.AssertNotCalled(t, "GetCharacterProfile", mock.Anything, mock.Anything, mock.Anything)
+	characterRepo.AssertNotCalled(t, "WithTx", mock.Anything)
+	characterRepo.AssertNotCalled(t, "UpdateCharacter", mock.Anything, mock.Anything)
+	messageSender.AssertNotCalled(t, "SendComplexMessage", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_Update_NoScoreChange_RefreshDetails(t *testing.T) {
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	// Same score, but the details haven't been refreshed for a day
+	character := createTestCharacter("testchar", "testrealm", 2500.0)
+	character.ItemLevel = 610
+	character.DateUpdated = time.Now().Add(-detailsRefreshInterval - time.Minute).Unix()
 	characters := []db.Character{character}
 	sameProfile := createTestProfile(2500.0) // Same score
 
 	// Mock expectations
 	characterRepo.On("ListCharacters", ctx, 0).Return(characters, nil)
 	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(sameProfile, nil)
+	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(createTestRaiderIOCharacter(2450.0, 0, 0), nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+	// The new gear and runs are saved even though the score is the same
+	characterRepo.On("WithTx", ctx).Return(nil)
+	characterRepo.On("UpdateCharacter", ctx, mock.MatchedBy(func(char *db.Character) bool {
+		return char.OverallScore == 2500.0 && char.TankScore == 2450.0 && char.ItemLevel == 620 && char.Guild == "Method"
+	})).Return(nil)
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
 	sleeper.On("Sleep", cooldownTime).Return()
 
-	// Should NOT call messageSender or UpdateCharacter when score is the same
+	// Should NOT record history or announce anything when the score is the same
 	err := service.Update(ctx, channelID)
 
 	assert.NoError(t, err)
 	characterRepo.AssertExpectations(t)
 	blizzardClient.AssertExpectations(t)
 	messageSender.AssertNotCalled(t, "SendMessage")
-	characterRepo.AssertNotCalled(t, "UpdateCharacter")
+	messageSender.AssertNotCalled(t, "SendComplexMessage")
+	characterRepo.AssertNotCalled(t, "AddScoreHistory")
+	blizzardClient.AssertNotCalled(t, "GetMythicKeystoneSeason")
 	sleeper.AssertExpectations(t)
 }
 
+func TestService_Update_NotModified_StaleScore(t *testing.T) {
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	// The cached profile has a newer rating than we stored, e.g. the last write failed
+	characters := []db.Character{createTestCharacter("testchar", "testrealm", 2500.0)}
+	cachedProfile := createTestProfile(2600.0)
+	cachedProfile.NotModified = true
+
+	characterRepo.On("ListCharacters", ctx, 0).Return(characters, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(cachedProfile, nil)
+	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(createTestRaiderIOCharacter(2400.0, 0, 0), nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+	characterRepo.On("WithTx", ctx).Return(nil)
+	characterRepo.On("UpdateCharacter", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+	characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil)
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
+	messageSender.On("SendComplexMessage", ctx, channelID, mock.AnythingOfType("discordgo.MessageSend")).Return(nil)
+	sleeper.On("Sleep", cooldownTime).Return()
+
+	err := service.Update(ctx, channelID)
+
+	assert.NoError(t, err)
+	characterRepo.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
 func TestService_Update_ListCharactersError(t *testing.T) {
 	service, characterRepo, _, _, _, _ := setupService()
 	ctx := context.Background()
@@ -215,6 +505,26 @@ func TestService_Update_ListCharactersError(t *testing.T) {
 	characterRepo.AssertExpectations(t)
 }
 
+func TestService_Update_FlushesCache(t *testing.T) {
+	service, characterRepo, blizzardClient, _, _, sleeper := setupService()
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{
+		{ID: 1, Name: "Testchar", Realm: "test-realm"},
+		{ID: 2, Name: "Otherchar", Realm: "test-realm"},
+	}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, mock.Anything, mock.Anything).
+		Return(nil, errors.New("API error"))
+	sleeper.On("Sleep", cooldownTime).Return().Maybe()
+
+	err := service.Update(ctx, channelID)
+
+	// The cache is saved once for the whole cycle, not per character
+	require.NoError(t, err)
+	blizzardClient.AssertNumberOfCalls(t, "FlushCache", 1)
+}
+
 func TestService_Update_BlizzardAPIError(t *testing.T) {
 	service, characterRepo, blizzardClient, _, messageSender, sleeper := setupService()
 	ctx := context.Background()
@@ -250,10 +560,15 @@ func TestService_Update_MessageSendError(t *testing.T) {
 	characterRepo.On("ListCharacters", ctx, 0).Return(characters, nil)
 	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(newProfile, nil)
 	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(raiderIOChar, nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
 	// UpdateCharacter happens BEFORE SendComplexMessage in the implementation
+	characterRepo.On("WithTx", ctx).Return(nil)
 	characterRepo.On("UpdateCharacter", ctx, mock.MatchedBy(func(char *db.Character) bool {
 		return char.Name == "testchar" && char.OverallScore == 2600.0
 	})).Return(nil)
+	characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil)
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
 	messageSender.On("SendComplexMessage", ctx, channelID, mock.AnythingOfType("discordgo.MessageSend")).Return(errors.New("discord error"))
 	// Sleep is NOT called when updateCharacter fails (due to message send error)
 
@@ -286,12 +601,22 @@ func TestService_Update_MultipleCharacters(t *testing.T) {
 	blizzardClient.On("GetMythicKeystoneProfile", ctx, "realm1", "char1").Return(profile1, nil)
 	blizzardClient.On("GetMythicKeystoneProfile", ctx, "realm2", "char2").Return(profile2, nil)
 
-	// Only char1 should trigger RaiderIO call, message and update (char2 has no score change)
+	// Both are refreshed, but only char1 gets a message and history entry (char2 has no score change)
 	raiderIOClient.On("GetCharacter", ctx, "realm1", "char1").Return(raiderIOChar1, nil)
+	raiderIOClient.On("GetCharacter", ctx, "realm2", "char2").Return(createTestRaiderIOCharacter(0, 0, 2300.0), nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "realm1", "char1").Return(createTestCharacterProfile(), nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "realm2", "char2").Return(createTestCharacterProfile(), nil)
 	messageSender.On("SendComplexMessage", ctx, channelID, mock.AnythingOfType("discordgo.MessageSend")).Return(nil).Once()
+	characterRepo.On("WithTx", ctx).Return(nil)
 	characterRepo.On("UpdateCharacter", ctx, mock.MatchedBy(func(char *db.Character) bool {
 		return char.Name == "char1" && char.OverallScore == 2600.0
 	})).Return(nil).Once()
+	characterRepo.On("UpdateCharacter", ctx, mock.MatchedBy(func(char *db.Character) bool {
+		return char.Name == "char2" && char.OverallScore == 2300.0
+	})).Return(nil).Once()
+	characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil).Once()
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil).Twice()
+	characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil).Twice()
 
 	// Should sleep after each character
 	sleeper.On("Sleep", cooldownTime).Return().Twice()
@@ -306,8 +631,386 @@ func TestService_Update_MultipleCharacters(t *testing.T) {
 	sleeper.AssertExpectations(t)
 }
 
+func TestService_Update_ScoreHistoryError(t *testing.T) {
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	characters := []db.Character{createTestCharacter("testchar", "testrealm", 2500.0)}
+
+	characterRepo.On("ListCharacters", ctx, 0).Return(characters, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(createTestProfile(2600.0), nil)
+	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(createTestRaiderIOCharacter(2400.0, 0, 0), nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+	characterRepo.On("WithTx", ctx).Return(nil)
+	characterRepo.On("UpdateCharacter", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+	characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(errors.New("database error"))
+
+	err := service.Update(ctx, channelID)
+
+	// The error is logged and the message is not sent
+	assert.NoError(t, err)
+	characterRepo.AssertExpectations(t)
+	messageSender.AssertNotCalled(t, "SendComplexMessage")
+	sleeper.AssertNotCalled(t, "Sleep")
+}
+
 // Test real implementations
 
+func TestService_Update_TransactionError(t *testing.T) {
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	characters := []db.Character{createTestCharacter("testchar", "testrealm", 2500.0)}
+
+	characterRepo.On("ListCharacters", ctx, 0).Return(characters, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(createTestProfile(2600.0), nil)
+	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(createTestRaiderIOCharacter(2400.0, 0, 0), nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+	characterRepo.On("WithTx", ctx).Return(errors.New("database is locked"))
+
+	err := service.Update(ctx, channelID)
+
+	// Nothing is written outside the transaction and the message is not sent
+	assert.NoError(t, err)
+	characterRepo.AssertExpectations(t)
+	characterRepo.AssertNotCalled(t, "UpdateCharacter")
+	messageSender.AssertNotCalled(t, "SendComplexMessage")
+	sleeper.AssertNotCalled(t, "Sleep")
+}
+
+func TestService_Update_DungeonPBs(t *testing.T) {
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{createTestCharacter("testchar", "testrealm", 2500.0)}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").
+		Return(createTestSeasonProfile(t, 2600.0, 12, 13), nil)
+	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(createTestRaiderIOCharacter(2400.0, 0, 0), nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+	blizzardClient.On("GetMythicKeystoneSeason", ctx, "testrealm", "testchar", 13).
+		Return(&blizzard.MythicKeystoneSeason{BestRuns: []blizzard.BestRun{
+			createTestBestRun(1, "Ara-Kara", 12, 300),
+			createTestBestRun(2, "The Stonevault", 8, 200),
+			createTestBestRun(3, "Grim Batol", 10, 240),
+		}}, nil)
+	// Grim Batol hasn't improved so only the other two are recorded
+	characterRepo.On("ListDungeonRuns", ctx, 1, 13).Return([]db.DungeonRun{
+		{CharacterID: 1, SeasonID: 13, DungeonID: 1, DungeonName: "Ara-Kara", KeystoneLevel: 10, MapRating: 250, Timed: true},
+		{CharacterID: 1, SeasonID: 13, DungeonID: 3, DungeonName: "Grim Batol", KeystoneLevel: 10, MapRating: 240, Timed: true},
+	}, nil)
+	characterRepo.On("WithTx", ctx).Return(nil)
+	characterRepo.On("UpdateCharacter", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+	characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil)
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("UpsertDungeonRun", ctx, mock.MatchedBy(func(run *db.DungeonRun) bool {
+		return run.SeasonID == 13 && run.DungeonID == 1 && run.KeystoneLevel == 12
+	})).Return(nil).Once()
+	characterRepo.On("UpsertDungeonRun", ctx, mock.MatchedBy(func(run *db.DungeonRun) bool {
+		return run.SeasonID == 13 && run.DungeonID == 2 && run.KeystoneLevel == 8
+	})).Return(nil).Once()
+	messageSender.On("SendComplexMessage", ctx, channelID, mock.MatchedBy(func(msg discordgo.MessageSend) bool {
+		return msg.Content != ""
+	})).Return(nil).Once()
+	messageSender.On("SendComplexMessage", ctx, channelID, mock.MatchedBy(func(msg discordgo.MessageSend) bool {
+		return len(msg.Embeds) == 1 && strings.Contains(msg.Embeds[0].Description,
+			"New dungeon PB: **Ara-Kara** +12 (was +10)\nNew dungeon PB: **The Stonevault** +8\n")
+	})).Return(nil).Once()
+	sleeper.On("Sleep", cooldownTime).Return()
+
+	err := service.Update(ctx, channelID)
+
+	assert.NoError(t, err)
+	characterRepo.AssertExpectations(t)
+	blizzardClient.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestService_Update_DungeonPBs_FirstSeen(t *testing.T) {
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{createTestCharacter("testchar", "testrealm", 2500.0)}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").
+		Return(createTestSeasonProfile(t, 2600.0, 13), nil)
+	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(createTestRaiderIOCharacter(2400.0, 0, 0), nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+	blizzardClient.On("GetMythicKeystoneSeason", ctx, "testrealm", "testchar", 13).
+		Return(&blizzard.MythicKeystoneSeason{BestRuns: []blizzard.BestRun{createTestBestRun(1, "Ara-Kara", 12, 300)}}, nil)
+	characterRepo.On("ListDungeonRuns", ctx, 1, 13).Return([]db.DungeonRun(nil), nil)
+	characterRepo.On("WithTx", ctx).Return(nil)
+	characterRepo.On("UpdateCharacter", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+	characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil)
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("UpsertDungeonRun", ctx, mock.AnythingOfType("*db.DungeonRun")).Return(nil)
+	messageSender.On("SendComplexMessage", ctx, channelID, mock.AnythingOfType("discordgo.MessageSend")).Return(nil)
+	sleeper.On("Sleep", cooldownTime).Return()
+
+	err := service.Update(ctx, channelID)
+
+	// The runs are stored as a baseline, but there is nothing to compare them against so only the score is announced
+	assert.NoError(t, err)
+	characterRepo.AssertNumberOfCalls(t, "UpsertDungeonRun", 1)
+	messageSender.AssertNumberOfCalls(t, "SendComplexMessage", 1)
+}
+
+func TestService_Update_DungeonPBs_SeasonError(t *testing.T) {
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{createTestCharacter("testchar", "testrealm", 2500.0)}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").
+		Return(createTestSeasonProfile(t, 2600.0, 13), nil)
+	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(createTestRaiderIOCharacter(2400.0, 0, 0), nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+	blizzardClient.On("GetMythicKeystoneSeason", ctx, "testrealm", "testchar", 13).Return(nil, errors.New("api error"))
+	characterRepo.On("WithTx", ctx).Return(nil)
+	characterRepo.On("UpdateCharacter", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+	characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil)
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
+	messageSender.On("SendComplexMessage", ctx, channelID, mock.AnythingOfType("discordgo.MessageSend")).Return(nil)
+	sleeper.On("Sleep", cooldownTime).Return()
+
+	err := service.Update(ctx, channelID)
+
+	// The score is still updated without any dungeon bests
+	assert.NoError(t, err)
+	characterRepo.AssertExpectations(t)
+	characterRepo.AssertNotCalled(t, "UpsertDungeonRun")
+	messageSender.AssertNumberOfCalls(t, "SendComplexMessage", 1)
+}
+
+func TestService_Update_SeasonCutoffs(t *testing.T) {
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	rCharacter := createTestRaiderIOCharacter(2600.0, 0, 0)
+	rCharacter.MythicPlusScoresBySeason[0].Season = "season-tww-2"
+	cutoffs := &raiderio.Cutoffs{}
+	cutoffs.P900.All.QuantileMinValue = 2700
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{createTestCharacter("testchar", "testrealm", 2500.0)}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(createTestProfile(2600.0), nil)
+	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(rCharacter, nil)
+	raiderIOClient.On("GetSeasonCutoffs", ctx, "season-tww-2").Return(cutoffs, nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+	characterRepo.On("WithTx", ctx).Return(nil)
+	characterRepo.On("UpdateCharacter", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+	characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil)
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
+	messageSender.On("SendComplexMessage", ctx, channelID, mock.MatchedBy(func(msg discordgo.MessageSend) bool {
+		fields := msg.Embeds[0].Fields
+		return len(fields) == 1 && fields[0].Value == "10% cutoff is 2700, you're 100 away"
+	})).Return(nil)
+	sleeper.On("Sleep", cooldownTime).Return()
+
+	err := service.Update(ctx, channelID)
+
+	assert.NoError(t, err)
+	raiderIOClient.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestService_Update_SeasonCutoffsError(t *testing.T) {
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	rCharacter := createTestRaiderIOCharacter(2600.0, 0, 0)
+	rCharacter.MythicPlusScoresBySeason[0].Season = "season-tww-2"
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{createTestCharacter("testchar", "testrealm", 2500.0)}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(createTestProfile(2600.0), nil)
+	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(rCharacter, nil)
+	raiderIOClient.On("GetSeasonCutoffs", ctx, "season-tww-2").Return(nil, errors.New("api error"))
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+	characterRepo.On("WithTx", ctx).Return(nil)
+	characterRepo.On("UpdateCharacter", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+	characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil)
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
+	// The update is still announced, just without the cutoffs
+	messageSender.On("SendComplexMessage", ctx, channelID, mock.MatchedBy(func(msg discordgo.MessageSend) bool {
+		return len(msg.Embeds[0].Fields) == 0
+	})).Return(nil)
+	sleeper.On("Sleep", cooldownTime).Return()
+
+	err := service.Update(ctx, channelID)
+
+	assert.NoError(t, err)
+	messageSender.AssertExpectations(t)
+}
+
+func TestService_Update_CustomTemplates(t *testing.T) {
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+	templates, err := discord.ParseTemplates(discord.TemplateText{Content: `{{.Name}} gained {{printf "%0.f" .Delta}}`})
+	require.NoError(t, err)
+	service.templates = templates
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{createTestCharacter("testchar", "testrealm", 2500.0)}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(createTestProfile(2600.0), nil)
+	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(createTestRaiderIOCharacter(2600.0, 0, 0), nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+	characterRepo.On("WithTx", ctx).Return(nil)
+	characterRepo.On("UpdateCharacter", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+	characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil)
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
+	messageSender.On("SendComplexMessage", ctx, channelID, mock.MatchedBy(func(msg discordgo.MessageSend) bool {
+		return msg.Content == "testchar gained 100"
+	})).Return(nil)
+	sleeper.On("Sleep", cooldownTime).Return()
+
+	err = service.Update(ctx, channelID)
+
+	assert.NoError(t, err)
+	messageSender.AssertExpectations(t)
+}
+
+func TestService_Update_Milestones(t *testing.T) {
+	tests := []struct {
+		name     string
+		checkErr error
+	}{
+		{name: "checked with the old score and cutoffs"},
+		// The score update has already been announced, so the update still succeeds
+		{name: "check error", checkErr: errors.New("discord error")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+			milestones := &MockMilestoneChecker{}
+			service.milestones = milestones
+			ctx := context.Background()
+			channelID := "test-channel"
+
+			rCharacter := createTestRaiderIOCharacter(2600.0, 0, 0)
+			rCharacter.MythicPlusScoresBySeason[0].Season = "season-tww-2"
+			cutoffs := &raiderio.Cutoffs{}
+
+			characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{createTestCharacter("testchar", "testrealm", 2450.0)}, nil)
+			blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(createTestProfile(2600.0), nil)
+			raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(rCharacter, nil)
+			raiderIOClient.On("GetSeasonCutoffs", ctx, "season-tww-2").Return(cutoffs, nil)
+			blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+			characterRepo.On("WithTx", ctx).Return(nil)
+			characterRepo.On("UpdateCharacter", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+			characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil)
+			characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+			characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
+			messageSender.On("SendComplexMessage", ctx, channelID, mock.AnythingOfType("discordgo.MessageSend")).Return(nil)
+			milestones.On("Check", ctx, channelID, mock.MatchedBy(func(c db.Character) bool {
+				return c.OverallScore == 2600.0
+			}), 2450.0, cutoffs).Return(tt.checkErr)
+			sleeper.On("Sleep", cooldownTime).Return()
+
+			err := service.Update(ctx, channelID)
+
+			assert.NoError(t, err)
+			milestones.AssertExpectations(t)
+			sleeper.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Update_NotifiesScoreChange(t *testing.T) {
+	service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+	notifier := &MockScoreNotifier{}
+	service.notifier = notifier
+	ctx := context.Background()
+	channelID := "test-channel"
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{createTestCharacter("testchar", "testrealm", 2450.0)}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(createTestProfile(2600.0), nil)
+	raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(createTestRaiderIOCharacter(2600.0, 0, 0), nil)
+	blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+	characterRepo.On("WithTx", ctx).Return(nil)
+	characterRepo.On("UpdateCharacter", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+	characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil)
+	characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+	characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
+	notifier.On("NotifyScoreChange", ctx, mock.MatchedBy(func(c notify.ScoreChange) bool {
+		return c.Event == notify.EventScoreChange && c.Character.Name == "testchar" && c.OldScore == 2450.0 &&
+			c.NewScore == 2600.0
+	})).Return(errors.New("webhook error"))
+	// A failed notification doesn't stop the announcement
+	messageSender.On("SendComplexMessage", ctx, channelID, mock.AnythingOfType("discordgo.MessageSend")).Return(nil)
+	sleeper.On("Sleep", cooldownTime).Return()
+
+	err := service.Update(ctx, channelID)
+
+	assert.NoError(t, err)
+	notifier.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+}
+
+func TestService_Update_Thread(t *testing.T) {
+	tests := []struct {
+		name      string
+		threadID  string
+		threadErr error
+		// target is where the score update is posted
+		target  string
+		summary bool
+	}{
+		{name: "posted in the thread", threadID: "thread-1", target: "thread-1", summary: true},
+		{name: "threads off", target: "test-channel"},
+		// The announcement isn't lost because the thread couldn't be started
+		{name: "thread error", threadErr: errors.New("missing permissions"), target: "test-channel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, characterRepo, blizzardClient, raiderIOClient, messageSender, sleeper := setupService()
+			threads := &MockThreadFinder{}
+			service.threads = threads
+			ctx := context.Background()
+			channelID := "test-channel"
+
+			characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{createTestCharacter("testchar", "testrealm", 2450.0)}, nil)
+			blizzardClient.On("GetMythicKeystoneProfile", ctx, "testrealm", "testchar").Return(createTestProfile(2600.0), nil)
+			raiderIOClient.On("GetCharacter", ctx, "testrealm", "testchar").Return(createTestRaiderIOCharacter(2600.0, 0, 0), nil)
+			blizzardClient.On("GetCharacterProfile", ctx, "testrealm", "testchar").Return(createTestCharacterProfile(), nil)
+			characterRepo.On("WithTx", ctx).Return(nil)
+			characterRepo.On("UpdateCharacter", ctx, mock.AnythingOfType("*db.Character")).Return(nil)
+			characterRepo.On("AddScoreHistory", ctx, mock.AnythingOfType("*db.ScoreHistory")).Return(nil)
+			characterRepo.On("ReplaceCharacterRuns", ctx, 1, mock.Anything).Return(nil)
+			characterRepo.On("ReplaceSpecScores", ctx, 1, mock.Anything).Return(nil)
+			threads.On("ThreadID", ctx, channelID, mock.MatchedBy(func(c db.Character) bool {
+				return c.Name == "testchar"
+			})).Return(tt.threadID, tt.threadErr)
+			messageSender.On("SendComplexMessage", ctx, tt.target, mock.MatchedBy(func(msg discordgo.MessageSend) bool {
+				return len(msg.Embeds) == 1
+			})).Return(nil).Once()
+			if tt.summary {
+				messageSender.On("SendComplexMessage", ctx, channelID, discordgo.MessageSend{
+					Content: "testchar-testrealm increased their score from 2450.00 to 2600.00, more in <#thread-1>",
+				}).Return(nil).Once()
+			}
+			sleeper.On("Sleep", cooldownTime).Return()
+
+			err := service.Update(ctx, channelID)
+
+			assert.NoError(t, err)
+			threads.AssertExpectations(t)
+			messageSender.AssertExpectations(t)
+			messageSender.AssertNumberOfCalls(t, "SendComplexMessage", len(messageSender.ExpectedCalls))
+		})
+	}
+}
+
 func TestRealSleeper_Sleep(t *testing.T) {
 	sleeper := &RealSleeper{}
 
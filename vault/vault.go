@@ -0,0 +1,190 @@
+// Package vault reports how far each tracked character is towards their weekly Great Vault rewards.
+//
+// The vault unlocks a reward slot after 1, 4 and 8 keys in a week, each slot rewarding gear for the lowest key level
+// among that many of the character's highest keys. Blizzard only lists a character's best run in each dungeon for the
+// current week, so repeat runs of the same dungeon aren't counted.
+package vault
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/blizzard"
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+)
+
+// The US weekly reset is Tuesday at 15:00 UTC.
+const (
+	resetWeekday = time.Tuesday
+	resetHour    = 15
+)
+
+// SlotRuns is how many runs unlock each vault slot.
+var SlotRuns = [3]int{1, 4, 8}
+
+type (
+	CharacterRepository interface {
+		ListCharacters(ctx context.Context, limit int) ([]db.Character, error)
+	}
+
+	BlizzardClient interface {
+		GetMythicKeystoneProfile(ctx context.Context, realm string, character string) (*blizzard.MythicKeystoneProfile, error)
+	}
+
+	MessageSender interface {
+		SendMessage(ctx context.Context, channelID, content string) error
+	}
+
+	// MessageBuilder builds the reminder in the announcement's language, see discord.BuildVaultReminderMessage.
+	MessageBuilder interface {
+		BuildVaultReminderMessage(l i18n.Locale, reset time.Time, characters []string) string
+	}
+
+	TimeProvider interface {
+		Now() time.Time
+	}
+
+	// Progress is a character's vault progress for the current week.
+	Progress struct {
+		Character db.Character
+		Runs      int
+		// Slots holds the key level each slot would reward, 0 if the slot isn't unlocked yet.
+		Slots [3]int
+	}
+)
+
+type RealTimeProvider struct{}
+
+func (r *RealTimeProvider) Now() time.Time {
+	return time.Now()
+}
+
+// Service handles vault progress with injected dependencies
+type Service struct {
+	characterRepo  CharacterRepository
+	blizzardClient BlizzardClient
+	messageSender  MessageSender
+	messageBuilder MessageBuilder
+	timeProvider   TimeProvider
+}
+
+// NewService creates a new vault service with dependencies
+func NewService(characterRepo CharacterRepository, blizzardClient BlizzardClient, messageSender MessageSender,
+	messageBuilder MessageBuilder, timeProvider TimeProvider,
+) *Service {
+	return &Service{
+		characterRepo:  characterRepo,
+		blizzardClient: blizzardClient,
+		messageSender:  messageSender,
+		messageBuilder: messageBuilder,
+		timeProvider:   timeProvider,
+	}
+}
+
+// Progress returns the vault progress of every tracked character, in the order they are listed by score.
+//
+// Characters whose profile can't be fetched are logged and left out.
+func (s *Service) Progress(ctx context.Context) ([]Progress, error) {
+	characters, err := s.characterRepo.ListCharacters(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list characters: %w", err)
+	}
+
+	progress := make([]Progress, 0, len(characters))
+	for _, c := range characters {
+		profile, err := s.blizzardClient.GetMythicKeystoneProfile(ctx, c.Realm, c.Name)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get mythic profile", "error", err, "character", c.Name, "realm", c.Realm)
+			continue
+		}
+
+		levels := make([]int, 0, len(profile.CurrentPeriod.BestRuns))
+		for _, run := range profile.CurrentPeriod.BestRuns {
+			levels = append(levels, run.KeystoneLevel)
+		}
+
+		progress = append(progress, Progress{Character: c, Runs: len(levels), Slots: slotLevels(levels)})
+	}
+
+	return progress, nil
+}
+
+// slotLevels returns the key level each slot rewards, the lowest of the character's top 1, 4 and 8 keys.
+func slotLevels(levels []int) [3]int {
+	sort.Sort(sort.Reverse(sort.IntSlice(levels)))
+
+	var slots [3]int
+	for i, runs := range SlotRuns {
+		if len(levels) >= runs {
+			slots[i] = levels[runs-1]
+		}
+	}
+	return slots
+}
+
+// Remind posts a message naming the characters that haven't run a key this week. Nothing is posted if everyone has.
+func (s *Service) Remind(ctx context.Context, channelID string) error {
+	progress, err := s.Progress(ctx)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, p := range progress {
+		if p.Runs == 0 {
+			names = append(names, fmt.Sprintf("%s-%s", p.Character.Name, p.Character.Realm))
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	reset := NextReset(s.timeProvider.Now())
+	return s.messageSender.SendMessage(ctx, channelID,
+		s.messageBuilder.BuildVaultReminderMessage(i18n.FromContext(ctx), reset, names))
+}
+
+// RunReminders posts the reminder the given duration before each weekly reset, until ctx is cancelled.
+func (s *Service) RunReminders(ctx context.Context, channelID string, before time.Duration) {
+	for {
+		now := s.timeProvider.Now()
+		next := NextReminder(now, before)
+		slog.DebugContext(ctx, "waiting for vault reminder", "at", next)
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.Remind(ctx, channelID); err != nil {
+			slog.ErrorContext(ctx, "failed to send vault reminder", "error", err)
+		}
+	}
+}
+
+// NextReset returns the first weekly reset after now.
+func NextReset(now time.Time) time.Time {
+	now = now.UTC()
+	days := (int(resetWeekday) - int(now.Weekday()) + 7) % 7
+	reset := time.Date(now.Year(), now.Month(), now.Day()+days, resetHour, 0, 0, 0, time.UTC)
+	if !reset.After(now) {
+		reset = reset.AddDate(0, 0, 7)
+	}
+	return reset
+}
+
+// NextReminder returns the first time after now that is the given duration before a weekly reset.
+func NextReminder(now time.Time, before time.Duration) time.Time {
+	reminder := NextReset(now).Add(-before)
+	for !reminder.After(now) {
+		reminder = reminder.AddDate(0, 0, 7)
+	}
+	return reminder
+}
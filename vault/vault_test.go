@@ -0,0 +1,209 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DylanNZL/mythicplusbot/blizzard"
+	"github.com/DylanNZL/mythicplusbot/db"
+	"github.com/DylanNZL/mythicplusbot/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock implementations for testing
+
+type MockCharacterRepository struct {
+	mock.Mock
+}
+
+func (m *MockCharacterRepository) ListCharacters(ctx context.Context, limit int) ([]db.Character, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]db.Character), args.Error(1)
+}
+
+type MockBlizzardClient struct {
+	mock.Mock
+}
+
+func (m *MockBlizzardClient) GetMythicKeystoneProfile(ctx context.Context, realm, character string) (*blizzard.MythicKeystoneProfile, error) {
+	args := m.Called(ctx, realm, character)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*blizzard.MythicKeystoneProfile), args.Error(1)
+}
+
+type MockMessageSender struct {
+	mock.Mock
+}
+
+func (m *MockMessageSender) SendMessage(ctx context.Context, channelID, content string) error {
+	args := m.Called(ctx, channelID, content)
+	return args.Error(0)
+}
+
+type MockMessageBuilder struct {
+	mock.Mock
+}
+
+func (m *MockMessageBuilder) BuildVaultReminderMessage(l i18n.Locale, reset time.Time, characters []string) string {
+	args := m.Called(l, reset, characters)
+	return args.String(0)
+}
+
+type MockTimeProvider struct {
+	now time.Time
+}
+
+func (m *MockTimeProvider) Now() time.Time {
+	return m.now
+}
+
+// Test helpers
+
+// Monday 1 January 2024, the following reset is Tuesday 2 January at 15:00 UTC
+var testNow = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func setupService() (*Service, *MockCharacterRepository, *MockBlizzardClient, *MockMessageSender) {
+	characterRepo := &MockCharacterRepository{}
+	blizzardClient := &MockBlizzardClient{}
+	messageSender := &MockMessageSender{}
+	messageBuilder := &MockMessageBuilder{}
+	// The reminder is built in the locale on the context, English by default
+	messageBuilder.On("BuildVaultReminderMessage", i18n.English, mock.Anything, mock.Anything).Return("reminder")
+
+	service := NewService(characterRepo, blizzardClient, messageSender, messageBuilder, &MockTimeProvider{now: testNow})
+	return service, characterRepo, blizzardClient, messageSender
+}
+
+// createTestProfile returns a profile with a run this week at each of the given key levels.
+func createTestProfile(t *testing.T, levels ...int) *blizzard.MythicKeystoneProfile {
+	t.Helper()
+
+	runs := make([]map[string]int, 0, len(levels))
+	for _, level := range levels {
+		runs = append(runs, map[string]int{"keystone_level": level})
+	}
+	data, err := json.Marshal(map[string]any{"current_period": map[string]any{"best_runs": runs}})
+	require.NoError(t, err)
+
+	var profile blizzard.MythicKeystoneProfile
+	require.NoError(t, json.Unmarshal(data, &profile))
+	return &profile
+}
+
+var (
+	paladin = db.Character{ID: 1, Name: "Paladylan", Realm: "tichondrius"}
+	mage    = db.Character{ID: 2, Name: "Magedylan", Realm: "area-52"}
+	priest  = db.Character{ID: 3, Name: "Priestdylan", Realm: "tichondrius"}
+)
+
+// Tests
+
+func TestService_Progress(t *testing.T) {
+	service, characterRepo, blizzardClient, _ := setupService()
+	ctx := context.Background()
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{paladin, mage, priest}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "tichondrius", "Paladylan").
+		Return(createTestProfile(t, 10, 12, 8, 11, 9, 12, 7, 10, 6), nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "area-52", "Magedylan").
+		Return(createTestProfile(t), nil)
+	// Characters we can't get a profile for are left out
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "tichondrius", "Priestdylan").
+		Return(nil, errors.New("api error"))
+
+	progress, err := service.Progress(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Progress{
+		{Character: paladin, Runs: 9, Slots: [3]int{12, 10, 7}},
+		{Character: mage},
+	}, progress)
+}
+
+func TestService_Progress_ListError(t *testing.T) {
+	service, characterRepo, _, _ := setupService()
+	ctx := context.Background()
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character(nil), errors.New("db error"))
+
+	progress, err := service.Progress(ctx)
+
+	assert.ErrorContains(t, err, "failed to list characters")
+	assert.Nil(t, progress)
+}
+
+func TestSlotLevels(t *testing.T) {
+	assert.Equal(t, [3]int{0, 0, 0}, slotLevels(nil))
+	assert.Equal(t, [3]int{12, 0, 0}, slotLevels([]int{10, 12}))
+	assert.Equal(t, [3]int{12, 8, 0}, slotLevels([]int{8, 12, 10, 9}))
+}
+
+func TestService_Remind(t *testing.T) {
+	service, characterRepo, blizzardClient, messageSender := setupService()
+	ctx := context.Background()
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{paladin, mage, priest}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "tichondrius", "Paladylan").Return(createTestProfile(t, 10), nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "area-52", "Magedylan").Return(createTestProfile(t), nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "tichondrius", "Priestdylan").Return(createTestProfile(t), nil)
+	messageSender.On("SendMessage", ctx, "channel1", "reminder").Return(nil)
+
+	err := service.Remind(ctx, "channel1")
+
+	assert.NoError(t, err)
+	messageSender.AssertExpectations(t)
+	service.messageBuilder.(*MockMessageBuilder).AssertCalled(t, "BuildVaultReminderMessage", i18n.English,
+		time.Date(2024, time.January, 2, 15, 0, 0, 0, time.UTC),
+		[]string{"Magedylan-area-52", "Priestdylan-tichondrius"})
+}
+
+func TestService_Remind_EveryoneHasRuns(t *testing.T) {
+	service, characterRepo, blizzardClient, messageSender := setupService()
+	ctx := context.Background()
+
+	characterRepo.On("ListCharacters", ctx, 0).Return([]db.Character{paladin}, nil)
+	blizzardClient.On("GetMythicKeystoneProfile", ctx, "tichondrius", "Paladylan").Return(createTestProfile(t, 10), nil)
+
+	err := service.Remind(ctx, "channel1")
+
+	assert.NoError(t, err)
+	messageSender.AssertNotCalled(t, "SendMessage")
+}
+
+func TestNextReset(t *testing.T) {
+	reset := time.Date(2024, time.January, 2, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected time.Time
+	}{
+		{name: "earlier in the week", now: testNow, expected: reset},
+		{name: "reset day before reset", now: reset.Add(-time.Minute), expected: reset},
+		{name: "at reset", now: reset, expected: reset.AddDate(0, 0, 7)},
+		{name: "reset day after reset", now: reset.Add(time.Hour), expected: reset.AddDate(0, 0, 7)},
+		{name: "other time zones", now: reset.In(time.FixedZone("NZDT", 13*60*60)).Add(-time.Hour), expected: reset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NextReset(tt.now))
+		})
+	}
+}
+
+func TestNextReminder(t *testing.T) {
+	reset := time.Date(2024, time.January, 2, 15, 0, 0, 0, time.UTC)
+
+	// 27 hours before the reset, the reminder is still to come this week
+	assert.Equal(t, reset.Add(-12*time.Hour), NextReminder(testNow, 12*time.Hour))
+	// Once this week's reminder has passed the next one is a week later
+	assert.Equal(t, reset.Add(-48*time.Hour).AddDate(0, 0, 7), NextReminder(testNow, 48*time.Hour))
+}